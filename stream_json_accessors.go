@@ -0,0 +1,257 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"time"
+)
+
+// GetString returns the string at the path. For a string that is still
+// streaming it returns the partial content with ok set to false; ok is true
+// only for a complete string value.
+func (p *StreamJSONParser) GetString(keys ...string) (string, bool) {
+	defer p.guardRead("GetString")()
+	node := p.findValueNode(keys)
+	if node == nil {
+		return "", false
+	}
+	value, isString := node.Value.(string)
+	return value, isString && node.Completed
+}
+
+// GetInt returns the number at the path as an int64. Floats convert when they
+// have no fractional part and fit in an int64; ok is false otherwise.
+func (p *StreamJSONParser) GetInt(keys ...string) (int64, bool) {
+	defer p.guardRead("GetInt")()
+	node := p.findValueNode(keys)
+	if node == nil || !node.Completed {
+		return 0, false
+	}
+
+	return numberToInt64(p.coercedValue(node.Value, NumberKind, p.options.lenientCoercion))
+}
+
+// GetFloat returns the number at the path as a float64, converting integers
+func (p *StreamJSONParser) GetFloat(keys ...string) (float64, bool) {
+	defer p.guardRead("GetFloat")()
+	node := p.findValueNode(keys)
+	if node == nil || !node.Completed {
+		return 0, false
+	}
+
+	return numberToFloat64(p.coercedValue(node.Value, NumberKind, p.options.lenientCoercion))
+}
+
+// GetBool returns the boolean at the path
+func (p *StreamJSONParser) GetBool(keys ...string) (bool, bool) {
+	defer p.guardRead("GetBool")()
+	node := p.findValueNode(keys)
+	if node == nil || !node.Completed {
+		return false, false
+	}
+	value, ok := p.coercedValue(node.Value, BoolKind, p.options.lenientCoercion).(bool)
+	return value, ok
+}
+
+// GetIntLenient is GetInt that also accepts a complete string holding a
+// number, such as "42" or " 42.0 ". A number at the path always takes
+// precedence; other strings and types report false.
+func (p *StreamJSONParser) GetIntLenient(keys ...string) (int64, bool) {
+	defer p.guardRead("GetIntLenient")()
+	node := p.findValueNode(keys)
+	if node == nil || !node.Completed {
+		return 0, false
+	}
+
+	return numberToInt64(p.coercedValue(node.Value, NumberKind, true))
+}
+
+// GetBoolLenient is GetBool that also accepts a complete string holding
+// "true" or "false", ignoring case and surrounding whitespace. Numbers such
+// as 0 and 1 are not converted.
+func (p *StreamJSONParser) GetBoolLenient(keys ...string) (bool, bool) {
+	defer p.guardRead("GetBoolLenient")()
+	node := p.findValueNode(keys)
+	if node == nil || !node.Completed {
+		return false, false
+	}
+	value, ok := p.coercedValue(node.Value, BoolKind, true).(bool)
+	return value, ok
+}
+
+// coercedValue returns value converted to kind when it is a string that
+// converts and lenient is set, and value unchanged otherwise
+func (p *StreamJSONParser) coercedValue(value interface{}, kind Kind, lenient bool) interface{} {
+	if _, isString := value.(string); !isString || !lenient {
+		return value
+	}
+	if coerced, ok := p.coerceValue(value, kind); ok {
+		return coerced
+	}
+	return value
+}
+
+// GetOr returns the value at the path once it is complete, and def while
+// the path is missing or its value is still streaming. A complete null is
+// returned as nil.
+func (p *StreamJSONParser) GetOr(def interface{}, keys ...string) interface{} {
+	defer p.guardRead("GetOr")()
+	if !p.IsComplete(keys...) {
+		return def
+	}
+	return p.Get(keys...)
+}
+
+// GetStringOr returns the complete string at the path, or def for a missing,
+// streaming or non-string value
+func (p *StreamJSONParser) GetStringOr(def string, keys ...string) string {
+	defer p.guardRead("GetStringOr")()
+	if value, ok := p.GetString(keys...); ok {
+		return value
+	}
+	return def
+}
+
+// GetTime parses the RFC 3339 timestamp at the path. ok is false while the
+// string is missing or still streaming; err reports a complete string that
+// is not a timestamp.
+func (p *StreamJSONParser) GetTime(keys ...string) (time.Time, bool, error) {
+	defer p.guardRead("GetTime")()
+	value, ok := p.GetString(keys...)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// GetDuration parses the Go duration string, such as "1m30s", at the path,
+// like GetTime
+func (p *StreamJSONParser) GetDuration(keys ...string) (time.Duration, bool, error) {
+	defer p.guardRead("GetDuration")()
+	value, ok := p.GetString(keys...)
+	if !ok {
+		return 0, false, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false, err
+	}
+	return d, true, nil
+}
+
+// GetUUID parses the UUID at the path, like GetTime
+func (p *StreamJSONParser) GetUUID(keys ...string) (UUID, bool, error) {
+	defer p.guardRead("GetUUID")()
+	value, ok := p.GetString(keys...)
+	if !ok {
+		return UUID{}, false, nil
+	}
+	u, err := ParseUUID(value)
+	if err != nil {
+		return UUID{}, false, err
+	}
+	return u, true, nil
+}
+
+// Exists reports whether the path has been seen. A key whose value has not
+// started yet, such as one followed by an unterminated number, counts as
+// seen. With no keys it reports whether the root has started.
+func (p *StreamJSONParser) Exists(keys ...string) bool {
+	defer p.guardRead("Exists")()
+	if p.root == nil {
+		return false
+	}
+	if p.findNode(keys) != nil {
+		return true
+	}
+	if len(keys) == 0 || len(p.stack) == 0 {
+		return false
+	}
+
+	// The innermost open object may have read the key but not its value
+	frame := p.stack[len(p.stack)-1]
+	return frame.Node.Type == ObjectNode &&
+		frame.CurrentKey == p.normalizeKey(keys[len(keys)-1]) &&
+		p.findNode(keys[:len(keys)-1]) == frame.Node
+}
+
+// IsComplete reports whether the value at the path has been finalized.
+// It is false both for values still streaming and for paths not seen yet;
+// use Exists to tell them apart.
+func (p *StreamJSONParser) IsComplete(keys ...string) bool {
+	defer p.guardRead("IsComplete")()
+	if p.root == nil {
+		return false
+	}
+	node := p.findNode(keys)
+	return node != nil && node.Completed
+}
+
+// Len returns the number of elements of the array, or members of the
+// object, at the path received so far, such as the 3 in "3 of ? items", and
+// whether the container is complete. An element or member still streaming
+// is counted; elements evicted by StreamArray are not. It returns 0 and
+// false for missing paths and values.
+func (p *StreamJSONParser) Len(keys ...string) (int, bool) {
+	defer p.guardRead("Len")()
+	node := p.findContainer(keys)
+	if node == nil {
+		return 0, false
+	}
+	return node.Len(), node.Completed
+}
+
+// Keys returns the keys of the object at the path received so far, in
+// sorted order, and whether the object is complete. A key whose value has
+// not started yet is not included. It returns nil and false for missing
+// paths and other values.
+func (p *StreamJSONParser) Keys(keys ...string) ([]string, bool) {
+	defer p.guardRead("Keys")()
+	node := p.findContainer(keys)
+	if node == nil || node.Type != ObjectNode {
+		return nil, false
+	}
+	return node.Keys(), node.Completed
+}
+
+// findContainer returns the object or array at the path, or nil for missing
+// paths and values
+func (p *StreamJSONParser) findContainer(keys []string) *Node {
+	if p.root == nil {
+		return nil
+	}
+	node := p.findNode(keys)
+	if node == nil || node.Type == ValueNode {
+		return nil
+	}
+	return node
+}
+
+// findValueNode returns the value node at the path, or nil for missing paths
+// and containers
+func (p *StreamJSONParser) findValueNode(keys []string) *Node {
+	if p.root == nil {
+		return nil
+	}
+	node := p.findNode(keys)
+	if node == nil || node.Type != ValueNode {
+		return nil
+	}
+	return node
+}
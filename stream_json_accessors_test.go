@@ -0,0 +1,295 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypedAccessors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"name":"Alice","age":30,"ratio":0.5,"whole":2.0,"big":1e30,"ok":true,"nothing":null,"list":[7]}`)
+
+	if name, ok := parser.GetString("name"); !ok || name != "Alice" {
+		t.Errorf("Expected name Alice, got %q %v", name, ok)
+	}
+
+	if age, ok := parser.GetInt("age"); !ok || age != 30 {
+		t.Errorf("Expected age 30, got %d %v", age, ok)
+	}
+
+	// Integral floats convert to int, fractional and out of range ones don't
+	if whole, ok := parser.GetInt("whole"); !ok || whole != 2 {
+		t.Errorf("Expected whole 2, got %d %v", whole, ok)
+	}
+	if _, ok := parser.GetInt("ratio"); ok {
+		t.Errorf("Expected fractional float not to convert to int")
+	}
+	if _, ok := parser.GetInt("big"); ok {
+		t.Errorf("Expected out of range float not to convert to int")
+	}
+
+	if ratio, ok := parser.GetFloat("ratio"); !ok || ratio != 0.5 {
+		t.Errorf("Expected ratio 0.5, got %v %v", ratio, ok)
+	}
+	if age, ok := parser.GetFloat("age"); !ok || age != 30 {
+		t.Errorf("Expected age 30.0, got %v %v", age, ok)
+	}
+
+	if value, ok := parser.GetBool("ok"); !ok || !value {
+		t.Errorf("Expected ok true, got %v %v", value, ok)
+	}
+
+	if item, ok := parser.GetInt("list", "0"); !ok || item != 7 {
+		t.Errorf("Expected list[0] 7, got %d %v", item, ok)
+	}
+
+	// Mismatched types, nulls, containers and missing paths
+	if _, ok := parser.GetString("age"); ok {
+		t.Errorf("Expected GetString on number to fail")
+	}
+	if _, ok := parser.GetBool("nothing"); ok {
+		t.Errorf("Expected GetBool on null to fail")
+	}
+	if _, ok := parser.GetInt("list"); ok {
+		t.Errorf("Expected GetInt on array to fail")
+	}
+	if _, ok := parser.GetFloat("missing"); ok {
+		t.Errorf("Expected GetFloat on missing path to fail")
+	}
+}
+
+func TestTypedAccessorsPartial(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	parser.Append(`{"message":"Hel`)
+	message, ok := parser.GetString("message")
+	if ok || message != "Hel" {
+		t.Errorf("Expected partial message 'Hel' not ok, got %q %v", message, ok)
+	}
+
+	parser.Append(`lo","count":4`)
+	if message, ok = parser.GetString("message"); !ok || message != "Hello" {
+		t.Errorf("Expected complete message 'Hello', got %q %v", message, ok)
+	}
+
+	if _, ok := parser.GetInt("count"); ok {
+		t.Errorf("Expected unterminated number not to be available")
+	}
+}
+
+func TestGetOrDefaults(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	parser.Append(`{"title":"Rep`)
+	if title := parser.GetStringOr("Untitled", "title"); title != "Untitled" {
+		t.Errorf("Expected default while streaming, got %q", title)
+	}
+	if value := parser.GetOr("none", "missing"); value != "none" {
+		t.Errorf("Expected default for missing path, got %v", value)
+	}
+
+	parser.Append(`ort","count":3,"owner":null,"tags":["a"]}`)
+	if title := parser.GetStringOr("Untitled", "title"); title != "Report" {
+		t.Errorf("Expected complete title, got %q", title)
+	}
+	if title := parser.GetStringOr("n/a", "count"); title != "n/a" {
+		t.Errorf("Expected default for non-string value, got %q", title)
+	}
+	if count := parser.GetOr(int64(0), "count"); count != int64(3) {
+		t.Errorf("Expected count 3, got %v", count)
+	}
+	if owner := parser.GetOr("nobody", "owner"); owner != nil {
+		t.Errorf("Expected complete null as nil, got %v", owner)
+	}
+	if tags := parser.GetOr(nil, "tags"); !reflect.DeepEqual(tags, []interface{}{"a"}) {
+		t.Errorf("Expected complete array, got %v", tags)
+	}
+}
+
+func TestTimeDurationAndUUIDAccessors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"at":"2025-03-01T12:30:00.5+02:00","timeout":"1m30s","id":"0F8FAD5B-D9CB-469F-A165-70867728950E","bad":"soon","note":"strea`)
+
+	at, ok, err := parser.GetTime("at")
+	expected := time.Date(2025, 3, 1, 10, 30, 0, 500000000, time.UTC)
+	if !ok || err != nil || !at.Equal(expected) {
+		t.Errorf("Expected time %v, got %v %v %v", expected, at, ok, err)
+	}
+
+	if timeout, ok, err := parser.GetDuration("timeout"); !ok || err != nil || timeout != 90*time.Second {
+		t.Errorf("Expected duration 1m30s, got %v %v %v", timeout, ok, err)
+	}
+
+	id, ok, err := parser.GetUUID("id")
+	if !ok || err != nil || id.String() != "0f8fad5b-d9cb-469f-a165-70867728950e" {
+		t.Errorf("Expected UUID, got %v %v %v", id, ok, err)
+	}
+
+	// A complete string that does not parse is an error
+	if _, ok, err := parser.GetTime("bad"); ok || err == nil {
+		t.Errorf("Expected an error for an invalid time, got %v %v", ok, err)
+	}
+	if _, ok, err := parser.GetUUID("bad"); ok || err == nil {
+		t.Errorf("Expected an error for an invalid UUID, got %v %v", ok, err)
+	}
+
+	// Missing and streaming values are not ready yet, which is not an error
+	if _, ok, err := parser.GetDuration("missing"); ok || err != nil {
+		t.Errorf("Expected a missing duration not to be ready, got %v %v", ok, err)
+	}
+	if _, ok, err := parser.GetTime("note"); ok || err != nil {
+		t.Errorf("Expected a streaming string not to be ready, got %v %v", ok, err)
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	for _, invalid := range []string{"", "0f8fad5bd9cb469fa16570867728950e", "0f8fad5b-d9cb-469f-a165-70867728950", "0f8fad5b_d9cb-469f-a165-70867728950e", "0g8fad5b-d9cb-469f-a165-70867728950e"} {
+		if _, err := ParseUUID(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestExistsAndIsComplete(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	if parser.Exists() || parser.IsComplete() {
+		t.Errorf("Expected nothing to exist before the root")
+	}
+
+	parser.Append(`{"user":{"name":"Al`)
+	if !parser.Exists("user", "name") || parser.IsComplete("user", "name") {
+		t.Errorf("Expected streaming name to exist but not be complete")
+	}
+	if parser.Exists("user", "age") || parser.IsComplete("user", "age") {
+		t.Errorf("Expected age not to exist yet")
+	}
+
+	parser.Append(`ice","age":4`)
+	if !parser.IsComplete("user", "name") {
+		t.Errorf("Expected name to be complete")
+	}
+	// The key was read but its number is not terminated
+	if !parser.Exists("user", "age") || parser.IsComplete("user", "age") {
+		t.Errorf("Expected pending age to exist but not be complete")
+	}
+	if parser.Exists("age") {
+		t.Errorf("Expected age to exist only under user")
+	}
+
+	parser.Append(`2},"tags":[1]}`)
+	if !parser.IsComplete("user", "age") || !parser.IsComplete("user") || !parser.IsComplete("tags", "0") {
+		t.Errorf("Expected values to be complete")
+	}
+	if parser.Exists("tags", "1") {
+		t.Errorf("Expected tags.1 not to exist")
+	}
+	if !parser.IsComplete() {
+		t.Errorf("Expected root to be complete")
+	}
+}
+
+func TestLenAndKeys(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"items": [{"id": 1}, {"id": 2}, "thi`)
+
+	if n, complete := parser.Len("items"); n != 3 || complete {
+		t.Errorf("Expected 3 items so far, got %d, %v", n, complete)
+	}
+	if keys, complete := parser.Keys(); len(keys) != 1 || keys[0] != "items" || complete {
+		t.Errorf("Expected the root keys so far, got %v, %v", keys, complete)
+	}
+
+	parser.Append(`rd"], "total": 3, "meta": {}}`)
+	if n, complete := parser.Len("items"); n != 3 || !complete {
+		t.Errorf("Expected 3 items in total, got %d, %v", n, complete)
+	}
+	if keys, complete := parser.Keys(); !reflect.DeepEqual(keys, []string{"items", "meta", "total"}) || !complete {
+		t.Errorf("Expected all root keys, got %v, %v", keys, complete)
+	}
+	if n, complete := parser.Len("meta"); n != 0 || !complete {
+		t.Errorf("Expected an empty complete object, got %d, %v", n, complete)
+	}
+
+	for _, path := range [][]string{{"total"}, {"missing"}, {"items", "0", "id"}} {
+		if n, complete := parser.Len(path...); n != 0 || complete {
+			t.Errorf("Expected no container at %v, got %d, %v", path, n, complete)
+		}
+	}
+	if keys, complete := parser.Keys("items"); keys != nil || complete {
+		t.Errorf("Expected no keys for an array, got %v, %v", keys, complete)
+	}
+}
+
+func TestLenientAccessors(t *testing.T) {
+	input := `{"count":"42","padded":" 7.0 ","native":3,"flag":"TRUE","off":"false","one":1,"word":"many","frac":"1.5","open":"12`
+	parser := NewStreamJSONParser()
+	parser.Append(input)
+
+	if _, ok := parser.GetInt("count"); ok {
+		t.Errorf("Expected GetInt to reject a string without WithLenientCoercion")
+	}
+	ints := []struct {
+		path string
+		want int64
+		ok   bool
+	}{
+		{"count", 42, true},
+		{"padded", 7, true},
+		{"native", 3, true},
+		{"frac", 0, false},
+		{"word", 0, false},
+		{"open", 0, false},
+	}
+	for _, tt := range ints {
+		if got, ok := parser.GetIntLenient(tt.path); got != tt.want || ok != tt.ok {
+			t.Errorf("GetIntLenient(%q) = %d, %v, want %d, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	bools := []struct {
+		path string
+		want bool
+		ok   bool
+	}{
+		{"flag", true, true},
+		{"off", false, true},
+		{"one", false, false},
+		{"word", false, false},
+	}
+	for _, tt := range bools {
+		if got, ok := parser.GetBoolLenient(tt.path); got != tt.want || ok != tt.ok {
+			t.Errorf("GetBoolLenient(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	lenient := NewStreamJSONParser(WithConfig(Config{LenientCoercion: true}))
+	lenient.Append(input)
+	if got, ok := lenient.GetInt("count"); !ok || got != 42 {
+		t.Errorf("Expected GetInt to coerce with WithLenientCoercion, got %d, %v", got, ok)
+	}
+	if got, ok := lenient.GetFloat("frac"); !ok || got != 1.5 {
+		t.Errorf("Expected GetFloat to coerce with WithLenientCoercion, got %v, %v", got, ok)
+	}
+	if got, ok := lenient.GetBool("flag"); !ok || !got {
+		t.Errorf("Expected GetBool to coerce with WithLenientCoercion, got %v, %v", got, ok)
+	}
+	if got := lenient.Get("count"); got != "42" {
+		t.Errorf("Expected the tree to keep the string, got %#v", got)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// arrayStream is a callback registered by StreamArray
+type arrayStream struct {
+	pattern  []string
+	callback func(index int, value interface{})
+}
+
+// StreamArray registers a callback for the elements of the array at a
+// dotted path, with "*" matching any single key or index and "" selecting
+// the root. Each element is delivered once it completes and is then evicted
+// from the AST, so arrays of any length are decoded in flat memory. Evicted
+// elements are no longer visible to Get, Query or MarshalJSON. Indices, both
+// in the callback and in paths, keep counting from the start of the array.
+func (p *StreamJSONParser) StreamArray(path string, callback func(index int, value interface{})) {
+	p.arrayStreams = append(p.arrayStreams, arrayStream{
+		pattern:  p.normalizePath(splitPath(path)),
+		callback: callback,
+	})
+}
+
+// deliverArrayElement hands a completed element of a streamed array to its
+// callbacks and evicts it. It must run after every other notification.
+func (p *StreamJSONParser) deliverArrayElement(path []string, node *Node) {
+	if len(p.arrayStreams) == 0 || node.Parent == nil || node.Parent.Type != ArrayNode || len(path) == 0 {
+		return
+	}
+
+	matched := false
+	for _, stream := range p.arrayStreams {
+		if matchPath(stream.pattern, path[:len(path)-1]) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return
+	}
+
+	// The array is the innermost open container, or the next one out when
+	// the element is a container whose frame is about to be popped
+	var frame *StackFrame
+	for i := len(p.stack) - 1; i >= 0 && i >= len(p.stack)-2; i-- {
+		if p.stack[i].Node == node.Parent {
+			frame = p.stack[i]
+			break
+		}
+	}
+	last := len(node.Parent.Array) - 1
+	if frame == nil || last < 0 || node.Parent.Array[last] != node {
+		return
+	}
+
+	index := frame.Evicted + last
+	value := p.collectNodeValue(node)
+	for _, stream := range p.arrayStreams {
+		if matchPath(stream.pattern, path[:len(path)-1]) {
+			stream.callback(index, value)
+		}
+	}
+
+	node.Parent.Array[last] = nil
+	node.Parent.Array = node.Parent.Array[:last]
+	frame.Evicted++
+	releaseTree(node)
+}
+
+// evictedElements returns the number of elements StreamArray has removed
+// from an open array
+func (p *StreamJSONParser) evictedElements(array *Node) int {
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].Node == array {
+			return p.stack[i].Evicted
+		}
+	}
+	return 0
+}
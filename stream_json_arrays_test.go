@@ -0,0 +1,134 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStreamJSONParserStreamArray(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	var indices []int
+	var values []interface{}
+	parser.StreamArray("results", func(index int, value interface{}) {
+		indices = append(indices, index)
+		values = append(values, value)
+	})
+
+	var names []interface{}
+	parser.OnValue("results.*.name", func(value interface{}, complete bool) {
+		if complete {
+			names = append(names, value)
+		}
+	})
+
+	parser.Append(`{"results":[{"name":"a","tags":[1]},"b`)
+	if len(values) != 1 {
+		t.Fatalf("Expected first element to be delivered, got %v", values)
+	}
+	if parser.Get("results", "1") != "b" {
+		t.Errorf("Expected streaming element to stay accessible, got %v", parser.Get("results"))
+	}
+
+	parser.Append(`c",3,[true]],"total":3}`)
+	if !parser.IsCompleted() {
+		t.Fatal("Expected parsing to complete")
+	}
+
+	expected := []string{`map[name:a tags:[1]]`, `bc`, `3`, `[true]`}
+	if len(values) != len(expected) {
+		t.Fatalf("Expected %d elements, got %v", len(expected), values)
+	}
+	for i := range expected {
+		if indices[i] != i {
+			t.Errorf("Element %d: expected index %d, got %d", i, i, indices[i])
+		}
+		if got := formatValue(values[i]); got != expected[i] {
+			t.Errorf("Element %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+
+	if array, ok := parser.Get("results").([]interface{}); !ok || len(array) != 0 {
+		t.Errorf("Expected elements to be evicted, got %v", parser.Get("results"))
+	}
+	if parser.Get("total") != int64(3) {
+		t.Errorf("Expected parsing to continue after the array, got %v", parser.Get("total"))
+	}
+	if len(names) != 1 || names[0] != "a" {
+		t.Errorf("Expected other callbacks to see elements before eviction, got %v", names)
+	}
+}
+
+func TestStreamJSONParserStreamArrayFlatMemory(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	sum := int64(0)
+	count := 0
+	parser.StreamArray("", func(index int, value interface{}) {
+		if index != count {
+			t.Fatalf("Expected index %d, got %d", count, index)
+		}
+		count++
+		sum += value.(map[string]interface{})["n"].(int64)
+	})
+
+	parser.Append("[")
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			parser.Append(",")
+		}
+		parser.Append(`{"n":` + strconv.Itoa(i) + `}`)
+		if len(parser.GetRoot().Array) > 1 {
+			t.Fatalf("Expected at most one element in memory, got %d", len(parser.GetRoot().Array))
+		}
+	}
+	parser.Append("]")
+
+	if count != 10000 || sum != 49995000 {
+		t.Errorf("Expected 10000 elements summing to 49995000, got %d and %d", count, sum)
+	}
+}
+
+// formatValue formats a collected value for comparison
+func formatValue(value interface{}) string {
+	var b strings.Builder
+	switch v := value.(type) {
+	case map[string]interface{}:
+		b.WriteString("map[name:")
+		b.WriteString(formatValue(v["name"]))
+		b.WriteString(" tags:")
+		b.WriteString(formatValue(v["tags"]))
+		b.WriteString("]")
+	case []interface{}:
+		b.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(formatValue(item))
+		}
+		b.WriteString("]")
+	case string:
+		b.WriteString(v)
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	}
+	return b.String()
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// asyncQueueSize is the number of chunks Send queues before it blocks
+const asyncQueueSize = 64
+
+// ErrAsyncClosed is returned by Send once the AsyncParser is closed
+var ErrAsyncClosed = errors.New("streamjson: async parser closed")
+
+// AsyncParser runs a parser on its own goroutine, as a stage of a streaming
+// pipeline. Chunks are queued with Send, which blocks while the queue is
+// full so a slow parser pushes back on the producer. Progress is published
+// on Events and Snapshots, and Parser gives concurrent read access to the
+// document. Close ends the input, waits for the queued chunks to be parsed
+// and finishes the stream.
+type AsyncParser struct {
+	parser    *SafeStreamJSONParser
+	chunks    chan string
+	snapshots chan Value
+	watching  atomic.Bool   // Whether Snapshots was called
+	done      chan struct{} // Closed once the worker has finished
+
+	mu     sync.RWMutex // Orders Send against Close
+	closed bool
+}
+
+// NewAsyncParser creates a parser configured by opts and starts its goroutine
+func NewAsyncParser(opts ...Option) *AsyncParser {
+	a := &AsyncParser{
+		parser:    NewSafeStreamJSONParser(append([]Option{WithBlockingEvents()}, opts...)...),
+		chunks:    make(chan string, asyncQueueSize),
+		snapshots: make(chan Value, 1),
+		done:      make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// run parses queued chunks until the queue is closed
+func (a *AsyncParser) run() {
+	defer close(a.done)
+	defer close(a.snapshots)
+
+	for chunk := range a.chunks {
+		a.parser.Append(chunk)
+		a.publish()
+	}
+	a.parser.Finish()
+	a.publish()
+}
+
+// publish replaces the pending snapshot with the current document, so a
+// slow reader skips intermediate states instead of holding up parsing
+func (a *AsyncParser) publish() {
+	if !a.watching.Load() {
+		return
+	}
+	snapshot := a.parser.Snapshot()
+	select {
+	case <-a.snapshots:
+	default:
+	}
+	a.snapshots <- snapshot
+}
+
+// Send queues a chunk for parsing. It blocks while the queue is full, until
+// ctx is done, and returns ErrAsyncClosed after Close.
+func (a *AsyncParser) Send(ctx context.Context, chunk string) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrAsyncClosed
+	}
+
+	select {
+	case a.chunks <- chunk:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Append queues content like Send without a deadline, so an AsyncParser can
+// be the target of an SSEFeeder or a binary feeder. Content appended after
+// Close is dropped.
+func (a *AsyncParser) Append(content string) {
+	_ = a.Send(context.Background(), content)
+}
+
+// Close ends the input and waits until every queued chunk is parsed and the
+// stream is finished. It returns the error that stopped the parser, if any.
+// Events and Snapshots are closed once it returns.
+func (a *AsyncParser) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.chunks)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+	return a.parser.Err()
+}
+
+// Done returns a channel closed once the parser has finished after Close
+func (a *AsyncParser) Done() <-chan struct{} {
+	return a.done
+}
+
+// Events returns the parser's event channel, see StreamJSONParser.Events.
+// Call it before the first Send. Events block as with WithBlockingEvents:
+// the worker waits while the channel is full, and Send in turn once the
+// queue is, so it must be drained.
+func (a *AsyncParser) Events() <-chan Event {
+	return a.parser.Events()
+}
+
+// Snapshots returns a channel of immutable copies of the document, see
+// StreamJSONParser.Snapshot, published after each chunk and once more when
+// the stream is finished. Only the latest snapshot is kept, so reading it
+// never slows down parsing. The channel is closed when the parser is done.
+func (a *AsyncParser) Snapshots() <-chan Value {
+	a.watching.Store(true)
+	return a.snapshots
+}
+
+// Parser returns the underlying parser for reading values while chunks are
+// being parsed. Content must only be added through Send.
+func (a *AsyncParser) Parser() *SafeStreamJSONParser {
+	return a.parser
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsyncParser(t *testing.T) {
+	parser := NewAsyncParser()
+	snapshots := parser.Snapshots()
+
+	var last Value
+	received := make(chan struct{})
+	go func() {
+		defer close(received)
+		for snapshot := range snapshots {
+			last = snapshot
+		}
+	}()
+
+	ctx := context.Background()
+	for _, chunk := range []string{`{"name":"Al`, `ice","tags":["a",`, `"b"]}`} {
+		if err := parser.Send(ctx, chunk); err != nil {
+			t.Fatalf("Unexpected send error: %v", err)
+		}
+	}
+
+	if err := parser.Close(); err != nil {
+		t.Fatalf("Unexpected parser error: %v", err)
+	}
+	<-received
+
+	expected := map[string]interface{}{"name": "Alice", "tags": []interface{}{"a", "b"}}
+	if !reflect.DeepEqual(last.Interface(), expected) {
+		t.Errorf("Expected final snapshot %v, got %v", expected, last.Interface())
+	}
+	if !parser.Parser().IsCompleted() {
+		t.Errorf("Expected the document to be completed")
+	}
+
+	if err := parser.Send(ctx, "x"); err != ErrAsyncClosed {
+		t.Errorf("Expected ErrAsyncClosed after Close, got %v", err)
+	}
+	if err := parser.Close(); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+}
+
+func TestAsyncParserBackpressure(t *testing.T) {
+	parser := NewAsyncParser()
+	events := parser.Events()
+
+	// Nobody drains the events, so the worker stalls and the queue fills up
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	chunk := strings.Repeat("1,", 100)
+
+	var err error
+	if err = parser.Send(ctx, "["); err == nil {
+		for i := 0; i < 10*asyncQueueSize && err == nil; i++ {
+			err = parser.Send(ctx, chunk)
+		}
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected Send to block until the deadline, got %v", err)
+	}
+
+	// Draining lets everything queued through
+	go func() {
+		for range events {
+		}
+	}()
+	if err := parser.Close(); err != nil {
+		t.Fatalf("Unexpected parser error: %v", err)
+	}
+	if parser.Parser().Completion() != Truncated {
+		t.Errorf("Expected the unterminated array to be truncated, got %v", parser.Parser().Completion())
+	}
+}
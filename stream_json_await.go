@@ -0,0 +1,88 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrValueNotCompleted is returned by Await when the stream ends before the
+// value at the path completes
+var ErrValueNotCompleted = errors.New("streamjson: value did not complete")
+
+// awaitResult is the outcome delivered to an Await call
+type awaitResult struct {
+	value interface{}
+	err   error
+}
+
+// Await blocks until the value at the path completes and returns it, for
+// code that only needs one field out of a long stream. A value already
+// complete is returned right away. It returns ErrValueNotCompleted when the
+// document completes without the path or Finish or Reset is called first,
+// and the context's error when ctx is done first. A string cut off by Finish
+// completes with its partial content, as with Watch.
+//
+// Await waits for Append calls made on other goroutines, which requires a
+// SafeStreamJSONParser.
+func (p *StreamJSONParser) Await(ctx context.Context, keys ...string) (interface{}, error) {
+	return waitResult(ctx, p.await(keys))
+}
+
+// await registers an Await for the value at keys and returns the channel
+// its result is delivered on
+func (p *StreamJSONParser) await(keys []string) chan awaitResult {
+	sub := watchSubscription{path: slices.Clone(p.normalizePath(keys)), result: make(chan awaitResult, 1)}
+
+	var node *Node
+	if p.root != nil {
+		node = p.findNode(keys)
+	}
+	switch {
+	case node != nil && node.Completed:
+		sub.result <- awaitResult{value: p.collectNodeValue(node)}
+	case p.finished || p.IsCompleted() && !p.options.multipleDocuments:
+		sub.result <- awaitResult{err: ErrValueNotCompleted} // Nothing more will arrive
+	default:
+		p.watches = append(p.watches, sub)
+	}
+	return sub.result
+}
+
+// resolveAwaits fails the pending awaits, keeping watches
+func (p *StreamJSONParser) resolveAwaits() {
+	kept := p.watches[:0]
+	for _, sub := range p.watches {
+		if sub.result != nil {
+			sub.result <- awaitResult{err: ErrValueNotCompleted}
+		} else {
+			kept = append(kept, sub)
+		}
+	}
+	clear(p.watches[len(kept):])
+	p.watches = kept
+}
+
+// waitResult waits for the result of an Await or for ctx to be done
+func waitResult(ctx context.Context, result <-chan awaitResult) (interface{}, error) {
+	select {
+	case r := <-result:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
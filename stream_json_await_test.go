@@ -0,0 +1,98 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStreamJSONParserAwait(t *testing.T) {
+	parser := NewSafeStreamJSONParser()
+	done := make(chan struct{})
+	var value interface{}
+	var err error
+	go func() {
+		value, err = parser.Await(context.Background(), "result", "answer")
+		close(done)
+	}()
+
+	for _, chunk := range []string{`{"log": "thinking...", "result": {"ans`, `wer": "fort`, `y-two"`, `, "more": 1}`} {
+		parser.Append(chunk)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Await to return once the value completed")
+	}
+	if err != nil || value != "forty-two" {
+		t.Errorf("Expected forty-two, got %v (err %v)", value, err)
+	}
+}
+
+func TestStreamJSONParserAwaitCompleted(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": {"b": [1, 2]}, "c": "partial`)
+
+	value, err := parser.Await(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if b := value.(map[string]interface{})["b"].([]interface{}); len(b) != 2 {
+		t.Errorf("Expected completed object, got %v", value)
+	}
+
+	// A value still streaming is not returned
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := parser.Await(ctx, "c"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestStreamJSONParserAwaitMissing(t *testing.T) {
+	parser := NewStreamJSONParser()
+	result := parser.await([]string{"missing"})
+	parser.Append(`{"a": 1}`)
+
+	if _, err := waitResult(context.Background(), result); !errors.Is(err, ErrValueNotCompleted) {
+		t.Errorf("Expected ErrValueNotCompleted once the document completed, got %v", err)
+	}
+	if _, err := parser.Await(context.Background(), "missing"); !errors.Is(err, ErrValueNotCompleted) {
+		t.Errorf("Expected ErrValueNotCompleted for a completed document, got %v", err)
+	}
+
+	// Finish and Reset end pending awaits
+	parser.Reset()
+	result = parser.await([]string{"a"})
+	parser.Append(`{"a": [1`)
+	parser.Finish()
+	if _, err := waitResult(context.Background(), result); !errors.Is(err, ErrValueNotCompleted) {
+		t.Errorf("Expected ErrValueNotCompleted after Finish, got %v", err)
+	}
+}
+
+func TestStreamJSONParserAwaitMultipleDocuments(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments())
+	result := parser.await([]string{"b"})
+	parser.Append(`{"a": 1}`)
+	parser.Append(`{"b": 2}`)
+
+	if value, err := waitResult(context.Background(), result); err != nil || value != int64(2) {
+		t.Errorf("Expected a later document to resolve the await, got %v (err %v)", value, err)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ErrBinaryInput is wrapped by the errors returned for malformed CBOR or
+// MessagePack input
+var ErrBinaryInput = errors.New("streamjson: invalid binary input")
+
+// binaryKind classifies the items of a CBOR or MessagePack stream
+type binaryKind int
+
+const (
+	binaryScalar binaryKind = iota // A number, bool or null held in value
+	binaryText                     // A UTF-8 string
+	binaryBytes                    // A byte string
+	binaryExt                      // A MessagePack extension
+	binaryArray                    // An array of length items
+	binaryMap                      // A map of length pairs
+	binaryTag                      // A CBOR tag, which annotates the next item
+	binaryBreak                    // The end of a CBOR indefinite-length item
+)
+
+// binaryItem is the header of an item, decoded by a format's decode function
+type binaryItem struct {
+	kind   binaryKind
+	length int         // Content bytes, elements or pairs, -1 for indefinite length
+	value  interface{} // Value of a scalar
+	ext    int8        // Type of a MessagePack extension
+}
+
+// binaryFrame is an open container, or an indefinite-length string whose
+// chunks are still arriving
+type binaryFrame struct {
+	kind      binaryKind
+	remaining int  // Items left, -1 until a break
+	key       bool // For maps, whether the next item is a key; for strings, whether the string is a key
+}
+
+// binaryString is a string whose content is still arriving
+type binaryString struct {
+	kind      binaryKind
+	remaining int  // Content bytes left
+	key       bool // Whether the string is a map key
+	chunk     bool // Whether the string is a chunk of an indefinite-length string
+	ext       int8 // Type of a MessagePack extension
+}
+
+// BinaryFeeder decodes a CBOR or MessagePack stream incrementally and
+// appends its JSON equivalent to a parser, so a service that switches wire
+// formats keeps the same Get API and partial-access semantics: strings are
+// exposed while their bytes arrive and containers fill as elements do.
+//
+// Byte strings become base64 strings, as in encoding/json, map keys that
+// are numbers or bools become their JSON text, MessagePack timestamps
+// become RFC 3339 strings, and other extensions, CBOR undefined and
+// non-finite floats become null. CBOR tags are ignored. Offsets reported by
+// the parser refer to the JSON text, not to the binary input.
+type BinaryFeeder struct {
+	writer  *StreamJSONWriter
+	decode  func(data []byte) (binaryItem, int, error) // Decodes an item header, 0 bytes when more input is needed
+	buf     []byte                                     // Input not decoded yet
+	stack   []binaryFrame
+	str     binaryString
+	inStr   bool   // Whether str is open
+	collect []byte // Content of keys, byte strings and extensions
+	err     error
+}
+
+// appenderWriter adapts an Appender to io.Writer
+type appenderWriter struct {
+	target Appender
+}
+
+// Write appends data to the target
+func (w appenderWriter) Write(data []byte) (int, error) {
+	w.target.Append(string(data))
+	return len(data), nil
+}
+
+// newBinaryFeeder creates a feeder for the format decoded by decode
+func newBinaryFeeder(target Appender, decode func(data []byte) (binaryItem, int, error)) *BinaryFeeder {
+	return &BinaryFeeder{
+		writer: NewStreamJSONWriter(appenderWriter{target: target}),
+		decode: decode,
+	}
+}
+
+// Write decodes data and appends the JSON for every item it completes or
+// extends, so io.Copy can feed a parser from a connection. Once the input
+// turns out to be malformed, every call returns an error wrapping
+// ErrBinaryInput.
+func (f *BinaryFeeder) Write(data []byte) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.buf = append(f.buf, data...)
+
+	pos := 0
+	for f.err == nil {
+		if f.inStr {
+			n := min(f.str.remaining, len(f.buf)-pos)
+			if n == 0 && f.str.remaining > 0 {
+				break // Wait for more content
+			}
+			f.stringContent(f.buf[pos : pos+n])
+			pos += n
+			f.str.remaining -= n
+			if f.str.remaining == 0 {
+				f.inStr = false
+				f.endString()
+			}
+			continue
+		}
+
+		item, n, err := f.decode(f.buf[pos:])
+		if err != nil {
+			f.err = err
+			break
+		}
+		if n == 0 {
+			break // Wait for the rest of the header
+		}
+		pos += n
+		f.item(item)
+	}
+
+	f.buf = append(f.buf[:0], f.buf[pos:]...)
+	if f.err != nil {
+		return len(data), f.err
+	}
+	return len(data), nil
+}
+
+// Close reports io.ErrUnexpectedEOF if the input ended inside an item. It
+// does not finish the target parser.
+func (f *BinaryFeeder) Close() error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.inStr || len(f.stack) > 0 || len(f.buf) > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// item handles a decoded item header
+func (f *BinaryFeeder) item(item binaryItem) {
+	if n := len(f.stack); n > 0 && (f.stack[n-1].kind == binaryText || f.stack[n-1].kind == binaryBytes) &&
+		item.kind != binaryText && item.kind != binaryBytes && item.kind != binaryBreak {
+		f.fail("invalid chunk of an indefinite-length string")
+		return
+	}
+
+	switch item.kind {
+	case binaryTag:
+		return // Only the tagged item matters
+
+	case binaryBreak:
+		f.endIndefinite()
+
+	case binaryScalar:
+		if f.atKey() {
+			f.check(f.writer.WriteKey(keyText(item.value)))
+		} else {
+			f.check(f.writer.WriteValue(jsonScalar(item.value)))
+		}
+		f.completed()
+
+	case binaryText, binaryBytes, binaryExt:
+		f.beginString(item)
+
+	case binaryArray, binaryMap:
+		if f.atKey() {
+			f.fail("%s as a map key", kindName(item.kind))
+			return
+		}
+		remaining := item.length
+		if item.kind == binaryMap {
+			remaining = max(remaining*2, -1) // Keys and values
+			f.check(f.writer.BeginObject())
+		} else {
+			f.check(f.writer.BeginArray())
+		}
+		f.stack = append(f.stack, binaryFrame{kind: item.kind, remaining: remaining, key: item.kind == binaryMap})
+		if remaining == 0 {
+			f.endContainer()
+		}
+	}
+}
+
+// beginString starts a string, byte string or extension
+func (f *BinaryFeeder) beginString(item binaryItem) {
+	chunk := false
+	key := f.atKey()
+	if n := len(f.stack); n > 0 && (f.stack[n-1].kind == binaryText || f.stack[n-1].kind == binaryBytes) {
+		if f.stack[n-1].kind != item.kind || item.length < 0 {
+			f.fail("invalid chunk of an indefinite-length string")
+			return
+		}
+		chunk = true
+		key = f.stack[n-1].key
+	} else {
+		f.collect = f.collect[:0]
+	}
+
+	if item.length < 0 {
+		if item.kind == binaryText && !key {
+			f.check(f.writer.WriteStringChunk("")) // Open the string for its chunks
+		}
+		f.stack = append(f.stack, binaryFrame{kind: item.kind, remaining: -1, key: key})
+		return
+	}
+
+	if item.kind == binaryText && !key && !chunk {
+		f.check(f.writer.WriteStringChunk(""))
+	}
+	f.str = binaryString{kind: item.kind, remaining: item.length, key: key, chunk: chunk, ext: item.ext}
+	f.inStr = true
+}
+
+// stringContent handles content bytes of the open string
+func (f *BinaryFeeder) stringContent(data []byte) {
+	if f.str.kind == binaryText && !f.str.key {
+		if len(data) > 0 {
+			f.check(f.writer.WriteStringChunk(string(data)))
+		}
+		return
+	}
+	f.collect = append(f.collect, data...)
+}
+
+// endString finishes the string whose content is complete
+func (f *BinaryFeeder) endString() {
+	if f.str.chunk {
+		return // The indefinite-length string continues until its break
+	}
+	f.writeString(f.str.kind, f.str.key, f.str.ext)
+}
+
+// writeString writes a complete string, byte string or extension as a key
+// or value
+func (f *BinaryFeeder) writeString(kind binaryKind, key bool, ext int8) {
+	switch {
+	case kind == binaryExt && key:
+		f.fail("extension as a map key")
+		return
+	case kind == binaryExt:
+		f.check(f.writer.WriteValue(extensionValue(ext, f.collect)))
+	case key:
+		f.check(f.writer.WriteKey(string(f.collect)))
+	case kind == binaryText:
+		f.check(f.writer.EndString())
+	default:
+		f.check(f.writer.WriteValue(f.collect))
+	}
+	f.completed()
+}
+
+// endIndefinite handles a break, closing the innermost indefinite-length item
+func (f *BinaryFeeder) endIndefinite() {
+	n := len(f.stack)
+	if n == 0 || f.stack[n-1].remaining >= 0 {
+		f.fail("unexpected break")
+		return
+	}
+
+	frame := f.stack[n-1]
+	switch frame.kind {
+	case binaryText, binaryBytes:
+		f.stack = f.stack[:n-1]
+		f.writeString(frame.kind, frame.key, 0)
+	case binaryMap:
+		if !frame.key {
+			f.fail("map without a value for its last key")
+			return
+		}
+		f.endContainer()
+	default:
+		f.endContainer()
+	}
+}
+
+// endContainer closes the innermost container
+func (f *BinaryFeeder) endContainer() {
+	frame := f.stack[len(f.stack)-1]
+	f.stack = f.stack[:len(f.stack)-1]
+	if frame.kind == binaryMap {
+		f.check(f.writer.EndObject())
+	} else {
+		f.check(f.writer.EndArray())
+	}
+	f.completed()
+}
+
+// completed counts an item as done in its container, closing containers
+// whose items are all done
+func (f *BinaryFeeder) completed() {
+	if len(f.stack) == 0 {
+		return
+	}
+	frame := &f.stack[len(f.stack)-1]
+	if frame.kind == binaryMap {
+		frame.key = !frame.key
+	}
+	if frame.remaining < 0 {
+		return // Ends with a break
+	}
+	frame.remaining--
+	if frame.remaining == 0 {
+		f.endContainer()
+	}
+}
+
+// atKey reports whether the next item is a map key
+func (f *BinaryFeeder) atKey() bool {
+	n := len(f.stack)
+	return n > 0 && f.stack[n-1].kind == binaryMap && f.stack[n-1].key
+}
+
+// check records a writer error
+func (f *BinaryFeeder) check(err error) {
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+}
+
+// fail records malformed input
+func (f *BinaryFeeder) fail(format string, args ...interface{}) {
+	if f.err == nil {
+		f.err = binaryError(format, args...)
+	}
+}
+
+// binaryError returns an error wrapping ErrBinaryInput
+func binaryError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrBinaryInput, fmt.Sprintf(format, args...))
+}
+
+// jsonScalar maps a scalar to a value encoding/json can write
+func jsonScalar(value interface{}) interface{} {
+	if v, ok := value.(float64); ok && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return nil
+	}
+	return value
+}
+
+// keyText returns the JSON key for a scalar map key
+func keyText(value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return "null"
+}
+
+// kindName names a container kind in error messages
+func kindName(kind binaryKind) string {
+	if kind == binaryMap {
+		return "map"
+	}
+	return "array"
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// textAppender collects appended content
+type textAppender struct {
+	strings.Builder
+}
+
+func (a *textAppender) Append(content string) {
+	a.WriteString(content)
+}
+
+func TestBinaryFeeders(t *testing.T) {
+	tests := []struct {
+		name     string
+		feeder   func(Appender) *BinaryFeeder
+		input    string
+		expected string
+	}{
+		{
+			name:     "cbor",
+			feeder:   NewCBORFeeder,
+			input:    "\xa3\x61a\x85\x01\x21\xf9\x3e\x00\xf5\xf6\x61s\x62hi\x61b\x42\x01\x02",
+			expected: `{"a":[1,-2,1.5,true,null],"s":"hi","b":"AQI="}`,
+		},
+		{
+			name:     "cbor indefinite length",
+			feeder:   NewCBORFeeder,
+			input:    "\xbf\x61t\x7f\x62he\x63llo\xff\x7f\x61k\x61y\xff\x9f\x01\xc1\x02\xff\x01\x80\xff",
+			expected: `{"t":"hello","ky":[1,2],"1":[]}`,
+		},
+		{
+			name:     "cbor roots",
+			feeder:   NewCBORFeeder,
+			input:    "\x18\x64\x38\x63\xa0",
+			expected: "100\n-100\n{}",
+		},
+		{
+			name:     "msgpack",
+			feeder:   NewMessagePackFeeder,
+			input:    "\x84\xa1a\x95\x01\xfe\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00\xc3\xc0\xa1s\xa2hi\xa1b\xc4\x02\x01\x02\xa1t\xd6\xff\x00\x00\x00\x00",
+			expected: `{"a":[1,-2,1.5,true,null],"s":"hi","b":"AQI=","t":"1970-01-01T00:00:00Z"}`,
+		},
+		{
+			name:     "msgpack sized",
+			feeder:   NewMessagePackFeeder,
+			input:    "\xde\x00\x02\xd9\x01k\xdc\x00\x01\xd1\xff\x38\x07\xcd\x01\x00\xd4\x05\x00",
+			expected: `{"k":[-200],"7":256}` + "\nnull",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, chunk := range []int{len(tt.input), 1} {
+			out := &textAppender{}
+			parser := NewStreamJSONParser()
+			feeders := []*BinaryFeeder{tt.feeder(out), tt.feeder(parser)}
+			for i := 0; i < len(tt.input); i += chunk {
+				for _, feeder := range feeders {
+					if _, err := feeder.Write([]byte(tt.input[i:min(i+chunk, len(tt.input))])); err != nil {
+						t.Fatalf("%s: unexpected error: %v", tt.name, err)
+					}
+				}
+			}
+			for _, feeder := range feeders {
+				if err := feeder.Close(); err != nil {
+					t.Errorf("%s: unexpected error on close: %v", tt.name, err)
+				}
+			}
+			if out.String() != tt.expected {
+				t.Errorf("%s (chunk %d): expected %s, got %s", tt.name, chunk, tt.expected, out.String())
+			}
+			if !strings.Contains(tt.expected, "\n") && !parser.IsCompleted() {
+				t.Errorf("%s: expected the parser to complete", tt.name)
+			}
+		}
+	}
+}
+
+func TestBinaryFeederPartialString(t *testing.T) {
+	parser := NewStreamJSONParser()
+	feeder := NewCBORFeeder(parser)
+
+	feeder.Write([]byte("\xa1\x61s\x65hel"))
+	if parser.Get("s") != "hel" {
+		t.Errorf("Expected partial string hel, got %v", parser.Get("s"))
+	}
+	if err := feeder.Close(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	feeder.Write([]byte("lo"))
+	if parser.Get("s") != "hello" || !parser.IsCompleted() {
+		t.Errorf("Expected completed hello, got %v", parser.Get("s"))
+	}
+	if err := feeder.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestBinaryFeederInvalidInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		feeder func(Appender) *BinaryFeeder
+		input  string
+	}{
+		{"cbor break", NewCBORFeeder, "\xff"},
+		{"cbor reserved", NewCBORFeeder, "\x1c"},
+		{"cbor container key", NewCBORFeeder, "\xa1\x80\x01"},
+		{"cbor mixed chunks", NewCBORFeeder, "\x7f\x41a\xff"},
+		{"msgpack never used", NewMessagePackFeeder, "\xc1"},
+		{"msgpack extension key", NewMessagePackFeeder, "\x81\xd4\x01\x00\x01"},
+	}
+
+	for _, tt := range tests {
+		feeder := tt.feeder(NewStreamJSONParser())
+		if _, err := feeder.Write([]byte(tt.input)); !errors.Is(err, ErrBinaryInput) {
+			t.Errorf("%s: expected ErrBinaryInput, got %v", tt.name, err)
+		}
+		if _, err := feeder.Write([]byte{0}); !errors.Is(err, ErrBinaryInput) {
+			t.Errorf("%s: expected the error to stick, got %v", tt.name, err)
+		}
+	}
+}
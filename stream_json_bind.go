@@ -0,0 +1,183 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// structBinding is a target registered with UnmarshalStream
+type structBinding struct {
+	target   reflect.Value // Element the pointer passed to UnmarshalStream points to
+	callback func(fieldPath string)
+}
+
+// UnmarshalStream binds the value pointed to by v to the stream, using the
+// rules of Unmarshal. Each value is stored as soon as it completes, and
+// callback, if not nil, is invoked with its dotted path, such as
+// "items.0.name". Struct fields, slice and array elements are filled one
+// value at a time, while maps and interfaces are filled whole when they
+// complete. Values that do not fit the target are left out without a
+// callback; Unmarshal reports them as errors. Types implementing
+// json.Unmarshaler or encoding.TextUnmarshaler are filled whole when they
+// complete. The callback runs during
+// Append, which is the only time v is written.
+func (p *StreamJSONParser) UnmarshalStream(v interface{}, callback func(fieldPath string)) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	p.bindings = append(p.bindings, structBinding{target: rv.Elem(), callback: callback})
+	return nil
+}
+
+// bindStarted clears the slices that the array starting at path fills,
+// like Unmarshal replaces them
+func (p *StreamJSONParser) bindStarted(path []string, node *Node) {
+	if node.Type != ArrayNode {
+		return
+	}
+	for _, b := range p.bindings {
+		target, _, ok := p.locate(b.target, path)
+		if !ok {
+			continue
+		}
+		target = derefAlloc(target)
+		if target.Kind() == reflect.Slice {
+			target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+		}
+	}
+}
+
+// bindCompleted stores the node completed at path into each bound target
+// and reports it
+func (p *StreamJSONParser) bindCompleted(path []string, node *Node) {
+	for _, b := range p.bindings {
+		target, quoted, ok := p.locate(b.target, path)
+		if !ok || !p.bindNode(node, target, path, quoted) {
+			continue
+		}
+		if b.callback != nil {
+			b.callback(strings.Join(path, "."))
+		}
+	}
+}
+
+// bindNode stores a completed node into target, a field with the ,string
+// option if quoted is set, and reports whether it fits. Objects and arrays
+// decoded into structs, slices and arrays are already filled in by their
+// elements.
+func (p *StreamJSONParser) bindNode(node *Node, target reflect.Value, path []string, quoted bool) bool {
+	if quoted {
+		d := nodeDecoder{parser: p, base: path}
+		d.decodeQuoted(node, target)
+		return d.err == nil
+	}
+
+	if node.Type != ValueNode || node.Value != nil {
+		target = derefAlloc(target)
+	}
+
+	switch kind := target.Kind(); {
+	case isUnmarshaler(target.Type()):
+	case node.Type == ObjectNode && kind == reflect.Struct:
+		return true
+	case node.Type == ArrayNode && (kind == reflect.Slice || kind == reflect.Array):
+		return true
+	}
+
+	d := nodeDecoder{parser: p}
+	d.decode(node, target, path)
+	return d.err == nil
+}
+
+// locate returns the field or element of target that the value at path is
+// stored into, allocating pointers and growing slices on the way, and
+// whether it is a field with the ,string option. It returns false if the
+// path has no place in target, or runs through a map, an interface or an
+// unmarshaler, which are only filled when they complete.
+func (p *StreamJSONParser) locate(target reflect.Value, path []string) (reflect.Value, bool, bool) {
+	quoted := false
+	for _, key := range path {
+		target = derefAlloc(target)
+		if isUnmarshaler(target.Type()) {
+			return reflect.Value{}, false, false
+		}
+
+		switch target.Kind() {
+		case reflect.Struct:
+			field, ok := p.fieldForKey(target.Type(), key)
+			if !ok {
+				return reflect.Value{}, false, false
+			}
+			if target, ok = fieldByIndexAlloc(target, field.index); !ok {
+				return reflect.Value{}, false, false
+			}
+			quoted = field.quoted
+
+		case reflect.Slice:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 {
+				return reflect.Value{}, false, false
+			}
+			if grow := index + 1 - target.Len(); grow > 0 {
+				target.Set(reflect.AppendSlice(target, reflect.MakeSlice(target.Type(), grow, grow)))
+			}
+			target = target.Index(index)
+
+		case reflect.Array:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= target.Len() {
+				return reflect.Value{}, false, false
+			}
+			target = target.Index(index)
+
+		default:
+			return reflect.Value{}, false, false
+		}
+	}
+	return target, quoted, true
+}
+
+// fieldForKey finds the struct field an object key decodes into, preferring
+// an exact match like encoding/json
+func (p *StreamJSONParser) fieldForKey(t reflect.Type, key string) (fieldInfo, bool) {
+	fields := cachedFields(t)
+	for _, field := range fields {
+		if p.normalizeKey(field.name) == key {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(p.normalizeKey(field.name), key) {
+			return field, true
+		}
+	}
+	return fieldInfo{}, false
+}
+
+// derefAlloc follows pointers, allocating nil ones
+func derefAlloc(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
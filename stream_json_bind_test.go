@@ -0,0 +1,188 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type streamReply struct {
+	Title  string            `json:"title"`
+	Score  float64           `json:"score"`
+	Author *streamAuthor     `json:"author"`
+	Tags   []string          `json:"tags"`
+	Meta   map[string]string `json:"meta"`
+	Extra  interface{}       `json:"extra"`
+}
+
+type streamAuthor struct {
+	Name string `json:"name"`
+}
+
+func TestUnmarshalStream(t *testing.T) {
+	input := `{"title": "Streaming", "author": {"name": "Ada"}, "tags": ["a", "b"], ` +
+		`"meta": {"k": "v"}, "extra": {"n": 1}, "unknown": 5, "score": 9.5}`
+
+	var reply streamReply
+	var paths []string
+	parser := NewStreamJSONParser()
+	if err := parser.UnmarshalStream(&reply, func(fieldPath string) {
+		paths = append(paths, fieldPath)
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parser.Append(`{"title": "Stream`)
+	if reply.Title != "" {
+		t.Errorf("Expected a streaming string to be left out, got %q", reply.Title)
+	}
+	parser.Append(input[len(`{"title": "Stream`):])
+
+	want := streamReply{
+		Title:  "Streaming",
+		Score:  9.5,
+		Author: &streamAuthor{Name: "Ada"},
+		Tags:   []string{"a", "b"},
+		Meta:   map[string]string{"k": "v"},
+		Extra:  map[string]interface{}{"n": int64(1)},
+	}
+	if !reflect.DeepEqual(reply, want) {
+		t.Errorf("Unexpected result: %+v", reply)
+	}
+
+	wantPaths := []string{"title", "author.name", "author", "tags.0", "tags.1", "tags", "meta", "extra", "score", ""}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("Unexpected field paths: %q", paths)
+	}
+}
+
+func TestUnmarshalStreamFillsAsValuesComplete(t *testing.T) {
+	var items []streamAuthor
+	parser := NewStreamJSONParser()
+	parser.UnmarshalStream(&items, nil)
+
+	parser.Append(`[{"name": "a"}, {"na`)
+	if len(items) != 1 || items[0].Name != "a" {
+		t.Errorf("Expected only the first element, got %+v", items)
+	}
+
+	parser.Append(`me": "b"}]`)
+	if !reflect.DeepEqual(items, []streamAuthor{{Name: "a"}, {Name: "b"}}) {
+		t.Errorf("Unexpected result: %+v", items)
+	}
+}
+
+func TestUnmarshalStreamReplacesSlices(t *testing.T) {
+	reply := streamReply{Tags: []string{"old", "older", "oldest"}}
+	parser := NewStreamJSONParser()
+	parser.UnmarshalStream(&reply, nil)
+	parser.Append(`{"tags": ["new"]}`)
+
+	if !reflect.DeepEqual(reply.Tags, []string{"new"}) {
+		t.Errorf("Expected the slice to be replaced, got %q", reply.Tags)
+	}
+
+	parser.Reset()
+	empty := streamReply{Tags: []string{"old"}}
+	parser.UnmarshalStream(&empty, nil)
+	parser.Append(`{"tags": []}`)
+	if empty.Tags == nil || len(empty.Tags) != 0 {
+		t.Errorf("Expected an empty slice, got %#v", empty.Tags)
+	}
+}
+
+func TestUnmarshalStreamMismatch(t *testing.T) {
+	var reply streamReply
+	var paths []string
+	parser := NewStreamJSONParser()
+	parser.UnmarshalStream(&reply, func(fieldPath string) {
+		paths = append(paths, fieldPath)
+	})
+	parser.Append(`{"score": "high", "title": "ok"`)
+
+	if reply.Score != 0 || reply.Title != "ok" {
+		t.Errorf("Expected the mismatched field to be left out, got %+v", reply)
+	}
+	if !reflect.DeepEqual(paths, []string{"title"}) {
+		t.Errorf("Expected no callback for the mismatch, got %q", paths)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if err := parser.Unmarshal(&reply); !errors.As(err, &typeErr) || typeErr.Field != "score" {
+		t.Errorf("Expected Unmarshal to report the mismatch, got %v", err)
+	}
+}
+
+func TestUnmarshalStreamInvalidTarget(t *testing.T) {
+	var reply streamReply
+	parser := NewStreamJSONParser()
+	if err := parser.UnmarshalStream(reply, nil); err == nil {
+		t.Errorf("Expected an error for a non-pointer target")
+	}
+}
+
+func TestUnmarshalStreamUnmarshalers(t *testing.T) {
+	var invoice unmarshalInvoice
+	var paths []string
+	parser := NewStreamJSONParser()
+	if err := parser.UnmarshalStream(&invoice, func(fieldPath string) {
+		paths = append(paths, fieldPath)
+	}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parser.Append(`{"raw":{"x":{"a":[1,2]}},"lines":[3.5],"due":"2025-01-31"}`)
+
+	if raw := invoice.Raw["x"]; raw == nil || string(*raw) != `{"a":[1,2]}` {
+		t.Errorf("Expected raw object decoded whole, got %v", invoice.Raw)
+	}
+	if len(invoice.Lines) != 1 || invoice.Lines[0].text != "3.5" {
+		t.Errorf("Expected lines decoded, got %+v", invoice.Lines)
+	}
+	if invoice.Due != (unmarshalDate{4, 2, 2}) {
+		t.Errorf("Expected due date decoded, got %+v", invoice.Due)
+	}
+	want := []string{"raw", "lines.0", "lines", "due", ""}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+}
+
+func TestUnmarshalStreamEmbeddingAndStringOption(t *testing.T) {
+	var self unmarshalSelf
+	var quoted unmarshalQuoted
+	var paths []string
+	parser := NewStreamJSONParser()
+	parser.UnmarshalStream(&self, nil)
+	parser.UnmarshalStream(&quoted, func(fieldPath string) {
+		paths = append(paths, fieldPath)
+	})
+
+	parser.Append(`{"x": 1, "id": "4`)
+	if self.X != 1 || quoted.ID != 0 {
+		t.Errorf("Expected x 1 and no id yet, got %+v and %+v", self, quoted)
+	}
+
+	parser.Append(`2", "active": true}`)
+	if quoted.ID != 42 || quoted.Active {
+		t.Errorf("Expected id 42 and the unquoted active left out, got %+v", quoted)
+	}
+	if !reflect.DeepEqual(paths, []string{"id", ""}) {
+		t.Errorf("Unexpected field paths: %q", paths)
+	}
+}
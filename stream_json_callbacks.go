@@ -0,0 +1,53 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// valueSubscription is a callback registered for value updates at a path
+type valueSubscription struct {
+	pattern  []string
+	callback func(value interface{}, complete bool)
+}
+
+// OnValue registers a callback for the value at a dotted path such as
+// "user.name", where "*" matches any single key or index and "" selects the root.
+// The callback receives the partial content each time a streaming string grows,
+// with complete set to false, and the final value once it completes. Objects and
+// arrays are delivered once, materialized, when they close.
+func (p *StreamJSONParser) OnValue(path string, callback func(value interface{}, complete bool)) {
+	p.valueSubscriptions = append(p.valueSubscriptions, valueSubscription{
+		pattern:  p.normalizePath(splitPath(path)),
+		callback: callback,
+	})
+}
+
+// deliverValue invokes the value subscriptions matching the node at path
+func (p *StreamJSONParser) deliverValue(path []string, node *Node) {
+	if len(p.valueSubscriptions) == 0 {
+		return
+	}
+
+	var value interface{}
+	materialized := false
+	for _, sub := range p.valueSubscriptions {
+		if !matchPath(sub.pattern, path) {
+			continue
+		}
+		if !materialized {
+			value = p.collectNodeValue(node)
+			materialized = true
+		}
+		sub.callback(value, node.Completed)
+	}
+}
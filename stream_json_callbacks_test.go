@@ -0,0 +1,88 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestOnValueStringFragments(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	type update struct {
+		value    interface{}
+		complete bool
+	}
+	var updates []update
+	parser.OnValue("user.name", func(value interface{}, complete bool) {
+		updates = append(updates, update{value, complete})
+	})
+
+	parser.Append(`{"user":{"name":"Al`)
+	parser.Append(`ic`)
+	parser.Append(`e","age":30}}`)
+
+	expected := []update{{"Al", false}, {"Alic", false}, {"Alice", true}}
+	if len(updates) != len(expected) {
+		t.Fatalf("Expected %d updates, got %v", len(expected), updates)
+	}
+	for i := range expected {
+		if updates[i] != expected[i] {
+			t.Errorf("Update %d: expected %v, got %v", i, expected[i], updates[i])
+		}
+	}
+}
+
+func TestOnValueContainersAndWildcards(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	var ids []interface{}
+	parser.OnValue("items.*.id", func(value interface{}, complete bool) {
+		if !complete {
+			t.Errorf("Expected only complete number updates, got %v", value)
+		}
+		ids = append(ids, value)
+	})
+
+	var items interface{}
+	parser.OnValue("items", func(value interface{}, complete bool) {
+		items = value
+	})
+
+	rootCalls := 0
+	parser.OnValue("", func(value interface{}, complete bool) {
+		rootCalls++
+	})
+
+	parser.Append(`{"items":[{"id":1},{"id":`)
+	if items != nil {
+		t.Errorf("Expected items callback only after the array closes, got %v", items)
+	}
+
+	parser.Append(`2}]}`)
+
+	if len(ids) != 2 || ids[0] != int64(1) || ids[1] != int64(2) {
+		t.Errorf("Expected ids [1 2], got %v", ids)
+	}
+
+	itemsSlice, ok := items.([]interface{})
+	if !ok || len(itemsSlice) != 2 {
+		t.Errorf("Expected materialized items array, got %v", items)
+	}
+
+	if rootCalls != 1 {
+		t.Errorf("Expected root callback once, got %d", rootCalls)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// CapturePolicy limits what payload capture keeps, so recordings and event
+// logs can be enabled without retaining sensitive user content. Paths are
+// dotted and may contain * wildcards; a path covers the values at and
+// inside it.
+type CapturePolicy struct {
+	SampleRate float64  // Fraction of streams captured, all of them when 0
+	Strip      []string // Paths whose values are replaced by null
+	Hash       []string // Paths whose values are replaced by "sha256:" and the hex digest
+	Salt       string   // Prefix hashed with each value, so digests cannot be looked up
+}
+
+// captureAction is what a CapturePolicy does with a value
+type captureAction int
+
+const (
+	captureKeep captureAction = iota
+	captureStrip
+	captureHash
+)
+
+// Sample decides whether a stream is captured, at SampleRate
+func (c *CapturePolicy) Sample() bool {
+	if c.SampleRate <= 0 || c.SampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < c.SampleRate
+}
+
+// FilterEvent applies the policy to an event for an event log. It reports
+// false for events to leave out: those at or inside stripped paths and the
+// StringDelta events of hashed strings. Completed values at hashed paths
+// are replaced by their digest.
+func (c *CapturePolicy) FilterEvent(event Event) (Event, bool) {
+	switch c.actionAt(event.Path) {
+	case captureStrip:
+		return event, false
+	case captureHash:
+		switch event.Type {
+		case StringDelta:
+			return event, false
+		case ValueCompleted:
+			if text, ok := captureText(event.Value); ok {
+				event.Value = c.hash(text)
+			}
+		}
+	}
+	return event, true
+}
+
+// actionAt returns what the policy does with the value at path. Stripping
+// wins over hashing.
+func (c *CapturePolicy) actionAt(path []string) captureAction {
+	if c.covers(c.Strip, path) {
+		return captureStrip
+	}
+	if c.covers(c.Hash, path) {
+		return captureHash
+	}
+	return captureKeep
+}
+
+// covers reports whether path is at or inside one of the patterns
+func (c *CapturePolicy) covers(patterns []string, path []string) bool {
+	for _, pattern := range patterns {
+		segments := splitPath(pattern)
+		if len(path) >= len(segments) && matchPath(segments, path[:len(segments)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// filtersPaths reports whether the policy rewrites any values
+func (c *CapturePolicy) filtersPaths() bool {
+	return len(c.Strip) > 0 || len(c.Hash) > 0
+}
+
+// hash returns the digest stored in place of a value with the given text
+func (c *CapturePolicy) hash(text string) string {
+	sum := sha256.Sum256([]byte(c.Salt + text))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// captureText returns the text hashed for a scalar value: strings as they
+// are, and numbers and bools as MarshalJSON writes them, so a value hashes
+// the same in recordings and event logs. It reports false for null.
+func captureText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	if n, ok := numberToInt64(value); ok {
+		return strconv.FormatInt(n, 10), true
+	}
+	if f, ok := numberToFloat64(value); ok {
+		return strconv.FormatFloat(f, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// captureFrame is an open container in the input of a captureFilter
+type captureFrame struct {
+	object bool
+	key    string // Last key read, in an object
+	index  int    // Number of elements started, in an array
+}
+
+// captureFilter rewrites chunks of JSON input as a CapturePolicy requests.
+// Tokens are written once complete, so a value split across chunks is
+// written with the chunk that completes it.
+type captureFilter struct {
+	policy   *CapturePolicy
+	scanner  *Scanner
+	frames   []captureFrame
+	skipping int // Depth of the stripped container being left out, or 0
+}
+
+// newCaptureFilter creates a filter applying policy
+func newCaptureFilter(policy *CapturePolicy) *captureFilter {
+	scanner := NewScanner()
+	scanner.tokenizer.SetPassthrough(true)
+	return &captureFilter{policy: policy, scanner: scanner}
+}
+
+// write filters content and returns the input it completes
+func (f *captureFilter) write(content string) string {
+	f.scanner.Append(content)
+	return f.drain()
+}
+
+// close flushes the pending token at the end of the input
+func (f *captureFilter) close() string {
+	f.scanner.Close()
+	return f.drain()
+}
+
+// drain filters the complete tokens buffered in the scanner
+func (f *captureFilter) drain() string {
+	var out strings.Builder
+	for {
+		pending := Invalid
+		if token := f.scanner.tokenizer.lastToken; token != nil {
+			pending = token.TokenType
+		}
+		tokenType, raw, err := f.scanner.Next()
+		if raw == nil && err != nil {
+			return out.String()
+		}
+		if err == io.ErrUnexpectedEOF {
+			tokenType = pending // A token cut off by the end of the input
+		}
+		f.token(&out, tokenType, string(raw))
+	}
+}
+
+// token writes the filtered form of a token
+func (f *captureFilter) token(out *strings.Builder, tokenType TokenType, raw string) {
+	if f.skipping > 0 {
+		switch tokenType {
+		case ObjectStart, ArrayStart:
+			f.skipping++
+		case ObjectEnd, ArrayEnd:
+			f.skipping--
+		}
+		return
+	}
+
+	switch tokenType {
+	case ObjectKey:
+		if len(f.frames) > 0 {
+			f.frames[len(f.frames)-1].key = unquoteCapture(raw)
+		}
+	case ObjectEnd, ArrayEnd:
+		if len(f.frames) > 0 {
+			f.frames = f.frames[:len(f.frames)-1]
+		}
+	case ObjectStart, ArrayStart, String, Number, Bool, Null:
+		raw = f.value(tokenType, raw)
+	}
+	out.WriteString(raw)
+}
+
+// value returns what to write for a token starting a value, and tracks
+// the containers it opens
+func (f *captureFilter) value(tokenType TokenType, raw string) string {
+	path := f.path()
+	if n := len(f.frames); n > 0 && !f.frames[n-1].object {
+		f.frames[n-1].index++
+	}
+
+	switch f.policy.actionAt(path) {
+	case captureStrip:
+		if tokenType == ObjectStart || tokenType == ArrayStart {
+			f.skipping = 1
+		}
+		return "null"
+	case captureHash:
+		switch tokenType {
+		case String:
+			return strconv.Quote(f.policy.hash(unquoteCapture(raw)))
+		case Number:
+			if value, ok := parseCaptureNumber(raw); ok {
+				raw, _ = captureText(value)
+			}
+			return strconv.Quote(f.policy.hash(raw))
+		case Bool:
+			return strconv.Quote(f.policy.hash(raw))
+		}
+	}
+	if tokenType == ObjectStart || tokenType == ArrayStart {
+		f.frames = append(f.frames, captureFrame{object: tokenType == ObjectStart})
+	}
+	return raw
+}
+
+// path returns the path of the value starting at the current position
+func (f *captureFilter) path() []string {
+	path := make([]string, len(f.frames))
+	for i, frame := range f.frames {
+		if frame.object {
+			path[i] = frame.key
+		} else {
+			path[i] = strconv.Itoa(frame.index)
+		}
+	}
+	return path
+}
+
+// unquoteCapture decodes a quoted string token, which may be unterminated
+// at the end of the input
+func unquoteCapture(raw string) string {
+	if len(raw) == 0 {
+		return raw
+	}
+	inner := raw[1:]
+	if n := len(inner); n > 0 && inner[n-1] == raw[0] {
+		inner = inner[:n-1]
+	} else {
+		return decodeString(inner, true)
+	}
+	return decodeString(inner, false)
+}
+
+// parseCaptureNumber parses a number token into the value the parser
+// would store for it
+func parseCaptureNumber(raw string) (interface{}, bool) {
+	if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCapturePolicyRecording(t *testing.T) {
+	policy := &CapturePolicy{
+		Strip: []string{"messages.*.content"},
+		Hash:  []string{"user"},
+		Salt:  "s",
+	}
+	chunks := []string{`{"user":"al`, `ice","messages":[{"role":"user","content":{"text":"se`, `cret","n":[1,2]}},`, `{"role":"bot","content":"hi"}],"n":1`, `2}`}
+
+	parser := NewStreamJSONParser()
+	recorder := NewRecorder(parser)
+	recorder.SetCapturePolicy(policy)
+	for _, chunk := range chunks {
+		recorder.Append(chunk)
+	}
+	recorder.Finish()
+
+	// The parser still sees the original input
+	if name, _ := parser.GetString("user"); name != "alice" {
+		t.Fatalf("parser user = %q", name)
+	}
+
+	var recorded strings.Builder
+	for _, chunk := range recorder.Recording().Chunks {
+		recorded.WriteString(chunk.Data)
+	}
+	want := `{"user":"` + policy.hash("alice") + `","messages":[{"role":"user","content":null},{"role":"bot","content":null}],"n":12}`
+	if recorded.String() != want {
+		t.Fatalf("recorded = %s\nwant       %s", recorded.String(), want)
+	}
+
+	replayed := NewStreamJSONParser()
+	recorder.Recording().Replay(replayed)
+	if !replayed.IsCompleted() {
+		t.Fatal("replayed recording did not complete")
+	}
+	if n, _ := replayed.GetInt("n"); n != 12 {
+		t.Fatalf("replayed n = %d", n)
+	}
+}
+
+func TestCapturePolicyHashesScalars(t *testing.T) {
+	policy := &CapturePolicy{Hash: []string{"*"}}
+	recorder := NewRecorder(NewStreamJSONParser())
+	recorder.SetCapturePolicy(policy)
+	recorder.Append(`{"a":1.50,"b":true,"c":null,"d":"xA"}`)
+
+	want := `{"a":"` + policy.hash("1.5") + `","b":"` + policy.hash("true") + `","c":null,"d":"` + policy.hash("xA") + `"}`
+	if got := recorder.Recording().Chunks[0].Data; got != want {
+		t.Fatalf("recorded = %s, want %s", got, want)
+	}
+}
+
+func TestCapturePolicyUnterminatedValue(t *testing.T) {
+	recorder := NewRecorder(NewStreamJSONParser())
+	recorder.SetCapturePolicy(&CapturePolicy{Strip: []string{"secret"}})
+	recorder.Append(`{"secret":"abc`)
+	recorder.Finish()
+
+	recording := recorder.Recording()
+	var recorded strings.Builder
+	for _, chunk := range recording.Chunks {
+		recorded.WriteString(chunk.Data)
+	}
+	if recorded.String() != `{"secret":null` || !recording.Finished {
+		t.Fatalf("recording = %+v", recording)
+	}
+}
+
+func TestCapturePolicySampling(t *testing.T) {
+	recorder := NewRecorder(NewStreamJSONParser())
+	recorder.SetCapturePolicy(&CapturePolicy{SampleRate: 1e-300})
+	recorder.Append(`{"a":1}`)
+	recorder.Finish()
+	if recording := recorder.Recording(); recording != nil {
+		t.Fatalf("unsampled stream recorded %+v", recording)
+	}
+
+	recorder = NewRecorder(NewStreamJSONParser())
+	recorder.SetCapturePolicy(&CapturePolicy{SampleRate: 1})
+	recorder.Append(`{"a":1}`)
+	if recording := recorder.Recording(); recording == nil || recording.Chunks[0].Data != `{"a":1}` {
+		t.Fatalf("sampled stream recorded %+v", recording)
+	}
+}
+
+func TestCapturePolicyFilterEvent(t *testing.T) {
+	policy := &CapturePolicy{Strip: []string{"token"}, Hash: []string{"user"}}
+	parser := NewStreamJSONParser(WithBlockingEvents())
+	events := parser.Events()
+	parser.Append(`{"user":"alice","token":{"v":"x"},"n":2}`)
+
+	var kept []Event
+	for event := range events {
+		if event, ok := policy.FilterEvent(event); ok {
+			kept = append(kept, event)
+		}
+	}
+	for _, event := range kept {
+		if len(event.Path) > 0 && event.Path[0] == "token" {
+			t.Errorf("stripped event kept: %+v", event)
+		}
+		if event.Type == StringDelta && len(event.Path) > 0 && event.Path[0] == "user" {
+			t.Errorf("hashed string delta kept: %+v", event)
+		}
+		if event.Type == ValueCompleted && event.Path[0] == "user" && event.Value != policy.hash("alice") {
+			t.Errorf("user = %v, want its hash", event.Value)
+		}
+		if event.Type == ValueCompleted && event.Path[0] == "n" && event.Value != int64(2) {
+			t.Errorf("n = %v", event.Value)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// NewCBORFeeder creates a feeder that decodes a stream of CBOR (RFC 8949)
+// data items and appends their JSON equivalent to target. Consecutive items
+// become consecutive root documents.
+func NewCBORFeeder(target Appender) *BinaryFeeder {
+	return newBinaryFeeder(target, decodeCBOR)
+}
+
+// decodeCBOR decodes the header of a CBOR data item
+func decodeCBOR(data []byte) (binaryItem, int, error) {
+	if len(data) == 0 {
+		return binaryItem{}, 0, nil
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+
+	if info == 31 {
+		switch major {
+		case 2:
+			return binaryItem{kind: binaryBytes, length: -1}, 1, nil
+		case 3:
+			return binaryItem{kind: binaryText, length: -1}, 1, nil
+		case 4:
+			return binaryItem{kind: binaryArray, length: -1}, 1, nil
+		case 5:
+			return binaryItem{kind: binaryMap, length: -1}, 1, nil
+		case 7:
+			return binaryItem{kind: binaryBreak}, 1, nil
+		}
+		return binaryItem{}, 0, binaryError("invalid indefinite-length CBOR item 0x%02x", data[0])
+	}
+
+	var arg uint64
+	n := 1
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(data) < 1+size {
+			return binaryItem{}, 0, nil
+		}
+		arg = readUint(data[1 : 1+size])
+		n += size
+	default:
+		return binaryItem{}, 0, binaryError("reserved CBOR additional information %d", info)
+	}
+
+	switch major {
+	case 0:
+		return binaryItem{kind: binaryScalar, value: unsignedValue(arg)}, n, nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return binaryItem{kind: binaryScalar, value: -1 - float64(arg)}, n, nil
+		}
+		return binaryItem{kind: binaryScalar, value: -1 - int64(arg)}, n, nil
+	case 2, 3, 4, 5:
+		if arg > math.MaxInt32 {
+			return binaryItem{}, 0, binaryError("CBOR length %d is too large", arg)
+		}
+		kinds := [...]binaryKind{binaryBytes, binaryText, binaryArray, binaryMap}
+		return binaryItem{kind: kinds[major-2], length: int(arg)}, n, nil
+	case 6:
+		return binaryItem{kind: binaryTag}, n, nil
+	}
+
+	// Major type 7: simple values and floats
+	switch info {
+	case 20:
+		return binaryItem{kind: binaryScalar, value: false}, n, nil
+	case 21:
+		return binaryItem{kind: binaryScalar, value: true}, n, nil
+	case 25:
+		return binaryItem{kind: binaryScalar, value: float16(uint16(arg))}, n, nil
+	case 26:
+		return binaryItem{kind: binaryScalar, value: float64(math.Float32frombits(uint32(arg)))}, n, nil
+	case 27:
+		return binaryItem{kind: binaryScalar, value: math.Float64frombits(arg)}, n, nil
+	}
+	return binaryItem{kind: binaryScalar, value: nil}, n, nil // null, undefined and unassigned simple values
+}
+
+// readUint reads a big-endian unsigned integer of 1, 2, 4 or 8 bytes
+func readUint(data []byte) uint64 {
+	switch len(data) {
+	case 1:
+		return uint64(data[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(data))
+	case 4:
+		return uint64(binary.BigEndian.Uint32(data))
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// unsignedValue returns an unsigned integer as int64 when it fits
+func unsignedValue(v uint64) interface{} {
+	if v > math.MaxInt64 {
+		return v
+	}
+	return int64(v)
+}
+
+// float16 converts an IEEE 754 half-precision float
+func float16(bits uint16) float64 {
+	exp := int(bits>>10) & 0x1f
+	mant := float64(bits & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if bits&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrCheckpointInvalid is returned by Restore for a checkpoint taken before
+// a Reset, released, or that cannot be returned to
+var ErrCheckpointInvalid = errors.New("streamjson: checkpoint no longer valid")
+
+// TokenizerCheckpoint is the state of a tokenizer saved by Checkpoint
+type TokenizerCheckpoint struct {
+	saved StreamJSONTokenizer // Copy of the tokenizer without its buffer
+	end   int                 // Input offset of the end of the buffer
+}
+
+// Checkpoint saves the state of the tokenizer, so tokens can be read ahead
+// speculatively and read again after Restore
+func (t *StreamJSONTokenizer) Checkpoint() *TokenizerCheckpoint {
+	cp := &TokenizerCheckpoint{saved: *t, end: t.base + len(t.buffer)}
+	cp.saved.buffer = nil
+	cp.saved.containers = slices.Clone(t.containers)
+	if t.lastToken != nil {
+		cp.saved.lastToken = savedToken(*t.lastToken)
+	}
+	return cp
+}
+
+// Restore returns the tokenizer to the state saved in cp: the tokens read
+// since are read again and the input appended since is dropped. It returns
+// ErrCheckpointInvalid after a Reset, or if the input cp needs has been
+// released by compaction.
+func (t *StreamJSONTokenizer) Restore(cp *TokenizerCheckpoint) error {
+	saved := &cp.saved
+	if saved.resets != t.resets || cp.retained() < t.base || cp.end > t.base+len(t.buffer) {
+		return ErrCheckpointInvalid
+	}
+
+	// Offsets in cp count from its base, which compaction may have moved
+	shift := saved.base - t.base
+	buffer, base := t.buffer[:cp.end-t.base], t.base
+	*t = *saved
+	t.buffer, t.base = buffer, base
+	t.position += shift
+	t.containers = slices.Clone(saved.containers)
+	if saved.lastToken != nil {
+		t.lastToken = savedToken(*saved.lastToken)
+		t.lastToken.TokenStart += shift
+		t.lastToken.TokenEnd += shift
+	}
+	return nil
+}
+
+// retained returns the input offset of the first byte Restore needs, for
+// producing tokens or counting lines
+func (cp *TokenizerCheckpoint) retained() int {
+	return min(cp.saved.counted, cp.saved.base+cp.saved.consumed())
+}
+
+// ParserCheckpoint is the state of a parser saved by Checkpoint
+type ParserCheckpoint struct {
+	saved     StreamJSONParser // Copy of the parser with its own tree
+	tokenizer *TokenizerCheckpoint
+}
+
+// Checkpoint saves the state of the parser, so input can be appended
+// speculatively, for example to test whether it is a code fence or JSON,
+// and rolled back with Restore. The checkpoint copies the tree and keeps
+// the input from its position on in memory until it is released with
+// Release or Reset.
+func (p *StreamJSONParser) Checkpoint() *ParserCheckpoint {
+	defer p.guardWrite("Checkpoint")()
+	cp := &ParserCheckpoint{saved: *p, tokenizer: p.tokenizer.Checkpoint()}
+	cp.saved.root, cp.saved.stack = cloneTree(p.root, p.stack)
+	if p.fence != nil {
+		fence := *p.fence
+		cp.saved.fence = &fence
+	}
+	p.checkpoints = append(p.checkpoints, cp)
+	return cp
+}
+
+// Restore returns the parser to the state saved in cp, as if the input
+// appended since had not been. Callbacks, events, watches and bindings
+// that input triggered are not taken back, and registrations made since
+// are kept. The checkpoint stays valid, so it can be restored again.
+//
+// It returns ErrCheckpointInvalid for a released checkpoint, one taken
+// before Reset, or one taken before Finish or, once Events has been called,
+// before the root completed.
+func (p *StreamJSONParser) Restore(cp *ParserCheckpoint) error {
+	defer p.guardWrite("Restore")()
+	if !slices.Contains(p.checkpoints, cp) || p.closed() && !cp.saved.closed() {
+		return ErrCheckpointInvalid
+	}
+	if err := p.tokenizer.Restore(cp.tokenizer); err != nil {
+		return err
+	}
+
+	releaseTree(p.root)
+	added := min(p.documentCount-cp.saved.documentCount, len(p.documents))
+	for _, root := range p.documents[len(p.documents)-added:] {
+		releaseTree(root)
+	}
+	for _, frame := range p.stack {
+		releaseStackFrame(frame)
+	}
+
+	current := *p
+	*p = cp.saved
+	p.root, p.stack = cloneTree(cp.saved.root, cp.saved.stack)
+	if cp.saved.fence != nil {
+		fence := *cp.saved.fence
+		p.fence = &fence
+	}
+
+	// Registrations belong to the caller, not to the parse state
+	p.tokenizer = current.tokenizer
+	p.rawSubscriptions = current.rawSubscriptions
+	p.valueSubscriptions = current.valueSubscriptions
+	p.watches = current.watches
+	p.arrayStreams = current.arrayStreams
+	p.elementSubscriptions = current.elementSubscriptions
+	p.transforms = current.transforms
+	p.globalTransforms = current.globalTransforms
+	p.events = current.events
+	p.eventSink = current.eventSink
+	p.documentCallbacks = current.documentCallbacks
+	p.documentStarts = current.documentStarts
+	p.bindings = current.bindings
+	p.tees = current.tees
+	p.spillFiles = current.spillFiles
+	p.checkpoints = current.checkpoints
+	return nil
+}
+
+// Release drops a checkpoint, letting compaction free the input it kept
+func (p *StreamJSONParser) Release(cp *ParserCheckpoint) {
+	defer p.guardWrite("Release")()
+	if i := slices.Index(p.checkpoints, cp); i >= 0 {
+		p.checkpoints = slices.Delete(p.checkpoints, i, i+1)
+		cp.release()
+	}
+}
+
+// release returns the saved copy of the tree and its stack frames to the
+// pools. Completed documents are shared with the parser and stay.
+func (cp *ParserCheckpoint) release() {
+	releaseTree(cp.saved.root)
+	for _, frame := range cp.saved.stack {
+		releaseStackFrame(frame)
+	}
+	cp.saved.root, cp.saved.stack = nil, nil
+}
+
+// closed reports whether the parser has finished, or closed the event
+// channel for a completed root, which Restore cannot undo
+func (p *StreamJSONParser) closed() bool {
+	return p.finished || p.events != nil && p.IsCompleted() && p.closesAtRoot()
+}
+
+// checkpointRetained returns the input offset of the first byte a live
+// checkpoint needs, or -1 if there is none
+func (p *StreamJSONParser) checkpointRetained() int {
+	retained := -1
+	for _, cp := range p.checkpoints {
+		if offset := cp.tokenizer.retained(); retained < 0 || offset < retained {
+			retained = offset
+		}
+	}
+	return retained
+}
+
+// cloneTree copies a tree and the stack frames pointing into it
+func cloneTree(root *Node, stack []*StackFrame) (*Node, []*StackFrame) {
+	clones := make(map[*Node]*Node)
+	rootClone := cloneNode(root, nil, clones)
+
+	stackClone := make([]*StackFrame, len(stack), cap(stack))
+	for i, frame := range stack {
+		frameClone := newStackFrame()
+		*frameClone = *frame
+		frameClone.Node = clones[frame.Node]
+		frameClone.Path = slices.Clone(frame.Path)
+		if frame.Shadowed != nil {
+			frameClone.Shadowed = cloneNode(frame.Shadowed, frameClone.Node, clones)
+		}
+		stackClone[i] = frameClone
+	}
+	return rootClone, stackClone
+}
+
+// cloneNode copies node and its descendants under parent, recording the
+// copies of open containers, which stack frames point to
+func cloneNode(node, parent *Node, clones map[*Node]*Node) *Node {
+	type cloneTask struct{ node, clone *Node }
+
+	root := cloneOne(node, parent, clones)
+	stack := []cloneTask{{node, root}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch node, clone := task.node, task.clone; {
+		case node == nil:
+		case node.Type == ObjectNode:
+			for key, child := range node.Children {
+				clone.Children[key] = cloneOne(child, clone, clones)
+				stack = append(stack, cloneTask{child, clone.Children[key]})
+			}
+		case node.Type == ArrayNode:
+			for _, child := range node.Array {
+				clone.Array = append(clone.Array, cloneOne(child, clone, clones))
+				stack = append(stack, cloneTask{child, clone.Array[len(clone.Array)-1]})
+			}
+		}
+	}
+	return root
+}
+
+// cloneOne copies node under parent without its children
+func cloneOne(node, parent *Node, clones map[*Node]*Node) *Node {
+	if node == nil {
+		return nil
+	}
+
+	clone := NewNode(node.Type)
+	children, array := clone.Children, clone.Array
+	*clone = *node
+	clone.Parent = parent
+	clone.Children, clone.Array = children, array
+	if node.times != nil {
+		times := *node.times
+		clone.times = &times
+	}
+	if node.Type != ValueNode && !node.Completed {
+		clones[node] = clone
+	}
+	return clone
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenizerCheckpoint(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append("{\"a\":\n[1, \"tw")
+	tokenizer.NextToken() // {
+
+	cp := tokenizer.Checkpoint()
+	var ahead []Token
+	for token := tokenizer.NextToken(); token.TokenType != EOF && token.Completed; token = tokenizer.NextToken() {
+		ahead = append(ahead, token)
+	}
+	tokenizer.Append(`o"]}`)
+
+	if err := tokenizer.Restore(cp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var again []Token
+	for token := tokenizer.NextToken(); token.TokenType != EOF && token.Completed; token = tokenizer.NextToken() {
+		again = append(again, token)
+	}
+	if !reflect.DeepEqual(ahead, again) {
+		t.Errorf("Expected the same tokens after Restore, and the appended input dropped:\nahead %v\nagain %v", ahead, again)
+	}
+	if again[3].Line != 2 || again[3].Column != 2 {
+		t.Errorf("Expected the number at line 2, column 2, got %d:%d", again[3].Line, again[3].Column)
+	}
+
+	tokenizer.Reset()
+	if err := tokenizer.Restore(cp); !errors.Is(err, ErrCheckpointInvalid) {
+		t.Errorf("Expected ErrCheckpointInvalid after Reset, got %v", err)
+	}
+}
+
+func TestStreamJSONParserCheckpoint(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": {"x": 1}, "msg": "hel`)
+
+	cp := parser.Checkpoint()
+	parser.Append(`lo", "b": [1, 2`)
+	if parser.Get("b", "0") != int64(1) {
+		t.Fatalf("Expected the speculative input to be parsed, got %v", parser.Get())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := parser.Restore(cp); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if parser.Get("b") != nil || parser.Get("msg") != "hel" {
+			t.Errorf("Expected the state at the checkpoint, got %v", parser.Get())
+		}
+		parser.Append(`p", "c": true`)
+	}
+	parser.Append(`}`)
+	parser.Release(cp)
+
+	want := map[string]interface{}{"a": map[string]interface{}{"x": int64(1)}, "msg": "help", "c": true}
+	if !parser.IsCompleted() || !reflect.DeepEqual(parser.Get(), want) {
+		t.Errorf("Expected %v, got %v", want, parser.Get())
+	}
+	if err := parser.Restore(cp); !errors.Is(err, ErrCheckpointInvalid) {
+		t.Errorf("Expected ErrCheckpointInvalid after Release, got %v", err)
+	}
+}
+
+func TestStreamJSONParserCheckpointKeepsInput(t *testing.T) {
+	padding := strings.Repeat("x", 3*compactMinBytes)
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": "` + padding + `", `)
+
+	cp := parser.Checkpoint()
+	parser.Append(`"b": "` + padding + `", "c": "` + padding + `", `)
+	if parser.tokenizer.base == 0 {
+		t.Fatal("Expected the input before the checkpoint to be compacted")
+	}
+	if err := parser.Restore(cp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	parser.Release(cp)
+	parser.Append(`"d": "` + padding + `", "e": 1}`)
+	if parser.Get("b") != nil || parser.Get("e") != int64(1) || !parser.IsCompleted() {
+		t.Errorf("Expected the input after the checkpoint to be replaced, got %v", parser.Stats())
+	}
+
+	// Released checkpoints no longer keep input
+	if retained := cp.tokenizer.retained(); parser.tokenizer.base <= retained {
+		t.Errorf("Expected input before %d to be compacted once the checkpoint was released, base %d", retained, parser.tokenizer.base)
+	}
+}
+
+func TestStreamJSONParserCheckpointInvalid(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": 1`)
+	cp := parser.Checkpoint()
+	parser.Finish()
+	if err := parser.Restore(cp); !errors.Is(err, ErrCheckpointInvalid) {
+		t.Errorf("Expected ErrCheckpointInvalid after Finish, got %v", err)
+	}
+
+	parser.Reset()
+	parser.Append(`{"a": 1`)
+	if err := parser.Restore(cp); !errors.Is(err, ErrCheckpointInvalid) {
+		t.Errorf("Expected ErrCheckpointInvalid after Reset, got %v", err)
+	}
+
+	events := NewStreamJSONParser()
+	events.Events()
+	events.Append(`{"a": 1`)
+	cp = events.Checkpoint()
+	events.Append(`}`)
+	if err := events.Restore(cp); !errors.Is(err, ErrCheckpointInvalid) {
+		t.Errorf("Expected ErrCheckpointInvalid once the event channel closed, got %v", err)
+	}
+}
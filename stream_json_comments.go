@@ -0,0 +1,74 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+)
+
+// blockCommentEnd terminates a /* */ comment
+var blockCommentEnd = []byte("*/")
+
+// skipComment skips a comment at the current position, or the rest of one
+// that started in an earlier chunk. It returns whether the position advanced.
+func (t *StreamJSONTokenizer) skipComment() bool {
+	start := t.position
+	if t.comment == 0 {
+		if t.buffer[t.position] != '/' || t.position+1 >= len(t.buffer) {
+			return false
+		}
+		kind := t.buffer[t.position+1]
+		if kind != '/' && kind != '*' {
+			return false
+		}
+		t.comment = kind
+		t.position += 2
+	}
+
+	if t.comment == '/' {
+		end := bytes.IndexByte(t.buffer[t.position:], '\n')
+		if end < 0 {
+			t.position = len(t.buffer)
+			return true
+		}
+		t.position += end + 1
+		t.comment = 0
+		return true
+	}
+
+	body := t.position
+	end := bytes.Index(t.buffer[body:], blockCommentEnd)
+	if end < 0 {
+		// A trailing '*' may be the start of the terminator, unless it is
+		// the one of the opening /*
+		t.position = len(t.buffer)
+		if t.buffer[t.position-1] == '*' && t.position-1 >= body {
+			t.position--
+		}
+		return t.position > start
+	}
+	t.position += end + len(blockCommentEnd)
+	t.comment = 0
+	return true
+}
+
+// awaitingComment reports whether the input stops inside a comment, or at a
+// '/' that may start one, so no token can be produced yet
+func (t *StreamJSONTokenizer) awaitingComment() bool {
+	if !t.comments {
+		return false
+	}
+	return t.comment != 0 || (t.position == len(t.buffer)-1 && t.buffer[t.position] == '/')
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserComments(t *testing.T) {
+	input := `// generated config
+{
+	"name": "app", // the name
+	/* block
+	   comment */ "port": 8080,
+	"url": "http://example.com/*not a comment*/",
+	"tags": ["a" /* inline */, "b"]
+}`
+
+	parser := NewStreamJSONParser(WithComments(), WithStrictMode())
+	parser.Append(input)
+
+	if parser.Err() != nil {
+		t.Fatalf("Unexpected error: %v", parser.Err())
+	}
+	if !parser.IsCompleted() {
+		t.Fatal("Expected parsing to complete")
+	}
+	if parser.Get("name") != "app" || parser.Get("port") != int64(8080) {
+		t.Errorf("Unexpected values %v", parser.Get())
+	}
+	if parser.Get("url") != "http://example.com/*not a comment*/" {
+		t.Errorf("Expected comment markers in strings to be kept, got %v", parser.Get("url"))
+	}
+	if parser.Get("tags", "1") != "b" {
+		t.Errorf("Expected second tag, got %v", parser.Get("tags"))
+	}
+}
+
+func TestStreamJSONParserCommentsSplit(t *testing.T) {
+	input := `{"a": 1, /* note */ "b": 2 // trailing
+, "c": /**/ 3}`
+
+	// Every split point, including inside the comment markers
+	for i := 1; i < len(input); i++ {
+		parser := NewStreamJSONParser(WithComments(), WithStrictMode())
+		parser.Append(input[:i])
+		parser.Append(input[i:])
+
+		if parser.Err() != nil || !parser.IsCompleted() {
+			t.Fatalf("Split at %d: err %v, completed %v", i, parser.Err(), parser.IsCompleted())
+		}
+		if parser.Get("a") != int64(1) || parser.Get("b") != int64(2) || parser.Get("c") != int64(3) {
+			t.Errorf("Split at %d: unexpected values %v", i, parser.Get())
+		}
+	}
+}
+
+func TestStreamJSONParserCommentsDisabled(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+	parser.Append(`{"a": 1 // comment
+}`)
+
+	if parser.Err() == nil {
+		t.Error("Expected comments to be rejected without WithComments")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+)
+
+// compactMinBytes is the amount of consumed input worth compacting on its own
+const compactMinBytes = 4096
+
+// ErrBufferLimit is returned by Err when the input the parser must retain
+// exceeds the size set with WithMaxBufferSize
+var ErrBufferLimit = errors.New("streamjson: buffer size limit exceeded")
+
+// compact releases consumed input so long streams do not hold their whole
+// raw text. Bytes are dropped once the consumed prefix is at least as large
+// as the rest of the buffer, keeping the copying amortized, or whenever the
+// buffer is over the configured limit.
+func (p *StreamJSONParser) compact() {
+	t := p.tokenizer
+	keep := t.consumed()
+
+	// Raw subtree callbacks need the source bytes of open matching containers
+	if start := p.rawRetained(); start >= 0 && start-t.base < keep {
+		keep = start - t.base
+	}
+
+	// GetRaw needs the whole current document when input is retained
+	if p.options.retainInput && p.started && p.root.start-t.base < keep {
+		keep = max(p.root.start-t.base, 0)
+	}
+
+	// Checkpoints need the input from their position on
+	if start := p.checkpointRetained(); start >= 0 && start-t.base < keep {
+		keep = start - t.base
+	}
+
+	// Captured leading text needs the bytes skipped since the last token
+	if p.options.captureLeadingText && p.beforeRoot() && p.leadingEnd-t.base < keep {
+		keep = p.leadingEnd - t.base
+	}
+
+	limit := p.options.maxBufferSize
+	over := limit > 0 && len(t.buffer) > limit
+	if over || (keep >= compactMinBytes && keep >= len(t.buffer)-keep) {
+		t.compact(keep)
+	}
+
+	if limit > 0 && len(t.buffer) > limit && p.err == nil {
+		p.err = ErrBufferLimit
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompactionBoundsBuffer(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments())
+
+	count := 0
+	parser.OnDocument(func(index int, document interface{}) {
+		count++
+	})
+
+	for i := 0; i < 5000; i++ {
+		parser.Append(fmt.Sprintf(`{"id":%d,"text":"%s"}`+"\n", i, strings.Repeat("x", 100)))
+	}
+
+	if count != 5000 {
+		t.Errorf("Expected 5000 documents, got %d", count)
+	}
+	if size := len(parser.tokenizer.buffer); size > 2*compactMinBytes {
+		t.Errorf("Expected buffer to stay bounded, got %d bytes", size)
+	}
+}
+
+func TestCompactionKeepsPartialTokens(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxBufferSize(64))
+
+	parser.Append(`{"items":[`)
+	for i := 0; i < 200; i++ {
+		parser.Append(fmt.Sprintf(`{"n":%d},`, i))
+	}
+	parser.Append(`{"text":"hel`)
+	parser.Append(`lo"},12`)
+	parser.Append(`34]}`)
+
+	if parser.Err() != nil {
+		t.Fatalf("Unexpected error: %v", parser.Err())
+	}
+	if v := parser.Get("items", "199", "n"); v != int64(199) {
+		t.Errorf("Expected 199, got %v", v)
+	}
+	if v := parser.Get("items", "200", "text"); v != "hello" {
+		t.Errorf("Expected hello, got %v", v)
+	}
+	if v := parser.Get("items", "201"); v != int64(1234) {
+		t.Errorf("Expected number split across compaction, got %v", v)
+	}
+	if len(parser.tokenizer.buffer) > 64 {
+		t.Errorf("Expected at most 64 retained bytes, got %d", len(parser.tokenizer.buffer))
+	}
+}
+
+func TestCompactionRawSubtree(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxBufferSize(16))
+
+	var raw string
+	parser.OnRawSubtree("watched", func(b []byte) {
+		raw = string(b)
+	})
+
+	parser.Append(`{"skipped":[`)
+	for i := 0; i < 50; i++ {
+		parser.Append(`1,`)
+	}
+	parser.Append(`2],"watched":{"a":[1,`)
+	parser.Append(`2]}}`)
+
+	if raw != `{"a":[1,2]}` {
+		t.Errorf("Expected watched subtree bytes, got %q", raw)
+	}
+}
+
+func TestCompactionErrorPosition(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode(), WithMaxBufferSize(8))
+
+	parser.Append("[\n")
+	for i := 0; i < 100; i++ {
+		parser.Append("  1,\n")
+	}
+	parser.Append("  ?]")
+
+	errs := parser.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %d", len(errs))
+	}
+	if errs[0].Offset != 504 || errs[0].Line != 102 || errs[0].Column != 3 {
+		t.Errorf("Expected error at offset 504, 102:3, got %d, %d:%d", errs[0].Offset, errs[0].Line, errs[0].Column)
+	}
+}
+
+func TestMaxBufferSizeExceeded(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxBufferSize(32))
+
+	parser.Append(`{"text":"`)
+	parser.Append(strings.Repeat("x", 40))
+
+	if parser.Err() != ErrBufferLimit {
+		t.Errorf("Expected ErrBufferLimit, got %v", parser.Err())
+	}
+}
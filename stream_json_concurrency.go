@@ -0,0 +1,92 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// ErrConcurrentUse is the error WithConcurrencyCheck panics with when a
+// parser is used from two goroutines at once
+var ErrConcurrentUse = errors.New("streamjson: concurrent use of StreamJSONParser")
+
+// concurrencyCheck tracks the goroutines using a parser for
+// WithConcurrencyCheck. It is shared by the checkpoints of the parser.
+type concurrencyCheck struct {
+	writer  atomic.Int64 // Goroutine running a method that modifies the parser, 0 for none
+	readers atomic.Int32 // Calls to methods that read the parser in progress
+}
+
+// guardWrite checks a call to method, which modifies the parser, with
+// WithConcurrencyCheck and returns the function ending it, for use as
+// defer p.guardWrite("Append")(). Callbacks run on the writing goroutine
+// and may modify the parser again.
+func (p *StreamJSONParser) guardWrite(method string) func() {
+	c := p.concurrency
+	if c == nil {
+		return func() {}
+	}
+	id := goroutineID()
+	if c.writer.CompareAndSwap(0, id) {
+		if c.readers.Load() > 0 {
+			c.writer.Store(0)
+			panic(concurrentUse(method, "while a read runs on another goroutine"))
+		}
+		return func() { c.writer.Store(0) }
+	}
+	if c.writer.Load() == id {
+		return func() {} // Only the outermost call ends the write
+	}
+	panic(concurrentUse(method, "while a write runs on another goroutine"))
+}
+
+// guardRead checks a call to method, which reads the parser, like
+// guardWrite. Reads from callbacks, which run on the writing goroutine,
+// are allowed.
+func (p *StreamJSONParser) guardRead(method string) func() {
+	c := p.concurrency
+	if c == nil {
+		return func() {}
+	}
+	c.readers.Add(1)
+	if writer := c.writer.Load(); writer != 0 && writer != goroutineID() {
+		c.readers.Add(-1)
+		panic(concurrentUse(method, "while a write runs on another goroutine"))
+	}
+	return func() { c.readers.Add(-1) }
+}
+
+// concurrentUse returns the error describing a detected concurrent call
+func concurrentUse(method, conflict string) error {
+	return fmt.Errorf("%w: %s called %s; guard the parser with a mutex or use NewSafeStreamJSONParser", ErrConcurrentUse, method, conflict)
+}
+
+// goroutineID returns the ID of the calling goroutine from its stack trace,
+// which is slow but only used by WithConcurrencyCheck
+func goroutineID() int64 {
+	var buf [64]byte
+	trace := buf[:runtime.Stack(buf[:], false)]
+	trace = bytes.TrimPrefix(trace, []byte("goroutine "))
+	if i := bytes.IndexByte(trace, ' '); i > 0 {
+		trace = trace[:i]
+	}
+	id, _ := strconv.ParseInt(string(trace), 10, 64)
+	return id
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// callConcurrently runs fn on another goroutine and returns what it
+// panicked with
+func callConcurrently(fn func()) (recovered interface{}) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() { recovered = recover() }()
+		fn()
+	}()
+	wg.Wait()
+	return recovered
+}
+
+func TestConcurrencyCheckDetectsReadDuringAppend(t *testing.T) {
+	parser := NewStreamJSONParser(WithConcurrencyCheck())
+
+	var recovered interface{}
+	parser.OnValue("name", func(value interface{}, complete bool) {
+		// Reading on the Append goroutine is allowed
+		if got := parser.Get("name"); got != value {
+			t.Errorf("Get in callback = %v, want %v", got, value)
+		}
+		if recovered == nil {
+			recovered = callConcurrently(func() { parser.Get("name") })
+		}
+	})
+	parser.Append(`{"name": "streamjson"}`)
+
+	err, ok := recovered.(error)
+	if !ok || !errors.Is(err, ErrConcurrentUse) {
+		t.Fatalf("concurrent Get panicked with %v, want ErrConcurrentUse", recovered)
+	}
+
+	// Once Append returns, other goroutines may read again
+	if r := callConcurrently(func() { parser.Exists("name") }); r != nil {
+		t.Errorf("Exists after Append panicked with %v", r)
+	}
+}
+
+func TestConcurrencyCheckDetectsConcurrentAppend(t *testing.T) {
+	parser := NewStreamJSONParser(WithConcurrencyCheck())
+
+	var recovered interface{}
+	parser.OnValue("a", func(value interface{}, complete bool) {
+		if complete && recovered == nil {
+			recovered = callConcurrently(func() { parser.Append(`, "b": 2`) })
+		}
+	})
+	parser.Append(`{"a": 1,`)
+
+	err, ok := recovered.(error)
+	if !ok || !errors.Is(err, ErrConcurrentUse) {
+		t.Fatalf("concurrent Append panicked with %v, want ErrConcurrentUse", recovered)
+	}
+}
+
+func TestConcurrencyCheckAllowsSafeParser(t *testing.T) {
+	parser := NewSafeStreamJSONParser(WithConcurrencyCheck())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				parser.Get("items")
+				parser.Exists("items")
+			}
+		}()
+	}
+	parser.Append(`{"items": [`)
+	for i := 0; i < 100; i++ {
+		parser.Append(`1, `)
+	}
+	parser.Append(`1]}`)
+	wg.Wait()
+}
+
+func TestConcurrencyCheckConfig(t *testing.T) {
+	parser := NewStreamJSONParser(WithConfig(Config{ConcurrencyCheck: true}))
+	if !parser.options.concurrencyCheck || parser.concurrency == nil {
+		t.Errorf("WithConfig(Config{ConcurrencyCheck: true}) does not check concurrency")
+	}
+}
+
+func TestConcurrencyCheckCoversReaders(t *testing.T) {
+	parser := NewStreamJSONParser(WithConcurrencyCheck())
+
+	readers := map[string]func(){
+		"GetString":   func() { parser.GetString("name") },
+		"GetPath":     func() { parser.GetPath("name") },
+		"Query":       func() { parser.Query("$.name") },
+		"Keys":        func() { parser.Keys() },
+		"Snapshot":    func() { parser.Snapshot() },
+		"MarshalJSON": func() { parser.MarshalJSON() },
+		"Unmarshal":   func() { var v interface{}; parser.Unmarshal(&v) },
+		"Stats":       func() { parser.Stats() },
+		"DumpState":   func() { parser.DumpState() },
+		"Elements": func() {
+			for range parser.Elements("list") {
+			}
+		},
+	}
+	checked := map[string]bool{}
+	parser.OnValue("name", func(value interface{}, complete bool) {
+		if !complete {
+			return
+		}
+		for name, read := range readers {
+			recovered := callConcurrently(read)
+			if err, ok := recovered.(error); ok && errors.Is(err, ErrConcurrentUse) {
+				checked[name] = true
+			}
+		}
+	})
+	parser.Append(`{"list": [1, 2], "name": "streamjson"}`)
+
+	for name := range readers {
+		if !checked[name] {
+			t.Errorf("%s during Append on another goroutine did not panic", name)
+		}
+	}
+
+	// ParseBytes is a write like Append
+	var recovered interface{}
+	parser = NewStreamJSONParser(WithConcurrencyCheck())
+	parser.OnValue("a", func(value interface{}, complete bool) {
+		if complete && recovered == nil {
+			recovered = callConcurrently(func() { parser.Get("a") })
+		}
+	})
+	parser.ParseBytes([]byte(`{"a": 1}`))
+	if err, ok := recovered.(error); !ok || !errors.Is(err, ErrConcurrentUse) {
+		t.Errorf("Get during ParseBytes panicked with %v, want ErrConcurrentUse", recovered)
+	}
+}
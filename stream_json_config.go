@@ -0,0 +1,165 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"io"
+	"math"
+)
+
+// Config holds the parser settings as one value, for settings read from a
+// configuration file or shared between parsers. Each field corresponds to
+// the option of the same name, and the zero value is the default parser.
+// Settings that are not data, such as the schema and key normalizer, are
+// not encoded to JSON.
+type Config struct {
+	RawStrings           bool       `json:"rawStrings,omitempty"`
+	CodeFenceExtraction  bool       `json:"codeFenceExtraction,omitempty"`
+	Repair               bool       `json:"repair,omitempty"`
+	LenientKeys          bool       `json:"lenientKeys,omitempty"`
+	Comments             bool       `json:"comments,omitempty"`
+	SmartQuotes          bool       `json:"smartQuotes,omitempty"`
+	TrimKeys             bool       `json:"trimKeys,omitempty"`
+	RetainInput          bool       `json:"retainInput,omitempty"`
+	ConcurrencyCheck     bool       `json:"concurrencyCheck,omitempty"`
+	BlockingEvents       bool       `json:"blockingEvents,omitempty"`
+	LenientCoercion      bool       `json:"lenientCoercion,omitempty"`
+	MultipleDocuments    bool       `json:"multipleDocuments,omitempty"`
+	DocumentDetection    bool       `json:"documentDetection,omitempty"`
+	StrictMode           bool       `json:"strictMode,omitempty"`
+	ScalarRoots          bool       `json:"scalarRoots,omitempty"`
+	PartialNumbers       bool       `json:"partialNumbers,omitempty"`
+	ChangeTracking       bool       `json:"changeTracking,omitempty"`
+	Recovery             bool       `json:"recovery,omitempty"`
+	NumberMode           NumberMode `json:"numberMode,omitempty"`
+	NonFiniteNumbers     bool       `json:"nonFiniteNumbers,omitempty"` // Parse NaN and Infinity into float64
+	IncludePaths         []string   `json:"includePaths,omitempty"`
+	ErrorOnLeadingText   bool       `json:"errorOnLeadingText,omitempty"`
+	CaptureLeadingText   bool       `json:"captureLeadingText,omitempty"`
+	ErrorOnTrailingText  bool       `json:"errorOnTrailingText,omitempty"`
+	CaptureTrailingText  bool       `json:"captureTrailingText,omitempty"`
+	Timestamps           bool       `json:"timestamps,omitempty"`
+	MaxBufferSize        int        `json:"maxBufferSize,omitempty"`
+	MaxDepth             int        `json:"maxDepth,omitempty"`
+	MaxKeyLength         int        `json:"maxKeyLength,omitempty"`
+	MaxStringLength      int        `json:"maxStringLength,omitempty"`
+	MaxNodes             int        `json:"maxNodes,omitempty"`
+	MaxDocuments         int        `json:"maxDocuments,omitempty"`
+	MemoryBudget         int        `json:"memoryBudget,omitempty"`
+	StringTruncation     int        `json:"stringTruncation,omitempty"`
+	SpillThreshold       int        `json:"spillThreshold,omitempty"` // Strings over this length go to temporary files, or to Spill
+	RedactPaths          []string   `json:"redactPaths,omitempty"`
+	RedactionPlaceholder string     `json:"redactionPlaceholder,omitempty"` // Empty for DefaultRedactionPlaceholder
+
+	Schema        *Schema                 `json:"-"`
+	Shape         interface{}             `json:"-"` // Value whose type, or Shape, WithShape checks against
+	KeyNormalizer func(key string) string `json:"-"`
+
+	Spill func(path []string) (io.WriterAt, error) `json:"-"` // Target of strings over SpillThreshold, nil for temporary files
+}
+
+// WithConfig applies every setting of cfg. Options after it override them,
+// as in NewStreamJSONParser(WithConfig(cfg), WithStrictMode()).
+func WithConfig(cfg Config) Option {
+	options := cfg.Options()
+	return func(o *parserOptions) {
+		for _, opt := range options {
+			opt(o)
+		}
+	}
+}
+
+// Options returns the options equivalent to the settings of c
+func (c Config) Options() []Option {
+	var opts []Option
+	flags := []struct {
+		set    bool
+		option func() Option
+	}{
+		{c.RawStrings, WithRawStrings},
+		{c.CodeFenceExtraction, WithCodeFenceExtraction},
+		{c.Repair, WithRepair},
+		{c.LenientKeys, WithLenientKeys},
+		{c.Comments, WithComments},
+		{c.SmartQuotes, WithSmartQuotes},
+		{c.TrimKeys, WithTrimmedKeys},
+		{c.RetainInput, WithRetainedInput},
+		{c.ConcurrencyCheck, WithConcurrencyCheck},
+		{c.BlockingEvents, WithBlockingEvents},
+		{c.LenientCoercion, WithLenientCoercion},
+		{c.MultipleDocuments, WithMultipleDocuments},
+		{c.DocumentDetection, WithDocumentDetection},
+		{c.StrictMode, WithStrictMode},
+		{c.ScalarRoots, WithScalarRoots},
+		{c.PartialNumbers, WithPartialNumbers},
+		{c.ChangeTracking, WithChangeTracking},
+		{c.Recovery, WithRecovery},
+		{c.ErrorOnLeadingText, WithErrorOnLeadingText},
+		{c.CaptureLeadingText, WithCaptureLeadingText},
+		{c.ErrorOnTrailingText, WithErrorOnTrailingText},
+		{c.CaptureTrailingText, WithCaptureTrailingText},
+		{c.Timestamps, WithTimestamps},
+	}
+	for _, flag := range flags {
+		if flag.set {
+			opts = append(opts, flag.option())
+		}
+	}
+
+	limits := []struct {
+		value  int
+		option func(int) Option
+	}{
+		{c.MaxBufferSize, WithMaxBufferSize},
+		{c.MaxDepth, WithMaxDepth},
+		{c.MaxKeyLength, WithMaxKeyLength},
+		{c.MaxStringLength, WithMaxStringLength},
+		{c.MaxNodes, WithMaxNodes},
+		{c.MaxDocuments, WithMaxDocuments},
+		{c.MemoryBudget, WithMemoryBudget},
+		{c.StringTruncation, WithStringTruncation},
+	}
+	for _, limit := range limits {
+		if limit.value != 0 {
+			opts = append(opts, limit.option(limit.value))
+		}
+	}
+
+	if c.NumberMode != NumberAsNative {
+		opts = append(opts, WithNumberMode(c.NumberMode))
+	}
+	if c.NonFiniteNumbers {
+		opts = append(opts, WithNonFiniteNumbers(math.NaN(), math.Inf(1), math.Inf(-1)))
+	}
+	if c.IncludePaths != nil {
+		opts = append(opts, WithIncludePaths(c.IncludePaths...))
+	}
+	if c.RedactPaths != nil {
+		opts = append(opts, WithRedaction(c.RedactionPlaceholder, c.RedactPaths...))
+	}
+	if c.Schema != nil {
+		opts = append(opts, WithSchema(c.Schema))
+	}
+	if c.Shape != nil {
+		opts = append(opts, WithShape(c.Shape))
+	}
+	if c.KeyNormalizer != nil {
+		opts = append(opts, WithKeyNormalizer(c.KeyNormalizer))
+	}
+	if c.SpillThreshold != 0 {
+		opts = append(opts, WithStringSpill(c.SpillThreshold, c.Spill))
+	}
+	return opts
+}
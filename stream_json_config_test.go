@@ -0,0 +1,86 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigMatchesOptions(t *testing.T) {
+	cfg := Config{
+		Repair:        true,
+		NumberMode:    NumberAsJSONNumber,
+		MaxDepth:      4,
+		IncludePaths:  []string{"a"},
+		KeyNormalizer: strings.ToLower,
+	}
+	fromConfig := NewStreamJSONParser(WithConfig(cfg))
+	fromOptions := NewStreamJSONParser(WithRepair(), WithNumberMode(NumberAsJSONNumber), WithMaxDepth(4),
+		WithIncludePaths("a"), WithKeyNormalizer(strings.ToLower))
+
+	input := `{A: [1, 'two', True], "b": 3}`
+	fromConfig.Append(input)
+	fromOptions.Append(input)
+	if !reflect.DeepEqual(fromConfig.Get(), fromOptions.Get()) {
+		t.Errorf("Expected the same result, got %v and %v", fromConfig.Get(), fromOptions.Get())
+	}
+	if fromConfig.Get("a", "0") != json.Number("1") || fromConfig.Get("b") != nil {
+		t.Errorf("Unexpected result: %v", fromConfig.Get())
+	}
+
+	parser := NewStreamJSONParser(WithConfig(cfg))
+	parser.Append(`{"a": [[[[[1]]]]]}`)
+	if parser.Err() != ErrDepthLimit {
+		t.Errorf("Expected the depth limit to apply, got %v", parser.Err())
+	}
+}
+
+func TestConfigLaterOptionsOverride(t *testing.T) {
+	parser := NewStreamJSONParser(WithConfig(Config{MaxNodes: 2}), WithMaxNodes(0))
+	parser.Append(`[1, 2, 3]`)
+	if parser.Err() != nil || !parser.IsCompleted() {
+		t.Errorf("Expected the later option to lift the limit, got %v", parser.Err())
+	}
+}
+
+func TestConfigFromJSON(t *testing.T) {
+	var cfg Config
+	if err := json.Unmarshal([]byte(`{"strictMode": true, "maxStringLength": 3}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewStreamJSONParser(WithConfig(cfg))
+	parser.Append(`{"a": "long"}`)
+	if !errors.Is(parser.Err(), ErrStringLengthLimit) {
+		t.Errorf("Expected the string limit, got %v", parser.Err())
+	}
+
+	parser = NewStreamJSONParser(WithConfig(cfg))
+	parser.Append(`{"a": 1,}`)
+	var parseErr *ParseError
+	if !errors.As(parser.Err(), &parseErr) {
+		t.Errorf("Expected strict mode, got %v", parser.Err())
+	}
+}
+
+func TestZeroConfig(t *testing.T) {
+	if opts := (Config{}).Options(); len(opts) != 0 {
+		t.Errorf("Expected no options for the zero config, got %d", len(opts))
+	}
+}
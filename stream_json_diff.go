@@ -0,0 +1,130 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"sort"
+	"strings"
+)
+
+// ChangeKind identifies what happened to a value
+type ChangeKind int
+
+const (
+	ChangeAdded     ChangeKind = iota // A value appeared at the path
+	ChangeExtended                    // A streaming value grew
+	ChangeCompleted                   // A value was finalized
+)
+
+// Change describes how the value at a path changed since a version
+type Change struct {
+	Kind ChangeKind
+	Path []string    // Path of the value from the root
+	Old  interface{} // Value at the version, nil for ChangeAdded
+	New  interface{} // Current value, nil for completed objects and arrays
+}
+
+// changeRecord is an entry of the change log. Strings only grow, so for a
+// string the previous value is kept as its length.
+type changeRecord struct {
+	version int
+	kind    ChangeKind
+	path    []string
+	old     interface{} // Previous value of a scalar that is not a string
+	oldLen  int         // Previous length of a string
+	isText  bool        // Whether the previous value was a string
+}
+
+// Version returns the number of Append calls so far. Pass it to Diff later
+// to get what changed in between.
+func (p *StreamJSONParser) Version() int {
+	return p.version
+}
+
+// Diff returns the changes made by Append calls after version, requires
+// WithChangeTracking. Changes are coalesced per path and ordered by first
+// occurrence, so a parent comes before its members: a value added since the
+// version is reported once as ChangeAdded with its current value, including
+// its members; otherwise a completion takes precedence over extensions. In
+// multi-document mode changes refer to the document being parsed.
+func (p *StreamJSONParser) Diff(version int) []Change {
+	defer p.guardRead("Diff")()
+	first := sort.Search(len(p.changes), func(i int) bool {
+		return p.changes[i].version > version
+	})
+
+	var changes []Change
+	var records []*changeRecord // First record of each change
+	index := make(map[string]int)
+	for i := first; i < len(p.changes); i++ {
+		record := &p.changes[i]
+		if p.addedAncestor(record.path, index, changes) {
+			continue
+		}
+
+		key := strings.Join(record.path, "\x00")
+		if j, ok := index[key]; ok {
+			if changes[j].Kind != ChangeAdded && record.kind == ChangeCompleted {
+				changes[j].Kind = ChangeCompleted
+			}
+			continue
+		}
+		index[key] = len(changes)
+		changes = append(changes, Change{Kind: record.kind, Path: record.path})
+		records = append(records, record)
+	}
+
+	for i := range changes {
+		change := &changes[i]
+		node := p.findNode(change.Path)
+		if node != nil && (node.Type == ValueNode || change.Kind == ChangeAdded) {
+			change.New = collectValue(node, true)
+		}
+		if change.Kind == ChangeAdded {
+			continue
+		}
+
+		record := records[i]
+		if !record.isText {
+			change.Old = record.old
+		} else if text, ok := change.New.(string); ok && record.oldLen <= len(text) {
+			change.Old = text[:record.oldLen]
+		}
+	}
+	return changes
+}
+
+// addedAncestor reports whether a proper ancestor of path is already
+// reported as added, which covers the path's own changes
+func (p *StreamJSONParser) addedAncestor(path []string, index map[string]int, changes []Change) bool {
+	for n := 0; n < len(path); n++ {
+		if j, ok := index[strings.Join(path[:n], "\x00")]; ok && changes[j].Kind == ChangeAdded {
+			return true
+		}
+	}
+	return false
+}
+
+// recordChange appends to the change log when change tracking is enabled
+func (p *StreamJSONParser) recordChange(kind ChangeKind, path []string, previous interface{}) {
+	record := changeRecord{version: p.version, kind: kind, path: path}
+	if text, ok := previous.(string); ok {
+		record.isText = true
+		record.oldLen = len(text)
+	} else {
+		record.old = previous
+	}
+	p.changes = append(p.changes, record)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// formatChanges renders changes as "kind path old->new" lines
+func formatChanges(changes []Change) string {
+	kinds := map[ChangeKind]string{ChangeAdded: "added", ChangeExtended: "extended", ChangeCompleted: "completed"}
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		old, _ := json.Marshal(change.Old)
+		value, _ := json.Marshal(change.New)
+		lines[i] = kinds[change.Kind] + " " + strings.Join(change.Path, ".") + " " + string(old) + "->" + string(value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestStreamJSONParserDiff(t *testing.T) {
+	parser := NewStreamJSONParser(WithChangeTracking())
+
+	parser.Append(`{"title":"Hel`)
+	v1 := parser.Version()
+	expected := `added  null->{"title":"Hel"}`
+	if got := formatChanges(parser.Diff(0)); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+
+	parser.Append(`lo","items":[1,`)
+	parser.Append(`{"a":tr`)
+	v3 := parser.Version()
+	expected = strings.Join([]string{
+		`completed title "Hel"->"Hello"`,
+		`added items null->[1,{}]`,
+	}, "\n")
+	if got := formatChanges(parser.Diff(v1)); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+
+	parser.Append(`ue}],"done":true}`)
+	expected = strings.Join([]string{
+		`added items.1.a null->true`,
+		`completed items.1 null->null`,
+		`completed items null->null`,
+		`added done null->true`,
+		`completed  null->null`,
+	}, "\n")
+	if got := formatChanges(parser.Diff(v3)); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+	if changes := parser.Diff(parser.Version()); len(changes) != 0 {
+		t.Errorf("Expected no changes since the current version, got %v", formatChanges(changes))
+	}
+}
+
+func TestStreamJSONParserDiffExtended(t *testing.T) {
+	parser := NewStreamJSONParser(WithChangeTracking(), WithPartialNumbers())
+	parser.Append(`{"text":"a`)
+	v := parser.Version()
+	parser.Append(`b`)
+	parser.Append(`c`)
+
+	expected := `extended text "a"->"abc"`
+	if got := formatChanges(parser.Diff(v)); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+
+	parser.Append(`","n":1`)
+	v = parser.Version()
+	parser.Append(`2`)
+	expected = `extended n 1->12`
+	if got := formatChanges(parser.Diff(v)); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestStreamJSONParserDiffDisabled(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":1}`)
+	if parser.Version() != 1 || parser.Diff(0) != nil {
+		t.Errorf("Expected versions without a change log")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import "slices"
+
+// OnDocument registers a callback invoked with the index and materialized
+// value of every root completed in multi-document mode
+func (p *StreamJSONParser) OnDocument(callback func(index int, document interface{})) {
+	p.documentCallbacks = append(p.documentCallbacks, callback)
+}
+
+// OnDocumentStart registers a callback invoked with the index of every root
+// as it begins in multi-document mode, before any of its content is seen
+func (p *StreamJSONParser) OnDocumentStart(callback func(index int)) {
+	p.documentStarts = append(p.documentStarts, callback)
+}
+
+// Documents returns the materialized values of all roots completed so far in
+// multi-document mode, in stream order, or of the last ones with
+// WithMaxDocuments. The document currently streaming is available through
+// Get.
+func (p *StreamJSONParser) Documents() []interface{} {
+	defer p.guardRead("Documents")()
+	documents := make([]interface{}, len(p.documents))
+	for i, root := range p.documents {
+		documents[i] = p.collectNodeValue(root)
+	}
+	return documents
+}
+
+// startDocument announces a new root in multi-document mode
+func (p *StreamJSONParser) startDocument() {
+	index := p.documentCount
+	if p.events != nil {
+		p.emit(Event{Type: DocumentStarted, Value: index})
+	}
+	for _, callback := range p.documentStarts {
+		callback(index)
+	}
+}
+
+// closesAtRoot reports whether the Events channel closes when the root
+// completes, which it does unless more documents may follow
+func (p *StreamJSONParser) closesAtRoot() bool {
+	return !p.options.multipleDocuments && !p.options.detectDocuments
+}
+
+// detectDocuments switches to multi-document mode, as WithDocumentDetection
+// requests, once the input after the completed root starts another object
+// or array on a new line. It reports whether it switched.
+func (p *StreamJSONParser) detectDocuments() bool {
+	if !p.options.detectDocuments || p.options.multipleDocuments {
+		return false
+	}
+	newline := false
+	for _, c := range p.afterRoot() {
+		switch c {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			newline = true
+			continue
+		case '{', '[':
+			if !newline {
+				return false
+			}
+			p.options.multipleDocuments = true
+			p.detectedDocuments = true
+			if p.events != nil {
+				p.emit(Event{Type: DocumentsDetected})
+			}
+			p.finishDocument()
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// finishDocument archives the completed root and prepares for the next
+// document. Roots beyond WithMaxDocuments are released, unless a checkpoint
+// still refers to them.
+func (p *StreamJSONParser) finishDocument() {
+	root := p.root
+	index := p.documentCount
+	p.documentCount++
+	p.documents = append(p.documents, root)
+	if limit := p.options.maxDocuments; limit > 0 && len(p.documents) > limit {
+		dropped := len(p.documents) - limit
+		if len(p.checkpoints) == 0 {
+			for _, old := range p.documents[:dropped] {
+				releaseTree(old)
+			}
+			copy(p.documents, p.documents[dropped:])
+			clear(p.documents[limit:])
+			p.documents = p.documents[:limit]
+		} else {
+			// Checkpoints share the slice
+			p.documents = slices.Clone(p.documents[dropped:])
+		}
+	}
+
+	p.root = nil
+	p.started = false
+	p.nodes = 0
+	p.changes = nil
+
+	if p.events != nil {
+		p.emit(Event{Type: DocumentCompleted, Value: index})
+	}
+
+	if len(p.documentCallbacks) > 0 {
+		document := p.collectNodeValue(root)
+		for _, callback := range p.documentCallbacks {
+			callback(index, document)
+		}
+	}
+}
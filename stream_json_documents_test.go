@@ -0,0 +1,234 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMultipleDocumentsNDJSON(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments())
+
+	var indices []int
+	var names []interface{}
+	parser.OnDocument(func(index int, document interface{}) {
+		indices = append(indices, index)
+		if object, ok := document.(map[string]interface{}); ok {
+			names = append(names, object["name"])
+		}
+	})
+
+	parser.Append("{\"name\":\"a\"}\n{\"na")
+	if len(names) != 1 || names[0] != "a" {
+		t.Errorf("Expected first document delivered, got %v", names)
+	}
+
+	// The streaming document is available through Get
+	parser.Append(`me":"b`)
+	if parser.Get("name") != "b" {
+		t.Errorf("Expected partial second document, got %v", parser.Get("name"))
+	}
+
+	parser.Append("\"}\n[1]\n{\"name\":\"c\"}\n")
+
+	if len(indices) != 4 || indices[3] != 3 {
+		t.Errorf("Expected 4 documents, got %v", indices)
+	}
+
+	documents := parser.Documents()
+	if len(documents) != 4 {
+		t.Fatalf("Expected 4 documents, got %d", len(documents))
+	}
+	if arr, ok := documents[2].([]interface{}); !ok || arr[0] != int64(1) {
+		t.Errorf("Expected third document [1], got %v", documents[2])
+	}
+
+	if parser.IsCompleted() {
+		t.Errorf("Expected parser to wait for the next document")
+	}
+}
+
+func TestMultipleDocumentsEvents(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments())
+	events := parser.Events()
+
+	parser.Append(`{"a":1}{"b":2}`)
+
+	var completed []interface{}
+	for len(events) > 0 {
+		event := <-events
+		if event.Type == DocumentCompleted {
+			completed = append(completed, event.Value)
+		}
+	}
+
+	if len(completed) != 2 || completed[0] != 0 || completed[1] != 1 {
+		t.Errorf("Expected DocumentCompleted for 0 and 1, got %v", completed)
+	}
+}
+
+func TestConcatenatedDocumentsStart(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments(), WithScalarRoots())
+	events := parser.Events()
+
+	var started []int
+	parser.OnDocumentStart(func(index int) {
+		started = append(started, index)
+		if len(parser.Documents()) != index {
+			t.Errorf("Expected %d completed documents when %d starts, got %d", index, index, len(parser.Documents()))
+		}
+	})
+
+	appendBytewise(parser, `{"a":1}[2]"three" {"b":{"c":4}}`)
+
+	if len(started) != 4 || started[3] != 3 {
+		t.Errorf("Expected 4 document starts, got %v", started)
+	}
+
+	// Each document is announced before its content
+	var types []EventType
+	for len(events) > 0 {
+		event := <-events
+		if event.Type == DocumentStarted || event.Type == DocumentCompleted {
+			types = append(types, event.Type)
+		}
+	}
+	if len(types) != 8 || types[0] != DocumentStarted || types[1] != DocumentCompleted || types[6] != DocumentStarted {
+		t.Errorf("Expected alternating start and completion events, got %v", types)
+	}
+
+	documents := parser.Documents()
+	if len(documents) != 4 || documents[2] != "three" {
+		t.Errorf("Expected 4 concatenated documents, got %v", documents)
+	}
+}
+
+func TestSingleDocumentIgnoresTrailingRoots(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":1}{"a":2}`)
+
+	if parser.Get("a") != int64(1) {
+		t.Errorf("Expected only the first document, got %v", parser.Get("a"))
+	}
+	if len(parser.Documents()) != 0 {
+		t.Errorf("Expected no archived documents in single-document mode")
+	}
+}
+
+func TestMaxDocuments(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments(), WithMaxDocuments(2))
+
+	var indices []int
+	parser.OnDocument(func(index int, document interface{}) {
+		indices = append(indices, index)
+	})
+	for i := 0; i < 1000; i++ {
+		parser.Append(`{"id":` + strconv.Itoa(i) + "}\n")
+	}
+
+	if len(indices) != 1000 || indices[999] != 999 {
+		t.Fatalf("Expected every document delivered with its index, got %d", len(indices))
+	}
+	documents := parser.Documents()
+	if len(documents) != 2 || documents[0].(map[string]interface{})["id"] != int64(998) ||
+		documents[1].(map[string]interface{})["id"] != int64(999) {
+		t.Errorf("Expected only the last two documents, got %v", documents)
+	}
+	if cap(parser.documents) > 4 {
+		t.Errorf("Expected the retained roots to stay bounded, capacity %d", cap(parser.documents))
+	}
+	if stats := parser.Stats(); stats.Documents != 1000 {
+		t.Errorf("Expected Stats to count every document, got %d", stats.Documents)
+	}
+
+	// Restoring a checkpoint brings back the documents retained when it was taken
+	cp := parser.Checkpoint()
+	parser.Append("{\"id\":1000}\n{\"id\":1001}\n")
+	if err := parser.Restore(cp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	documents = parser.Documents()
+	if len(documents) != 2 || documents[1].(map[string]interface{})["id"] != int64(999) {
+		t.Errorf("Expected the documents of the checkpoint, got %v", documents)
+	}
+}
+
+func TestDocumentDetection(t *testing.T) {
+	parser := NewStreamJSONParser(WithDocumentDetection(), WithBlockingEvents())
+	events := parser.Events()
+	var indices []int
+	parser.OnDocument(func(index int, document interface{}) {
+		indices = append(indices, index)
+	})
+
+	parser.Append(`{"id":1}`)
+	parser.Append("\n")
+	if docs := parser.Documents(); len(docs) != 0 || !parser.IsCompleted() {
+		t.Fatalf("switched before a second root: %v", docs)
+	}
+	parser.Append("{\"id\":2}\n{\"id\"")
+	parser.Append(":3}")
+	parser.Finish()
+
+	if docs := parser.Documents(); len(docs) != 3 {
+		t.Fatalf("documents = %v, want 3", docs)
+	}
+	if len(indices) != 3 || indices[0] != 0 || indices[2] != 2 {
+		t.Fatalf("OnDocument indices = %v", indices)
+	}
+
+	var types []EventType
+	for event := range events {
+		if event.Type == DocumentsDetected || event.Type == DocumentStarted || event.Type == DocumentCompleted {
+			types = append(types, event.Type)
+		}
+	}
+	want := []EventType{DocumentsDetected, DocumentCompleted, DocumentStarted, DocumentCompleted, DocumentStarted, DocumentCompleted}
+	if !slices.Equal(types, want) {
+		t.Fatalf("document events = %v, want %v", types, want)
+	}
+
+	// Reset returns to single-document mode until the next detection
+	parser.Reset()
+	parser.Append(`{"a":1} {"a":2}`)
+	if len(parser.Documents()) != 0 {
+		t.Fatal("Reset kept multi-document mode")
+	}
+	parser.Reset()
+	parser.Append(`{"a":1}` + "\n" + `{"a":2}`)
+	if len(parser.Documents()) != 2 {
+		t.Fatalf("documents after Reset = %v, want 2", parser.Documents())
+	}
+}
+
+func TestDocumentDetectionSingleDocument(t *testing.T) {
+	for _, after := range []string{` {"a":2}`, "\nthanks {\"a\":2}", ""} {
+		parser := NewStreamJSONParser(WithDocumentDetection())
+		events := parser.Events()
+		parser.Append(`{"a":1}` + after)
+
+		if docs := parser.Documents(); len(docs) != 0 {
+			t.Errorf("%q: switched to documents %v", after, docs)
+		}
+		if a, _ := parser.GetInt("a"); a != 1 {
+			t.Errorf("%q: a = %d", after, a)
+		}
+		parser.Finish()
+		for range events {
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// decodeString decodes the JSON escape sequences in s, the content of a string
+// without its quotes. For a partial string, a trailing incomplete escape and a
+// trailing high surrogate waiting for its pair are held back, so the result
+// only ever grows as more content arrives.
+func decodeString(s string, partial bool) string {
+	// Fast path: nothing to decode
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c != '\\' {
+			// Copy the run up to the next escape in one go
+			next := strings.IndexByte(s[i:], '\\')
+			if next < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			b.WriteString(s[i : i+next])
+			i += next
+			continue
+		}
+
+		if i+1 >= len(s) {
+			if !partial {
+				b.WriteByte('\\')
+			}
+			break
+		}
+
+		switch s[i+1] {
+		case '"', '\\', '/':
+			b.WriteByte(s[i+1])
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			r, size, ok := decodeUnicodeEscape(s[i:], partial)
+			if !ok {
+				// Incomplete escape at the end of a partial string
+				return b.String()
+			}
+			b.WriteRune(r)
+			i += size
+			continue
+		default:
+			// Tolerate unknown escapes by keeping the escaped character
+			b.WriteByte(s[i+1])
+		}
+		i += 2
+	}
+
+	return b.String()
+}
+
+// decodeUnicodeEscape decodes a \uXXXX escape at the start of s, combining
+// surrogate pairs. It returns the rune and the number of bytes consumed, or
+// ok=false when a partial string ends before the escape can be decided.
+func decodeUnicodeEscape(s string, partial bool) (r rune, size int, ok bool) {
+	r1, valid := parseHex4(s, 2)
+	if !valid {
+		if partial && len(s) < 6 && isHexPrefix(s[2:]) {
+			return 0, 0, false
+		}
+		// Malformed escape: keep the escaped character like other unknown escapes
+		return 'u', 2, true
+	}
+
+	if !utf16.IsSurrogate(r1) {
+		return r1, 6, true
+	}
+
+	// Look for the low surrogate of a pair
+	if len(s) >= 12 && s[6] == '\\' && s[7] == 'u' {
+		if r2, valid := parseHex4(s, 8); valid {
+			if combined := utf16.DecodeRune(r1, r2); combined != utf8.RuneError {
+				return combined, 12, true
+			}
+		}
+	} else if partial && len(s) < 12 && isSurrogateTailPrefix(s[6:]) {
+		return 0, 0, false
+	}
+
+	return utf8.RuneError, 6, true
+}
+
+// parseHex4 parses four hex digits at s[offset:]
+func parseHex4(s string, offset int) (rune, bool) {
+	if len(s) < offset+4 {
+		return 0, false
+	}
+	var r rune
+	for i := offset; i < offset+4; i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			r = r<<4 | rune(c-'0')
+		case c >= 'a' && c <= 'f':
+			r = r<<4 | rune(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			r = r<<4 | rune(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+	return r, true
+}
+
+// isHexPrefix reports whether s consists only of hex digits
+func isHexPrefix(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSurrogateTailPrefix reports whether s could still grow into a \uXXXX escape
+func isSurrogateTailPrefix(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	if s[0] != '\\' {
+		return false
+	}
+	if len(s) == 1 {
+		return true
+	}
+	return s[1] == 'u' && isHexPrefix(s[2:])
+}
+
+// completeUTF8 returns the length of the longest prefix of s that does not
+// end inside an incomplete UTF-8 sequence
+func completeUTF8(s string) int {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if utf8.FullRuneInString(s[i:]) {
+				return len(s)
+			}
+			return i
+		}
+	}
+	return len(s)
+}
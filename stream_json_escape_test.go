@@ -0,0 +1,136 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDecodeStringMatchesEncodingJSON(t *testing.T) {
+	inputs := []string{
+		`"plain"`,
+		`"line\nbreak\ttab\r"`,
+		`"quote \" backslash \\ slash \/"`,
+		`"\b\f"`,
+		`"café"`,
+		`"emoji 😀!"`,
+		`"lone \ud83d surrogate"`,
+		`"中文"`,
+	}
+
+	for _, input := range inputs {
+		var expected string
+		if err := json.Unmarshal([]byte(input), &expected); err != nil {
+			t.Fatalf("encoding/json failed on %s: %v", input, err)
+		}
+
+		got := decodeString(input[1:len(input)-1], false)
+		if got != expected {
+			t.Errorf("Input %s: expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestDecodeStringPartial(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`hello\`, "hello"},
+		{`hello\n`, "hello\n"},
+		{`caf\u00`, "caf"},
+		{`café`, "café"},
+		{`x\ud83d`, "x"},
+		{`x\ud83d\`, "x"},
+		{`x\ud83d\ude`, "x"},
+		{`x😀`, "x😀"},
+		{`x\ud83dy`, "x�y"},
+	}
+
+	for _, test := range tests {
+		if got := decodeString(test.input, true); got != test.expected {
+			t.Errorf("Partial %q: expected %q, got %q", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestStreamJSONParserDecodesEscapes(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	parser.Append(`{"say\"":"line1\nli`)
+	if parser.Get(`say"`) != "line1\nli" {
+		t.Errorf("Expected decoded partial value, got %q", parser.Get(`say"`))
+	}
+
+	parser.Append(`ne2 é\u`)
+	if parser.Get(`say"`) != "line1\nline2 é" {
+		t.Errorf("Expected incomplete escape to be held back, got %q", parser.Get(`say"`))
+	}
+
+	parser.Append(`00e9"}`)
+	if parser.Get(`say"`) != "line1\nline2 éé" {
+		t.Errorf("Expected fully decoded value, got %q", parser.Get(`say"`))
+	}
+}
+
+func TestStreamJSONParserRawStrings(t *testing.T) {
+	parser := NewStreamJSONParser(WithRawStrings())
+	parser.Append(`{"k1":"a\nb"}`)
+
+	if parser.Get(`k1`) != `a\nb` {
+		t.Errorf("Expected raw content, got %q", parser.Get(`k1`))
+	}
+}
+
+func TestStreamJSONParserSplitCharacters(t *testing.T) {
+	input := `{"text":"café 中文 😀 \u00e9\ud83d\ude00 end"}`
+	expected := "café 中文 😀 é😀 end"
+
+	for _, rawStrings := range []bool{false, true} {
+		var opts []Option
+		if rawStrings {
+			opts = append(opts, WithRawStrings())
+		}
+
+		// Feed one byte at a time so every character is split
+		parser := NewStreamJSONParser(opts...)
+		events := parser.Events()
+		var deltas strings.Builder
+		for i := 0; i < len(input); i++ {
+			parser.Append(input[i : i+1])
+			for len(events) > 0 {
+				if event := <-events; event.Type == StringDelta {
+					deltas.WriteString(event.Delta)
+				}
+			}
+
+			value, _ := parser.Get("text").(string)
+			if !utf8.ValidString(value) {
+				t.Fatalf("Raw %v: invalid UTF-8 after %d bytes: %q", rawStrings, i+1, value)
+			}
+		}
+
+		final := parser.Get("text").(string)
+		if !rawStrings && final != expected {
+			t.Errorf("Expected %q, got %q", expected, final)
+		}
+		if !rawStrings && deltas.String() != expected {
+			t.Errorf("Expected deltas to add up to %q, got %q", expected, deltas.String())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// EventType represents the kind of a parser event
+type EventType int
+
+const (
+	ObjectStarted     EventType = iota // An object was opened
+	ObjectClosed                       // An object was closed
+	ArrayStarted                       // An array was opened
+	ArrayClosed                        // An array was closed
+	ArrayItemAdded                     // A new element started in an array
+	KeyStarted                         // An object key was read, its value follows
+	StringDelta                        // A string value grew by Delta
+	ValueCompleted                     // A string, number, bool or null value completed
+	DocumentCompleted                  // A root completed in multi-document mode, Value holds its index
+	Recovered                          // The parser resynchronized after invalid input, Value holds the *Recovery
+	DocumentStarted                    // A root began in multi-document mode, Value holds its index
+	ValueEvicted                       // A completed string was emptied to stay within WithMemoryBudget, Value holds its length
+	DocumentsDetected                  // WithDocumentDetection switched to multi-document mode on a second root
+)
+
+// eventBufferSize is the capacity of the channel returned by Events
+const eventBufferSize = 256
+
+// Event describes an incremental change to the document
+type Event struct {
+	Type  EventType
+	Path  []string    // Path of the affected value from the root
+	Delta string      // Newly received text, for StringDelta
+	Value interface{} // Final value, for ValueCompleted
+}
+
+// Events returns a channel of structured events describing the document as
+// it streams, for push-based consumers such as token-streaming UIs. Call it
+// before appending content; events are only produced once it has been called.
+// The channel is closed when the root completes, except in multi-document
+// mode where it stays open across documents. Events that do not fit in the
+// buffer of 256 are dropped and counted by DroppedEvents, so a consumer
+// may read the channel on the goroutine calling Append; WithBlockingEvents
+// makes Append wait for room instead.
+func (p *StreamJSONParser) Events() <-chan Event {
+	if p.events == nil {
+		p.events = make(chan Event, eventBufferSize)
+		if p.IsCompleted() && p.closesAtRoot() || p.finished {
+			close(p.events)
+		}
+	}
+	return p.events
+}
+
+// DroppedEvents returns the number of events left out because the Events
+// channel was full
+func (p *StreamJSONParser) DroppedEvents() int {
+	return p.droppedEvents
+}
+
+// emit sends an event to the event channel, dropping it if the channel is
+// full unless events block
+func (p *StreamJSONParser) emit(event Event) {
+	if p.eventSink != nil {
+		p.eventSink(event)
+		return
+	}
+	if p.options.blockingEvents {
+		p.events <- event
+		return
+	}
+	select {
+	case p.events <- event:
+	default:
+		p.droppedEvents++
+	}
+}
+
+// sendEventsTo enables events on a new parser and delivers them to sink.
+// The event channel is unbuffered, as it is only closed.
+func (p *StreamJSONParser) sendEventsTo(sink func(Event)) {
+	p.events = make(chan Event)
+	p.eventSink = sink
+}
+
+// emitStarted emits the events for a node added to the document
+func (p *StreamJSONParser) emitStarted(path []string, node *Node) {
+	if node.Parent != nil && node.Parent.Type == ArrayNode {
+		p.emit(Event{Type: ArrayItemAdded, Path: path})
+	}
+
+	switch node.Type {
+	case ObjectNode:
+		p.emit(Event{Type: ObjectStarted, Path: path})
+	case ArrayNode:
+		p.emit(Event{Type: ArrayStarted, Path: path})
+	}
+}
+
+// emitDelta emits the text a string gained since its previous partial value
+func (p *StreamJSONParser) emitDelta(path []string, node *Node, previous interface{}) {
+	value, ok := node.Value.(string)
+	if !ok || len(value) <= textLen(previous) {
+		return
+	}
+	p.emit(Event{Type: StringDelta, Path: path, Delta: value[textLen(previous):]})
+}
+
+// emitCompleted emits the events for a completed node and closes the channel
+// once the root is done. The final StringDelta of a string is emitted by
+// the caller, before transformers replace the text.
+func (p *StreamJSONParser) emitCompleted(path []string, node *Node) {
+	switch node.Type {
+	case ObjectNode:
+		p.emit(Event{Type: ObjectClosed, Path: path})
+	case ArrayNode:
+		p.emit(Event{Type: ArrayClosed, Path: path})
+	case ValueNode:
+		p.emit(Event{Type: ValueCompleted, Path: path, Value: node.Value})
+	}
+
+	if node == p.root && p.closesAtRoot() {
+		close(p.events)
+	}
+}
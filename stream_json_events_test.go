@@ -0,0 +1,147 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEventsSequence(t *testing.T) {
+	parser := NewStreamJSONParser()
+	events := parser.Events()
+
+	parser.Append(`{"title":"Hel`)
+	parser.Append(`lo","items":[1,{"a":true}]}`)
+
+	var got []string
+	for event := range events {
+		entry := strings.Join(event.Path, ".")
+		switch event.Type {
+		case ObjectStarted:
+			entry += " ObjectStarted"
+		case ObjectClosed:
+			entry += " ObjectClosed"
+		case ArrayStarted:
+			entry += " ArrayStarted"
+		case ArrayClosed:
+			entry += " ArrayClosed"
+		case ArrayItemAdded:
+			entry += " ArrayItemAdded"
+		case KeyStarted:
+			entry += " KeyStarted"
+		case StringDelta:
+			entry += " StringDelta " + event.Delta
+		case ValueCompleted:
+			entry += " ValueCompleted"
+		}
+		got = append(got, entry)
+	}
+
+	expected := []string{
+		" ObjectStarted",
+		"title KeyStarted",
+		"title StringDelta Hel",
+		"title StringDelta lo",
+		"title ValueCompleted",
+		"items KeyStarted",
+		"items ArrayStarted",
+		"items.0 ArrayItemAdded",
+		"items.0 ValueCompleted",
+		"items.1 ArrayItemAdded",
+		"items.1 ObjectStarted",
+		"items.1.a KeyStarted",
+		"items.1.a ValueCompleted",
+		"items.1 ObjectClosed",
+		"items ArrayClosed",
+		" ObjectClosed",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %v", len(expected), len(got), got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Event %d: expected %q, got %q", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestEventsValues(t *testing.T) {
+	parser := NewStreamJSONParser(WithBlockingEvents())
+	events := parser.Events()
+
+	done := make(chan map[string]interface{})
+	go func() {
+		values := make(map[string]interface{})
+		for event := range events {
+			if event.Type == ValueCompleted {
+				values[strings.Join(event.Path, ".")] = event.Value
+			}
+		}
+		done <- values
+	}()
+
+	// More events than the buffer holds must not deadlock with a reader
+	parser.Append(`{"n":1,"list":[`)
+	for i := 0; i < eventBufferSize; i++ {
+		parser.Append(`0,`)
+	}
+	parser.Append(`2]}`)
+
+	values := <-done
+	if values["n"] != int64(1) {
+		t.Errorf("Expected n to be 1, got %v", values["n"])
+	}
+	if len(values) != eventBufferSize+2 {
+		t.Errorf("Expected %d completed values, got %d", eventBufferSize+2, len(values))
+	}
+	if parser.DroppedEvents() != 0 {
+		t.Errorf("Expected no dropped events, got %d", parser.DroppedEvents())
+	}
+}
+
+func TestEventsSameGoroutine(t *testing.T) {
+	parser := NewStreamJSONParser()
+	events := parser.Events()
+
+	// One Append producing more events than the buffer holds, read on the
+	// same goroutine afterwards, must not deadlock
+	parser.Append(`[` + strings.Repeat(`0,`, 2*eventBufferSize) + `0]`)
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != eventBufferSize {
+		t.Errorf("Expected a full buffer of %d events, got %d", eventBufferSize, received)
+	}
+
+	// ArrayStarted, ArrayClosed and two events per element
+	if total := received + parser.DroppedEvents(); total != 2+2*(2*eventBufferSize+1) {
+		t.Errorf("Expected %d events in total, got %d received and %d dropped",
+			2+2*(2*eventBufferSize+1), received, parser.DroppedEvents())
+	}
+}
+
+func TestEventsAfterCompletion(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`[]`)
+
+	// The channel is closed right away once the document is done
+	if _, ok := <-parser.Events(); ok {
+		t.Errorf("Expected closed channel after completion")
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+)
+
+// fenceState is the state of the code fence filter
+type fenceState int
+
+const (
+	fenceDetect      fenceState = iota // Before the first non-whitespace character
+	fenceSearch                        // Inside prose, looking for a fence or bare JSON
+	fenceCandidate                     // After a bracket inside prose, deciding whether JSON starts there
+	fenceInfo                          // On the opening fence line, skipping the info string
+	fenceInside                        // Inside the fenced block, passing content through
+	fencePassthrough                   // Bare JSON without a fence, passing everything through
+	fenceDone                          // After the closing fence, discarding everything
+)
+
+// fenceMarker is the character forming Markdown code fences
+const fenceMarker = '`'
+
+// codeFenceFilter extracts the JSON payload from Markdown-wrapped model output.
+// Content starting with '{' or '[' passes through untouched. Otherwise the text
+// is treated as prose and dropped until a line opens a ``` fence, whose content
+// is passed on up to the closing fence, or until a line starts with '{' or '['.
+// Before any fence, a bracket later in a line also starts the payload if what
+// follows it looks like JSON, as in `Sure! {"a":1}`, while prose such as
+// "the [requested] {data}" is still dropped. Fences are only recognized at
+// the start of a line, so backticks inside JSON strings are never mistaken
+// for one. State carries across chunks.
+type codeFenceFilter struct {
+	state       fenceState
+	atLineStart bool // Whether only spaces or tabs were seen since the last newline
+	ticks       int  // Fence markers seen at the start of the current line
+
+	captureBefore bool   // Whether to keep the prose dropped before the payload
+	captureAfter  bool   // Whether to keep the prose dropped after the closing fence
+	before        []byte // Prose dropped before the payload
+	after         []byte // Prose dropped after the closing fence
+
+	pending []byte // Bracket and whitespace held while in fenceCandidate
+}
+
+// newCodeFenceFilter creates a filter waiting for the first content, keeping
+// the prose it drops before and after the payload as requested
+func newCodeFenceFilter(captureBefore, captureAfter bool) *codeFenceFilter {
+	return &codeFenceFilter{state: fenceDetect, atLineStart: true, captureBefore: captureBefore, captureAfter: captureAfter}
+}
+
+// filter returns the part of chunk that belongs to the JSON payload
+func (f *codeFenceFilter) filter(chunk string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(chunk); i++ {
+		c := chunk[i]
+
+		switch f.state {
+		case fenceDetect:
+			switch c {
+			case ' ', '\t', '\n', '\r':
+				continue
+			case '{', '[':
+				f.state = fencePassthrough
+				return chunk[i:]
+			}
+			f.state = fenceSearch
+			i-- // Reprocess as prose at the start of a line
+
+		case fenceSearch:
+			if f.ticks == 0 && (c == '{' || c == '[') {
+				if f.atLineStart {
+					f.state = fencePassthrough
+					out.WriteString(chunk[i:])
+					return out.String()
+				}
+				f.state = fenceCandidate
+				f.pending = append(f.pending[:0], c)
+				continue
+			}
+			if f.captureBefore {
+				f.before = append(f.before, c)
+			}
+			if f.lineFence(c) {
+				f.state = fenceInfo
+				if f.captureBefore {
+					f.before = f.before[:len(f.before)-3] // The fence is not prose
+				}
+			}
+
+		case fenceCandidate:
+			switch {
+			case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+				f.pending = append(f.pending, c)
+				continue
+			case opensJSON(f.pending[0], c):
+				f.state = fencePassthrough
+				out.Write(f.pending)
+				out.WriteString(chunk[i:])
+				f.pending = nil
+				return out.String()
+			}
+			// Prose after all, which cannot complete a fence
+			f.state = fenceSearch
+			for _, b := range f.pending {
+				if f.captureBefore {
+					f.before = append(f.before, b)
+				}
+				f.lineFence(b)
+			}
+			i-- // Reprocess as prose
+
+		case fenceInfo:
+			if c == '\n' {
+				f.state = fenceInside
+				f.atLineStart = true
+			}
+
+		case fenceInside:
+			if f.atLineStart && c == fenceMarker {
+				f.ticks++
+				if f.ticks == 3 {
+					f.state = fenceDone
+					if f.captureAfter {
+						f.after = append(f.after, chunk[i+1:]...)
+					}
+					return out.String()
+				}
+				continue
+			}
+			// Markers that did not make up a fence are content
+			for ; f.ticks > 0; f.ticks-- {
+				out.WriteByte(fenceMarker)
+			}
+			f.trackLineStart(c)
+			out.WriteByte(c)
+
+		case fencePassthrough:
+			out.WriteString(chunk[i:])
+			return out.String()
+
+		case fenceDone:
+			if f.captureAfter {
+				f.after = append(f.after, chunk[i:]...)
+			}
+			return out.String()
+		}
+	}
+
+	return out.String()
+}
+
+// opensJSON reports whether c, the first non-whitespace byte after the
+// bracket open, makes the bracket look like the start of a JSON payload
+func opensJSON(open, c byte) bool {
+	if open == '{' {
+		return c == '"' || c == '}'
+	}
+	return c == '{' || c == '[' || c == '"' || c == ']'
+}
+
+// lineFence tracks fence markers at the start of a prose line and reports
+// whether c completes an opening fence
+func (f *codeFenceFilter) lineFence(c byte) bool {
+	if f.atLineStart && c == fenceMarker {
+		f.ticks++
+		if f.ticks == 3 {
+			f.ticks = 0
+			f.atLineStart = false
+			return true
+		}
+		return false
+	}
+	f.ticks = 0
+	f.trackLineStart(c)
+	return false
+}
+
+// trackLineStart updates whether the filter is at the start of a line
+func (f *codeFenceFilter) trackLineStart(c byte) {
+	switch c {
+	case '\n':
+		f.atLineStart = true
+	case ' ', '\t', '\r':
+		// Indentation keeps the line start
+	default:
+		f.atLineStart = false
+	}
+}
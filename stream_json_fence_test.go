@@ -0,0 +1,107 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+// appendBytewise appends input one byte at a time
+func appendBytewise(parser *StreamJSONParser, input string) {
+	for i := 0; i < len(input); i++ {
+		parser.Append(input[i : i+1])
+	}
+}
+
+func TestCodeFenceExtraction(t *testing.T) {
+	input := "Sure! Here is the [requested] {data}:\n\n```json\n{\"code\":\"use ```go fences\",\"n\":1}\n```\n\nLet me know {if} you need more."
+
+	for _, bytewise := range []bool{false, true} {
+		parser := NewStreamJSONParser(WithCodeFenceExtraction())
+		if bytewise {
+			appendBytewise(parser, input)
+		} else {
+			parser.Append(input)
+		}
+
+		if !parser.IsCompleted() {
+			t.Errorf("Expected parser to be completed (bytewise=%v)", bytewise)
+		}
+
+		if parser.Get("code") != "use ```go fences" {
+			t.Errorf("Expected code from fenced block (bytewise=%v), got %v", bytewise, parser.Get("code"))
+		}
+
+		if parser.Get("n") != int64(1) {
+			t.Errorf("Expected n to be 1 (bytewise=%v), got %v", bytewise, parser.Get("n"))
+		}
+	}
+}
+
+func TestCodeFenceExtractionBareJSON(t *testing.T) {
+	// Unfenced payloads pass straight through
+	parser := NewStreamJSONParser(WithCodeFenceExtraction())
+	parser.Append("  \n[1,2]")
+	if parser.Get("1") != int64(2) {
+		t.Errorf("Expected bare array to parse, got %v", parser.Get("1"))
+	}
+
+	// A line starting with JSON after prose is picked up without a fence
+	parser = NewStreamJSONParser(WithCodeFenceExtraction())
+	appendBytewise(parser, "The result {is}:\n  {\"ok\":true}")
+	if parser.Get("ok") != true {
+		t.Errorf("Expected ok to be true, got %v", parser.Get("ok"))
+	}
+
+	// Without a fence, JSON after prose on the same line is picked up too
+	inputs := map[string]string{
+		`Sure! {"a":1} bye`:          "a",
+		"Result: [ \n {\"a\":1}]":    "0.a",
+		`See [note] {here}: {"a":1}`: "a",
+	}
+	for input, path := range inputs {
+		for _, bytewise := range []bool{false, true} {
+			parser := NewStreamJSONParser(WithCodeFenceExtraction(), WithCaptureLeadingText())
+			if bytewise {
+				appendBytewise(parser, input)
+			} else {
+				parser.Append(input)
+			}
+			if parser.GetPath(path) != int64(1) {
+				t.Errorf("Expected %s to be 1 in %q (bytewise=%v), got %v", path, input, bytewise, parser.GetPath(path))
+			}
+			if leading := parser.LeadingText(); strings.ContainsAny(leading, `"1`) {
+				t.Errorf("Expected only prose as leading text of %q, got %q", input, leading)
+			}
+		}
+	}
+}
+
+func TestCodeFenceExtractionPartialAccess(t *testing.T) {
+	parser := NewStreamJSONParser(WithCodeFenceExtraction())
+
+	parser.Append("```js")
+	parser.Append("on\n{\"message\":\"Hel")
+	if parser.Get("message") != "Hel" {
+		t.Errorf("Expected partial message 'Hel', got %v", parser.Get("message"))
+	}
+
+	parser.Append("lo\"}\n`")
+	parser.Append("``")
+	if parser.Get("message") != "Hello" || !parser.IsCompleted() {
+		t.Errorf("Expected completed message 'Hello', got %v", parser.Get("message"))
+	}
+}
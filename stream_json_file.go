@@ -0,0 +1,57 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"os"
+)
+
+// ParseFile reads the whole file at path and parses it with ParseBytes. It
+// returns the read error, if any; parse errors are reported by Err.
+func (p *StreamJSONParser) ParseFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	p.ParseBytes(data)
+	return nil
+}
+
+// ParseBytes parses data as the complete input and finishes the stream, for
+// documents that are already whole, such as large files in batch jobs. The
+// parser reads data in place instead of copying it, so it must not be
+// modified afterwards. Since nothing can follow, a value ending with data is
+// complete rather than streaming, and the input is never compacted, so
+// GetRaw works for every completed value.
+func (p *StreamJSONParser) ParseBytes(data []byte) {
+	defer p.guardWrite("ParseBytes")()
+	if p.finished {
+		return
+	}
+
+	// Fenced and size-limited input, or input following earlier chunks,
+	// goes through the buffer like any other chunk
+	if p.fence != nil || p.options.maxBufferSize > 0 || len(p.tokenizer.buffer) > 0 {
+		p.AppendBytes(data)
+		p.Finish()
+		return
+	}
+
+	p.version++
+	p.tokenizer.load(data)
+	p.processTokens()
+	p.checkTrailingText()
+	p.Finish()
+}
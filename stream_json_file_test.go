@@ -0,0 +1,119 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseBytesMatchesStreaming(t *testing.T) {
+	inputs := []string{
+		`{"name":"Alice","tags":["a","b"],"score":9.5,"ok":true,"none":null}`,
+		`[1, 2, {"nested": [3, {"deep": "x"}]}]`,
+		`{"truncated": "no end`,
+		`{"count": 12`,
+		` {"a": 1} trailing`,
+	}
+
+	for _, input := range inputs {
+		streamed := NewStreamJSONParser()
+		streamed.Append(input)
+		streamed.Finish()
+
+		parser := NewStreamJSONParser()
+		parser.ParseBytes([]byte(input))
+
+		if !reflect.DeepEqual(parser.Get(), streamed.Get()) {
+			t.Errorf("ParseBytes(%q) = %v, streaming gave %v", input, parser.Get(), streamed.Get())
+		}
+		if parser.Completion() != streamed.Completion() {
+			t.Errorf("ParseBytes(%q) completion %v, streaming gave %v", input, parser.Completion(), streamed.Completion())
+		}
+	}
+}
+
+func TestParseBytesEndsValues(t *testing.T) {
+	// A number ending with the input needs no terminator
+	parser := NewStreamJSONParser(WithScalarRoots())
+	parser.ParseBytes([]byte("42"))
+	if parser.Get() != int64(42) || !parser.IsCompleted() {
+		t.Errorf("Expected a completed 42, got %v", parser.Get())
+	}
+
+	parser = NewStreamJSONParser(WithRepair())
+	parser.ParseBytes([]byte("{a: True"))
+	if parser.Get("a") != true {
+		t.Errorf("Expected a bare literal at the end to be complete, got %v", parser.Get("a"))
+	}
+}
+
+func TestParseBytesKeepsInput(t *testing.T) {
+	data := []byte(`{"user": {"id": 7}, "items": [1, 2]}`)
+	original := string(data)
+
+	parser := NewStreamJSONParser()
+	parser.ParseBytes(data)
+
+	// Nothing is compacted away
+	if raw := string(parser.GetRaw("user")); raw != `{"id": 7}` {
+		t.Errorf("Expected the raw user object, got %q", raw)
+	}
+
+	// Further content and reuse never write to the caller's bytes
+	parser.Append(`{"more": true}`)
+	parser.Reset()
+	parser.Append(`{"other": "document of some length"}`)
+	if string(data) != original {
+		t.Errorf("Expected the input to be left unchanged, got %q", data)
+	}
+	if parser.Get("other") != "document of some length" {
+		t.Errorf("Expected the reset parser to parse new input, got %v", parser.Get())
+	}
+}
+
+func TestParseBytesAfterAppend(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": "he`)
+	parser.ParseBytes([]byte(`llo", "b": 2}`))
+
+	if parser.Get("a") != "hello" || parser.Get("b") != int64(2) || !parser.IsCompleted() {
+		t.Errorf("Expected the input to continue the stream, got %v", parser.Get())
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(path, []byte(`{"records": [{"id": 1}, {"id": 2}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewSafeStreamJSONParser()
+	if err := parser.ParseFile(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id, ok := parser.GetInt("records", "1", "id"); !ok || id != 2 {
+		t.Errorf("Expected records.1.id to be 2, got %v", parser.Get("records"))
+	}
+
+	missing := NewStreamJSONParser()
+	if err := missing.ParseFile(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+}
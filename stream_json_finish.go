@@ -0,0 +1,105 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// CompletionState describes how far a document got
+type CompletionState int
+
+const (
+	NotStarted CompletionState = iota // No document has started yet
+	InProgress                        // A document is still streaming
+	Complete                          // The root closed, or every document did in multi-document mode
+	Truncated                         // Finish was called before the root closed
+)
+
+// Finish signals the end of the input, for streams that may be cut off.
+// A number or string still streaming is completed with the content
+// received so far, open objects and arrays are marked Truncated, and the
+// event stream and pending Watch channels are closed. Content appended
+// afterwards is ignored.
+func (p *StreamJSONParser) Finish() {
+	defer p.guardWrite("Finish")()
+	if p.finished {
+		return
+	}
+
+	if p.err == nil && !p.IsCompleted() {
+		// Terminate a number at the end of the input
+		if t := p.tokenizer; t.lastToken != nil && t.lastToken.TokenType == Number {
+			t.Append(" ")
+			p.processTokens()
+		}
+		p.finishString()
+
+		for _, frame := range p.stack {
+			frame.Node.Truncated = true
+			p.truncated = true
+		}
+	}
+	p.finished = true
+
+	// The channel is already closed once a single document has completed
+	if p.events != nil && !(p.IsCompleted() && p.closesAtRoot()) {
+		close(p.events)
+	}
+	p.closeWatches()
+}
+
+// finishString completes the string streaming into the innermost open
+// value, or the scalar root, with its partial content
+func (p *StreamJSONParser) finishString() {
+	var node *Node
+	var path []string
+	if len(p.stack) > 0 {
+		frame := p.stack[len(p.stack)-1]
+		if p.skimDepth > 0 {
+			return // The placeholder of a skipped value is not a string
+		}
+		node = p.partialNode(frame)
+		if node != nil && p.tracksValuePaths() {
+			path = p.childPath(frame)
+		}
+	} else if p.started && !p.root.Completed {
+		node = p.root
+	}
+
+	if node == nil {
+		return
+	}
+	if _, ok := node.Value.(string); !ok {
+		return
+	}
+	node.Completed = true
+	p.truncated = true
+	p.nodeCompleted(path, node, node.Value)
+}
+
+// Completion reports whether the document is complete, still streaming, or
+// was cut off before Finish. A string completed by Finish leaves the
+// document Truncated even when that string was the whole document.
+func (p *StreamJSONParser) Completion() CompletionState {
+	defer p.guardRead("Completion")()
+	switch {
+	case p.truncated:
+		return Truncated
+	case p.IsCompleted():
+		return Complete
+	case p.started:
+		return InProgress
+	case p.documentCount > 0:
+		return Complete
+	}
+	return NotStarted
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserFinish(t *testing.T) {
+	parser := NewStreamJSONParser()
+	if parser.Completion() != NotStarted {
+		t.Errorf("Expected NotStarted, got %v", parser.Completion())
+	}
+
+	parser.Append(`{"title": "Report", "items": [1, 2], "summary": "The results were`)
+	if parser.Completion() != InProgress {
+		t.Errorf("Expected InProgress, got %v", parser.Completion())
+	}
+
+	parser.Finish()
+
+	if parser.Completion() != Truncated {
+		t.Errorf("Expected Truncated, got %v", parser.Completion())
+	}
+	if parser.IsCompleted() {
+		t.Error("Expected a truncated document not to be completed")
+	}
+	if parser.GetCompleted("summary") != "The results were" {
+		t.Errorf("Expected the partial string to be completed, got %v", parser.GetCompleted("summary"))
+	}
+	if !parser.GetRoot().Truncated || parser.GetNode("items").Truncated {
+		t.Error("Expected only the open root to be marked truncated")
+	}
+
+	// Content after Finish is ignored
+	parser.Append(` good."}`)
+	if parser.Get("summary") != "The results were" || parser.IsCompleted() {
+		t.Errorf("Expected appends after Finish to be ignored, got %v", parser.Get("summary"))
+	}
+}
+
+func TestStreamJSONParserFinishNumber(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"count": 12`)
+	if parser.Get("count") != nil {
+		t.Fatalf("Expected the number to be pending, got %v", parser.Get("count"))
+	}
+
+	parser.Finish()
+	if parser.Get("count") != int64(12) {
+		t.Errorf("Expected the number to be completed, got %v", parser.Get("count"))
+	}
+
+	// A scalar root number cannot be told from a truncated one
+	scalar := NewStreamJSONParser(WithScalarRoots())
+	scalar.Append(`42`)
+	scalar.Finish()
+	if scalar.Completion() != Complete || scalar.Get() != int64(42) {
+		t.Errorf("Expected a complete scalar root, got %v %v", scalar.Completion(), scalar.Get())
+	}
+}
+
+func TestStreamJSONParserFinishComplete(t *testing.T) {
+	parser := NewStreamJSONParser()
+	events := parser.Events()
+	parser.Append(`{"a": 1}`)
+	parser.Finish()
+
+	if parser.Completion() != Complete || parser.GetRoot().Truncated {
+		t.Errorf("Expected Complete, got %v", parser.Completion())
+	}
+	for range events {
+	}
+}
+
+func TestStreamJSONParserFinishClosesChannels(t *testing.T) {
+	parser := NewStreamJSONParser()
+	events := parser.Events()
+	watch := parser.Watch("items")
+	parser.Append(`{"items": [1, "tw`)
+	parser.Finish()
+
+	var last Event
+	for event := range events {
+		last = event
+	}
+	if last.Type != ValueCompleted || last.Value != "tw" {
+		t.Errorf("Expected the completed string as last event, got %+v", last)
+	}
+	for range watch {
+	}
+
+	// Channels requested after Finish are closed
+	if _, ok := <-parser.Watch("missing"); ok {
+		t.Error("Expected the watch channel to be closed")
+	}
+	if _, ok := <-parser.Events(); ok {
+		t.Error("Expected the event channel to be closed")
+	}
+
+	parser.Reset()
+	parser.Append(`[1]`)
+	if parser.Completion() != Complete {
+		t.Errorf("Expected Reset to clear the finished state, got %v", parser.Completion())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// elementSubscription is a callback registered by ForEach
+type elementSubscription struct {
+	pattern  []string
+	callback func(index int, node *Node, path []string)
+}
+
+// ElementError reports an array element ForEach could not decode. The
+// element is skipped.
+type ElementError struct {
+	Path string // Dotted path of the element
+	Err  error  // The *json.UnmarshalTypeError, or the error of an UnmarshalJSON method
+}
+
+// Error implements the error interface
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("streamjson: decoding element %q failed: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the decoding error
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ForEach registers a callback for the elements of the array at a dotted
+// path, with "*" matching any single key or index and "" selecting the
+// root. Each element is decoded into a T as in Unmarshal once it completes,
+// and handed over with its index. Elements that do not decode are skipped
+// and reported by ElementErrors. Unlike StreamArray, elements stay in the
+// tree; register both to decode elements in flat memory.
+func ForEach[T any](p *StreamJSONParser, path string, callback func(index int, item T)) {
+	p.elementSubscriptions = append(p.elementSubscriptions, elementSubscription{
+		pattern: p.normalizePath(splitPath(path)),
+		callback: func(index int, node *Node, path []string) {
+			var item T
+			d := nodeDecoder{parser: p}
+			d.decode(node, reflect.ValueOf(&item).Elem(), path)
+			if d.err != nil {
+				p.elementErrors = append(p.elementErrors, &ElementError{Path: strings.Join(path, "."), Err: d.err})
+				return
+			}
+			callback(index, item)
+		},
+	})
+}
+
+// ElementErrors returns the array elements ForEach could not decode
+func (p *StreamJSONParser) ElementErrors() []*ElementError {
+	return p.elementErrors
+}
+
+// deliverElement hands a completed array element to the ForEach callbacks
+// matching its array
+func (p *StreamJSONParser) deliverElement(path []string, node *Node) {
+	if len(p.elementSubscriptions) == 0 || node.Parent == nil || node.Parent.Type != ArrayNode || len(path) == 0 {
+		return
+	}
+
+	index, err := strconv.Atoi(path[len(path)-1])
+	if err != nil {
+		return
+	}
+	for _, sub := range p.elementSubscriptions {
+		if matchPath(sub.pattern, path[:len(path)-1]) {
+			sub.callback(index, node, path)
+		}
+	}
+}
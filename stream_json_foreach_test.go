@@ -0,0 +1,105 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type forEachItem struct {
+	Name  string   `json:"name"`
+	Price float64  `json:"price"`
+	Tags  []string `json:"tags"`
+}
+
+func TestForEach(t *testing.T) {
+	parser := NewStreamJSONParser()
+	var items []forEachItem
+	var indices []int
+	ForEach(parser, "items", func(i int, item forEachItem) {
+		indices = append(indices, i)
+		items = append(items, item)
+	})
+
+	parser.Append(`{"items": [{"name": "pen", "price": 1.5, "tags": ["a"]}, {"name": "bo`)
+	if len(items) != 1 || items[0].Name != "pen" || items[0].Price != 1.5 || items[0].Tags[0] != "a" {
+		t.Fatalf("Expected the first item once complete, got %+v", items)
+	}
+
+	parser.Append(`ok", "price": 12}]}`)
+	if len(items) != 2 || items[1].Name != "book" || indices[1] != 1 {
+		t.Errorf("Expected the second item at index 1, got %+v at %v", items, indices)
+	}
+	if parser.Get("items", "0", "name") != "pen" {
+		t.Error("Expected elements to stay in the tree")
+	}
+}
+
+func TestForEachScalarsAndWildcards(t *testing.T) {
+	parser := NewStreamJSONParser()
+	var sum int
+	ForEach(parser, "groups.*.values", func(_ int, n int) {
+		sum += n
+	})
+	var roots []string
+	ForEach(parser, "", func(_ int, s string) {
+		roots = append(roots, s)
+	})
+
+	parser.Append(`{"groups": [{"values": [1, 2]}, {"values": [3]}]}`)
+	if sum != 6 {
+		t.Errorf("Expected the values of every group, got %d", sum)
+	}
+	if len(roots) != 0 {
+		t.Errorf("Expected no elements for an object root, got %v", roots)
+	}
+}
+
+func TestForEachDecodeErrors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	var items []forEachItem
+	ForEach(parser, "", func(_ int, item forEachItem) {
+		items = append(items, item)
+	})
+	parser.Append(`[{"name": "ok"}, {"name": 42}, {"name": "also ok"}]`)
+
+	if len(items) != 2 || items[1].Name != "also ok" {
+		t.Errorf("Expected the mismatched element to be skipped, got %+v", items)
+	}
+	errs := parser.ElementErrors()
+	var typeErr *json.UnmarshalTypeError
+	if len(errs) != 1 || errs[0].Path != "1" || !errors.As(errs[0], &typeErr) {
+		t.Errorf("Expected one element error at 1, got %v", errs)
+	}
+}
+
+func TestForEachWithStreamArray(t *testing.T) {
+	parser := NewStreamJSONParser()
+	var names []string
+	ForEach(parser, "items", func(i int, item forEachItem) {
+		names = append(names, item.Name)
+	})
+	parser.StreamArray("items", func(int, interface{}) {})
+	parser.Append(`{"items": [{"name": "a"}, {"name": "b"}]}`)
+
+	if len(names) != 2 || names[1] != "b" {
+		t.Errorf("Expected elements decoded before eviction, got %v", names)
+	}
+	if n, _ := parser.Len("items"); n != 0 {
+		t.Errorf("Expected StreamArray to evict the elements, %d left", n)
+	}
+}
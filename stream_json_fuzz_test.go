@@ -0,0 +1,157 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// Run with -tags streamjson_invariants so the parser checks its stack, AST
+// and token ranges after every token:
+//
+//	go test -tags streamjson_invariants -fuzz FuzzParserSplit
+
+var fuzzSeeds = []string{
+	`{"a":[1,{"b":"xéy"},true,null],"c":{"d":-1.5e3}}`,
+	`[1, 2, [3, [4]], {"k": "v"}]`,
+	`Here is the JSON: {"ok":true} trailing`,
+	"```json\n{\"a\":1}\n```",
+	`{"a":"\"quoted\\", "b":tru, "c":[1,,2], d: 'x'}`,
+	`{/* comment */ "a": 1 // line` + "\n}",
+	`"scalar" 42 true null`,
+	`{"a":1}{"b":2} [3]`,
+	`{"n":NaN,"i":-Infinity,"e":1e999}`,
+	`{"emoji":"😀","bad":"\ud83d"}`,
+	"{\"a\":\"\xff\xfe\"}",
+}
+
+// splitChunks cuts data into pseudo-random chunks determined by seed
+func splitChunks(data string, seed uint64) []string {
+	rng := rand.New(rand.NewPCG(seed, seed>>32))
+	var chunks []string
+	for len(data) > 0 {
+		n := min(1+rng.IntN(8), len(data))
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	return chunks
+}
+
+// fuzzOptions picks parser options from the bits of flags
+func fuzzOptions(flags uint16) []Option {
+	all := []Option{
+		WithRepair(), WithComments(), WithScalarRoots(), WithMultipleDocuments(),
+		WithRecovery(), WithLenientKeys(), WithStrictMode(), WithPartialNumbers(),
+		WithCodeFenceExtraction(), WithNonFiniteNumbers(nil, "inf", "-inf"),
+	}
+	var options []Option
+	for i, option := range all {
+		if flags&(1<<i) != 0 {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// completeTokens reads the complete tokens of data appended in chunks
+func completeTokens(t *testing.T, chunks []string) []Token {
+	tokenizer := NewStreamJSONTokenizer()
+	var tokens []Token
+	end := 0
+	for _, chunk := range chunks {
+		tokenizer.Append(chunk)
+		for {
+			token := tokenizer.NextToken()
+			if tokenizer.position > len(tokenizer.buffer) {
+				t.Fatalf("Position %d beyond buffer length %d", tokenizer.position, len(tokenizer.buffer))
+			}
+			if token.TokenType == EOF || !token.Completed {
+				break
+			}
+			if token.TokenStart < end || token.TokenEnd < token.TokenStart {
+				t.Fatalf("Token %v out of order after offset %d", token, end)
+			}
+			end = token.TokenEnd
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func FuzzTokenizerSplit(f *testing.F) {
+	for i, seed := range fuzzSeeds {
+		f.Add(seed, uint64(i))
+	}
+
+	f.Fuzz(func(t *testing.T, data string, seed uint64) {
+		whole := completeTokens(t, []string{data})
+		split := completeTokens(t, splitChunks(data, seed))
+		if !reflect.DeepEqual(whole, split) {
+			t.Errorf("Tokens differ when split:\nwhole %v\nsplit %v", whole, split)
+		}
+	})
+}
+
+// parseChunks parses data appended in chunks, checking the invariants after
+// every chunk, and finishes the stream
+func parseChunks(t *testing.T, chunks []string, options []Option) *StreamJSONParser {
+	parser := NewStreamJSONParser(options...)
+	for _, chunk := range chunks {
+		parser.Append(chunk)
+		if err := parser.checkInvariants(0); err != nil {
+			t.Fatalf("After %q: %v", chunk, err)
+		}
+		var invariantErr *InvariantError
+		if errors.As(parser.Err(), &invariantErr) {
+			t.Fatalf("After %q: %v", chunk, invariantErr)
+		}
+	}
+	return parser
+}
+
+func FuzzParserSplit(f *testing.F) {
+	for i, seed := range fuzzSeeds {
+		f.Add(seed, uint64(i), uint16(0))
+		f.Add(seed, uint64(i), uint16(0x3ff))
+		f.Add(seed, uint64(i), uint16(1<<i))
+	}
+
+	f.Fuzz(func(t *testing.T, data string, seed uint64, flags uint16) {
+		options := fuzzOptions(flags)
+		whole := parseChunks(t, []string{data}, options)
+		split := parseChunks(t, splitChunks(data, seed), options)
+
+		compare := func(stage string) {
+			if !reflect.DeepEqual(whole.Get(), split.Get()) {
+				t.Fatalf("%s: values differ when split:\nwhole %#v\nsplit %#v", stage, whole.Get(), split.Get())
+			}
+			if !reflect.DeepEqual(whole.Documents(), split.Documents()) {
+				t.Fatalf("%s: documents differ when split:\nwhole %#v\nsplit %#v", stage, whole.Documents(), split.Documents())
+			}
+			if whole.IsCompleted() != split.IsCompleted() || (whole.Err() == nil) != (split.Err() == nil) {
+				t.Fatalf("%s: state differs when split: completed %v/%v, error %v/%v",
+					stage, whole.IsCompleted(), split.IsCompleted(), whole.Err(), split.Err())
+			}
+		}
+
+		compare("streaming")
+		whole.Finish()
+		split.Finish()
+		compare("finished")
+	})
+}
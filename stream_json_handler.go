@@ -0,0 +1,229 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"io"
+)
+
+// Handler receives the structure of a document as it is parsed by a
+// HandlerParser, without a tree being built. Keys are decoded and
+// normalized, and values are strings, numbers in the parser's number mode,
+// booleans or nil. Returning an error stops parsing with that error.
+type Handler interface {
+	OnObjectStart() error
+	OnObjectEnd() error
+	OnArrayStart() error
+	OnArrayEnd() error
+	OnKey(key string) error
+	OnValue(value interface{}) error
+}
+
+// BaseHandler implements Handler with methods that do nothing, for
+// embedding in handlers that only need some of them
+type BaseHandler struct{}
+
+func (BaseHandler) OnObjectStart() error            { return nil }
+func (BaseHandler) OnObjectEnd() error              { return nil }
+func (BaseHandler) OnArrayStart() error             { return nil }
+func (BaseHandler) OnArrayEnd() error               { return nil }
+func (BaseHandler) OnKey(key string) error          { return nil }
+func (BaseHandler) OnValue(value interface{}) error { return nil }
+
+// HandlerParser parses streamed input into calls of a Handler instead of a
+// tree, for high-throughput consumers that route values as they complete
+// and cannot afford the memory of the tree. Memory use is bounded by the
+// nesting depth and the longest pending token. Values are reported once
+// complete; strings are not exposed while they stream.
+//
+// Options about the input and values apply, such as WithRepair,
+// WithLenientKeys, WithComments, WithCodeFenceExtraction, WithRawStrings,
+// WithNumberMode, WithNonFiniteNumbers, WithKeyNormalizer,
+// WithScalarRoots, WithMultipleDocuments, WithMaxDepth, WithMaxBufferSize
+// and WithTokenInterceptor, which only sees complete tokens. Options about
+// the tree are ignored. Text before the root is skipped, and a closing
+// bracket that does not match the open container is ignored.
+type HandlerParser struct {
+	handler  Handler
+	parser   *StreamJSONParser // Tokenizer, options and value conversion; its tree stays empty
+	open     []bool            // Whether each open container is an object, innermost last
+	done     bool              // Whether the root completed, in single-document mode
+	finished bool              // Whether Finish was called
+}
+
+// NewHandlerParser creates a parser that reports to handler, configured by
+// opts
+func NewHandlerParser(handler Handler, opts ...Option) *HandlerParser {
+	return &HandlerParser{
+		handler: handler,
+		parser:  NewStreamJSONParser(opts...),
+	}
+}
+
+// Append adds more content and reports the tokens it completes
+func (h *HandlerParser) Append(content string) {
+	if h.finished {
+		return
+	}
+	p := h.parser
+	if p.fence != nil {
+		content = p.fence.filter(content)
+	}
+	p.tokenizer.Append(content)
+	h.process()
+	p.compact()
+}
+
+// AppendBytes is like Append for input that arrives as bytes
+func (h *HandlerParser) AppendBytes(data []byte) {
+	if h.finished {
+		return
+	}
+	if h.parser.fence != nil {
+		h.Append(string(data))
+		return
+	}
+	h.parser.tokenizer.AppendBytes(data)
+	h.process()
+	h.parser.compact()
+}
+
+// Finish signals the end of the input, completing a number at the end. It
+// returns Err, or io.ErrUnexpectedEOF if the input ended inside the root.
+// Content appended afterwards is ignored.
+func (h *HandlerParser) Finish() error {
+	p := h.parser
+	if !h.finished {
+		h.finished = true
+		if t := p.tokenizer; t.lastToken != nil && t.lastToken.TokenType == Number {
+			t.Append(" ")
+			h.process()
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	// An open container, or a scalar root still streaming
+	if pending := p.tokenizer.lastToken; len(h.open) > 0 || p.options.scalarRoots && pending != nil && pending.TokenType == String {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// Err returns the error that stopped parsing: one returned by the handler,
+// or a limit such as ErrDepthLimit
+func (h *HandlerParser) Err() error {
+	return h.parser.err
+}
+
+// Parse reads r until io.EOF and reports the document to handler, see
+// HandlerParser. It returns the first read or handler error, or
+// io.ErrUnexpectedEOF if the input ended inside the root.
+func Parse(r io.Reader, handler Handler, opts ...Option) error {
+	h := NewHandlerParser(handler, opts...)
+	buf := make([]byte, readChunkSize)
+	for h.Err() == nil {
+		n, err := r.Read(buf)
+		if n > 0 {
+			h.AppendBytes(buf[:n])
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return h.Finish()
+}
+
+// process reports the complete tokens available
+func (h *HandlerParser) process() {
+	p := h.parser
+	for p.err == nil && !h.done {
+		token := p.tokenizer.NextToken()
+		if token.TokenType == EOF || !token.Completed {
+			return
+		}
+		token, ok := p.intercept(token)
+		if !ok {
+			return
+		}
+		if err := h.handle(token); err != nil {
+			p.err = err
+		}
+	}
+}
+
+// handle reports a complete token to the handler
+func (h *HandlerParser) handle(token Token) error {
+	p := h.parser
+	switch token.TokenType {
+	case ObjectStart, ArrayStart:
+		if p.options.maxDepth > 0 && len(h.open) >= p.options.maxDepth {
+			return ErrDepthLimit
+		}
+		isObject := token.TokenType == ObjectStart
+		h.open = append(h.open, isObject)
+		if isObject {
+			return h.handler.OnObjectStart()
+		}
+		return h.handler.OnArrayStart()
+
+	case ObjectEnd, ArrayEnd:
+		isObject := token.TokenType == ObjectEnd
+		if len(h.open) == 0 || h.open[len(h.open)-1] != isObject {
+			return nil
+		}
+		h.open = h.open[:len(h.open)-1]
+		var err error
+		if isObject {
+			err = h.handler.OnObjectEnd()
+		} else {
+			err = h.handler.OnArrayEnd()
+		}
+		if len(h.open) == 0 {
+			h.endRoot()
+		}
+		return err
+
+	case ObjectKey:
+		if len(h.open) == 0 || !h.open[len(h.open)-1] {
+			return nil
+		}
+		content := token.Content
+		if isQuoted(content) {
+			content = p.stringContent(content[1:len(content)-1], false)
+		}
+		return h.handler.OnKey(p.normalizeKey(content))
+
+	case String, Number, Bool, Null:
+		if len(h.open) == 0 {
+			if !p.options.scalarRoots {
+				return nil // Text before the root
+			}
+			defer h.endRoot()
+		}
+		return h.handler.OnValue(p.parseTokenValue(token))
+	}
+	return nil // Colons, commas and invalid input
+}
+
+// endRoot stops after the completed root unless documents follow
+func (h *HandlerParser) endRoot() {
+	if !h.parser.options.multipleDocuments {
+		h.done = true
+	}
+}
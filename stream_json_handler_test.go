@@ -0,0 +1,171 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingHandler records the calls it receives
+type recordingHandler struct {
+	calls []string
+	stop  string // Key whose value stops parsing
+}
+
+func (h *recordingHandler) OnObjectStart() error { h.calls = append(h.calls, "{"); return nil }
+func (h *recordingHandler) OnObjectEnd() error   { h.calls = append(h.calls, "}"); return nil }
+func (h *recordingHandler) OnArrayStart() error  { h.calls = append(h.calls, "["); return nil }
+func (h *recordingHandler) OnArrayEnd() error    { h.calls = append(h.calls, "]"); return nil }
+
+func (h *recordingHandler) OnKey(key string) error {
+	h.calls = append(h.calls, "key "+key)
+	if key == h.stop {
+		return errStopHandler
+	}
+	return nil
+}
+
+func (h *recordingHandler) OnValue(value interface{}) error {
+	h.calls = append(h.calls, fmt.Sprintf("%T %v", value, value))
+	return nil
+}
+
+var errStopHandler = errors.New("stop")
+
+func TestHandlerParser(t *testing.T) {
+	input := `Here you go: {"name": "Ada é", "tags": ["a", 1, 2.5, true, null], "nested": {"x": {}}} trailing`
+	want := []string{
+		"{", "key name", "string Ada é",
+		"key tags", "[", "string a", "int64 1", "float64 2.5", "bool true", "<nil> <nil>", "]",
+		"key nested", "{", "key x", "{", "}", "}",
+		"}",
+	}
+
+	// Whole, and one byte at a time
+	for _, size := range []int{len(input), 1} {
+		handler := &recordingHandler{}
+		parser := NewHandlerParser(handler)
+		for start := 0; start < len(input); start += size {
+			parser.Append(input[start:min(start+size, len(input))])
+		}
+		if err := parser.Finish(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(handler.calls, want) {
+			t.Errorf("Chunks of %d: expected %v, got %v", size, want, handler.calls)
+		}
+	}
+}
+
+func TestHandlerParserOptions(t *testing.T) {
+	handler := &recordingHandler{}
+	parser := NewHandlerParser(handler,
+		WithNumberMode(NumberAsJSONNumber),
+		WithKeyNormalizer(SnakeCaseKey),
+		WithMultipleDocuments(),
+	)
+	parser.Append(`{"userId": 12345678901234567890} [3`)
+	if err := parser.Finish(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF for an open array, got %v", err)
+	}
+
+	want := []string{"{", "key user_id", "json.Number 12345678901234567890", "}", "[", "json.Number 3"}
+	if !reflect.DeepEqual(handler.calls, want) {
+		t.Errorf("Expected %v, got %v", want, handler.calls)
+	}
+
+	handler = &recordingHandler{}
+	parser = NewHandlerParser(handler, WithScalarRoots())
+	parser.Append(`42`)
+	if err := parser.Finish(); err != nil || !reflect.DeepEqual(handler.calls, []string{"int64 42"}) {
+		t.Errorf("Expected a scalar root, got %v, %v", handler.calls, err)
+	}
+}
+
+func TestHandlerParserErrors(t *testing.T) {
+	handler := &recordingHandler{stop: "b"}
+	parser := NewHandlerParser(handler)
+	parser.Append(`{"a": 1, "b": 2, "c": 3}`)
+	if !errors.Is(parser.Err(), errStopHandler) || !errors.Is(parser.Finish(), errStopHandler) {
+		t.Errorf("Expected the handler's error, got %v", parser.Err())
+	}
+	if want := []string{"{", "key a", "int64 1", "key b"}; !reflect.DeepEqual(handler.calls, want) {
+		t.Errorf("Expected parsing to stop at the error, got %v", handler.calls)
+	}
+
+	parser = NewHandlerParser(BaseHandler{}, WithMaxDepth(2))
+	parser.Append(`[[[1]]]`)
+	if !errors.Is(parser.Err(), ErrDepthLimit) {
+		t.Errorf("Expected ErrDepthLimit, got %v", parser.Err())
+	}
+
+	// A closing bracket that does not match is skipped
+	handler = &recordingHandler{}
+	parser = NewHandlerParser(handler)
+	parser.Append(`{"a": [1}]}`)
+	if want := []string{"{", "key a", "[", "int64 1", "]", "}"}; !reflect.DeepEqual(handler.calls, want) {
+		t.Errorf("Expected %v, got %v", want, handler.calls)
+	}
+}
+
+// sumHandler adds up the numbers of a document
+type sumHandler struct {
+	BaseHandler
+	sum float64
+}
+
+func (h *sumHandler) OnValue(value interface{}) error {
+	if f, ok := numberToFloat64(value); ok {
+		h.sum += f
+	}
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	var numbers []int
+	for i := 0; i < 5000; i++ {
+		numbers = append(numbers, i)
+	}
+	data, _ := json.Marshal(map[string]interface{}{"numbers": numbers})
+
+	handler := &sumHandler{}
+	if err := Parse(strings.NewReader(string(data)), handler); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if handler.sum != 5000*4999/2 {
+		t.Errorf("Expected sum %d, got %v", 5000*4999/2, handler.sum)
+	}
+
+	if err := Parse(strings.NewReader(`{"a": [1, 2`), BaseHandler{}); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func BenchmarkHandlerParser(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkInput)))
+	for i := 0; i < b.N; i++ {
+		parser := NewHandlerParser(BaseHandler{})
+		for start := 0; start < len(benchmarkInput); start += 64 {
+			parser.Append(benchmarkInput[start:min(start+64, len(benchmarkInput))])
+		}
+	}
+}
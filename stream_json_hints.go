@@ -0,0 +1,283 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of value a Shape expects at a path
+type Kind int
+
+const (
+	AnyKind           Kind = iota // Any value
+	StringKind                    // A string
+	NumberKind                    // A number
+	BoolKind                      // true or false
+	ObjectKind                    // An object
+	ArrayKind                     // An array of any values
+	ArrayOfStringKind             // An array of strings
+	ArrayOfNumberKind             // An array of numbers
+	ArrayOfBoolKind               // An array of booleans
+	ArrayOfObjectKind             // An array of objects
+)
+
+var kindNames = [...]string{"any", "string", "number", "bool", "object", "array",
+	"array of strings", "array of numbers", "array of bools", "array of objects"}
+
+// String returns a readable name for the kind
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// element returns the kind of the elements of an array kind
+func (k Kind) element() Kind {
+	switch k {
+	case ArrayOfStringKind:
+		return StringKind
+	case ArrayOfNumberKind:
+		return NumberKind
+	case ArrayOfBoolKind:
+		return BoolKind
+	case ArrayOfObjectKind:
+		return ObjectKind
+	}
+	return AnyKind
+}
+
+// isArray reports whether k is one of the array kinds
+func (k Kind) isArray() bool {
+	return k >= ArrayKind
+}
+
+// Shape declares the kind of value expected at dotted paths, such as
+// {"title": StringKind, "tags": ArrayOfStringKind, "items.*.price": NumberKind}.
+// Passed to WithShape, it makes the parser fix common mistakes of models
+// as values complete: a scalar where an array is expected is wrapped into a
+// one-element array, a number or bool where a string is expected becomes
+// its text, and a string holding a number or a bool where one is expected
+// is converted. Other deviations are left as they are. Both are reported by
+// ShapeDeviations. null and values set by transformers are accepted for any
+// kind.
+type Shape map[string]Kind
+
+// shapeHint is a path pattern of a Shape with its expected kind
+type shapeHint struct {
+	pattern []string
+	kind    Kind
+}
+
+// hints returns the patterns of the shape, sorted by path
+func (s Shape) hints() []shapeHint {
+	hints := make([]shapeHint, 0, len(s))
+	for path, kind := range s {
+		hints = append(hints, shapeHint{pattern: splitPath(path), kind: kind})
+	}
+	sort.Slice(hints, func(i, j int) bool {
+		return strings.Join(hints[i].pattern, ".") < strings.Join(hints[j].pattern, ".")
+	})
+	return hints
+}
+
+// ShapeDeviation is a value that did not have the kind its Shape declares
+type ShapeDeviation struct {
+	Path     string // Dotted path of the value, "" for the root
+	Offset   int    // Input offset where the value starts
+	Expected Kind   // Kind declared for the path
+	Got      string // JSON type received, such as "string" or "object"
+	Coerced  bool   // Whether the value was converted to the expected kind
+}
+
+// Error implements the error interface
+func (e *ShapeDeviation) Error() string {
+	action := "kept"
+	if e.Coerced {
+		action = "coerced"
+	}
+	return fmt.Sprintf("streamjson: expected %s at %q (offset %d), got %s (%s)", e.Expected, e.Path, e.Offset, e.Got, action)
+}
+
+// ShapeDeviations returns the values found so far that deviate from the
+// Shape passed to WithShape, including those that were coerced
+func (p *StreamJSONParser) ShapeDeviations() []*ShapeDeviation {
+	return p.shapeDeviations
+}
+
+// hintAt returns the kind expected at path. A pattern with fewer wildcards
+// wins, and elements of an array kind get its element kind.
+func (p *StreamJSONParser) hintAt(path []string) (Kind, bool) {
+	kind, wildcards := AnyKind, -1
+	for _, hint := range p.options.hints {
+		if !matchPath(hint.pattern, path) {
+			continue
+		}
+		n := 0
+		for _, segment := range hint.pattern {
+			if segment == pathWildcard {
+				n++
+			}
+		}
+		if wildcards < 0 || n < wildcards {
+			kind, wildcards = hint.kind, n
+		}
+	}
+	if wildcards >= 0 {
+		return kind, true
+	}
+
+	if len(path) > 0 && isIndex(path[len(path)-1]) {
+		if parent, ok := p.hintAt(path[:len(path)-1]); ok && parent.isArray() {
+			return parent.element(), true
+		}
+	}
+	return AnyKind, false
+}
+
+// checkHintStarted reports an object or array starting at path where the
+// shape expects another kind
+func (p *StreamJSONParser) checkHintStarted(path []string, node *Node) {
+	kind, ok := p.hintAt(path)
+	if !ok || kind == AnyKind {
+		return
+	}
+	if node.Type == ObjectNode && kind != ObjectKind || node.Type == ArrayNode && !kind.isArray() {
+		p.shapeDeviation(path, node, kind, false)
+	}
+}
+
+// checkHintCompleted coerces the value completed at path to the kind the
+// shape expects, if it can, and reports a deviation. It returns the node
+// now holding the value, which is a new array when the value was wrapped.
+func (p *StreamJSONParser) checkHintCompleted(path []string, node *Node) *Node {
+	kind, ok := p.hintAt(path)
+	if !ok || kind == AnyKind || node.Value == nil {
+		return node
+	}
+	got, ok := valueKind(node.Value)
+	if !ok || got == kind {
+		return node
+	}
+
+	if kind.isArray() {
+		p.shapeDeviation(path, node, kind, true)
+		array := p.wrapInArray(path, node)
+		p.checkHintCompleted(append(path[:len(path):len(path)], "0"), node)
+		return array
+	}
+
+	coerced, ok := p.coerceValue(node.Value, kind)
+	p.shapeDeviation(path, node, kind, ok)
+	if ok {
+		node.Value = coerced
+	}
+	return node
+}
+
+// shapeDeviation records a ShapeDeviation for node
+func (p *StreamJSONParser) shapeDeviation(path []string, node *Node, kind Kind, coerced bool) {
+	p.shapeDeviations = append(p.shapeDeviations, &ShapeDeviation{
+		Path:     strings.Join(path, "."),
+		Offset:   node.start,
+		Expected: kind,
+		Got:      jsonTypeName(node),
+		Coerced:  coerced,
+	})
+}
+
+// wrapInArray replaces the value node at path with a completed array
+// holding it
+func (p *StreamJSONParser) wrapInArray(path []string, node *Node) *Node {
+	array := NewNode(ArrayNode)
+	array.Parent = node.Parent
+	array.Completed = true
+	array.start, array.end = node.start, node.end
+	array.times = node.times
+	array.Array = append(array.Array, node)
+
+	switch parent := node.Parent; {
+	case parent == nil:
+		p.root = array
+	case parent.Type == ObjectNode:
+		parent.Children[path[len(path)-1]] = array
+	default:
+		for i := len(parent.Array) - 1; i >= 0; i-- {
+			if parent.Array[i] == node {
+				parent.Array[i] = array
+				break
+			}
+		}
+	}
+	node.Parent = array
+	return array
+}
+
+// coerceValue converts a scalar to kind, reporting whether it could
+func (p *StreamJSONParser) coerceValue(value interface{}, kind Kind) (interface{}, bool) {
+	switch kind {
+	case StringKind:
+		switch v := value.(type) {
+		case int64:
+			return strconv.FormatInt(v, 10), true
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64), true
+		case json.Number:
+			return string(v), true
+		case *big.Float:
+			return v.Text('g', -1), true
+		case bool:
+			return strconv.FormatBool(v), true
+		}
+
+	case NumberKind:
+		s, ok := value.(string)
+		s = strings.TrimSpace(s)
+		if ok && s != "" && (s[0] == '-' || s[0] >= '0' && s[0] <= '9') && json.Valid([]byte(s)) {
+			return p.parseNumber(s), true
+		}
+
+	case BoolKind:
+		if s, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(s)) {
+			case "true":
+				return true, true
+			case "false":
+				return false, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// valueKind returns the kind of a scalar value, and false for values set
+// by transformers
+func valueKind(value interface{}) (Kind, bool) {
+	switch value.(type) {
+	case string:
+		return StringKind, true
+	case int64, float64, json.Number, *big.Float:
+		return NumberKind, true
+	case bool:
+		return BoolKind, true
+	}
+	return AnyKind, false
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShapeHintsCoerce(t *testing.T) {
+	parser := NewStreamJSONParser(WithShape(map[string]Kind{
+		"title":         StringKind,
+		"tags":          ArrayOfStringKind,
+		"count":         NumberKind,
+		"done":          BoolKind,
+		"items":         ArrayOfObjectKind,
+		"items.*.price": NumberKind,
+	}))
+	parser.Append(`{"title": 42, "tags": "urgent", "count": " 7 ", "done": "True", ` +
+		`"items": [{"price": "1.5"}, {"price": 2}]}`)
+
+	want := map[string]interface{}{
+		"title": "42",
+		"tags":  []interface{}{"urgent"},
+		"count": int64(7),
+		"done":  true,
+		"items": []interface{}{
+			map[string]interface{}{"price": 1.5},
+			map[string]interface{}{"price": int64(2)},
+		},
+	}
+	if got := parser.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	var paths []string
+	for _, deviation := range parser.ShapeDeviations() {
+		if !deviation.Coerced {
+			t.Errorf("Expected %v to be coerced", deviation)
+		}
+		paths = append(paths, deviation.Path)
+	}
+	if want := []string{"title", "tags", "count", "done", "items.0.price"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected deviations at %v, got %v", want, paths)
+	}
+	if parser.Completion() != Complete {
+		t.Errorf("Expected the document to complete, got %v", parser.Completion())
+	}
+}
+
+func TestShapeHintsWrapArrays(t *testing.T) {
+	parser := NewStreamJSONParser(WithScalarRoots(), WithShape(Shape{"": ArrayOfNumberKind}))
+	parser.Append(`"3"`)
+	parser.Finish()
+
+	if got := parser.Get(); !reflect.DeepEqual(got, []interface{}{int64(3)}) {
+		t.Errorf("Expected the root wrapped and its element coerced, got %v", got)
+	}
+	if n := len(parser.ShapeDeviations()); n != 2 {
+		t.Errorf("Expected deviations for the root and its element, got %v", parser.ShapeDeviations())
+	}
+
+	parser = NewStreamJSONParser(WithShape(Shape{"*.ids": ArrayKind}))
+	parser.Append(`[{"ids": 1}, {"ids": [2]}]`)
+	if got := parser.Get("0", "ids"); !reflect.DeepEqual(got, []interface{}{int64(1)}) {
+		t.Errorf("Expected a wrapped array, got %v", got)
+	}
+	if n, complete := parser.Len("0", "ids"); n != 1 || !complete {
+		t.Errorf("Expected a complete array of 1, got %d, %v", n, complete)
+	}
+	if got := parser.ShapeDeviations(); len(got) != 1 || got[0].Path != "0.ids" {
+		t.Errorf("Expected one deviation at 0.ids, got %v", got)
+	}
+}
+
+func TestShapeHintsFlag(t *testing.T) {
+	parser := NewStreamJSONParser(WithShape(Shape{
+		"count":  NumberKind,
+		"meta":   ObjectKind,
+		"title":  StringKind,
+		"tags":   ArrayOfStringKind,
+		"absent": BoolKind,
+	}))
+	parser.Append(`{"count": "many", "meta": [1], "title": {"text": "x"}, "tags": null}`)
+
+	got := parser.ShapeDeviations()
+	want := []ShapeDeviation{
+		{Path: "count", Offset: 10, Expected: NumberKind, Got: "string"},
+		{Path: "meta", Offset: 26, Expected: ObjectKind, Got: "array"},
+		{Path: "title", Offset: 40, Expected: StringKind, Got: "object"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d deviations, got %v", len(want), got)
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], *got[i])
+		}
+	}
+	if parser.Get("count") != "many" || parser.Get("tags") != nil {
+		t.Errorf("Expected deviations that cannot be coerced to be kept, got %v", parser.Get())
+	}
+	if msg := got[0].Error(); msg != `streamjson: expected number at "count" (offset 10), got string (kept)` {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
+
+func TestShapeHintsPreferSpecificPatterns(t *testing.T) {
+	parser := NewStreamJSONParser(WithShape(Shape{
+		"*.id":    StringKind,
+		"user.id": NumberKind,
+	}))
+	parser.Append(`{"user": {"id": "7"}, "team": {"id": 8}}`)
+
+	if parser.Get("user", "id") != int64(7) || parser.Get("team", "id") != "8" {
+		t.Errorf("Expected the exact pattern to win, got %v", parser.Get())
+	}
+}
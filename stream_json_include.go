@@ -0,0 +1,104 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// skim consumes the tokens of a value outside the paths given to
+// WithIncludePaths and reports whether the token was consumed. Incomplete
+// tokens are only looked at; the value is skipped once they complete.
+func (p *StreamJSONParser) skim(token Token) bool {
+	frame := p.stack[len(p.stack)-1]
+
+	if p.skimDepth == 0 {
+		if frame.Included || !startsValue(token, frame) || p.includes(p.childPath(frame)) {
+			return false
+		}
+		if !token.Completed {
+			return true
+		}
+
+		// Array elements keep a null placeholder so indices stay stable
+		if frame.Node.Type == ArrayNode {
+			placeholder := NewNode(ValueNode)
+			placeholder.Parent = frame.Node
+			placeholder.start = token.TokenStart
+			placeholder.end = token.TokenEnd
+			frame.Node.Array = append(frame.Node.Array, placeholder)
+		}
+		if token.TokenType != ObjectStart && token.TokenType != ArrayStart {
+			p.skimmed(frame, token)
+			return true
+		}
+	}
+
+	if !token.Completed {
+		return true
+	}
+	switch token.TokenType {
+	case ObjectStart, ArrayStart:
+		p.skimDepth++
+	case ObjectEnd, ArrayEnd:
+		p.skimDepth--
+		if p.skimDepth == 0 {
+			p.skimmed(frame, token)
+		}
+	}
+	return true
+}
+
+// skimmed updates the frame once the skipped value ended with token
+func (p *StreamJSONParser) skimmed(frame *StackFrame, token Token) {
+	if frame.Node.Type == ArrayNode {
+		placeholder := frame.Node.Array[len(frame.Node.Array)-1]
+		placeholder.end = token.TokenEnd
+		placeholder.Completed = true
+	} else {
+		frame.CurrentKey = ""
+	}
+	frame.ExpectingKey = false
+	frame.ExpectingValue = false
+}
+
+// startsValue reports whether a complete or incomplete token starts the
+// next value of the frame's node
+func startsValue(token Token, frame *StackFrame) bool {
+	switch token.TokenType {
+	case ObjectStart, ArrayStart, String, Number, Bool, Null:
+		return frame.Node.Type == ArrayNode || frame.CurrentKey != ""
+	}
+	return false
+}
+
+// includes reports whether a value at path lies on or under one of the
+// paths given to WithIncludePaths
+func (p *StreamJSONParser) includes(path []string) bool {
+	for _, pattern := range p.options.includePaths {
+		n := min(len(pattern), len(path))
+		if matchPath(pattern[:n], path[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// includesAll reports whether everything under path is included, so values
+// below it need no checks
+func (p *StreamJSONParser) includesAll(path []string) bool {
+	for _, pattern := range p.options.includePaths {
+		if len(pattern) <= len(path) && matchPath(pattern, path[:len(pattern)]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserIncludePaths(t *testing.T) {
+	input := `{"id": "chatcmpl-1", "choices": [{"index": 0, "delta": {"content": "Hi", "tool_calls": [{"id": "c1"}]}, "logprobs": {"content": [{"token": "Hi"}]}}], "usage": {"total_tokens": 7}, "meta": [1, [2, {"x": 3}]]}`
+	expected := `{"choices":[{"delta":{"content":"Hi","tool_calls":[{"id":"c1"}]}}],"usage":{"total_tokens":7}}`
+
+	// Whole input and one byte at a time
+	for _, chunk := range []int{len(input), 1} {
+		parser := NewStreamJSONParser(WithIncludePaths("choices.*.delta", "usage"))
+		for i := 0; i < len(input); i += chunk {
+			parser.Append(input[i:min(i+chunk, len(input))])
+		}
+
+		if !parser.IsCompleted() {
+			t.Fatalf("%d: expected parsing to complete", chunk)
+		}
+		data, err := parser.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", chunk, err)
+		}
+		if string(data) != expected {
+			t.Errorf("%d: expected %s, got %s", chunk, expected, data)
+		}
+	}
+}
+
+func TestStreamJSONParserIncludePathsIndices(t *testing.T) {
+	parser := NewStreamJSONParser(WithIncludePaths("items.2"))
+	parser.Append(`{"items": ["a", {"b": [1]}, "c", "d"], "other": true}`)
+
+	data, _ := parser.MarshalJSON()
+	if string(data) != `{"items":[null,null,"c",null]}` {
+		t.Errorf("Expected skipped elements to keep their indices, got %s", data)
+	}
+	if parser.Get("items", "2") != "c" {
+		t.Errorf("Expected items.2, got %v", parser.Get("items", "2"))
+	}
+}
+
+func TestStreamJSONParserIncludePathsPartial(t *testing.T) {
+	parser := NewStreamJSONParser(WithIncludePaths("answer"))
+	parser.Append(`{"thoughts": "long reasoning`)
+
+	if parser.Get("thoughts") != nil {
+		t.Errorf("Expected skipped string to stay absent, got %v", parser.Get("thoughts"))
+	}
+
+	parser.Append(` text", "answer": "forty`)
+	if parser.Get("answer") != "forty" {
+		t.Errorf("Expected partial included string, got %v", parser.Get("answer"))
+	}
+
+	parser.Append(`-two"}`)
+	if !parser.IsCompleted() || parser.Get("answer") != "forty-two" {
+		t.Errorf("Unexpected document %v", parser.Get())
+	}
+}
+
+func TestStreamJSONParserIncludePathsNormalized(t *testing.T) {
+	parser := NewStreamJSONParser(WithKeyNormalizer(SnakeCaseKey), WithIncludePaths("userName"))
+	parser.Append(`{"user_name": "ada", "age": 36}`)
+
+	data, _ := parser.MarshalJSON()
+	if string(data) != `{"user_name":"ada"}` {
+		t.Errorf("Expected normalized include path, got %s", data)
+	}
+}
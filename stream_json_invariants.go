@@ -0,0 +1,116 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+)
+
+// InvariantError reports an internal inconsistency between the parsing stack
+// and the AST. It indicates a bug in the parser rather than bad input.
+type InvariantError struct {
+	Offset  int    // Input offset of the token after which the check failed
+	Message string // Description of the violated invariant
+}
+
+// Error implements the error interface
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("streamjson: invariant violated at offset %d: %s", e.Offset, e.Message)
+}
+
+// checkTokenRange validates that a token lies within the input read so far
+// and does not start before the previous complete token ended
+func (p *StreamJSONParser) checkTokenRange(token Token) error {
+	violation := func(format string, args ...interface{}) error {
+		return &InvariantError{Offset: token.TokenEnd, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if token.TokenStart > token.TokenEnd {
+		return violation("token starts at %d after its end", token.TokenStart)
+	}
+	if token.TokenStart < p.tokenEnd {
+		return violation("token starts at %d before the previous token ended at %d", token.TokenStart, p.tokenEnd)
+	}
+	if end := p.tokenizer.base + len(p.tokenizer.buffer); token.TokenEnd > end {
+		return violation("token ends beyond the input length %d", end)
+	}
+	if token.Completed {
+		p.tokenEnd = token.TokenEnd
+	}
+	return nil
+}
+
+// checkInvariants validates stack and AST consistency.
+// It is called after every token when built with the streamjson_invariants tag.
+func (p *StreamJSONParser) checkInvariants(offset int) error {
+	violation := func(format string, args ...interface{}) error {
+		return &InvariantError{Offset: offset, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if p.tokenizer.position > len(p.tokenizer.buffer) {
+		return violation("tokenizer position %d beyond buffer length %d", p.tokenizer.position, len(p.tokenizer.buffer))
+	}
+
+	if !p.started {
+		if p.root != nil || len(p.stack) != 0 {
+			return violation("parser not started but has root or stack frames")
+		}
+		return nil
+	}
+
+	if p.root == nil {
+		return violation("parser started without a root node")
+	}
+
+	if len(p.stack) == 0 {
+		// Only a scalar root streams without a frame
+		if !p.root.Completed && p.root.Type != ValueNode {
+			return violation("stack is empty but root is not completed")
+		}
+		return nil
+	}
+
+	for i, frame := range p.stack {
+		if frame == nil || frame.Node == nil {
+			return violation("stack frame %d has no node", i)
+		}
+
+		node := frame.Node
+		switch node.Type {
+		case ObjectNode:
+			if node.Children == nil {
+				return violation("object node in frame %d has nil children", i)
+			}
+		case ArrayNode:
+			// Arrays may legitimately hold a nil slice
+		default:
+			return violation("frame %d holds a value node", i)
+		}
+
+		if node.Completed {
+			return violation("frame %d holds a completed node", i)
+		}
+
+		if i == 0 {
+			if node != p.root {
+				return violation("bottom stack frame is not the root")
+			}
+		} else if node.Parent != p.stack[i-1].Node {
+			return violation("frame %d node parent does not match frame %d", i, i-1)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInvariantsHoldWhileStreaming(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	chunks := []string{`noise {"a":[1,{"b":"x`, `y"},tr`, `ue],"c":{"d":nu`, `ll}}`}
+	for _, chunk := range chunks {
+		parser.Append(chunk)
+		if err := parser.checkInvariants(parser.tokenizer.position); err != nil {
+			t.Errorf("Expected invariants to hold after %q, got %v", chunk, err)
+		}
+	}
+
+	if parser.Err() != nil {
+		t.Errorf("Expected no error, got %v", parser.Err())
+	}
+}
+
+func TestInvariantsDetectCorruption(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":{"b":`)
+
+	// Detach the inner frame from its parent to simulate a state machine bug
+	parser.stack[1].Node.Parent = nil
+
+	err := parser.checkInvariants(parser.tokenizer.position)
+	var invariantErr *InvariantError
+	if !errors.As(err, &invariantErr) {
+		t.Fatalf("Expected InvariantError, got %v", err)
+	}
+
+	if invariantErr.Offset != len(`{"a":{"b":`) {
+		t.Errorf("Expected offset %d, got %d", len(`{"a":{"b":`), invariantErr.Offset)
+	}
+}
+
+func TestInvariantsDetectIncompleteRoot(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":1}`)
+
+	parser.root.Completed = false
+	if err := parser.checkInvariants(0); err == nil {
+		t.Errorf("Expected error for empty stack with incomplete root")
+	}
+}
+
+func TestStreamJSONParserIncompleteLeadingToken(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	// An unterminated string before the root must not stall the parser
+	parser.Append(`Here is "the`)
+	if parser.GetRoot() != nil {
+		t.Errorf("Expected no root yet")
+	}
+
+	parser.Append(` result": {"ok":true}`)
+	if parser.Get("ok") != true {
+		t.Errorf("Expected ok to be true, got %v", parser.Get("ok"))
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// Entries returns an iterator over the completed members of the object at
+// the path, in sorted key order. Members still streaming are skipped; the
+// iterator yields nothing if the path does not hold an object.
+func (p *StreamJSONParser) Entries(keys ...string) iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		end := p.guardRead("Entries")
+		node := p.findNode(keys)
+		names := node.Keys()
+		end()
+		for _, key := range names {
+			end := p.guardRead("Entries")
+			child := node.Children[key]
+			var value interface{}
+			if child.Completed {
+				value = p.collectNodeValue(child)
+			}
+			end()
+			if child.Completed && !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// Elements returns an iterator over the completed elements of the array at
+// the path, in order. An element still streaming is skipped; the iterator
+// yields nothing if the path does not hold an array.
+func (p *StreamJSONParser) Elements(keys ...string) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		end := p.guardRead("Elements")
+		node := p.findNode(keys)
+		end()
+		if node == nil || node.Type != ArrayNode {
+			return
+		}
+		for _, element := range node.Array {
+			end := p.guardRead("Elements")
+			var value interface{}
+			if element.Completed {
+				value = p.collectNodeValue(element)
+			}
+			end()
+			if element.Completed && !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// NextElements returns the elements of the array at the dotted path that
+// completed from index cursor on, and the cursor to pass next time, for
+// pull-based pagination over a streaming array. Start with cursor 0. It
+// returns no elements and the same cursor while the next element is still
+// streaming or the path does not hold an array. Elements evicted by
+// StreamArray are skipped.
+func (p *StreamJSONParser) NextElements(path string, cursor int) ([]interface{}, int) {
+	defer p.guardRead("NextElements")()
+	node := p.findNode(splitPath(path))
+	if node == nil || node.Type != ArrayNode {
+		return nil, cursor
+	}
+
+	evicted := p.evictedElements(node)
+	cursor = max(cursor, evicted)
+	var elements []interface{}
+	for i := cursor - evicted; i < len(node.Array) && node.Array[i].Completed; i++ {
+		elements = append(elements, p.collectNodeValue(node.Array[i]))
+	}
+	return elements, cursor + len(elements)
+}
+
+// ReadElements returns an iterator that reads r as needed and yields each
+// element of the array at the path as soon as it completes, blocking on r
+// in between. It stops when the array closes or at io.EOF; any other read
+// error is yielded with a nil value as the last pair. Elements stay in the
+// tree; use StreamArray instead to keep memory flat.
+func (p *StreamJSONParser) ReadElements(r io.Reader, keys ...string) iter.Seq2[interface{}, error] {
+	return func(yield func(interface{}, error) bool) {
+		buf := make([]byte, readChunkSize)
+		next := 0
+		for {
+			node := p.findNode(keys)
+			if node != nil && node.Type == ArrayNode {
+				for ; next < len(node.Array) && node.Array[next].Completed; next++ {
+					if !yield(p.collectNodeValue(node.Array[next]), nil) {
+						return
+					}
+				}
+				if node.Completed {
+					return
+				}
+			}
+
+			n, err := r.Read(buf)
+			if n > 0 {
+				p.AppendBytes(buf[:n])
+			}
+			if errors.Is(err, io.EOF) {
+				err = nil
+				if n == 0 {
+					return
+				}
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamJSONParserEntries(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"user": {"name": "Ada", "tags": ["x"], "bio": "still stre`)
+
+	var keys []string
+	for key, value := range parser.Entries("user") {
+		keys = append(keys, key)
+		if key == "name" && value != "Ada" {
+			t.Errorf("Expected name Ada, got %v", value)
+		}
+	}
+	if !reflect.DeepEqual(keys, []string{"name", "tags"}) {
+		t.Errorf("Expected completed members in key order, got %v", keys)
+	}
+
+	for range parser.Entries("missing") {
+		t.Error("Expected no entries for a missing path")
+	}
+}
+
+func TestStreamJSONParserElements(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"items": [1, {"a": 2}, "thr`)
+
+	var values []interface{}
+	for value := range parser.Elements("items") {
+		values = append(values, value)
+	}
+	expected := []interface{}{int64(1), map[string]interface{}{"a": int64(2)}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+
+	// Breaking out early
+	for range parser.Elements("items") {
+		break
+	}
+
+	for range parser.Elements("items", "1") {
+		t.Error("Expected no elements for an object")
+	}
+}
+
+func TestStreamJSONParserReadElements(t *testing.T) {
+	input := `{"results": [{"id": 1}, {"id": 2}, {"id": 3}], "next": null}`
+	parser := NewStreamJSONParser()
+	reader := iotest.OneByteReader(strings.NewReader(input))
+
+	var ids []interface{}
+	for value, err := range parser.ReadElements(reader, "results") {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ids = append(ids, value.(map[string]interface{})["id"])
+
+		// Each element is yielded before the rest is read
+		if parser.Exists("next") {
+			t.Error("Expected the element before the rest of the input")
+		}
+	}
+	if !reflect.DeepEqual(ids, []interface{}{int64(1), int64(2), int64(3)}) {
+		t.Errorf("Unexpected ids %v", ids)
+	}
+
+	// The rest of the stream can still be parsed
+	if err := parser.ParseReader(reader); err != nil || !parser.IsCompleted() {
+		t.Errorf("Expected the document to complete, got %v", err)
+	}
+}
+
+func TestStreamJSONParserReadElementsError(t *testing.T) {
+	failure := errors.New("connection reset")
+	reader := io.MultiReader(strings.NewReader(`[1, 2, `), iotest.ErrReader(failure))
+
+	var values []interface{}
+	var last error
+	for value, err := range NewStreamJSONParser().ReadElements(reader) {
+		if err != nil {
+			last = err
+			continue
+		}
+		values = append(values, value)
+	}
+	if len(values) != 2 || !errors.Is(last, failure) {
+		t.Errorf("Expected two elements and the read error, got %v %v", values, last)
+	}
+}
+
+func TestStreamJSONParserNextElements(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"results": [{"id": 1}, {"id": 2}, {"id"`)
+
+	page, cursor := parser.NextElements("results", 0)
+	if len(page) != 2 || cursor != 2 {
+		t.Fatalf("Expected two elements and cursor 2, got %v, %d", page, cursor)
+	}
+
+	// Nothing new while the next element streams
+	if page, next := parser.NextElements("results", cursor); len(page) != 0 || next != cursor {
+		t.Errorf("Expected no elements and the same cursor, got %v, %d", page, next)
+	}
+
+	parser.Append(`: 3}, 4, "five"]}`)
+	page, cursor = parser.NextElements("results", cursor)
+	if !reflect.DeepEqual(page, []interface{}{map[string]interface{}{"id": int64(3)}, int64(4), "five"}) || cursor != 5 {
+		t.Errorf("Expected the remaining elements and cursor 5, got %v, %d", page, cursor)
+	}
+
+	if page, next := parser.NextElements("missing", 0); page != nil || next != 0 {
+		t.Errorf("Expected nothing for a missing path, got %v, %d", page, next)
+	}
+}
+
+func TestStreamJSONParserNextElementsEvicted(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.StreamArray("", func(int, interface{}) {})
+	parser.Append(`[1, 2, 3, "fo`)
+
+	// Evicted elements are skipped, and the cursor moves past them
+	if page, cursor := parser.NextElements("", 0); len(page) != 0 || cursor != 3 {
+		t.Errorf("Expected cursor past the evicted elements, got %v, %d", page, cursor)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SnakeCaseKey converts a key to snake_case, for use with WithKeyNormalizer.
+// "userName", "UserName", "user-name" and "user_name" all become "user_name",
+// and runs of capitals are kept together, so "HTTPServer" becomes
+// "http_server".
+func SnakeCaseKey(key string) string {
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+
+	var prev rune
+	for i, r := range key {
+		switch {
+		case r == '-' || r == ' ' || r == '_':
+			r = '_'
+		case unicode.IsUpper(r):
+			next, _ := utf8.DecodeRuneInString(key[i+utf8.RuneLen(r):])
+			if i > 0 && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && unicode.IsLower(next))) {
+				b.WriteByte('_')
+			}
+			prev = r
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		// Collapse separators
+		if r == '_' && prev == '_' {
+			continue
+		}
+		prev = r
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// trimmedKeys returns a key normalizer that trims surrounding whitespace
+// before applying normalize, if any
+func trimmedKeys(normalize func(key string) string) func(key string) string {
+	if normalize == nil {
+		return strings.TrimSpace
+	}
+	return func(key string) string {
+		return normalize(strings.TrimSpace(key))
+	}
+}
+
+// normalizeKey applies the configured key normalizer
+func (p *StreamJSONParser) normalizeKey(key string) string {
+	if p.options.keyNormalizer == nil {
+		return key
+	}
+	return p.options.keyNormalizer(key)
+}
+
+// normalizePath applies the configured key normalizer to path segments,
+// leaving wildcards and array indices alone
+func (p *StreamJSONParser) normalizePath(path []string) []string {
+	if p.options.keyNormalizer == nil {
+		return path
+	}
+	normalized := make([]string, len(path))
+	for i, segment := range path {
+		if segment == pathWildcard || segment == pathEach || isIndex(segment) {
+			normalized[i] = segment
+		} else {
+			normalized[i] = p.options.keyNormalizer(segment)
+		}
+	}
+	return normalized
+}
+
+// isIndex reports whether a path segment is an array index
+func isIndex(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for i := 0; i < len(segment); i++ {
+		if segment[i] < '0' || segment[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnakeCaseKey(t *testing.T) {
+	tests := map[string]string{
+		"userName":   "user_name",
+		"UserName":   "user_name",
+		"user_name":  "user_name",
+		"user-name":  "user_name",
+		"userID":     "user_id",
+		"HTTPServer": "http_server",
+		"version2Id": "version2_id",
+		"already__x": "already_x",
+		"ÉtatCivil":  "état_civil",
+		"":           "",
+	}
+	for input, expected := range tests {
+		if got := SnakeCaseKey(input); got != expected {
+			t.Errorf("SnakeCaseKey(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestStreamJSONParserKeyNormalizer(t *testing.T) {
+	parser := NewStreamJSONParser(WithKeyNormalizer(SnakeCaseKey))
+
+	var names []interface{}
+	parser.OnValue("userProfile.displayName", func(value interface{}, complete bool) {
+		if complete {
+			names = append(names, value)
+		}
+	})
+
+	parser.Append(`{"userProfile":{"displayName":"Ada","Tags":["x"]},"user-id":7,"pending`)
+
+	if parser.Get("userProfile", "displayName") != "Ada" || parser.Get("user_profile", "display_name") != "Ada" {
+		t.Errorf("Expected both spellings to resolve, got %v", parser.Get())
+	}
+	if id, ok := parser.GetInt("userId"); !ok || id != 7 {
+		t.Errorf("Expected user_id, got %v", parser.Get())
+	}
+	if len(names) != 1 {
+		t.Errorf("Expected callback path to be normalized, got %v", names)
+	}
+	if _, ok := parser.Get().(map[string]interface{})["user_profile"]; !ok {
+		t.Errorf("Expected keys to be stored normalized, got %v", parser.Get())
+	}
+
+	results, err := parser.Query("$.userProfile.Tags[0]")
+	if err != nil || len(results) != 1 || results[0] != "x" {
+		t.Errorf("Expected query names to be normalized, got %v, %v", results, err)
+	}
+
+	var target struct {
+		UserID      int `json:"userId"`
+		UserProfile struct {
+			DisplayName string
+		}
+	}
+	parser.Append(`Key": 1}`)
+	if err := parser.Unmarshal(&target); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.UserID != 7 || target.UserProfile.DisplayName != "Ada" {
+		t.Errorf("Expected struct fields to be normalized, got %+v", target)
+	}
+}
+
+func TestStreamJSONParserKeyNormalizerLower(t *testing.T) {
+	parser := NewStreamJSONParser(WithKeyNormalizer(strings.ToLower))
+	parser.Append(`{"Name":"a","NAME":"b"}`)
+
+	// Colliding keys keep the last value
+	if parser.Get("name") != "b" || parser.Get("Name") != "b" {
+		t.Errorf("Expected lowercased keys, got %v", parser.Get())
+	}
+}
+
+func TestStreamJSONParserTrimmedKeys(t *testing.T) {
+	parser := NewStreamJSONParser(WithTrimmedKeys())
+	parser.Append(`{" name ": "Ada", "user\t": {"  id": 7}}`)
+
+	if parser.Get("name") != "Ada" || parser.Get("user", "id") != int64(7) {
+		t.Errorf("Expected trimmed keys, got %v", parser.Get())
+	}
+	if parser.Get(" name ") != "Ada" {
+		t.Errorf("Expected lookup paths to be trimmed, got %v", parser.Get(" name "))
+	}
+
+	var target struct {
+		Name string `json:"name"`
+	}
+	if err := parser.Unmarshal(&target); err != nil || target.Name != "Ada" {
+		t.Errorf("Expected Unmarshal to see trimmed keys, got %+v %v", target, err)
+	}
+
+	exact := NewStreamJSONParser()
+	exact.Append(`{" name ": "Ada"}`)
+	if exact.Get("name") != nil {
+		t.Errorf("Expected keys to be kept as is by default")
+	}
+}
+
+func TestStreamJSONParserTrimmedKeysWithNormalizer(t *testing.T) {
+	// The order of the options does not matter
+	for _, opts := range [][]Option{
+		{WithTrimmedKeys(), WithKeyNormalizer(SnakeCaseKey)},
+		{WithKeyNormalizer(SnakeCaseKey), WithTrimmedKeys()},
+		{WithConfig(Config{TrimKeys: true, KeyNormalizer: SnakeCaseKey})},
+	} {
+		parser := NewStreamJSONParser(opts...)
+		parser.Append(`{" userName ": "Ada"}`)
+		if parser.Get("user_name") != "Ada" {
+			t.Errorf("Expected trimmed then normalized key, got %v", parser.Get())
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"sync/atomic"
+)
+
+// PoolCounters counts the nodes and stack frames handed out and taken back
+// by the pools since the program started. They are only maintained when
+// built with the streamjson_leakcheck tag, and are zero otherwise.
+type PoolCounters struct {
+	NodesAcquired  int64 // Nodes created by NewNode
+	NodesReleased  int64 // Nodes returned by ReleaseNode, Reset and eviction
+	FramesAcquired int64 // Stack frames created while parsing
+	FramesReleased int64 // Stack frames returned when containers close
+	DoubleReleases int64 // ReleaseNode calls on a node already released, which are ignored
+}
+
+// LiveNodes returns the number of nodes acquired and not yet released
+func (c PoolCounters) LiveNodes() int64 {
+	return c.NodesAcquired - c.NodesReleased
+}
+
+// LiveFrames returns the number of stack frames acquired and not yet released
+func (c PoolCounters) LiveFrames() int64 {
+	return c.FramesAcquired - c.FramesReleased
+}
+
+var (
+	nodesAcquired  atomic.Int64
+	nodesReleased  atomic.Int64
+	framesAcquired atomic.Int64
+	framesReleased atomic.Int64
+	doubleReleases atomic.Int64
+)
+
+// ReadPoolCounters returns the current pool counters. Compare two readings
+// around code that should release everything it parses:
+//
+//	before := streamjson.ReadPoolCounters()
+//	// parse and Reset
+//	leaked := streamjson.ReadPoolCounters().LiveNodes() - before.LiveNodes()
+func ReadPoolCounters() PoolCounters {
+	return PoolCounters{
+		NodesAcquired:  nodesAcquired.Load(),
+		NodesReleased:  nodesReleased.Load(),
+		FramesAcquired: framesAcquired.Load(),
+		FramesReleased: framesReleased.Load(),
+		DoubleReleases: doubleReleases.Load(),
+	}
+}
+
+// countPool increments a pool counter when leak checking is enabled
+func countPool(counter *atomic.Int64) {
+	if leakCheckEnabled {
+		counter.Add(1)
+	}
+}
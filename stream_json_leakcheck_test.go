@@ -0,0 +1,113 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleaseNodeDetaches(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": {"b": 1}, "list": [1, {"c": 2}, 3], "d": "x"}`)
+
+	ReleaseNode(parser.GetNode("a"))
+	ReleaseNode(parser.GetNode("list", "1"))
+	want := map[string]interface{}{"list": []interface{}{int64(1), int64(3)}, "d": "x"}
+	if !reflect.DeepEqual(parser.Get(), want) {
+		t.Errorf("Expected released nodes to be detached, got %v", parser.Get())
+	}
+
+	// Nodes taken from the pool afterwards must not show up in the tree
+	for i := 0; i < 16; i++ {
+		node := NewNode(ObjectNode)
+		node.Children["reused"] = NewNode(ValueNode)
+	}
+	if !reflect.DeepEqual(parser.Get(), want) {
+		t.Errorf("Expected the tree to be unaffected by reuse, got %v", parser.Get())
+	}
+}
+
+func TestReleaseNodeTwice(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": {"b": 1}}`)
+	node := parser.GetNode("a")
+
+	before := ReadPoolCounters()
+	ReleaseNode(node)
+	ReleaseNode(node)
+	first, second := NewNode(ValueNode), NewNode(ValueNode)
+	if first == second {
+		t.Fatal("Expected a node released twice to be pooled once")
+	}
+
+	after := ReadPoolCounters()
+	if leakCheckEnabled && after.DoubleReleases != before.DoubleReleases+1 {
+		t.Errorf("Expected one double release, got %d", after.DoubleReleases-before.DoubleReleases)
+	}
+}
+
+func TestMaterializedValuesOutliveReset(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"user": {"name": "Ada", "tags": ["x", "y"]}}`)
+	value := parser.Get()
+	user := parser.GetNode("user").Materialize()
+
+	parser.Reset()
+	parser.Append(`{"user": {"name": "Bob", "tags": []}, "extra": true}`)
+
+	want := map[string]interface{}{"name": "Ada", "tags": []interface{}{"x", "y"}}
+	if !reflect.DeepEqual(value, map[string]interface{}{"user": want}) || !reflect.DeepEqual(user, want) {
+		t.Errorf("Expected materialized values to be copies, got %v and %v", value, user)
+	}
+}
+
+func TestPoolCountersBalance(t *testing.T) {
+	if !leakCheckEnabled {
+		t.Skip("pool counters need -tags streamjson_leakcheck")
+	}
+
+	before := ReadPoolCounters()
+	parser := NewStreamJSONParser(WithScalarRoots())
+	document := `{"items": [{"id": 1}, {"id": 2}, {"id": 3}], "text": "streaming", "n": 12.5}`
+	parser.StreamArray("items", func(int, interface{}) {})
+	for i := 0; i < len(document); i += 7 {
+		parser.Append(document[i:min(i+7, len(document))])
+		if i == 21 {
+			cp := parser.Checkpoint()
+			parser.Append(`"junk`)
+			if err := parser.Restore(cp); err != nil {
+				t.Fatal(err)
+			}
+			parser.Release(cp)
+		}
+	}
+	if !parser.IsCompleted() {
+		t.Fatalf("Expected a complete document, got %v", parser.Get())
+	}
+	parser.Checkpoint() // Left for Reset to release
+	parser.Reset()
+
+	parser.Append(`"a scalar that never ends`)
+	parser.Reset()
+
+	after := ReadPoolCounters()
+	if leaked := after.LiveNodes() - before.LiveNodes(); leaked != 0 {
+		t.Errorf("Expected every node to be released, %d leaked", leaked)
+	}
+	if leaked := after.LiveFrames() - before.LiveFrames(); leaked != 0 {
+		t.Errorf("Expected every stack frame to be released, %d leaked", leaked)
+	}
+}
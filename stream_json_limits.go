@@ -0,0 +1,76 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+)
+
+// Errors returned by Err when input exceeds a configured limit
+var (
+	ErrDepthLimit        = errors.New("streamjson: nesting depth limit exceeded")
+	ErrKeyLengthLimit    = errors.New("streamjson: key length limit exceeded")
+	ErrStringLengthLimit = errors.New("streamjson: string length limit exceeded")
+	ErrNodeLimit         = errors.New("streamjson: node count limit exceeded")
+)
+
+// hasTokenLimits reports whether any per-token limit is configured
+func (o *parserOptions) hasTokenLimits() bool {
+	return o.maxDepth > 0 || o.maxKeyLength > 0 || o.maxStringLength > 0
+}
+
+// checkLimits stops parsing if token, complete or not, exceeds a limit.
+// Strings are checked while they stream, so an endless string is cut off
+// before it is complete.
+func (p *StreamJSONParser) checkLimits(token Token) bool {
+	var err error
+	switch token.TokenType {
+	case ObjectStart, ArrayStart:
+		if p.options.maxDepth > 0 && len(p.stack) >= p.options.maxDepth {
+			err = ErrDepthLimit
+		}
+
+	case ObjectKey, String:
+		// Keys and values are told apart by position, as the tokenizer
+		// reports array elements after a comma as keys
+		length := len(token.Content)
+		if length > 0 && isQuote(token.Content[0]) {
+			length-- // Opening quote
+			if token.Completed {
+				length-- // Closing quote
+			}
+		}
+		isKey := len(p.stack) > 0 && p.stack[len(p.stack)-1].Node.Type == ObjectNode && p.stack[len(p.stack)-1].ExpectingKey
+		if isKey && p.options.maxKeyLength > 0 && length > p.options.maxKeyLength {
+			err = ErrKeyLengthLimit
+		} else if !isKey && p.options.maxStringLength > 0 && length > p.options.maxStringLength {
+			err = ErrStringLengthLimit
+		}
+	}
+
+	if err != nil {
+		p.err = err
+		return false
+	}
+	return true
+}
+
+// countNode enforces the node limit as nodes are added to the tree
+func (p *StreamJSONParser) countNode() {
+	p.nodes++
+	if p.options.maxNodes > 0 && p.nodes > p.options.maxNodes && p.err == nil {
+		p.err = ErrNodeLimit
+	}
+}
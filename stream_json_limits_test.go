@@ -0,0 +1,218 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestLimitsWithinBounds(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxDepth(3), WithMaxKeyLength(4), WithMaxStringLength(5), WithMaxNodes(7))
+	parser.Append(`{"abcd":[{"k":"12345"}],"n":["x"]}`)
+
+	if parser.Err() != nil {
+		t.Fatalf("Unexpected error: %v", parser.Err())
+	}
+	if !parser.IsCompleted() {
+		t.Errorf("Expected parsing to complete")
+	}
+}
+
+func TestLimitsExceeded(t *testing.T) {
+	tests := []struct {
+		name   string
+		option Option
+		chunks []string
+		err    error
+	}{
+		{"depth", WithMaxDepth(2), []string{`{"a":[[1]]}`}, ErrDepthLimit},
+		{"key length", WithMaxKeyLength(3), []string{`{"abcd":1}`}, ErrKeyLengthLimit},
+		{"streaming key", WithMaxKeyLength(3), []string{`{"ab`, `cd`}, ErrKeyLengthLimit},
+		{"string length", WithMaxStringLength(3), []string{`{"a":"abcd"}`}, ErrStringLengthLimit},
+		{"streaming string", WithMaxStringLength(3), []string{`{"a":"ab`, `cd`}, ErrStringLengthLimit},
+		{"array string", WithMaxStringLength(3), []string{`["a","abcd"]`}, ErrStringLengthLimit},
+		{"nodes", WithMaxNodes(3), []string{`[1,2,3]`}, ErrNodeLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewStreamJSONParser(tt.option)
+			for _, chunk := range tt.chunks {
+				parser.Append(chunk)
+			}
+			if parser.Err() != tt.err {
+				t.Errorf("Expected %v, got %v", tt.err, parser.Err())
+			}
+			if parser.IsCompleted() {
+				t.Errorf("Expected parsing to stop")
+			}
+		})
+	}
+}
+
+func TestLimitsStopDeepNesting(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxDepth(100))
+	parser.Append(strings.Repeat("[", 100000))
+
+	if parser.Err() != ErrDepthLimit {
+		t.Errorf("Expected ErrDepthLimit, got %v", parser.Err())
+	}
+	if len(parser.stack) != 100 {
+		t.Errorf("Expected stack to stop at 100 frames, got %d", len(parser.stack))
+	}
+}
+
+func TestDeepNestingWithoutLimit(t *testing.T) {
+	const depth = 2000
+	parser := NewStreamJSONParser()
+	parser.Append(strings.Repeat(`{"a":[`, depth) + "1" + strings.Repeat("]}", depth))
+
+	if parser.Err() != nil {
+		t.Fatalf("Unexpected error: %v", parser.Err())
+	}
+	value := parser.GetCompleted()
+	for i := 0; i < depth; i++ {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected object at depth %d, got %T", i, value)
+		}
+		array, ok := object["a"].([]interface{})
+		if !ok || len(array) != 1 {
+			t.Fatalf("Expected single element array at depth %d, got %v", i, object["a"])
+		}
+		value = array[0]
+	}
+	if value != int64(1) {
+		t.Errorf("Expected innermost value 1, got %v", value)
+	}
+	parser.Reset()
+}
+
+func TestDeepNestingAllocatesLinearly(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	parser := NewStreamJSONParser()
+	parser.Append(strings.Repeat("[", 16000))
+	runtime.ReadMemStats(&after)
+
+	// Copying every frame's path would allocate about 2 GB here
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("Expected allocations to grow linearly with depth, got %d bytes for 16000 levels", allocated)
+	}
+}
+
+func TestPathsOfFramesPushedBeforeSubscribing(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":{"b":[{"c":`)
+
+	var got []interface{}
+	parser.OnValue("a.b.0", func(value interface{}, complete bool) {
+		got = append(got, value)
+	})
+	var raw string
+	parser.OnRawSubtree("a.b", func(b []byte) {
+		raw = string(b)
+	})
+	parser.Append(`1}]}}`)
+
+	if len(got) != 1 || !reflect.DeepEqual(got[0], map[string]interface{}{"c": int64(1)}) {
+		t.Errorf("Expected a.b.0 delivered once, got %v", got)
+	}
+	if raw != `[{"c":1}]` {
+		t.Errorf("Expected the raw bytes of a.b, got %q", raw)
+	}
+}
+
+func BenchmarkDeepNesting(b *testing.B) {
+	input := strings.Repeat(`{"a":[`, 5000) + "1" + strings.Repeat("]}", 5000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		parser := NewStreamJSONParser()
+		parser.Append(input)
+		parser.Reset()
+	}
+}
+
+// deepNode is a recursive type for decoding deeply nested input
+type deepNode struct {
+	A []deepNode `json:"a"`
+}
+
+func TestDeepNestingWalkersIterative(t *testing.T) {
+	const depth = 10000
+	input := strings.Repeat(`{"a":[`, depth) + strings.Repeat("]}", depth)
+
+	// Any walker still recursing over the tree would overflow this stack
+	defer debug.SetMaxStack(debug.SetMaxStack(256 << 10))
+
+	parser := NewStreamJSONParser(WithMemoryBudget(1 << 30))
+	parser.Append(input[:len(input)-depth])
+	cp := parser.Checkpoint()
+	parser.Append(input[len(input)-depth:])
+
+	parser.GetOrdered()
+	bigFloatsAsNumbers(parser.Snapshot().Interface())
+	parser.Stats()
+	parser.MemoryUsage()
+	if _, err := parser.Query("$..missing"); err != nil {
+		t.Errorf("Unexpected query error: %v", err)
+	}
+	if data, err := parser.MarshalJSON(); err != nil || string(data) != input {
+		t.Errorf("Expected the input back from MarshalJSON, got %d bytes, %v", len(data), err)
+	}
+	var decoded deepNode
+	if err := parser.Unmarshal(&decoded); err != nil {
+		t.Errorf("Unexpected unmarshal error: %v", err)
+	}
+	var differences []Difference
+	parser.matchNode(parser.root, parser.Get(), &differences)
+	if len(differences) != 0 {
+		t.Errorf("Expected the document to match itself, got %d differences", len(differences))
+	}
+	if err := parser.Restore(cp); err != nil {
+		t.Errorf("Unexpected restore error: %v", err)
+	}
+	parser.Reset()
+}
+
+func TestLimitsRepairBareKeys(t *testing.T) {
+	parser := NewStreamJSONParser(WithRepair(), WithMaxKeyLength(4))
+	parser.Append(`{abcd: 1, abcde: 2}`)
+
+	if parser.Err() != ErrKeyLengthLimit {
+		t.Errorf("Expected ErrKeyLengthLimit, got %v", parser.Err())
+	}
+	if parser.Get("abcd") != int64(1) {
+		t.Errorf("Expected abcd to be parsed, got %v", parser.Get("abcd"))
+	}
+}
+
+func TestNodeLimitPerDocument(t *testing.T) {
+	parser := NewStreamJSONParser(WithMultipleDocuments(), WithMaxNodes(3))
+	parser.Append("[1,2]\n[3,4]\n[5,6]\n")
+
+	if parser.Err() != nil {
+		t.Errorf("Expected limit to apply per document, got %v", parser.Err())
+	}
+	if len(parser.Documents()) != 3 {
+		t.Errorf("Expected 3 documents, got %d", len(parser.Documents()))
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// MarshalJSON serializes the current, possibly incomplete, AST as valid JSON.
+// Open strings, objects and arrays are closed, and a key still waiting for
+// its value is left out. Object keys are written in sorted order. NaN and
+// infinite numbers, which JSON cannot represent, are written as null. Before
+// the root has started the result is null.
+func (p *StreamJSONParser) MarshalJSON() ([]byte, error) {
+	defer p.guardRead("MarshalJSON")()
+	if p.root == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	p.writeNode(&buf, p.root)
+	return buf.Bytes(), nil
+}
+
+// String returns the current AST as JSON, see MarshalJSON
+func (p *StreamJSONParser) String() string {
+	data, _ := p.MarshalJSON()
+	return string(data)
+}
+
+// writeNode appends the JSON encoding of node to buf. Nested values are
+// written with an explicit stack of the nodes and closing brackets to come.
+func (p *StreamJSONParser) writeNode(buf *bytes.Buffer, node *Node) {
+	type writeTask struct {
+		node  *Node
+		key   string
+		keyed bool // Whether key is written before the node
+		comma bool // Whether a comma is written before the node
+		close byte // Closing bracket to write instead of a node
+	}
+
+	stack := []writeTask{{node: node}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if task.close != 0 {
+			buf.WriteByte(task.close)
+			continue
+		}
+		if task.comma {
+			buf.WriteByte(',')
+		}
+		if task.keyed {
+			p.writeString(buf, task.key, true)
+			buf.WriteByte(':')
+		}
+
+		// Members are pushed in reverse so they are written in order
+		switch node := task.node; node.Type {
+		case ObjectNode:
+			keys := make([]string, 0, len(node.Children))
+			for key := range node.Children {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			buf.WriteByte('{')
+			stack = append(stack, writeTask{close: '}'})
+			for i := len(keys) - 1; i >= 0; i-- {
+				stack = append(stack, writeTask{node: node.Children[keys[i]], key: keys[i], keyed: true, comma: i > 0})
+			}
+
+		case ArrayNode:
+			buf.WriteByte('[')
+			stack = append(stack, writeTask{close: ']'})
+			for i := len(node.Array) - 1; i >= 0; i-- {
+				stack = append(stack, writeTask{node: node.Array[i], comma: i > 0})
+			}
+
+		case ValueNode:
+			p.writeValue(buf, node)
+		}
+	}
+}
+
+// writeValue appends the JSON encoding of a scalar node to buf
+func (p *StreamJSONParser) writeValue(buf *bytes.Buffer, node *Node) {
+	switch v := node.Value.(type) {
+	case string:
+		p.writeString(buf, v, node.Completed)
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			buf.WriteString("null") // JSON has no literal for them
+			return
+		}
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case json.Number:
+		buf.WriteString(string(v))
+	case *big.Float:
+		if v.IsInf() {
+			buf.WriteString("null")
+			return
+		}
+		buf.WriteString(v.Text('g', -1))
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	default:
+		// Values produced by transformers, such as time.Time
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte("null")
+		}
+		buf.Write(data)
+	}
+}
+
+// writeString appends s as a quoted JSON string. With raw strings enabled s
+// still holds its escape sequences and is decoded first, so the output is
+// valid even when a partial string ends inside an escape.
+func (p *StreamJSONParser) writeString(buf *bytes.Buffer, s string, completed bool) {
+	if p.options.rawStrings {
+		s = decodeString(s, !completed)
+	}
+
+	buf.WriteByte('"')
+	writeEscaped(buf, s)
+	buf.WriteByte('"')
+}
+
+// writeEscaped appends s to buf with the escaping of a JSON string body.
+// Invalid UTF-8 is replaced with \ufffd.
+func writeEscaped(buf *bytes.Buffer, s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case c == '\n':
+				buf.WriteString(`\n`)
+			case c == '\r':
+				buf.WriteString(`\r`)
+			case c == '\t':
+				buf.WriteString(`\t`)
+			case c < 0x20:
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hexDigits[c>>4])
+				buf.WriteByte(hexDigits[c&0xF])
+			default:
+				buf.WriteByte(c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf.WriteString(`\ufffd`)
+		} else {
+			buf.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+}
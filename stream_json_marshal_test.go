@@ -0,0 +1,104 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestMarshalJSONPartial(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	if parser.String() != "null" {
+		t.Errorf("Expected null before the root, got %s", parser.String())
+	}
+
+	parser.Append(`{"title":"Say \"hi\"\n","items":[1,2.5,true,null,{"name":"Al`)
+	expected := `{"items":[1,2.5,true,null,{"name":"Al"}],"title":"Say \"hi\"\n"}`
+	if parser.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, parser.String())
+	}
+
+	// A key waiting for its value is left out, an unterminated number too
+	parser.Append(`ice"}],"count":12`)
+	expected = `{"items":[1,2.5,true,null,{"name":"Alice"}],"title":"Say \"hi\"\n"}`
+	if parser.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, parser.String())
+	}
+
+	parser.Append(`}`)
+	data, err := json.Marshal(parser)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = `{"count":12,"items":[1,2.5,true,null,{"name":"Alice"}],"title":"Say \"hi\"\n"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestMarshalJSONAlwaysValid(t *testing.T) {
+	input := `{"a":[{"b":"xé😀\\"},[],{}],"c":{"d":"\t"}}`
+
+	for _, raw := range []bool{false, true} {
+		var opts []Option
+		if raw {
+			opts = append(opts, WithRawStrings())
+		}
+		parser := NewStreamJSONParser(opts...)
+		for i := 0; i < len(input); i++ {
+			parser.Append(input[i : i+1])
+			if !json.Valid([]byte(parser.String())) {
+				t.Fatalf("Invalid JSON after %q (raw %v): %s", input[:i+1], raw, parser.String())
+			}
+		}
+
+		var got, want interface{}
+		json.Unmarshal([]byte(parser.String()), &got)
+		json.Unmarshal([]byte(input), &want)
+		gotData, _ := json.Marshal(got)
+		wantData, _ := json.Marshal(want)
+		if string(gotData) != string(wantData) {
+			t.Errorf("Expected round trip %s, got %s (raw %v)", wantData, gotData, raw)
+		}
+	}
+}
+
+func TestMarshalJSONNonFinite(t *testing.T) {
+	input := `{"nan":NaN,"inf":[Infinity,-Infinity],"n":2}`
+	parser := NewStreamJSONParser(WithNonFiniteNumbers(math.NaN(), math.Inf(1), math.Inf(-1)))
+	for i := 0; i < len(input); i++ {
+		parser.Append(input[i : i+1])
+		if !json.Valid([]byte(parser.String())) {
+			t.Fatalf("Invalid JSON after %q: %s", input[:i+1], parser.String())
+		}
+	}
+	if expected := `{"inf":[null,null],"n":2,"nan":null}`; parser.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, parser.String())
+	}
+
+	// Infinite *big.Float values, such as from a transformer, too
+	parser = NewStreamJSONParser(WithNumberMode(NumberAsBigFloat))
+	parser.Transform("x", func(value interface{}) (interface{}, error) {
+		return new(big.Float).SetInf(false), nil
+	})
+	parser.Append(`{"x":1}`)
+	if data, err := parser.MarshalJSON(); err != nil || string(data) != `{"x":null}` {
+		t.Errorf("Expected an infinite big.Float as null, got %s, %v", data, err)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// DifferenceKind identifies how the stream differs from a reference document
+type DifferenceKind int
+
+const (
+	DifferenceMissing    DifferenceKind = iota // The reference has a value the stream lacks
+	DifferenceUnexpected                       // The stream has a value the reference lacks
+	DifferenceValue                            // The values or their types differ
+	DifferenceIncomplete                       // The stream value is not completed
+)
+
+// Difference describes where the parsed document differs from a reference
+type Difference struct {
+	Kind DifferenceKind
+	Path []string    // Path of the value from the root
+	Got  interface{} // Parsed value, nil for DifferenceMissing
+	Want interface{} // Reference value with numbers as json.Number, nil for DifferenceUnexpected
+}
+
+// MatchesFinal compares the document against the reference JSON in
+// expected, ignoring whitespace, key order and how numbers are written, so
+// 1.0 matches 1 and 1e3 matches 1000. Differences are ordered by path with
+// object keys sorted. A reference that is not valid JSON never matches and
+// is reported as a DifferenceValue at the root.
+func (p *StreamJSONParser) MatchesFinal(expected []byte) (bool, []Difference) {
+	defer p.guardRead("MatchesFinal")()
+	decoder := json.NewDecoder(bytes.NewReader(expected))
+	decoder.UseNumber()
+	var want interface{}
+	if err := decoder.Decode(&want); err != nil {
+		return false, []Difference{{Kind: DifferenceValue, Got: p.Get()}}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return false, []Difference{{Kind: DifferenceValue, Got: p.Get()}}
+	}
+
+	if p.root == nil {
+		return false, []Difference{{Kind: DifferenceMissing, Want: want}}
+	}
+	var differences []Difference
+	p.matchNode(p.root, want, &differences)
+	return len(differences) == 0, differences
+}
+
+// matchNode appends the differences between node and want to differences,
+// in document order. Nested values are compared with an explicit stack.
+func (p *StreamJSONParser) matchNode(node *Node, want interface{}, differences *[]Difference) {
+	type matchTask struct {
+		node   *Node
+		want   interface{}
+		link   int
+		report bool           // Whether to report a difference of kind instead of comparing
+		kind   DifferenceKind // Missing or unexpected member
+	}
+
+	var links pathLinks
+	stack := []matchTask{{node: node, want: want}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node, want := task.node, task.want
+		if task.report {
+			*differences = append(*differences, Difference{
+				Kind: task.kind, Path: links.path(nil, task.link), Got: collectValue(node, true), Want: want,
+			})
+			continue
+		}
+		mismatch := func() {
+			*differences = append(*differences, Difference{
+				Kind: DifferenceValue, Path: links.path(nil, task.link), Got: collectValue(node, true), Want: want,
+			})
+		}
+		if !node.Completed {
+			*differences = append(*differences, Difference{
+				Kind: DifferenceIncomplete, Path: links.path(nil, task.link), Got: collectValue(node, true), Want: want,
+			})
+			if node.Type == ValueNode {
+				continue
+			}
+		}
+
+		// Children are pushed in reverse so they are compared in order
+		var children []matchTask
+		switch node.Type {
+		case ObjectNode:
+			members, ok := want.(map[string]interface{})
+			if !ok {
+				mismatch()
+				continue
+			}
+			keys := node.Keys()
+			for key := range members {
+				if _, ok := node.Children[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				child := node.Children[key]
+				value, ok := members[key]
+				link := links.add(task.link, key)
+				switch {
+				case child == nil:
+					children = append(children, matchTask{want: value, link: link, report: true, kind: DifferenceMissing})
+				case !ok:
+					children = append(children, matchTask{node: child, link: link, report: true, kind: DifferenceUnexpected})
+				default:
+					children = append(children, matchTask{node: child, want: value, link: link})
+				}
+			}
+
+		case ArrayNode:
+			elements, ok := want.([]interface{})
+			if !ok {
+				mismatch()
+				continue
+			}
+			for i := 0; i < len(node.Array) || i < len(elements); i++ {
+				link := links.add(task.link, strconv.Itoa(i))
+				switch {
+				case i >= len(node.Array):
+					children = append(children, matchTask{want: elements[i], link: link, report: true, kind: DifferenceMissing})
+				case i >= len(elements):
+					children = append(children, matchTask{node: node.Array[i], link: link, report: true, kind: DifferenceUnexpected})
+				default:
+					children = append(children, matchTask{node: node.Array[i], want: elements[i], link: link})
+				}
+			}
+
+		default:
+			if number, ok := want.(json.Number); ok {
+				got, ok := nodeRat(node)
+				expected, valid := new(big.Rat).SetString(string(number))
+				if !ok || !valid || got.Cmp(expected) != 0 {
+					mismatch()
+				}
+				continue
+			}
+			if _, ok := nodeRat(node); ok || node.Value != want {
+				mismatch()
+			}
+		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+}
+
+// nodeRat returns the exact value of a number node, from its literal when
+// the parser kept one
+func nodeRat(node *Node) (*big.Rat, bool) {
+	if node.literal != "" {
+		return new(big.Rat).SetString(node.literal)
+	}
+	switch v := node.Value.(type) {
+	case int64:
+		return new(big.Rat).SetInt64(v), true
+	case float64:
+		rat := new(big.Rat)
+		if rat.SetFloat64(v) == nil {
+			return nil, false
+		}
+		return rat, true
+	case json.Number:
+		return new(big.Rat).SetString(string(v))
+	case *big.Float:
+		if v.IsInf() {
+			return nil, false
+		}
+		rat, _ := v.Rat(nil)
+		return rat, true
+	}
+	return nil, false
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStreamJSONParserMatchesFinal(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"b": [1, 2.50, 1e3], "a": {"x": "y", "ok": true, "none": null}}`)
+
+	ok, differences := parser.MatchesFinal([]byte(`
+		{"a": {"none": null, "ok": true, "x": "y"}, "b": [1.0, 2.5, 1000]}`))
+	if !ok || len(differences) != 0 {
+		t.Errorf("Expected a match, got %+v", differences)
+	}
+}
+
+func TestStreamJSONParserMatchesFinalDifferences(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"name": "Ada", "age": 36, "tags": ["x", "y"], "extra": 1, "kind": {}}`)
+
+	ok, differences := parser.MatchesFinal([]byte(
+		`{"name": "Ada", "age": 37, "tags": ["x"], "role": "admin", "kind": "person"}`))
+	if ok {
+		t.Fatal("Expected no match")
+	}
+	want := []Difference{
+		{Kind: DifferenceValue, Path: []string{"age"}, Got: int64(36), Want: json.Number("37")},
+		{Kind: DifferenceUnexpected, Path: []string{"extra"}, Got: int64(1)},
+		{Kind: DifferenceValue, Path: []string{"kind"}, Got: map[string]interface{}{}, Want: "person"},
+		{Kind: DifferenceMissing, Path: []string{"role"}, Want: "admin"},
+		{Kind: DifferenceUnexpected, Path: []string{"tags", "1"}, Got: "y"},
+	}
+	if !reflect.DeepEqual(differences, want) {
+		t.Errorf("Expected %+v, got %+v", want, differences)
+	}
+}
+
+func TestStreamJSONParserMatchesFinalIncomplete(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"text": "Hel`)
+
+	ok, differences := parser.MatchesFinal([]byte(`{"text": "Hello"}`))
+	want := []Difference{
+		{Kind: DifferenceIncomplete, Got: map[string]interface{}{"text": "Hel"},
+			Want: map[string]interface{}{"text": "Hello"}},
+		{Kind: DifferenceIncomplete, Path: []string{"text"}, Got: "Hel", Want: "Hello"},
+	}
+	if ok || !reflect.DeepEqual(differences, want) {
+		t.Errorf("Expected incomplete differences, got %+v", differences)
+	}
+
+	parser.Append(`lo"}`)
+	if ok, differences := parser.MatchesFinal([]byte(`{"text": "Hello"}`)); !ok {
+		t.Errorf("Expected a match once complete, got %+v", differences)
+	}
+}
+
+func TestStreamJSONParserMatchesFinalNumberModes(t *testing.T) {
+	for _, mode := range []NumberMode{NumberAsFloat64, NumberAsJSONNumber, NumberAsBigFloat} {
+		parser := NewStreamJSONParser(WithNumberMode(mode))
+		parser.Append(`[12345678901234567890, 0.1]`)
+		if ok, differences := parser.MatchesFinal([]byte(`[1.2345678901234567890e19, 1e-1]`)); !ok {
+			t.Errorf("Mode %v: expected a match, got %+v", mode, differences)
+		}
+	}
+}
+
+func TestStreamJSONParserMatchesFinalInvalidReference(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{}`)
+
+	for _, expected := range []string{`{`, `{} {}`, ``} {
+		ok, differences := parser.MatchesFinal([]byte(expected))
+		if ok || len(differences) != 1 || differences[0].Kind != DifferenceValue {
+			t.Errorf("Expected %q to never match, got %+v", expected, differences)
+		}
+	}
+	if ok, _ := NewStreamJSONParser().MatchesFinal([]byte(`{}`)); ok {
+		t.Error("Expected an empty parser not to match")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+)
+
+// ErrMemoryBudget is returned by Err when the tree exceeds the budget set
+// with WithMemoryBudget and no completed string is left to evict
+var ErrMemoryBudget = errors.New("streamjson: memory budget exceeded")
+
+// Approximate bytes held by a node and by an object member besides their
+// strings, for memory accounting
+const (
+	nodeOverhead   = 96
+	memberOverhead = 32
+)
+
+// memoryLeaf is a completed string that can be evicted to meet the budget
+type memoryLeaf struct {
+	node *Node
+	path []string
+	size int
+}
+
+// MemoryUsage returns the approximate number of bytes held by the value at
+// the path, the whole document if no keys are given: node overhead plus the
+// length of keys and strings.
+func (p *StreamJSONParser) MemoryUsage(keys ...string) int {
+	defer p.guardRead("MemoryUsage")()
+	node := p.findNode(keys)
+	if node == nil {
+		return 0
+	}
+	return p.measureNode(node, nil, nil)
+}
+
+// trackMemory adds the growth of the tree to the running estimate and
+// enforces the budget once the estimate exceeds it
+func (p *StreamJSONParser) trackMemory(grown int) {
+	p.memory += grown
+	if p.memory > p.options.memoryBudget {
+		p.enforceMemoryBudget()
+	}
+}
+
+// stringLen returns the length of a string value, 0 for other values
+func stringLen(value interface{}) int {
+	text, _ := value.(string)
+	return len(text)
+}
+
+// enforceMemoryBudget measures the tree and, while it is over the budget,
+// empties the largest completed strings of the current document. It frees
+// down to three quarters of the budget, so evictions come in batches rather
+// than on every token.
+func (p *StreamJSONParser) enforceMemoryBudget() {
+	budget := p.options.memoryBudget
+	var leaves []memoryLeaf
+	p.memory = 0
+	for _, document := range p.documents {
+		p.memory += p.measureNode(document, nil, nil)
+	}
+	if p.root != nil {
+		p.memory += p.measureNode(p.root, []string{}, &leaves)
+	}
+	if p.memory <= budget {
+		return
+	}
+
+	slices.SortStableFunc(leaves, func(a, b memoryLeaf) int { return b.size - a.size })
+	target := budget - budget/4
+	for _, leaf := range leaves {
+		if p.memory <= target {
+			break
+		}
+		leaf.node.Value = ""
+		leaf.node.Truncated = true
+		p.memory -= leaf.size
+		if p.events != nil && !p.closed() {
+			p.emit(Event{Type: ValueEvicted, Path: leaf.path, Value: leaf.size})
+		}
+	}
+	if p.memory > budget && p.err == nil {
+		p.err = ErrMemoryBudget
+	}
+}
+
+// measureNode returns the approximate bytes held by node and its
+// descendants. With leaves non-nil, completed strings are collected with
+// their paths under path.
+func (p *StreamJSONParser) measureNode(node *Node, path []string, leaves *[]memoryLeaf) int {
+	type measureTask struct {
+		node *Node
+		link int
+	}
+
+	var links pathLinks
+	size := 0
+	stack := []measureTask{{node: node}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := task.node
+		size += nodeOverhead
+		switch node.Type {
+		case ObjectNode:
+			for key, child := range node.Children {
+				size += memberOverhead + len(key)
+				stack = append(stack, measureTask{child, childLeafLink(&links, task.link, key, leaves)})
+			}
+		case ArrayNode:
+			evicted := 0
+			if leaves != nil && !node.Completed {
+				evicted = p.evictedElements(node) // Indices count elements StreamArray removed
+			}
+			for i, child := range node.Array {
+				stack = append(stack, measureTask{child, childLeafLink(&links, task.link, strconv.Itoa(evicted+i), leaves)})
+			}
+		default:
+			if text, ok := node.Value.(string); ok {
+				size += len(text)
+				if leaves != nil && node.Completed && len(text) > 0 {
+					*leaves = append(*leaves, memoryLeaf{node: node, path: links.path(path, task.link), size: len(text)})
+				}
+			}
+		}
+	}
+	return size
+}
+
+// childLeafLink returns the link of a child's path when leaves are collected
+func childLeafLink(links *pathLinks, parent int, key string, leaves *[]memoryLeaf) int {
+	if leaves == nil {
+		return 0
+	}
+	return links.add(parent, key)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStreamJSONParserMemoryUsage(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"small": "ab", "big": "` + strings.Repeat("x", 1000) + `", "list": [1, 2]}`)
+
+	if usage := parser.MemoryUsage("big"); usage != nodeOverhead+1000 {
+		t.Errorf("Expected %d bytes for the big string, got %d", nodeOverhead+1000, usage)
+	}
+	if usage := parser.MemoryUsage("list"); usage != 3*nodeOverhead {
+		t.Errorf("Expected %d bytes for the list, got %d", 3*nodeOverhead, usage)
+	}
+	if total := parser.MemoryUsage(); total <= parser.MemoryUsage("big")+parser.MemoryUsage("list") {
+		t.Errorf("Expected the document to include every member, got %d", total)
+	}
+	if parser.MemoryUsage("missing") != 0 {
+		t.Error("Expected no usage for a missing path")
+	}
+}
+
+func TestStreamJSONParserMemoryBudget(t *testing.T) {
+	const budget = 8 << 10
+	parser := NewStreamJSONParser(WithMemoryBudget(budget))
+	events := parser.Events()
+
+	parser.Append(`{"keep": "short", "log": [`)
+	for i := 0; i < 20; i++ {
+		if i > 0 {
+			parser.Append(", ")
+		}
+		parser.Append(`"` + strings.Repeat("x", 1000+i) + `"`)
+		if usage := parser.MemoryUsage(); usage > budget {
+			t.Fatalf("Usage %d over budget after element %d", usage, i)
+		}
+	}
+	parser.Append(`]}`)
+
+	if parser.Err() != nil || !parser.IsCompleted() {
+		t.Fatalf("Expected the document to complete, err %v", parser.Err())
+	}
+	if parser.Get("keep") != "short" || parser.IsTruncated("keep") {
+		t.Error("Expected small strings to be kept")
+	}
+	if parser.Get("log", "19") == "" {
+		t.Error("Expected the last string to be kept")
+	}
+
+	evicted := 0
+	for event := range events {
+		if event.Type != ValueEvicted {
+			continue
+		}
+		evicted++
+		if len(event.Path) != 2 || event.Path[0] != "log" || !parser.IsTruncated(event.Path...) || parser.Get(event.Path...) != "" {
+			t.Errorf("Unexpected eviction of %v", event.Path)
+		}
+	}
+	if evicted == 0 {
+		t.Error("Expected ValueEvicted events")
+	}
+}
+
+func TestStreamJSONParserMemoryBudgetLargestFirst(t *testing.T) {
+	parser := NewStreamJSONParser(WithMemoryBudget(4000))
+	parser.Append(`{"a": "` + strings.Repeat("a", 500) + `", "b": "` + strings.Repeat("b", 2500) + `", "c": "` + strings.Repeat("c", 1000) + `"}`)
+
+	if !parser.IsTruncated("b") || parser.IsTruncated("a") || parser.IsTruncated("c") {
+		t.Errorf("Expected only the largest string to be evicted, got %v", parser.Get())
+	}
+}
+
+func TestStreamJSONParserMemoryBudgetExceeded(t *testing.T) {
+	parser := NewStreamJSONParser(WithMemoryBudget(1000))
+	parser.Append(`{"text": "` + strings.Repeat("x", 2000))
+
+	if !errors.Is(parser.Err(), ErrMemoryBudget) {
+		t.Errorf("Expected ErrMemoryBudget for a streaming string over the budget, got %v", parser.Err())
+	}
+}
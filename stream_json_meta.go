@@ -0,0 +1,58 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"time"
+)
+
+// valueTimes holds when a node started and completed
+type valueTimes struct {
+	started   time.Time
+	completed time.Time
+}
+
+// Meta describes where a value is in the input and when it arrived
+type Meta struct {
+	Start       int       // Offset of the value's first byte in the input
+	End         int       // Offset just past its last byte so far, 0 while an object or array is open
+	Completed   bool      // Whether the value is complete
+	StartedAt   time.Time // When the value started, with WithTimestamps
+	CompletedAt time.Time // When the value completed, with WithTimestamps
+}
+
+// Meta returns the byte range in the input of the value at the path and,
+// with WithTimestamps, when it started and completed, for relating fields
+// to model latency or to the tokens that produced them. Offsets count from
+// the start of the input, including text before the root, so the source of
+// a completed value is input[Start:End]; with WithCodeFenceExtraction they
+// count the extracted payload. It returns false for missing paths.
+func (p *StreamJSONParser) Meta(keys ...string) (Meta, bool) {
+	defer p.guardRead("Meta")()
+	if p.root == nil {
+		return Meta{}, false
+	}
+	node := p.findNode(keys)
+	if node == nil {
+		return Meta{}, false
+	}
+
+	meta := Meta{Start: node.start, End: node.end, Completed: node.Completed}
+	if node.times != nil {
+		meta.StartedAt = node.times.started
+		meta.CompletedAt = node.times.completed
+	}
+	return meta, true
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetaOffsets(t *testing.T) {
+	input := `Result: {"name": "Ada", "tags": ["x", "y"]}`
+	parser := NewStreamJSONParser()
+	parser.Append(input[:25])
+
+	meta, ok := parser.Meta("name")
+	if !ok || !meta.Completed || input[meta.Start:meta.End] != `"Ada"` {
+		t.Errorf("Unexpected meta for name: %+v", meta)
+	}
+	if meta, _ := parser.Meta(); meta.Start != 8 || meta.End != 0 || meta.Completed {
+		t.Errorf("Expected the open root to start at 8, got %+v", meta)
+	}
+
+	parser.Append(input[25:40])
+	if meta, _ := parser.Meta("tags", "0"); input[meta.Start:meta.End] != `"x"` {
+		t.Errorf("Unexpected meta for tags.0: %+v", meta)
+	}
+	if meta, _ := parser.Meta("tags", "1"); meta.Completed || input[meta.Start:meta.End] != `"y` {
+		t.Errorf("Expected the streaming string so far, got %+v", meta)
+	}
+
+	parser.Append(input[40:])
+	if meta, _ := parser.Meta(); input[meta.Start:meta.End] != input[8:] {
+		t.Errorf("Expected the root to span the document, got %+v", meta)
+	}
+	if _, ok := parser.Meta("missing"); ok {
+		t.Errorf("Expected no meta for a missing path")
+	}
+	if meta, _ := parser.Meta("name"); !meta.StartedAt.IsZero() || !meta.CompletedAt.IsZero() {
+		t.Errorf("Expected no timestamps without WithTimestamps, got %+v", meta)
+	}
+}
+
+func TestMetaTimestamps(t *testing.T) {
+	parser := NewStreamJSONParser(WithTimestamps())
+	before := time.Now()
+	parser.Append(`{"a": 1, "b": "te`)
+
+	meta, _ := parser.Meta("b")
+	if meta.StartedAt.Before(before) || !meta.CompletedAt.IsZero() {
+		t.Errorf("Expected a start time only while streaming, got %+v", meta)
+	}
+
+	time.Sleep(time.Millisecond)
+	parser.Append(`xt"}`)
+
+	a, _ := parser.Meta("a")
+	b, _ := parser.Meta("b")
+	if a.CompletedAt.IsZero() || !b.CompletedAt.After(a.CompletedAt) || b.CompletedAt.Sub(b.StartedAt) < time.Millisecond {
+		t.Errorf("Expected b to complete a chunk later, got %+v and %+v", a, b)
+	}
+}
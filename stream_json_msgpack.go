@@ -0,0 +1,143 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// msgpackTimestamp is the extension type of MessagePack timestamps
+const msgpackTimestamp = -1
+
+// NewMessagePackFeeder creates a feeder that decodes a stream of
+// MessagePack objects and appends their JSON equivalent to target.
+// Consecutive objects become consecutive root documents.
+func NewMessagePackFeeder(target Appender) *BinaryFeeder {
+	return newBinaryFeeder(target, decodeMessagePack)
+}
+
+// decodeMessagePack decodes the header of a MessagePack object
+func decodeMessagePack(data []byte) (binaryItem, int, error) {
+	if len(data) == 0 {
+		return binaryItem{}, 0, nil
+	}
+	b := data[0]
+
+	switch {
+	case b <= 0x7f:
+		return binaryItem{kind: binaryScalar, value: int64(b)}, 1, nil
+	case b >= 0xe0:
+		return binaryItem{kind: binaryScalar, value: int64(int8(b))}, 1, nil
+	case b <= 0x8f:
+		return binaryItem{kind: binaryMap, length: int(b & 0x0f)}, 1, nil
+	case b <= 0x9f:
+		return binaryItem{kind: binaryArray, length: int(b & 0x0f)}, 1, nil
+	case b <= 0xbf:
+		return binaryItem{kind: binaryText, length: int(b & 0x1f)}, 1, nil
+	}
+
+	switch b {
+	case 0xc0:
+		return binaryItem{kind: binaryScalar, value: nil}, 1, nil
+	case 0xc2:
+		return binaryItem{kind: binaryScalar, value: false}, 1, nil
+	case 0xc3:
+		return binaryItem{kind: binaryScalar, value: true}, 1, nil
+	case 0xc4, 0xc5, 0xc6:
+		return msgpackLength(data, binaryBytes, 1<<(b-0xc4))
+	case 0xc7, 0xc8, 0xc9:
+		size := 1 << (b - 0xc7)
+		if len(data) < 2+size {
+			return binaryItem{}, 0, nil
+		}
+		item, n, err := msgpackLength(data, binaryExt, size)
+		item.ext = int8(data[1+size])
+		return item, n + 1, err
+	case 0xca, 0xcb:
+		size := 4 << (b - 0xca)
+		if len(data) < 1+size {
+			return binaryItem{}, 0, nil
+		}
+		if size == 4 {
+			return binaryItem{kind: binaryScalar, value: float64(math.Float32frombits(binary.BigEndian.Uint32(data[1:])))}, 5, nil
+		}
+		return binaryItem{kind: binaryScalar, value: math.Float64frombits(binary.BigEndian.Uint64(data[1:]))}, 9, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		size := 1 << (b - 0xcc)
+		if len(data) < 1+size {
+			return binaryItem{}, 0, nil
+		}
+		return binaryItem{kind: binaryScalar, value: unsignedValue(readUint(data[1 : 1+size]))}, 1 + size, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (b - 0xd0)
+		if len(data) < 1+size {
+			return binaryItem{}, 0, nil
+		}
+		v := readUint(data[1 : 1+size])
+		shift := 64 - 8*size
+		return binaryItem{kind: binaryScalar, value: int64(v<<shift) >> shift}, 1 + size, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		if len(data) < 2 {
+			return binaryItem{}, 0, nil
+		}
+		return binaryItem{kind: binaryExt, length: 1 << (b - 0xd4), ext: int8(data[1])}, 2, nil
+	case 0xd9, 0xda, 0xdb:
+		return msgpackLength(data, binaryText, 1<<(b-0xd9))
+	case 0xdc, 0xdd:
+		return msgpackLength(data, binaryArray, 2<<(b-0xdc))
+	case 0xde, 0xdf:
+		return msgpackLength(data, binaryMap, 2<<(b-0xde))
+	}
+	return binaryItem{}, 0, binaryError("invalid MessagePack type 0x%02x", b)
+}
+
+// msgpackLength decodes an item whose length follows its type byte in size
+// bytes
+func msgpackLength(data []byte, kind binaryKind, size int) (binaryItem, int, error) {
+	if len(data) < 1+size {
+		return binaryItem{}, 0, nil
+	}
+	length := readUint(data[1 : 1+size])
+	if length > math.MaxInt32 {
+		return binaryItem{}, 0, binaryError("MessagePack length %d is too large", length)
+	}
+	return binaryItem{kind: kind, length: int(length)}, 1 + size, nil
+}
+
+// extensionValue maps a MessagePack extension to a JSON value: timestamps
+// become RFC 3339 strings and other extensions null
+func extensionValue(ext int8, data []byte) interface{} {
+	if ext != msgpackTimestamp {
+		return nil
+	}
+	switch len(data) {
+	case 4:
+		return formatTimestamp(time.Unix(int64(binary.BigEndian.Uint32(data)), 0))
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return formatTimestamp(time.Unix(int64(v&0x3ffffffff), int64(v>>34)))
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		return formatTimestamp(time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(nsec)))
+	}
+	return nil
+}
+
+// formatTimestamp formats a timestamp as an RFC 3339 string in UTC
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
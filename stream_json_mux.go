@@ -0,0 +1,170 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrStreamExists is returned by Multiplexer.Open for an ID already open
+var ErrStreamExists = errors.New("streamjson: stream already open")
+
+// MuxEvent is an event of one stream of a Multiplexer
+type MuxEvent struct {
+	StreamID string
+	Event          // Zero for the event that ends the stream
+	Closed   bool  // Whether the stream was closed, as the last event of its ID
+	Err      error // Error that stopped the stream's parser, for the closing event
+}
+
+// Multiplexer parses many concurrent streams, such as one per tool call or
+// per request in a server, keyed by stream ID. Streams are created with the
+// multiplexer's options, share the package's node pools, and publish their
+// events on a single channel. It is safe for concurrent use; Append on
+// different streams runs in parallel.
+type Multiplexer struct {
+	opts    []Option
+	mu      sync.RWMutex
+	streams map[string]*muxStream
+	events  chan MuxEvent // Shared event channel, created by Events
+}
+
+// muxStream is an open stream of a Multiplexer
+type muxStream struct {
+	parser    *SafeStreamJSONParser
+	publishes bool // Whether the stream was opened after Events
+}
+
+// NewMultiplexer creates a multiplexer whose streams are configured by opts
+func NewMultiplexer(opts ...Option) *Multiplexer {
+	return &Multiplexer{
+		opts:    opts,
+		streams: make(map[string]*muxStream),
+	}
+}
+
+// Events returns the channel of events from all streams, each tagged with
+// its stream ID. Call it before opening streams; only streams opened
+// afterwards publish events. Append blocks while the channel is full, so it
+// must be drained. The channel is never closed, as streams can be opened at
+// any time.
+func (m *Multiplexer) Events() <-chan MuxEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.events == nil {
+		m.events = make(chan MuxEvent, eventBufferSize)
+	}
+	return m.events
+}
+
+// Open starts a stream with the multiplexer's options followed by opts,
+// which override them, and returns its parser for reading values. Content
+// must be added through the Multiplexer. It returns ErrStreamExists if id
+// is already open.
+func (m *Multiplexer) Open(id string, opts ...Option) (*SafeStreamJSONParser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.streams[id]; ok {
+		return nil, ErrStreamExists
+	}
+	return m.open(id, opts).parser, nil
+}
+
+// open creates and registers the stream id; m.mu must be held
+func (m *Multiplexer) open(id string, opts []Option) *muxStream {
+	if len(opts) > 0 {
+		opts = append(append([]Option(nil), m.opts...), opts...)
+	} else {
+		opts = m.opts
+	}
+	stream := &muxStream{parser: NewSafeStreamJSONParser(opts...)}
+	if events := m.events; events != nil {
+		stream.parser.parser.sendEventsTo(func(event Event) {
+			events <- MuxEvent{StreamID: id, Event: event}
+		})
+		stream.publishes = true
+	}
+	m.streams[id] = stream
+	return stream
+}
+
+// Append adds content to the stream id, opening it on first use
+func (m *Multiplexer) Append(id string, content string) {
+	m.mu.RLock()
+	stream, ok := m.streams[id]
+	m.mu.RUnlock()
+
+	if !ok {
+		m.mu.Lock()
+		if stream, ok = m.streams[id]; !ok {
+			stream = m.open(id, nil)
+		}
+		m.mu.Unlock()
+	}
+	stream.parser.Append(content)
+}
+
+// Parser returns the parser of the stream id, or nil if it is not open
+func (m *Multiplexer) Parser(id string) *SafeStreamJSONParser {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if stream, ok := m.streams[id]; ok {
+		return stream.parser
+	}
+	return nil
+}
+
+// Close finishes the stream id and removes it, so the ID can be opened
+// again. With Events, the stream's last event has Closed set. It returns
+// the error that stopped the stream's parser, and nil for IDs not open.
+// Parsers returned earlier stay readable.
+func (m *Multiplexer) Close(id string) error {
+	m.mu.Lock()
+	stream, ok := m.streams[id]
+	delete(m.streams, id)
+	events := m.events
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	stream.parser.Finish()
+	err := stream.parser.Err()
+	if stream.publishes {
+		events <- MuxEvent{StreamID: id, Closed: true, Err: err}
+	}
+	return err
+}
+
+// Len returns the number of open streams
+func (m *Multiplexer) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.streams)
+}
+
+// IDs returns the IDs of the open streams in sorted order
+func (m *Multiplexer) IDs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.streams))
+	for id := range m.streams {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
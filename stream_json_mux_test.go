@@ -0,0 +1,125 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMultiplexerStreams(t *testing.T) {
+	mux := NewMultiplexer()
+
+	const streams = 50
+	var wg sync.WaitGroup
+	for i := 0; i < streams; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("call-%02d", i)
+			mux.Append(id, `{"id":`)
+			mux.Append(id, fmt.Sprintf(`%d,"name":"tool`, i))
+			mux.Append(id, `"}`)
+		}(i)
+	}
+	wg.Wait()
+
+	if mux.Len() != streams {
+		t.Fatalf("Expected %d streams, got %d", streams, mux.Len())
+	}
+	for i := 0; i < streams; i++ {
+		id := fmt.Sprintf("call-%02d", i)
+		if got := mux.Parser(id).Get("id"); got != int64(i) {
+			t.Errorf("Expected %s to have id %d, got %v", id, i, got)
+		}
+	}
+	if ids := mux.IDs(); len(ids) != streams || ids[0] != "call-00" || ids[streams-1] != "call-49" {
+		t.Errorf("Expected sorted IDs, got %v", ids)
+	}
+
+	parser := mux.Parser("call-07")
+	if err := mux.Close("call-07"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if mux.Parser("call-07") != nil || mux.Len() != streams-1 {
+		t.Errorf("Expected the closed stream to be removed")
+	}
+	if parser.Get("name") != "tool" {
+		t.Errorf("Expected the closed stream's parser to stay readable, got %v", parser.Get())
+	}
+	if err := mux.Close("missing"); err != nil {
+		t.Errorf("Expected nil for a stream not open, got %v", err)
+	}
+}
+
+func TestMultiplexerOpen(t *testing.T) {
+	mux := NewMultiplexer(WithMaxDepth(1))
+
+	parser, err := mux.Open("deep", WithMaxDepth(8))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := mux.Open("deep"); !errors.Is(err, ErrStreamExists) {
+		t.Errorf("Expected ErrStreamExists, got %v", err)
+	}
+
+	mux.Append("shallow", `{"a":{"b":1}}`)
+	if err := mux.Close("shallow"); !errors.Is(err, ErrDepthLimit) {
+		t.Errorf("Expected the multiplexer's options for streams opened by Append, got %v", err)
+	}
+
+	mux.Append("deep", `{"a":{"b":1}}`)
+	if parser.Get("a", "b") != int64(1) || parser.Err() != nil {
+		t.Errorf("Expected the options of Open to override, got %v, %v", parser.Get(), parser.Err())
+	}
+}
+
+func TestMultiplexerEvents(t *testing.T) {
+	mux := NewMultiplexer()
+	events := mux.Events()
+
+	mux.Append("a", `[1]`)
+	mux.Append("b", `{"k":"v"}`)
+	mux.Close("a")
+
+	var got []string
+	for len(events) > 0 {
+		event := <-events
+		if event.Closed {
+			got = append(got, event.StreamID+" closed")
+			continue
+		}
+		got = append(got, fmt.Sprintf("%s %d", event.StreamID, event.Type))
+	}
+
+	want := []string{
+		fmt.Sprintf("a %d", ArrayStarted),
+		fmt.Sprintf("a %d", ArrayItemAdded),
+		fmt.Sprintf("a %d", ValueCompleted),
+		fmt.Sprintf("a %d", ArrayClosed),
+		fmt.Sprintf("b %d", ObjectStarted),
+		fmt.Sprintf("b %d", KeyStarted),
+		fmt.Sprintf("b %d", StringDelta),
+		fmt.Sprintf("b %d", ValueCompleted),
+		fmt.Sprintf("b %d", ObjectClosed),
+		"a closed",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected events %v, got %v", want, got)
+	}
+}
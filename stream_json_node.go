@@ -0,0 +1,77 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"sort"
+)
+
+// Keys returns the keys of an object node in sorted order, or nil for other
+// nodes. A key whose value has not started yet is not included.
+func (n *Node) Keys() []string {
+	if n == nil || n.Type != ObjectNode {
+		return nil
+	}
+	keys := make([]string, 0, len(n.Children))
+	for key := range n.Children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns the number of members of an object or elements of an array
+// received so far, and 0 for values
+func (n *Node) Len() int {
+	if n == nil {
+		return 0
+	}
+	switch n.Type {
+	case ObjectNode:
+		return len(n.Children)
+	case ArrayNode:
+		return len(n.Array)
+	}
+	return 0
+}
+
+// At returns the i-th element of an array node, or nil if the node is not
+// an array or i is out of range
+func (n *Node) At(i int) *Node {
+	if n == nil || n.Type != ArrayNode || i < 0 || i >= len(n.Array) {
+		return nil
+	}
+	return n.Array[i]
+}
+
+// Child returns the member of an object node with the given key, or nil.
+// The key is matched exactly, without the parser's key normalizer.
+func (n *Node) Child(key string) *Node {
+	if n == nil || n.Type != ObjectNode {
+		return nil
+	}
+	return n.Children[key]
+}
+
+// IsComplete reports whether the node has been finalized
+func (n *Node) IsComplete() bool {
+	return n != nil && n.Completed
+}
+
+// Materialize converts the node and its descendants into plain Go values,
+// as returned by Get, including strings that are still streaming
+func (n *Node) Materialize() interface{} {
+	return collectValue(n, true)
+}
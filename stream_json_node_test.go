@@ -0,0 +1,63 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestNodeInspection(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"user":{"name":"Ada","langs":["go","c"]},"count":2,"note":"dra`)
+
+	root := parser.GetRoot()
+	if keys := root.Keys(); len(keys) != 3 || keys[0] != "count" || keys[1] != "note" || keys[2] != "user" {
+		t.Errorf("Expected sorted keys, got %v", keys)
+	}
+	if root.Len() != 3 || root.IsComplete() {
+		t.Errorf("Expected open root with 3 members")
+	}
+
+	langs := parser.GetNode("user", "langs")
+	if langs.Len() != 2 || langs.At(1).Value != "c" || !langs.IsComplete() {
+		t.Errorf("Unexpected langs node %v", langs.Materialize())
+	}
+	if langs.At(2) != nil || langs.At(-1) != nil || langs.Child("x") != nil || langs.Keys() != nil {
+		t.Error("Expected nil for out of range and mismatched accessors")
+	}
+
+	user := root.Child("user")
+	if user == nil || user.Child("name").Value != "Ada" {
+		t.Fatalf("Expected user object, got %v", user)
+	}
+	materialized, ok := user.Materialize().(map[string]interface{})
+	if !ok || materialized["name"] != "Ada" {
+		t.Errorf("Unexpected materialized value %v", user.Materialize())
+	}
+
+	note := parser.GetNode("note")
+	if note.Materialize() != "dra" || note.IsComplete() || note.Len() != 0 {
+		t.Errorf("Expected streaming note, got %v", note.Materialize())
+	}
+	if parser.GetNode("missing") != nil {
+		t.Error("Expected nil for a missing path")
+	}
+
+	// Accessors are safe on nil nodes
+	var missing *Node
+	if missing.Len() != 0 || missing.IsComplete() || missing.Child("a") != nil || missing.Materialize() != nil {
+		t.Error("Expected zero values from a nil node")
+	}
+}
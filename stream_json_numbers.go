@@ -0,0 +1,203 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberMode selects the Go type numbers are parsed into
+type NumberMode int
+
+const (
+	NumberAsNative     NumberMode = iota // int64 when the literal is an integer that fits, float64 otherwise
+	NumberAsJSONNumber                   // json.Number holding the literal unchanged
+	NumberAsBigFloat                     // *big.Float with enough precision for every digit
+	NumberAsFloat64                      // float64 for every number, integers included
+)
+
+// nonFiniteLiterals are the number literals accepted with WithNonFiniteNumbers
+var nonFiniteLiterals = map[string]struct{}{
+	"NaN": {}, "Infinity": {}, "-Infinity": {},
+}
+
+// isNonFiniteStart reports whether the input at rest can start a non-finite
+// literal. A '-' only does when followed by 'I'.
+func isNonFiniteStart(rest []byte) bool {
+	switch rest[0] {
+	case 'N', 'I':
+		return true
+	case '-':
+		return len(rest) > 1 && rest[1] == 'I'
+	}
+	return false
+}
+
+// awaitingSign reports whether the input ends with a '-' that may start
+// either a number or -Infinity, so it must wait for the next byte
+func (t *StreamJSONTokenizer) awaitingSign() bool {
+	return t.nonFinite && t.position == len(t.buffer)-1 && t.buffer[t.position] == '-'
+}
+
+// isNumberLiteral reports whether s is a number in JSON syntax, or a
+// non-finite literal when those are accepted
+func (p *StreamJSONParser) isNumberLiteral(s string) bool {
+	if _, ok := p.options.nonFinite[s]; ok {
+		return true
+	}
+	return isValidNumber(s)
+}
+
+// parseNumber converts a number literal according to the number mode.
+// Literals that are not valid numbers are returned unchanged as strings.
+func (p *StreamJSONParser) parseNumber(content string) interface{} {
+	if p.options.nonFinite != nil {
+		if value, ok := p.options.nonFinite[content]; ok {
+			return value
+		}
+	}
+
+	switch p.options.numberMode {
+	case NumberAsJSONNumber:
+		if isValidNumber(content) {
+			return json.Number(content)
+		}
+		return content
+
+	case NumberAsBigFloat:
+		// Over 3.33 bits per decimal digit keeps every digit of the literal
+		precision := uint(len(content))*4 + 64
+		if f, _, err := big.ParseFloat(content, 10, precision, big.ToNearestEven); err == nil && isValidNumber(content) {
+			return f
+		}
+		return content
+
+	case NumberAsFloat64:
+		if val, err := strconv.ParseFloat(content, 64); err == nil && isValidNumber(content) {
+			return val
+		}
+		return content
+	}
+
+	// Optimized number parsing - check for integer vs float efficiently
+	hasDecimal := false
+	hasExp := false
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		if c == '.' {
+			hasDecimal = true
+			break
+		} else if c == 'e' || c == 'E' {
+			hasExp = true
+			break
+		}
+	}
+
+	if !hasDecimal && !hasExp {
+		// Try integer parsing first for performance
+		if val, err := strconv.ParseInt(content, 10, 64); err == nil {
+			return val
+		}
+	}
+
+	// Parse as float
+	if val, err := strconv.ParseFloat(content, 64); err == nil {
+		return val
+	}
+
+	return content // Fallback to string if parsing fails
+}
+
+// isNumberValue reports whether v is a parsed number in any number mode
+func isNumberValue(v interface{}) bool {
+	switch v.(type) {
+	case int64, float64, json.Number, *big.Float:
+		return true
+	}
+	return false
+}
+
+// numberToInt64 converts a parsed number to an int64 if it is integral and
+// in range
+func numberToInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return int64(n), true
+		}
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i, true
+		}
+		if f, err := n.Float64(); err == nil {
+			return numberToInt64(f)
+		}
+	case *big.Float:
+		if i, accuracy := n.Int64(); accuracy == big.Exact {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// numberToFloat64 converts a parsed number to the nearest float64
+func numberToFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case *big.Float:
+		f, _ := n.Float64()
+		return f, !math.IsInf(f, 0)
+	}
+	return 0, false
+}
+
+// isIntegralNumber reports whether a parsed number has no fractional part
+func isIntegralNumber(v interface{}) bool {
+	switch n := v.(type) {
+	case int64:
+		return true
+	case float64:
+		return n == math.Trunc(n)
+	case json.Number:
+		f, ok := new(big.Float).SetString(string(n))
+		return ok && f.IsInt()
+	case *big.Float:
+		return n.IsInt()
+	}
+	return false
+}
+
+// partialNumber parses the longest valid prefix of an unterminated number,
+// dropping a trailing '.', exponent or sign that has no digits yet
+func (p *StreamJSONParser) partialNumber(content string) (interface{}, bool) {
+	content = strings.TrimRight(content, ".eE+-")
+	if !isValidNumber(content) {
+		return nil, false
+	}
+	return p.parseNumber(content), true
+}
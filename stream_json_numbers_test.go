@@ -0,0 +1,246 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+const bigNumbers = `{"id":12345678901234567890123,"price":0.1000000000000000000000001,"small":42}`
+
+func TestNumberAsJSONNumber(t *testing.T) {
+	parser := NewStreamJSONParser(WithNumberMode(NumberAsJSONNumber))
+	parser.Append(bigNumbers)
+
+	if id := parser.Get("id"); id != json.Number("12345678901234567890123") {
+		t.Errorf("Expected exact id, got %v (%T)", id, id)
+	}
+	if price := parser.Get("price"); price != json.Number("0.1000000000000000000000001") {
+		t.Errorf("Expected exact price, got %v", price)
+	}
+	if small, ok := parser.GetInt("small"); !ok || small != 42 {
+		t.Errorf("Expected GetInt to convert json.Number, got %v %v", small, ok)
+	}
+	if _, ok := parser.GetInt("id"); ok {
+		t.Errorf("Expected GetInt to reject an id beyond int64")
+	}
+
+	// Snapshots keep the literal
+	if parser.String() != `{"id":12345678901234567890123,"price":0.1000000000000000000000001,"small":42}` {
+		t.Errorf("Expected literals in snapshot, got %s", parser.String())
+	}
+
+	var target struct {
+		ID    json.Number `json:"id"`
+		Small int         `json:"small"`
+	}
+	if err := parser.Unmarshal(&target); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.ID != "12345678901234567890123" || target.Small != 42 {
+		t.Errorf("Unexpected unmarshal result %+v", target)
+	}
+}
+
+func TestNumberAsBigFloat(t *testing.T) {
+	parser := NewStreamJSONParser(WithNumberMode(NumberAsBigFloat))
+	parser.Append(bigNumbers)
+
+	id, ok := parser.Get("id").(*big.Float)
+	if !ok {
+		t.Fatalf("Expected *big.Float, got %T", parser.Get("id"))
+	}
+	expected, _ := new(big.Int).SetString("12345678901234567890123", 10)
+	if actual, _ := id.Int(nil); actual.Cmp(expected) != 0 {
+		t.Errorf("Expected exact id, got %s", actual)
+	}
+
+	price := parser.Get("price").(*big.Float)
+	if price.Text('g', 25) != "0.1000000000000000000000001" {
+		t.Errorf("Expected precise price, got %s", price.Text('g', 25))
+	}
+
+	if small, ok := parser.GetFloat("small"); !ok || small != 42 {
+		t.Errorf("Expected GetFloat to convert *big.Float, got %v %v", small, ok)
+	}
+
+	var target struct {
+		ID    *big.Float `json:"id"`
+		Small int64      `json:"small"`
+	}
+	if err := parser.Unmarshal(&target); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.ID == nil || target.ID.Cmp(id) != 0 || target.Small != 42 {
+		t.Errorf("Unexpected unmarshal result %+v", target)
+	}
+}
+
+func TestNumberAsNativeDefault(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(bigNumbers)
+
+	if _, ok := parser.Get("id").(float64); !ok {
+		t.Errorf("Expected float64 for an integer beyond int64, got %T", parser.Get("id"))
+	}
+	if parser.Get("small") != int64(42) {
+		t.Errorf("Expected int64, got %T", parser.Get("small"))
+	}
+}
+
+func TestNumberAsFloat64(t *testing.T) {
+	parser := NewStreamJSONParser(WithNumberMode(NumberAsFloat64))
+	parser.Append(`{"count": 3, "ratio": 0.5, "big": 1e3}`)
+
+	for _, key := range []string{"count", "ratio", "big"} {
+		if _, ok := parser.Get(key).(float64); !ok {
+			t.Errorf("Expected float64 for %s, got %T", key, parser.Get(key))
+		}
+	}
+	if count, ok := parser.GetInt("count"); !ok || count != 3 {
+		t.Errorf("Expected GetInt to convert an integral float, got %v %v", count, ok)
+	}
+}
+
+func TestNonFiniteNumbers(t *testing.T) {
+	input := `{"a": NaN, "b": Infinity, "c": -Infinity, "d": -1, "e": [NaN,-Infinity]}`
+
+	// Whole input and one byte at a time, where "-" must wait for the next byte
+	for _, chunk := range []int{len(input), 1} {
+		parser := NewStreamJSONParser(WithNonFiniteNumbers(math.NaN(), math.Inf(1), math.Inf(-1)), WithStrictMode())
+		for i := 0; i < len(input); i += chunk {
+			parser.Append(input[i:min(i+chunk, len(input))])
+		}
+
+		if parser.Err() != nil || !parser.IsCompleted() {
+			t.Fatalf("%d: expected parsing to complete, got %v", chunk, parser.Err())
+		}
+		if a, ok := parser.GetFloat("a"); !ok || !math.IsNaN(a) {
+			t.Errorf("%d: expected NaN, got %v", chunk, parser.Get("a"))
+		}
+		if parser.Get("b") != math.Inf(1) || parser.Get("c") != math.Inf(-1) {
+			t.Errorf("%d: expected infinities, got %v %v", chunk, parser.Get("b"), parser.Get("c"))
+		}
+		if parser.Get("d") != int64(-1) || parser.Get("e", "1") != math.Inf(-1) {
+			t.Errorf("%d: unexpected values %v %v", chunk, parser.Get("d"), parser.Get("e"))
+		}
+	}
+}
+
+func TestNonFiniteNumbersMarshalJSON(t *testing.T) {
+	parser := NewStreamJSONParser(WithNonFiniteNumbers(math.NaN(), math.Inf(1), math.Inf(-1)))
+	parser.Append(`{"a": NaN, "b": -Infinity, "c": [Infinity, 1.5]}`)
+
+	data, err := parser.MarshalJSON()
+	if err != nil || !json.Valid(data) {
+		t.Fatalf("Expected valid JSON, got %s, %v", data, err)
+	}
+	if string(data) != `{"a":null,"b":null,"c":[null,1.5]}` {
+		t.Errorf("Expected non-finite numbers as null, got %s", data)
+	}
+
+	// The output parses back without the option
+	roundTrip := NewStreamJSONParser(WithStrictMode())
+	roundTrip.Append(string(data))
+	if roundTrip.Err() != nil || roundTrip.String() != string(data) {
+		t.Errorf("Expected %s to round trip, got %s, %v", data, roundTrip.String(), roundTrip.Err())
+	}
+}
+
+func TestNonFiniteNumbersSentinels(t *testing.T) {
+	parser := NewStreamJSONParser(WithNonFiniteNumbers(nil, "inf", "-inf"))
+	parser.Append(`[NaN, Infinity, -Infinity]`)
+
+	if parser.String() != `[null,"inf","-inf"]` {
+		t.Errorf("Expected sentinels, got %s", parser.String())
+	}
+
+	// Without the option the literals stay invalid
+	parser = NewStreamJSONParser(WithStrictMode())
+	parser.Append(`[NaN]`)
+	if parser.Err() == nil {
+		t.Error("Expected NaN to be rejected by default")
+	}
+}
+
+func TestStreamJSONParserPartialNumbers(t *testing.T) {
+	parser := NewStreamJSONParser(WithPartialNumbers())
+
+	var updates []interface{}
+	parser.OnValue("progress", func(value interface{}, complete bool) {
+		updates = append(updates, value)
+	})
+
+	steps := []struct {
+		chunk    string
+		expected interface{}
+	}{
+		{`{"progress":`, nil},
+		{`-`, nil},
+		{`12`, int64(-12)},
+		{`3.`, int64(-123)},
+		{`4`, -123.4},
+		{`5e`, -123.45},
+		{`+1`, -1234.5},
+	}
+	for _, step := range steps {
+		parser.Append(step.chunk)
+		if got := parser.Get("progress"); got != step.expected {
+			t.Errorf("After %q: expected %v, got %v", step.chunk, step.expected, got)
+		}
+		if parser.IsComplete("progress") {
+			t.Errorf("After %q: expected number to be incomplete", step.chunk)
+		}
+	}
+
+	parser.Append(`,"items":[1,2`)
+	if !parser.IsComplete("progress") || parser.Get("progress") != -1234.5 {
+		t.Errorf("Expected terminated number to be complete, got %v", parser.Get("progress"))
+	}
+	if parser.Get("items", "1") != int64(2) || parser.IsComplete("items", "1") {
+		t.Errorf("Expected partial array element, got %v", parser.Get("items"))
+	}
+
+	parser.Append(`0]}`)
+	if parser.Get("items", "1") != int64(20) || !parser.IsCompleted() {
+		t.Errorf("Expected completed element, got %v", parser.Get("items"))
+	}
+	if len(updates) != 6 {
+		t.Errorf("Expected 5 partial updates and the final value, got %v", updates)
+	}
+}
+
+func TestStreamJSONParserPartialNumbersOff(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"n":12`)
+	if parser.Get("n") != nil {
+		t.Errorf("Expected no partial number by default, got %v", parser.Get("n"))
+	}
+}
+
+func TestStreamJSONParserPartialNumberRoot(t *testing.T) {
+	parser := NewStreamJSONParser(WithPartialNumbers(), WithScalarRoots())
+	parser.Append(`4`)
+	if parser.Get() != int64(4) || parser.IsCompleted() {
+		t.Errorf("Expected partial root number, got %v", parser.Get())
+	}
+	parser.Append("2\n")
+	if parser.Get() != int64(42) || !parser.IsCompleted() {
+		t.Errorf("Expected completed root number, got %v", parser.Get())
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"io"
+	"reflect"
+)
+
+// Option configures optional parser behavior
+type Option func(*parserOptions)
+
+// parserOptions holds the settings applied by Option values
+type parserOptions struct {
+	rawStrings  bool // Keep escape sequences in strings undecoded
+	codeFences  bool // Extract the JSON payload from Markdown code fences
+	repair      bool // Accept common malformations in model output
+	lenientKeys bool // Accept single-quoted and unquoted object keys
+	comments    bool // Skip // and /* */ comments
+	smartQuotes bool // Accept typographic double quotes around strings
+
+	multipleDocuments bool                    // Parse consecutive roots instead of stopping after the first
+	detectDocuments   bool                    // Switch to multiple documents when a second root follows on a new line
+	strict            bool                    // Stop at the first token that is not valid JSON
+	maxBufferSize     int                     // Bound on retained input bytes, 0 for no bound
+	retainInput       bool                    // Keep the input of the current document for GetRaw
+	concurrencyCheck  bool                    // Panic when the parser is used from two goroutines at once
+	blockingEvents    bool                    // Wait for room in the Events channel instead of dropping events
+	schema            *Schema                 // Schema values are validated against as they complete
+	shape             reflect.Type            // Go type the document must decode into, nil for any
+	hints             []shapeHint             // Kinds expected at paths, from a Shape
+	maxDepth          int                     // Maximum nesting of objects and arrays, 0 for no limit
+	maxKeyLength      int                     // Maximum key length in bytes, 0 for no limit
+	maxStringLength   int                     // Maximum string value length in bytes, 0 for no limit
+	maxNodes          int                     // Maximum nodes per document, 0 for no limit
+	maxDocuments      int                     // Completed roots kept for Documents, 0 for all
+	memoryBudget      int                     // Approximate bytes the tree may hold, 0 for no limit
+	numberMode        NumberMode              // Go type numbers are parsed into
+	scalarRoots       bool                    // Accept strings, numbers, bools and null as the root
+	keyNormalizer     func(key string) string // Applied to object keys and lookup paths
+	trimKeys          bool                    // Trim whitespace around object keys
+	lenientCoercion   bool                    // Typed accessors convert stringified numbers and booleans
+	partialNumbers    bool                    // Expose numbers while they stream
+	changeTracking    bool                    // Keep a change log for Diff
+	recovery          bool                    // Resynchronize after invalid tokens inside structures
+	includePaths      [][]string              // Paths nodes are built for, nil to build everything
+	nonFinite         map[string]interface{}  // Values of NaN, Infinity and -Infinity, nil to reject them
+	interceptors      []func(Token) Token     // Applied to each token before it is consumed
+	redactions        []redaction             // Paths whose values are replaced by a placeholder
+	truncateStrings   int                     // Length strings are cut to, 0 to keep them whole
+
+	spillThreshold int                                      // Length above which strings are spilled, 0 to keep them in memory
+	spillOpen      func(path []string) (io.WriterAt, error) // Target of a spilled string, nil for a temporary file
+
+	errorOnLeadingText  bool // Stop at text before the root
+	captureLeadingText  bool // Keep text before the root for LeadingText
+	errorOnTrailingText bool // Stop at text after the root
+	captureTrailingText bool // Keep text after the root for TrailingText
+	timestamps          bool // Record when values start and complete, for Meta
+}
+
+// WithRawStrings keeps string values and object keys exactly as they appear
+// in the input, without decoding escape sequences
+func WithRawStrings() Option {
+	return func(o *parserOptions) {
+		o.rawStrings = true
+	}
+}
+
+// WithCodeFenceExtraction locks onto the JSON payload of Markdown-wrapped
+// model output, dropping prose and ```json fences around it while streaming
+func WithCodeFenceExtraction() Option {
+	return func(o *parserOptions) {
+		o.codeFences = true
+	}
+}
+
+// WithRepair accepts common malformations in model-generated JSON while
+// streaming: single-quoted and smart-quoted strings, unquoted object keys
+// and the Python literals True, False and None. Trailing commas are always
+// tolerated.
+func WithRepair() Option {
+	return func(o *parserOptions) {
+		o.repair = true
+	}
+}
+
+// WithMultipleDocuments parses a stream of consecutive top-level documents,
+// such as newline-delimited JSON. Each time a root completes it is handed to
+// the OnDocument callbacks and the parser starts over with the next document.
+// Completed roots are kept for Documents; see WithMaxDocuments for long
+// streams.
+func WithMultipleDocuments() Option {
+	return func(o *parserOptions) {
+		o.multipleDocuments = true
+	}
+}
+
+// WithDocumentDetection switches to multi-document mode when the first
+// root is followed by another one on a new line, as in NDJSON, instead of
+// ignoring everything after the first root. A DocumentsDetected event
+// announces the switch, after which the first root is handed to OnDocument
+// and kept for Documents as document 0, without a DocumentStarted event.
+// Until it is known whether another root follows, the Events channel stays
+// open after the root completes, up to Finish.
+func WithDocumentDetection() Option {
+	return func(o *parserOptions) {
+		o.detectDocuments = true
+	}
+}
+
+// WithMaxDocuments keeps only the last count completed roots in
+// multi-document mode, releasing older ones, so a long stream handled by
+// OnDocument uses flat memory. Documents then returns the retained roots,
+// while document indices keep counting from the start of the stream. By
+// default every root is kept.
+func WithMaxDocuments(count int) Option {
+	return func(o *parserOptions) {
+		o.maxDocuments = count
+	}
+}
+
+// WithStrictMode stops parsing at the first token that is not valid JSON
+// instead of skipping it, and records a ParseError available through Errors
+// and Err. Trailing commas, stray characters and prose around the document
+// are all reported.
+func WithStrictMode() Option {
+	return func(o *parserOptions) {
+		o.strict = true
+	}
+}
+
+// WithMaxBufferSize bounds the raw input the parser retains. Consumed input is
+// always released over time; with a bound it is released as soon as the
+// buffer grows past size bytes. If the input still needed, such as a single
+// very long string or a subtree watched by OnRawSubtree, exceeds size,
+// parsing stops and Err returns ErrBufferLimit.
+func WithMaxBufferSize(size int) Option {
+	return func(o *parserOptions) {
+		o.maxBufferSize = size
+	}
+}
+
+// WithRetainedInput keeps the source of the current document in memory, so
+// GetRaw works for every completed value however long the document is.
+// Input before the document, such as earlier documents and leading text, is
+// still released. WithMaxBufferSize still applies.
+func WithRetainedInput() Option {
+	return func(o *parserOptions) {
+		o.retainInput = true
+	}
+}
+
+// WithConcurrencyCheck makes the parser panic with ErrConcurrentUse when a
+// method that reads the tree, such as Get, Query, Snapshot or MarshalJSON,
+// runs while another goroutine is in Append, AppendBytes, ParseBytes,
+// Finish, Reset, Checkpoint, Restore or Release, or when two of those
+// overlap, instead of returning corrupted reads. Registering callbacks and
+// the accessors of errors and options are not checked. It is a debugging
+// aid: it slows every checked call down, and it only sees calls that
+// actually overlap. Callbacks may still read the parser.
+func WithConcurrencyCheck() Option {
+	return func(o *parserOptions) {
+		o.concurrencyCheck = true
+	}
+}
+
+// WithBlockingEvents makes the parser wait while the Events channel is full
+// instead of dropping events, so a consumer on another goroutine receives
+// every event and slows parsing down when it falls behind. The channel must
+// then be drained from another goroutine than the one calling Append.
+func WithBlockingEvents() Option {
+	return func(o *parserOptions) {
+		o.blockingEvents = true
+	}
+}
+
+// WithSchema validates the document against schema while it streams.
+// Violations are available from SchemaErrors as soon as the offending value
+// is seen, so a bad generation can be abandoned early.
+func WithSchema(schema *Schema) Option {
+	return func(o *parserOptions) {
+		o.schema = schema
+	}
+}
+
+// WithShape checks the document against the type of v, such as a struct
+// or a pointer to one, while it streams. Parsing stops at the first value
+// that Unmarshal could not store, with a *json.UnmarshalTypeError from Err
+// naming its path. Objects and arrays are checked as soon as they start,
+// other values when they complete. A Shape, or a map[string]Kind, instead
+// declares kinds at paths and coerces deviations, see Shape.
+func WithShape(v interface{}) Option {
+	return func(o *parserOptions) {
+		switch shape := v.(type) {
+		case Shape:
+			o.hints = shape.hints()
+		case map[string]Kind:
+			o.hints = Shape(shape).hints()
+		default:
+			o.shape = reflect.TypeOf(v)
+		}
+	}
+}
+
+// WithMaxDepth limits how deeply objects and arrays may nest. Parsing stops
+// with ErrDepthLimit when a container would exceed depth.
+func WithMaxDepth(depth int) Option {
+	return func(o *parserOptions) {
+		o.maxDepth = depth
+	}
+}
+
+// WithMaxKeyLength limits object keys to length bytes, as written in the
+// input. Parsing stops with ErrKeyLengthLimit, even before the key ends.
+func WithMaxKeyLength(length int) Option {
+	return func(o *parserOptions) {
+		o.maxKeyLength = length
+	}
+}
+
+// WithMaxStringLength limits string values to length bytes, as written in
+// the input. Parsing stops with ErrStringLengthLimit, even before the string
+// ends.
+func WithMaxStringLength(length int) Option {
+	return func(o *parserOptions) {
+		o.maxStringLength = length
+	}
+}
+
+// WithMaxNodes limits the number of objects, arrays and values in a
+// document. Parsing stops with ErrNodeLimit when the tree would grow past it.
+func WithMaxNodes(count int) Option {
+	return func(o *parserOptions) {
+		o.maxNodes = count
+	}
+}
+
+// WithMemoryBudget bounds the approximate memory held by the tree, for
+// long-running sessions. Once it is exceeded the largest completed strings
+// are emptied and marked truncated, with a ValueEvicted event each, until
+// the tree is back under three quarters of the budget. Parsing stops with
+// ErrMemoryBudget when nothing is left to evict. Completed documents in
+// multi-document mode count toward the budget but are not evicted from.
+func WithMemoryBudget(bytes int) Option {
+	return func(o *parserOptions) {
+		o.memoryBudget = bytes
+	}
+}
+
+// WithNumberMode selects how numbers are represented. NumberAsJSONNumber and
+// NumberAsBigFloat keep large integers and high-precision decimals that
+// would otherwise be truncated to int64 or float64. NumberAsFloat64 returns
+// float64 for integers too, for consumers that expect a single type.
+func WithNumberMode(mode NumberMode) Option {
+	return func(o *parserOptions) {
+		o.numberMode = mode
+	}
+}
+
+// WithScalarRoots accepts a string, number, bool or null as the whole
+// document, as in bare-string function call arguments. A top-level string is
+// available while it streams; a number completes once it is followed by
+// whitespace. Without this option anything before the first '{' or '[' is
+// skipped, so leading prose is tolerated.
+func WithScalarRoots() Option {
+	return func(o *parserOptions) {
+		o.scalarRoots = true
+	}
+}
+
+// WithComments skips // line comments and /* */ block comments between
+// tokens, as emitted by models trained on JSON5 and JSONC. A comment split
+// across Append calls is skipped once the rest arrives.
+func WithComments() Option {
+	return func(o *parserOptions) {
+		o.comments = true
+	}
+}
+
+// WithSmartQuotes accepts strings and keys delimited by the typographic
+// quotes “ and ” that some chat frontends substitute for ", normalizing
+// them to ASCII quotes. A byte order mark and zero-width spaces between
+// tokens are skipped with or without this option.
+func WithSmartQuotes() Option {
+	return func(o *parserOptions) {
+		o.smartQuotes = true
+	}
+}
+
+// WithKeyNormalizer rewrites object keys as they are inserted, for models
+// that are inconsistent about key naming. Keys passed to Get and the other
+// accessors, Watch, callback paths, queries and struct field names in
+// Unmarshal are normalized the same way, so with SnakeCaseKey both
+// Get("userName") and Get("user_name") find the key "userName". Keys that
+// normalize to the same name collide and the last one wins. Schemas see the
+// normalized keys.
+func WithKeyNormalizer(normalize func(key string) string) Option {
+	return func(o *parserOptions) {
+		o.keyNormalizer = normalize
+	}
+}
+
+// WithTrimmedKeys trims whitespace around object keys as they are inserted,
+// so a padded key such as " name " is found by Get("name"). It applies
+// before any WithKeyNormalizer, and lookup paths are trimmed the same way.
+func WithTrimmedKeys() Option {
+	return func(o *parserOptions) {
+		o.trimKeys = true
+	}
+}
+
+// WithLenientCoercion makes GetInt, GetFloat and GetBool accept complete
+// strings holding a number or a boolean, as GetIntLenient and GetBoolLenient
+// do. The tree is unchanged, so Get still returns the string.
+func WithLenientCoercion() Option {
+	return func(o *parserOptions) {
+		o.lenientCoercion = true
+	}
+}
+
+// WithPartialNumbers exposes numbers while they stream, with the value of
+// the longest valid prefix, so 123.4 is visible while 123.45 is arriving.
+// Such values are not complete until the number is terminated; check
+// IsComplete to tell them apart.
+func WithPartialNumbers() Option {
+	return func(o *parserOptions) {
+		o.partialNumbers = true
+	}
+}
+
+// WithChangeTracking keeps a log of the values added, extended and
+// completed by each Append, so Diff can report what changed since a
+// version. The log grows with the document and is cleared by Reset.
+func WithChangeTracking() Option {
+	return func(o *parserOptions) {
+		o.changeTracking = true
+	}
+}
+
+// WithRecovery resynchronizes after invalid input inside an object or
+// array instead of skipping just the invalid token. The value the invalid
+// input belongs to is discarded along with everything up to the next comma
+// or closing bracket at the same depth, so later values cannot be assigned
+// to the wrong key. Each resynchronization is recorded in Recoveries.
+func WithRecovery() Option {
+	return func(o *parserOptions) {
+		o.recovery = true
+	}
+}
+
+// WithIncludePaths builds nodes only for the values at the given dotted
+// paths, their ancestors and their descendants, and skims over everything
+// else without building it, so only the needed parts of a large response
+// use memory. A "*" segment matches any key or index, as in
+// WithIncludePaths("choices.*.delta", "usage"). Skipped object members are
+// absent and skipped array elements are null, so indices are unchanged.
+func WithIncludePaths(paths ...string) Option {
+	return func(o *parserOptions) {
+		for _, path := range paths {
+			o.includePaths = append(o.includePaths, splitPath(path))
+		}
+	}
+}
+
+// WithLenientKeys accepts single-quoted and unquoted object keys, as in
+// {key: "value"} or {'key': 'value'}, without the rest of WithRepair.
+// Unquoted keys are made of letters, digits, '_', '$' and '-', and must not
+// start with a digit or '-'.
+func WithLenientKeys() Option {
+	return func(o *parserOptions) {
+		o.lenientKeys = true
+	}
+}
+
+// WithNonFiniteNumbers accepts the NaN, Infinity and -Infinity literals
+// emitted by Python-trained models, which are otherwise invalid tokens, and
+// parses them into the given values. Pass math.NaN(), math.Inf(1) and
+// math.Inf(-1) to get the float64 values, which MarshalJSON writes as null
+// and Snapshot().MarshalJSON rejects, or nil to treat them as missing.
+func WithNonFiniteNumbers(nan, posInf, negInf interface{}) Option {
+	return func(o *parserOptions) {
+		o.nonFinite = map[string]interface{}{
+			"NaN":       nan,
+			"Infinity":  posInf,
+			"-Infinity": negInf,
+		}
+	}
+}
+
+// WithErrorOnLeadingText stops parsing when anything but whitespace comes
+// before the root, instead of skipping it, and Err returns ErrLeadingText.
+// Prose dropped by WithCodeFenceExtraction is not an error. In
+// multi-document mode only text before the first document is checked.
+func WithErrorOnLeadingText() Option {
+	return func(o *parserOptions) {
+		o.errorOnLeadingText = true
+	}
+}
+
+// WithCaptureLeadingText keeps the text skipped before the root, such as a
+// model's preamble, for LeadingText
+func WithCaptureLeadingText() Option {
+	return func(o *parserOptions) {
+		o.captureLeadingText = true
+	}
+}
+
+// WithErrorOnTrailingText makes Err return ErrTrailingText once anything but
+// whitespace follows the completed root. The root itself stays complete.
+// Prose dropped by WithCodeFenceExtraction is not an error, and in
+// multi-document mode what follows a root is the next document.
+func WithErrorOnTrailingText() Option {
+	return func(o *parserOptions) {
+		o.errorOnTrailingText = true
+	}
+}
+
+// WithCaptureTrailingText keeps the text after the completed root for
+// TrailingText
+func WithCaptureTrailingText() Option {
+	return func(o *parserOptions) {
+		o.captureTrailingText = true
+	}
+}
+
+// WithTimestamps records when each value starts and completes, so Meta can
+// relate fields to the latency of the stream that produced them
+func WithTimestamps() Option {
+	return func(o *parserOptions) {
+		o.timestamps = true
+	}
+}
+
+// WithTokenInterceptor registers a function that sees every token before
+// the parser consumes it and returns the token to consume instead, for
+// sanitizing, logging or redacting input without forking the parser.
+// Interceptors run in the order they are registered. A string that is still
+// streaming is seen again, with Completed false, each time it grows, and its
+// Content keeps its quotes. Changes only affect the parsed values, while
+// GetRaw, OnRawSubtree and Meta still refer to the original input. Parsing
+// stops with ErrTokenType if an interceptor returns an unknown token type.
+func WithTokenInterceptor(intercept func(Token) Token) Option {
+	return func(o *parserOptions) {
+		o.interceptors = append(o.interceptors, intercept)
+	}
+}
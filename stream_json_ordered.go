@@ -0,0 +1,134 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// Member is a key and its value in an OrderedObject
+type Member struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedObject is an object materialized by GetOrdered, with its members
+// in the order their keys arrived in the stream
+type OrderedObject []Member
+
+// Get returns the value of key and whether the object has it
+func (o OrderedObject) Get(key string) (interface{}, bool) {
+	for _, member := range o {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the keys in stream order
+func (o OrderedObject) Keys() []string {
+	keys := make([]string, len(o))
+	for i, member := range o {
+		keys[i] = member.Key
+	}
+	return keys
+}
+
+// MarshalJSON encodes the object with its keys in stream order
+func (o OrderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(member.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(member.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// GetOrdered is like Get, but materializes objects as OrderedObject values
+// whose members are in the order the model produced them, for renderers
+// that must show fields in that order. A key repeated in the stream takes
+// the position of its last occurrence.
+func (p *StreamJSONParser) GetOrdered(keys ...string) interface{} {
+	defer p.guardRead("GetOrdered")()
+	if p.root == nil {
+		return nil
+	}
+	return collectOrdered(p.findNode(keys))
+}
+
+// OrderedKeys returns the keys of an object node in the order they arrived
+// in the stream, or nil for other nodes
+func (n *Node) OrderedKeys() []string {
+	keys := n.Keys()
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return n.Children[a].start - n.Children[b].start
+	})
+	return keys
+}
+
+// collectOrdered materializes a node like collectValue with partial values,
+// with objects as OrderedObject. Each task fills in the slot its value goes.
+func collectOrdered(node *Node) interface{} {
+	type orderedTask struct {
+		node *Node
+		slot *interface{}
+	}
+
+	var result interface{}
+	stack := []orderedTask{{node, &result}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := task.node
+		switch {
+		case node == nil:
+		case node.Type == ObjectNode:
+			keys := node.OrderedKeys()
+			object := make(OrderedObject, len(keys))
+			for i, key := range keys {
+				object[i].Key = key
+				stack = append(stack, orderedTask{node.Children[key], &object[i].Value})
+			}
+			*task.slot = object
+		case node.Type == ArrayNode:
+			array := make([]interface{}, len(node.Array))
+			for i, child := range node.Array {
+				stack = append(stack, orderedTask{child, &array[i]})
+			}
+			*task.slot = array
+		default:
+			*task.slot = node.Value
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStreamJSONParserGetOrdered(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"zeta": 1, "alpha": {"b": true, "a": [{"y": 2, "x": 3}]}, "mid": "par`)
+
+	result, ok := parser.GetOrdered().(OrderedObject)
+	if !ok {
+		t.Fatalf("Expected OrderedObject, got %T", parser.GetOrdered())
+	}
+	if !reflect.DeepEqual(result.Keys(), []string{"zeta", "alpha", "mid"}) {
+		t.Errorf("Expected stream key order, got %v", result.Keys())
+	}
+	if value, _ := result.Get("mid"); value != "par" {
+		t.Errorf("Expected partial string, got %v", value)
+	}
+	if _, ok := result.Get("missing"); ok {
+		t.Error("Expected missing key to be absent")
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"zeta":1,"alpha":{"b":true,"a":[{"y":2,"x":3}]},"mid":"par"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestStreamJSONParserGetOrderedPath(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"items": [{"c": 1, "b": 2, "a": 3}], "n": 5}`)
+
+	item, ok := parser.GetOrdered("items", "0").(OrderedObject)
+	if !ok || !reflect.DeepEqual(item.Keys(), []string{"c", "b", "a"}) {
+		t.Errorf("Expected ordered element, got %v", parser.GetOrdered("items", "0"))
+	}
+	if value := parser.GetOrdered("n"); value != int64(5) {
+		t.Errorf("Expected scalar 5, got %v", value)
+	}
+	if value := parser.GetOrdered("missing"); value != nil {
+		t.Errorf("Expected nil for missing path, got %v", value)
+	}
+	if value := NewStreamJSONParser().GetOrdered(); value != nil {
+		t.Errorf("Expected nil before any input, got %v", value)
+	}
+}
+
+func TestStreamJSONParserGetOrderedRepeatedKey(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": 1, "b": 2, "a": 3}`)
+
+	result := parser.GetOrdered().(OrderedObject)
+	want := OrderedObject{{Key: "b", Value: int64(2)}, {Key: "a", Value: int64(3)}}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("Expected %v, got %v", want, result)
+	}
+}
@@ -15,10 +15,18 @@
 package streamjson
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
 	"strconv"
-	"sync"
+	"time"
 )
 
+// ErrTokenType is returned by Err when a token interceptor returns a token
+// type the parser does not know
+var ErrTokenType = errors.New("streamjson: interceptor returned an unknown token type")
+
 // NodeType represents the type of AST node
 type NodeType int
 
@@ -35,32 +43,32 @@ type Node struct {
 	Children  map[string]*Node // For objects
 	Array     []*Node          // For arrays
 	Completed bool             // Whether this node is complete
+	Truncated bool             // Whether Finish found this object or array still open, or WithStringTruncation cut or WithMemoryBudget emptied this string
 	Parent    *Node            // Reference to parent node
-}
 
-// Object pools for memory reuse
-var (
-	nodePool = sync.Pool{
-		New: func() interface{} {
-			return &Node{}
-		},
-	}
-	stackFramePool = sync.Pool{
-		New: func() interface{} {
-			return &StackFrame{}
-		},
-	}
-)
+	start    int         // Offset of the node's first byte in the input
+	end      int         // Offset just past the node's last byte, once completed
+	times    *valueTimes // When the node started and completed, with WithTimestamps
+	literal  string      // Source text of a number, for GetRawNumber
+	released bool        // Whether the node is back in the pool
+}
 
 // NewNode creates a new AST node with object pooling
 func NewNode(nodeType NodeType) *Node {
-	node := nodePool.Get().(*Node)
+	node := getNode()
+	countPool(&nodesAcquired)
 
 	// Reset the node
+	node.released = false
 	node.Type = nodeType
 	node.Value = nil
 	node.Completed = false
+	node.Truncated = false
 	node.Parent = nil
+	node.start = 0
+	node.end = 0
+	node.times = nil
+	node.literal = ""
 
 	// Clear existing children/array but reuse maps/slices when possible
 	if nodeType == ObjectNode {
@@ -89,78 +97,321 @@ func NewNode(nodeType NodeType) *Node {
 	return node
 }
 
-// ReleaseNode returns a node to the pool
+// ReleaseNode returns a node and its descendants to the pool, first
+// detaching it from its parent so the rest of the tree never refers to a
+// pooled node. Releasing a node twice is ignored. The node, and any node
+// below it, must not be used afterwards, and a node still being parsed must
+// not be released; values from Get and Materialize are copies and stay
+// valid.
 func ReleaseNode(node *Node) {
 	if node == nil {
 		return
 	}
+	if node.released {
+		countPool(&doubleReleases)
+		return
+	}
 
-	// Recursively release child nodes
-	if node.Children != nil {
-		for _, child := range node.Children {
-			ReleaseNode(child)
+	if parent := node.Parent; parent != nil && !parent.released {
+		for key, child := range parent.Children {
+			if child == node {
+				delete(parent.Children, key)
+				break
+			}
 		}
-	}
-	if node.Array != nil {
-		for _, child := range node.Array {
-			ReleaseNode(child)
+		if i := slices.Index(parent.Array, node); i >= 0 {
+			parent.Array = slices.Delete(parent.Array, i, i+1)
 		}
 	}
+	releaseTree(node)
+}
+
+// releaseTree returns a node that is no longer referenced by its parent and
+// its descendants to the pool, walking the tree with an explicit stack
+func releaseTree(node *Node) {
+	var buf [16]*Node
+	stack := append(buf[:0], node)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == nil || node.released {
+			continue
+		}
+		node.released = true
+		node.Parent = nil
+
+		// Children are read before the node goes back to the pool
+		for _, child := range node.Children {
+			stack = append(stack, child)
+		}
+		stack = append(stack, node.Array...)
 
-	nodePool.Put(node)
+		countPool(&nodesReleased)
+		putNode(node)
+	}
 }
 
 // newStackFrame creates a new stack frame with pooling
 func newStackFrame() *StackFrame {
-	frame := stackFramePool.Get().(*StackFrame)
+	frame := getStackFrame()
+	countPool(&framesAcquired)
 	// Reset fields
 	frame.Node = nil
 	frame.CurrentKey = ""
 	frame.ExpectingKey = false
 	frame.ExpectingValue = false
+	frame.Path = nil
+	frame.Evicted = 0
+	frame.Included = false
+	frame.Shadowed = nil
+	frame.key = ""
 	return frame
 }
 
 // releaseStackFrame returns a stack frame to the pool
 func releaseStackFrame(frame *StackFrame) {
 	if frame != nil {
-		stackFramePool.Put(frame)
+		countPool(&framesReleased)
+		putStackFrame(frame)
 	}
 }
 
 // StackFrame represents a frame in the parsing stack
 type StackFrame struct {
 	Node           *Node
-	CurrentKey     string // For objects, the current key being parsed
-	ExpectingKey   bool   // For objects, whether we're expecting a key next
-	ExpectingValue bool   // Whether we're expecting a value next
+	CurrentKey     string   // For objects, the current key being parsed
+	ExpectingKey   bool     // For objects, whether we're expecting a key next
+	ExpectingValue bool     // Whether we're expecting a value next
+	Path           []string // Path of keys and indices from the root to Node
+	Evicted        int      // For arrays, elements already removed by StreamArray
+	Included       bool     // Whether everything under Node is inside the paths given to WithIncludePaths
+	Shadowed       *Node    // For objects, the child a streaming value under a duplicate key replaced
+
+	key string // Key or index of Node in its parent, the last element of Path
 }
 
-// StreamJSONParser implements a streaming JSON parser with AST building
+// StreamJSONParser implements a streaming JSON parser with AST building.
+//
+// A parser is not safe for concurrent use: Get, Exists and the other
+// readers must not run while another goroutine appends to, finishes, resets
+// or restores it. Callbacks run on the goroutine calling Append and may
+// read the parser. To share a parser between goroutines, guard it with a
+// mutex or use NewSafeStreamJSONParser; WithConcurrencyCheck detects misuse.
 type StreamJSONParser struct {
 	tokenizer *StreamJSONTokenizer
 	root      *Node
 	stack     []*StackFrame
 	started   bool
+	err       error // Sticky error, set when an invariant check or strict mode fails
+	options   parserOptions
+	fence     *codeFenceFilter // Non-nil when code fence extraction is enabled
+
+	rawSubscriptions     []rawSubscription                                             // Callbacks for raw subtree bytes
+	valueSubscriptions   []valueSubscription                                           // Callbacks for value updates
+	watches              []watchSubscription                                           // Channels registered by Watch
+	arrayStreams         []arrayStream                                                 // Callbacks registered by StreamArray
+	elementSubscriptions []elementSubscription                                         // Callbacks registered by ForEach
+	transforms           []transformSubscription                                       // Transformers registered by Transform
+	globalTransforms     []func(path []string, value interface{}) (interface{}, error) // Transformers registered by TransformAll
+	transformErrors      []*TransformError                                             // Values transformers rejected
+	elementErrors        []*ElementError                                               // Array elements ForEach could not decode
+	version              int                                                           // Number of Append calls
+	changes              []changeRecord                                                // Change log, with change tracking enabled
+	events               chan Event                                                    // Event stream, created by Events
+	droppedEvents        int                                                           // Events left out of a full events channel
+	eventSink            func(Event)                                                   // Receives events instead of events, for a Multiplexer
+
+	errors          []*ParseError            // Parse errors recorded in strict mode
+	schemaErrors    []*SchemaError           // Schema violations found so far
+	shapeDeviations []*ShapeDeviation        // Values deviating from the Shape
+	nodes           int                      // Nodes added to the current document
+	expect          grammarState             // Next expected token class in strict mode
+	recoveries      []*Recovery              // Resynchronizations after invalid input
+	skipping        bool                     // Whether tokens are skipped up to the next resynchronization point
+	skipDepth       int                      // Containers opened inside the skipped input
+	skimDepth       int                      // Containers opened inside a value outside the included paths
+	finished        bool                     // Whether Finish was called
+	tokenCounts     [len(tokenTypeNames)]int // Completed tokens by type, for Stats
+	maxDepthSeen    int                      // Deepest stack reached, for Stats
+	truncated       bool                     // Whether Finish completed or marked anything
+	tokenEnd        int                      // End of the last complete token, for the invariant checks
+	memory          int                      // Approximate bytes held by the tree, with WithMemoryBudget
+	leadingText     []byte                   // Text skipped before the root, with WithCaptureLeadingText
+	leadingEnd      int                      // Input offset leadingText extends to
+
+	documents         []*Node                                 // Completed roots in multi-document mode
+	documentCount     int                                     // Roots completed, including those no longer kept
+	detectedDocuments bool                                    // Whether WithDocumentDetection switched to multi-document mode
+	documentCallbacks []func(index int, document interface{}) // Callbacks per completed root
+	documentStarts    []func(index int)                       // Callbacks per started root
+
+	bindings    []structBinding     // Targets of UnmarshalStream
+	tees        []*teeSubscription  // Writers registered with Tee
+	spillFiles  []*os.File          // Temporary files of spilled strings
+	spillErr    error               // First error opening or writing a spill target
+	checkpoints []*ParserCheckpoint // Checkpoints not yet released, whose input is kept
+
+	concurrency *concurrencyCheck // Calls in progress, with WithConcurrencyCheck
 }
 
 // NewStreamJSONParser creates a new streaming JSON parser
-func NewStreamJSONParser() *StreamJSONParser {
-	return &StreamJSONParser{
+func NewStreamJSONParser(opts ...Option) *StreamJSONParser {
+	p := &StreamJSONParser{
 		tokenizer: NewStreamJSONTokenizer(),
 		stack:     make([]*StackFrame, 0, 16), // Pre-allocate reasonable stack capacity
 		started:   false,
 	}
+	for _, opt := range opts {
+		opt(&p.options)
+	}
+	if p.options.trimKeys {
+		p.options.keyNormalizer = trimmedKeys(p.options.keyNormalizer)
+	}
+	p.tokenizer.repair = p.options.repair
+	p.tokenizer.lenientKeys = p.options.lenientKeys
+	p.tokenizer.nonFinite = p.options.nonFinite != nil
+	p.tokenizer.comments = p.options.comments
+	p.tokenizer.smartQuotes = p.options.smartQuotes || p.options.repair
+	for i, path := range p.options.includePaths {
+		p.options.includePaths[i] = p.normalizePath(path)
+	}
+	for i, hint := range p.options.hints {
+		p.options.hints[i].pattern = p.normalizePath(hint.pattern)
+	}
+	for i, r := range p.options.redactions {
+		p.options.redactions[i].pattern = p.normalizePath(r.pattern)
+	}
+	if p.options.codeFences {
+		p.fence = newCodeFenceFilter(p.options.captureLeadingText, p.options.captureTrailingText)
+	}
+	if p.options.concurrencyCheck {
+		p.concurrency = &concurrencyCheck{}
+	}
+	return p
+}
+
+// Reset clears the parser so the instance can be reused for a new stream
+// with the same options. Nodes of previous documents go back to the pool and
+// must not be used afterwards. Registered callbacks are removed and open
+// Events and Watch channels are closed.
+func (p *StreamJSONParser) Reset() {
+	defer p.guardWrite("Reset")()
+	releaseTree(p.root)
+	for _, root := range p.documents {
+		releaseTree(root)
+	}
+	for i, frame := range p.stack {
+		releaseStackFrame(frame)
+		p.stack[i] = nil
+	}
+	for _, cp := range p.checkpoints {
+		cp.release()
+	}
+
+	// The channel is already closed once a single document has completed,
+	// or after Finish
+	if p.events != nil && !(p.IsCompleted() && p.closesAtRoot()) && !p.finished {
+		close(p.events)
+	}
+
+	p.closeWatches()
+	p.tokenizer.Reset()
+	if p.options.codeFences {
+		p.fence = newCodeFenceFilter(p.options.captureLeadingText, p.options.captureTrailingText)
+	}
+
+	p.root = nil
+	p.stack = p.stack[:0]
+	p.started = false
+	p.err = nil
+	p.rawSubscriptions = nil
+	p.valueSubscriptions = nil
+	p.arrayStreams = nil
+	p.elementSubscriptions = nil
+	p.elementErrors = nil
+	p.transforms = nil
+	p.globalTransforms = nil
+	p.transformErrors = nil
+	p.version = 0
+	p.changes = nil
+	p.events = nil
+	p.droppedEvents = 0
+	p.eventSink = nil
+	p.documents = nil
+	p.documentCount = 0
+	if p.detectedDocuments {
+		p.options.multipleDocuments = false
+		p.detectedDocuments = false
+	}
+	p.checkpoints = nil
+	p.documentCallbacks = nil
+	p.documentStarts = nil
+	p.bindings = nil
+	p.tees = nil
+	p.RemoveSpillFiles()
+	p.spillErr = nil
+	p.errors = nil
+	p.schemaErrors = nil
+	p.shapeDeviations = nil
+	p.recoveries = nil
+	p.skipping = false
+	p.tokenEnd = 0
+	p.memory = 0
+	p.leadingText = nil
+	p.leadingEnd = 0
+	p.skipDepth = 0
+	p.skimDepth = 0
+	p.finished = false
+	p.truncated = false
+	p.tokenCounts = [len(tokenTypeNames)]int{}
+	p.maxDepthSeen = 0
+	p.nodes = 0
+	p.expect = expectDocument
 }
 
 // Append adds more content to the parser and processes tokens
 func (p *StreamJSONParser) Append(content string) {
+	defer p.guardWrite("Append")()
+	if p.finished {
+		return
+	}
+	if p.fence != nil {
+		content = p.fence.filter(content)
+	}
+	p.version++
 	p.tokenizer.Append(content)
 	p.processTokens()
+	p.checkTrailingText()
+	p.compact()
+}
+
+// AppendBytes is like Append for input that arrives as bytes, such as gRPC
+// or network reads. The bytes are copied into the parser's buffer directly,
+// without an intermediate string, so data may be reused once it returns.
+func (p *StreamJSONParser) AppendBytes(data []byte) {
+	defer p.guardWrite("AppendBytes")()
+	if p.finished {
+		return
+	}
+	if p.fence != nil {
+		p.Append(string(data))
+		return
+	}
+	p.version++
+	p.tokenizer.AppendBytes(data)
+	p.processTokens()
+	p.checkTrailingText()
+	p.compact()
 }
 
 // processTokens processes available tokens and builds the AST
 func (p *StreamJSONParser) processTokens() {
+	// Stop consuming input once the tree is known to be inconsistent,
+	// and leave anything after a completed root in the buffer
+	if p.err != nil || p.IsCompleted() && !p.detectDocuments() {
+		return
+	}
+
 	// Keep processing until no more complete tokens are available
 	for {
 		token := p.tokenizer.NextToken()
@@ -169,43 +420,162 @@ func (p *StreamJSONParser) processTokens() {
 		if token.TokenType == EOF {
 			break
 		}
+		if invariantsEnabled {
+			if err := p.checkTokenRange(token); err != nil {
+				p.err = err
+				break
+			}
+		}
+		token, ok := p.intercept(token)
+		if !ok {
+			break
+		}
+		if token.Completed {
+			p.tokenCounts[token.TokenType]++
+		}
+
+		if p.options.strict && token.Completed && !p.checkStrict(token) {
+			break
+		}
+
+		if p.options.hasTokenLimits() && !p.checkLimits(token) {
+			break
+		}
+
+		if p.beforeRoot() && !p.checkLeadingText(token) {
+			break
+		}
+
+		if p.options.includePaths != nil && p.started && len(p.stack) > 0 && p.skim(token) {
+			if !token.Completed {
+				break // Wait for the skipped token to end
+			}
+			continue
+		}
+
+		if token.TokenType == Invalid && !token.Completed {
+			break // Wait for the rest of the bad word
+		}
+
+		if p.options.recovery && p.started && len(p.stack) > 0 {
+			if token.TokenType == Invalid {
+				p.recover(token)
+				continue
+			}
+			if p.skipping {
+				if !token.Completed {
+					break // Wait for the skipped token to end
+				}
+				if p.skip(token) {
+					continue
+				}
+			}
+		}
 
 		if token.TokenType == Invalid {
 			continue // Tolerate errors as required
 		}
 
+		// Scalar roots are complete as soon as their token is
+		if p.options.scalarRoots && (!p.started || p.root.Type == ValueNode) && isScalarToken(token) {
+			p.processScalarRoot(token)
+			if !p.verify(token) || !p.IsCompleted() || !p.options.multipleDocuments {
+				break
+			}
+			p.finishDocument()
+			continue
+		}
+
 		// If we haven't started, we need ObjectStart or ArrayStart
 		if !p.started {
+			if !token.Completed {
+				break // Wait for more input to finish the leading token
+			}
 			if token.TokenType == ObjectStart {
 				p.root = NewNode(ObjectNode)
+				p.root.start = token.TokenStart
 				frame := newStackFrame()
 				frame.Node = p.root
 				frame.ExpectingKey = true
 				p.stack = append(p.stack, frame)
 				p.started = true
+				p.nodeStarted(nil, p.root)
 			} else if token.TokenType == ArrayStart {
 				p.root = NewNode(ArrayNode)
+				p.root.start = token.TokenStart
 				frame := newStackFrame()
 				frame.Node = p.root
 				frame.ExpectingValue = true
 				p.stack = append(p.stack, frame)
 				p.started = true
+				p.nodeStarted(nil, p.root)
 			}
 			// Tolerate other tokens until we find a valid start
+			p.maxDepthSeen = max(p.maxDepthSeen, len(p.stack))
+			if !p.verify(token) {
+				break
+			}
 			continue
 		}
 
 		// Process both completed and incomplete tokens
 		if token.Completed {
 			p.processCompleteToken(token)
+			p.maxDepthSeen = max(p.maxDepthSeen, len(p.stack))
+			if !p.verify(token) {
+				break
+			}
+			if len(p.stack) == 0 {
+				if !p.options.multipleDocuments {
+					if p.detectDocuments() {
+						continue
+					}
+					break
+				}
+				p.finishDocument()
+			}
 		} else {
 			// Handle incomplete tokens for partial access
 			p.processIncompleteToken(token)
+			p.verify(token)
 			break // Break after handling incomplete token
 		}
 	}
 }
 
+// intercept passes token through the WithTokenInterceptor functions. It
+// returns false, with the error set, if one of them returned a token type
+// the parser does not know.
+func (p *StreamJSONParser) intercept(token Token) (Token, bool) {
+	if len(p.options.interceptors) == 0 {
+		return token, true
+	}
+	for _, intercept := range p.options.interceptors {
+		token = intercept(token)
+	}
+	if token.TokenType < 0 || int(token.TokenType) >= len(tokenTypeNames) {
+		p.err = fmt.Errorf("%w: %v", ErrTokenType, token.TokenType)
+		return token, false
+	}
+	return token, true
+}
+
+// verify runs the invariant checks when enabled and records the first violation.
+// It returns false if processing must stop.
+func (p *StreamJSONParser) verify(token Token) bool {
+	if p.err != nil {
+		return false
+	}
+	if !invariantsEnabled {
+		return true
+	}
+	if err := p.checkInvariants(token.TokenEnd); err != nil {
+		p.err = err
+		return false
+	}
+	return true
+}
+
 // processIncompleteToken processes an incomplete token for partial access
 func (p *StreamJSONParser) processIncompleteToken(token Token) {
 	if len(p.stack) == 0 {
@@ -214,20 +584,43 @@ func (p *StreamJSONParser) processIncompleteToken(token Token) {
 
 	currentFrame := p.stack[len(p.stack)-1]
 
-	// Handle incomplete strings for partial access
-	if token.TokenType == String && currentFrame.Node.Type == ObjectNode && currentFrame.CurrentKey != "" {
-		content := token.Content
-		if len(content) >= 1 && content[0] == '"' {
-			partialValue := content[1:] // Remove opening quote
+	// Handle incomplete strings, and numbers if requested, for partial
+	// access, both as object values and as array elements
+	if p.isStreamingValue(token, currentFrame) {
+		if partialValue, ok := p.partialValue(token); ok {
+			var path []string
+			if p.tracksValuePaths() {
+				path = p.childPath(currentFrame)
+			}
 
-			// Provide partial access for any incomplete string
-			valueNode := NewNode(ValueNode)
+			// Provide partial access for any incomplete value,
+			// updating the node from the previous chunk in place
+			valueNode := p.partialNode(currentFrame)
+			var previous interface{}
+			if valueNode != nil {
+				previous = valueNode.Value
+			} else {
+				valueNode = NewNode(ValueNode)
+				valueNode.Completed = false // Mark as incomplete
+				valueNode.Parent = currentFrame.Node
+				valueNode.start = token.TokenStart
+
+				// Store the partial value in the AST
+				if currentFrame.Node.Type == ObjectNode {
+					currentFrame.Shadowed = currentFrame.Node.Children[currentFrame.CurrentKey]
+					currentFrame.Node.Children[currentFrame.CurrentKey] = valueNode
+				} else {
+					currentFrame.Node.Array = append(currentFrame.Node.Array, valueNode)
+				}
+				p.nodeStarted(path, valueNode)
+			}
 			valueNode.Value = partialValue
-			valueNode.Completed = false // Mark as incomplete
-			valueNode.Parent = currentFrame.Node
-
-			// Store the partial value in the AST
-			currentFrame.Node.Children[currentFrame.CurrentKey] = valueNode
+			valueNode.end = token.TokenEnd
+			p.nodeUpdated(path, valueNode, previous)
+		} else {
+			// A number that stopped being valid shows nothing, as it
+			// would had it arrived in one chunk
+			p.dropPartial()
 		}
 	}
 }
@@ -242,16 +635,16 @@ func (p *StreamJSONParser) processCompleteToken(token Token) {
 
 	switch token.TokenType {
 	case ObjectStart:
-		p.handleObjectStart(currentFrame)
+		p.handleObjectStart(token, currentFrame)
 
 	case ArrayStart:
-		p.handleArrayStart(currentFrame)
+		p.handleArrayStart(token, currentFrame)
 
 	case ObjectEnd:
-		p.handleObjectEnd()
+		p.handleObjectEnd(token)
 
 	case ArrayEnd:
-		p.handleArrayEnd()
+		p.handleArrayEnd(token)
 
 	case ObjectKey:
 		p.handleObjectKey(token, currentFrame)
@@ -271,46 +664,68 @@ func (p *StreamJSONParser) processCompleteToken(token Token) {
 }
 
 // handleObjectStart handles the start of an object
-func (p *StreamJSONParser) handleObjectStart(currentFrame *StackFrame) {
+func (p *StreamJSONParser) handleObjectStart(token Token, currentFrame *StackFrame) {
 	newNode := NewNode(ObjectNode)
 	newNode.Parent = currentFrame.Node
+	newNode.start = token.TokenStart
+	key := p.childKey(currentFrame)
+	path := p.containerPath(currentFrame, key)
 
 	if currentFrame.Node.Type == ObjectNode && currentFrame.CurrentKey != "" {
 		currentFrame.Node.Children[currentFrame.CurrentKey] = newNode
 		currentFrame.CurrentKey = ""
+		p.nodeStarted(path, newNode)
 	} else if currentFrame.Node.Type == ArrayNode {
 		currentFrame.Node.Array = append(currentFrame.Node.Array, newNode)
+		p.nodeStarted(path, newNode)
 	}
 
 	frame := newStackFrame()
 	frame.Node = newNode
 	frame.ExpectingKey = true
+	frame.Path = path
+	frame.key = key
+	frame.Included = currentFrame.Included || p.includesAll(path)
 	p.stack = append(p.stack, frame)
 }
 
 // handleArrayStart handles the start of an array
-func (p *StreamJSONParser) handleArrayStart(currentFrame *StackFrame) {
+func (p *StreamJSONParser) handleArrayStart(token Token, currentFrame *StackFrame) {
 	newNode := NewNode(ArrayNode)
 	newNode.Parent = currentFrame.Node
+	newNode.start = token.TokenStart
+	key := p.childKey(currentFrame)
+	path := p.containerPath(currentFrame, key)
 
 	if currentFrame.Node.Type == ObjectNode && currentFrame.CurrentKey != "" {
 		currentFrame.Node.Children[currentFrame.CurrentKey] = newNode
 		currentFrame.CurrentKey = ""
+		p.nodeStarted(path, newNode)
 	} else if currentFrame.Node.Type == ArrayNode {
 		currentFrame.Node.Array = append(currentFrame.Node.Array, newNode)
+		p.nodeStarted(path, newNode)
 	}
 
 	frame := newStackFrame()
 	frame.Node = newNode
 	frame.ExpectingValue = true
+	frame.Path = path
+	frame.key = key
+	frame.Included = currentFrame.Included || p.includesAll(path)
 	p.stack = append(p.stack, frame)
 }
 
 // handleObjectEnd handles the end of an object
-func (p *StreamJSONParser) handleObjectEnd() {
+func (p *StreamJSONParser) handleObjectEnd(token Token) {
 	if len(p.stack) > 0 {
 		currentFrame := p.stack[len(p.stack)-1]
 		currentFrame.Node.Completed = true
+		currentFrame.Node.end = token.TokenEnd
+		var path []string
+		if p.tracksValuePaths() {
+			path = p.framePath(currentFrame)
+		}
+		p.nodeCompleted(path, currentFrame.Node, nil)
 		releaseStackFrame(currentFrame)
 		p.stack = p.stack[:len(p.stack)-1]
 
@@ -328,10 +743,16 @@ func (p *StreamJSONParser) handleObjectEnd() {
 }
 
 // handleArrayEnd handles the end of an array
-func (p *StreamJSONParser) handleArrayEnd() {
+func (p *StreamJSONParser) handleArrayEnd(token Token) {
 	if len(p.stack) > 0 {
 		currentFrame := p.stack[len(p.stack)-1]
 		currentFrame.Node.Completed = true
+		currentFrame.Node.end = token.TokenEnd
+		var path []string
+		if p.tracksValuePaths() {
+			path = p.framePath(currentFrame)
+		}
+		p.nodeCompleted(path, currentFrame.Node, nil)
 		releaseStackFrame(currentFrame)
 		p.stack = p.stack[:len(p.stack)-1]
 
@@ -353,12 +774,16 @@ func (p *StreamJSONParser) handleObjectKey(token Token, currentFrame *StackFrame
 	if currentFrame.Node.Type == ObjectNode {
 		// Extract the key from the quoted string efficiently
 		content := token.Content
-		if len(content) >= 2 && content[0] == '"' && content[len(content)-1] == '"' {
-			currentFrame.CurrentKey = content[1 : len(content)-1]
+		if isQuoted(content) {
+			currentFrame.CurrentKey = p.normalizeKey(p.stringContent(content[1:len(content)-1], false))
 		} else {
-			currentFrame.CurrentKey = content
+			currentFrame.CurrentKey = p.normalizeKey(content)
 		}
 		currentFrame.ExpectingKey = false
+
+		if p.events != nil {
+			p.emit(Event{Type: KeyStarted, Path: p.childPath(currentFrame)})
+		}
 	}
 }
 
@@ -375,18 +800,321 @@ func (p *StreamJSONParser) handleComma(currentFrame *StackFrame) {
 
 // handleValue handles value tokens (string, number, bool, null)
 func (p *StreamJSONParser) handleValue(token Token, currentFrame *StackFrame) {
-	valueNode := NewNode(ValueNode)
+	// Value paths are only built when someone is listening
+	var path []string
+	if p.tracksValuePaths() {
+		path = p.childPath(currentFrame)
+	}
+
+	// Complete the partial node of a streamed string in place
+	valueNode := p.partialNode(currentFrame)
+	isNew := valueNode == nil
+	var previous interface{}
+	if isNew {
+		valueNode = NewNode(ValueNode)
+		valueNode.Parent = currentFrame.Node
+		valueNode.start = token.TokenStart
+	} else {
+		previous = valueNode.Value
+	}
 	valueNode.Value = p.parseTokenValue(token)
 	valueNode.Completed = true
-	valueNode.Parent = currentFrame.Node
+	valueNode.end = token.TokenEnd
+	valueNode.literal = p.numberLiteral(token)
 
 	if currentFrame.Node.Type == ObjectNode && currentFrame.CurrentKey != "" {
 		currentFrame.Node.Children[currentFrame.CurrentKey] = valueNode
 		currentFrame.CurrentKey = ""
 		currentFrame.ExpectingValue = false
 	} else if currentFrame.Node.Type == ArrayNode {
-		currentFrame.Node.Array = append(currentFrame.Node.Array, valueNode)
+		if isNew {
+			currentFrame.Node.Array = append(currentFrame.Node.Array, valueNode)
+		}
 		currentFrame.ExpectingValue = false
+	} else {
+		return
+	}
+
+	if isNew {
+		p.nodeStarted(path, valueNode)
+	}
+	p.nodeCompleted(path, valueNode, previous)
+}
+
+// isStreamingValue reports whether an incomplete token is a string value,
+// or a number with partial numbers enabled, of the frame's node that can be
+// exposed while it streams. In strict mode it must also be in a value
+// position, or it could replace a sibling before its error is found.
+func (p *StreamJSONParser) isStreamingValue(token Token, frame *StackFrame) bool {
+	if p.options.strict && p.expect != expectFirstValue && p.expect != expectValue {
+		return false
+	}
+	inValue := frame.Node.Type == ArrayNode || frame.CurrentKey != ""
+	if token.TokenType == Number {
+		return p.options.partialNumbers && inValue
+	}
+	return token.TokenType == String && inValue
+}
+
+// partialValue returns the value an incomplete string or number token has
+// so far. ok is false when there is nothing to expose yet.
+func (p *StreamJSONParser) partialValue(token Token) (interface{}, bool) {
+	content := token.Content
+	if len(content) >= 1 && isQuote(content[0]) {
+		return p.stringContent(content[1:], true), true // Remove opening quote
+	}
+	if token.TokenType == Number && p.options.partialNumbers {
+		return p.partialNumber(content)
+	}
+	return nil, false
+}
+
+// partialNode returns the incomplete value node the frame's current value
+// is streaming into, if any
+func (p *StreamJSONParser) partialNode(frame *StackFrame) *Node {
+	var node *Node
+	switch frame.Node.Type {
+	case ObjectNode:
+		if frame.CurrentKey == "" {
+			return nil
+		}
+		node = frame.Node.Children[frame.CurrentKey]
+	case ArrayNode:
+		if n := len(frame.Node.Array); n > 0 {
+			node = frame.Node.Array[n-1]
+		}
+	}
+	if node == nil || node.Type != ValueNode || node.Completed {
+		return nil
+	}
+	return node
+}
+
+// dropPartial removes the incomplete value streaming into the innermost
+// container, or the scalar root that is still streaming, if any. A child
+// it replaced under a duplicate key is put back.
+func (p *StreamJSONParser) dropPartial() {
+	if len(p.stack) == 0 {
+		if p.root != nil && p.root.Type == ValueNode && !p.root.Completed {
+			releaseTree(p.root)
+			p.root = nil
+			p.started = false
+		}
+		return
+	}
+	frame := p.stack[len(p.stack)-1]
+	partial := p.partialNode(frame)
+	if partial == nil {
+		return
+	}
+	if frame.Node.Type == ObjectNode {
+		if frame.Shadowed != nil {
+			frame.Node.Children[frame.CurrentKey] = frame.Shadowed
+		} else {
+			delete(frame.Node.Children, frame.CurrentKey)
+		}
+	} else {
+		frame.Node.Array[len(frame.Node.Array)-1] = nil
+		frame.Node.Array = frame.Node.Array[:len(frame.Node.Array)-1]
+	}
+	releaseTree(partial)
+}
+
+// childPath returns the path of the child about to be added to the frame's
+// node, or of the partial child currently streaming into it
+func (p *StreamJSONParser) childPath(frame *StackFrame) []string {
+	parent := p.framePath(frame)
+	path := make([]string, len(parent), len(parent)+1)
+	copy(path, parent)
+	return append(path, p.childKey(frame))
+}
+
+// childKey returns the key or index of the child about to be added to the
+// frame's node, or of the partial child currently streaming into it
+func (p *StreamJSONParser) childKey(frame *StackFrame) string {
+	if frame.Node.Type == ArrayNode {
+		index := frame.Evicted + len(frame.Node.Array)
+		if p.partialNode(frame) != nil {
+			index--
+		}
+		return strconv.Itoa(index)
+	}
+	return frame.CurrentKey
+}
+
+// containerPath returns the path of an object or array about to be pushed
+// onto the stack under frame, or nil when nothing needs it yet. Copying the
+// parent's path for every container is quadratic in the nesting depth, so
+// it is left to framePath until a subscriber or path filter asks.
+func (p *StreamJSONParser) containerPath(frame *StackFrame, key string) []string {
+	if !p.tracksValuePaths() && len(p.options.includePaths) == 0 {
+		return nil
+	}
+	parent := p.framePath(frame)
+	path := make([]string, len(parent), len(parent)+1)
+	copy(path, parent)
+	return append(path, key)
+}
+
+// framePath returns the path of the frame's node, building the paths of the
+// frames between it and the nearest ancestor that has one. The frames share
+// one backing array, each capped at its own length so appends copy. It
+// stores the paths, so methods that only read the parser use stackPath.
+func (p *StreamJSONParser) framePath(frame *StackFrame) []string {
+	if frame.Path != nil || len(p.stack) == 0 || frame == p.stack[0] {
+		return frame.Path
+	}
+	i := len(p.stack) - 1
+	for i > 0 && p.stack[i] != frame {
+		i--
+	}
+	if i == 0 {
+		return nil // Not on the stack
+	}
+	j := i - 1
+	for j > 0 && p.stack[j].Path == nil {
+		j--
+	}
+
+	path := make([]string, len(p.stack[j].Path), len(p.stack[j].Path)+i-j)
+	copy(path, p.stack[j].Path)
+	for j++; j <= i; j++ {
+		path = append(path, p.stack[j].key)
+		p.stack[j].Path = path[:len(path):len(path)]
+	}
+	return frame.Path
+}
+
+// stackPath returns the path of the frame at index i of the stack like
+// framePath, without storing it
+func (p *StreamJSONParser) stackPath(i int) []string {
+	j := i
+	for j > 0 && p.stack[j].Path == nil {
+		j--
+	}
+	path := append([]string(nil), p.stack[j].Path...)
+	for j++; j <= i; j++ {
+		path = append(path, p.stack[j].key)
+	}
+	return path
+}
+
+// tracksValuePaths reports whether completed values need their path computed
+func (p *StreamJSONParser) tracksValuePaths() bool {
+	return len(p.rawSubscriptions) > 0 || len(p.valueSubscriptions) > 0 || len(p.watches) > 0 || len(p.arrayStreams) > 0 ||
+		len(p.transforms) > 0 || len(p.globalTransforms) > 0 || p.options.changeTracking || p.events != nil || p.options.schema != nil || len(p.bindings) > 0 ||
+		p.options.shape != nil || len(p.options.hints) > 0 || len(p.tees) > 0 || len(p.options.redactions) > 0 ||
+		len(p.elementSubscriptions) > 0 || p.options.spillThreshold > 0
+}
+
+// nodeStarted notifies subscribers that a node has been added at path
+func (p *StreamJSONParser) nodeStarted(path []string, node *Node) {
+	p.countNode()
+	if p.options.timestamps {
+		node.times = &valueTimes{started: time.Now()}
+	}
+	if p.options.shape != nil && node.Type != ValueNode && !p.checkShapeStarted(path, node) {
+		return // Parsing stops at the mismatch
+	}
+	if len(p.options.hints) > 0 && node.Type != ValueNode {
+		p.checkHintStarted(path, node)
+	}
+	if node == p.root && p.options.multipleDocuments {
+		p.startDocument()
+	}
+	if p.options.changeTracking {
+		p.recordChange(ChangeAdded, path, nil)
+	}
+	if p.events != nil {
+		p.emitStarted(path, node)
+	}
+	if p.options.schema != nil {
+		p.validateStarted(path, node)
+	}
+	if len(p.bindings) > 0 {
+		p.bindStarted(path, node)
+	}
+	if p.options.memoryBudget > 0 {
+		p.trackMemory(nodeOverhead + memberOverhead + stringLen(node.Value))
+	}
+}
+
+// nodeUpdated notifies subscribers that an incomplete node at path has grown.
+// previous is the partial value before the update.
+func (p *StreamJSONParser) nodeUpdated(path []string, node *Node, previous interface{}) {
+	if p.options.hasValuePolicies() {
+		p.applyValuePolicies(path, node)
+	}
+	if p.events != nil {
+		p.emitDelta(path, node, previous)
+	}
+	if p.options.changeTracking {
+		p.recordChange(ChangeExtended, path, previous)
+	}
+	if len(p.tees) > 0 {
+		p.deliverTee(path, node, previous)
+	}
+	p.deliverValue(path, node)
+	p.deliverWatch(path, node)
+	if p.options.spillThreshold > 0 {
+		p.spillString(path, node, previous)
+	}
+	if p.options.memoryBudget > 0 {
+		p.trackMemory(stringLen(node.Value) - stringLen(previous))
+	}
+}
+
+// nodeCompleted notifies subscribers that the node at path has been completed.
+// previous is the partial value streamed before completion, if any.
+func (p *StreamJSONParser) nodeCompleted(path []string, node *Node, previous interface{}) {
+	if node.times != nil {
+		node.times.completed = time.Now()
+	}
+	if p.options.hasValuePolicies() && node.Type == ValueNode {
+		p.applyValuePolicies(path, node)
+	}
+	// The schema checks the value as parsed, everything else sees it transformed
+	if p.options.schema != nil {
+		p.validateCompleted(path, node)
+	}
+	if p.events != nil && node.Type == ValueNode {
+		p.emitDelta(path, node, previous)
+	}
+	if len(p.tees) > 0 && node.Type == ValueNode {
+		p.deliverTee(path, node, previous)
+	}
+	if node.Type == ValueNode && (len(p.transforms) > 0 || len(p.globalTransforms) > 0) {
+		p.applyTransforms(path, node)
+	}
+	if len(p.options.hints) > 0 && node.Type == ValueNode {
+		node = p.checkHintCompleted(path, node)
+	}
+	if p.options.shape != nil && node.Type == ValueNode && !p.checkShapeCompleted(path, node) {
+		return // Parsing stops at the mismatch
+	}
+	if p.events != nil {
+		p.emitCompleted(path, node)
+	}
+	if p.options.changeTracking {
+		p.recordChange(ChangeCompleted, path, previous)
+	}
+	p.deliverRawSubtree(path, node)
+	p.deliverValue(path, node)
+	p.deliverWatch(path, node)
+	p.deliverElement(path, node)
+	p.deliverArrayElement(path, node)
+	if len(p.bindings) > 0 {
+		p.bindCompleted(path, node)
+	}
+	counted := previous
+	if p.options.spillThreshold > 0 && node.Type == ValueNode {
+		if counted == nil {
+			counted = node.Value // Counted when the node started
+		}
+		p.spillString(path, node, previous)
+	}
+	if p.options.memoryBudget > 0 && counted != nil {
+		p.trackMemory(stringLen(node.Value) - stringLen(counted))
 	}
 }
 
@@ -397,44 +1125,17 @@ func (p *StreamJSONParser) parseTokenValue(token Token) interface{} {
 	switch token.TokenType {
 	case String:
 		// Remove quotes from string content efficiently
-		if len(content) >= 2 && content[0] == '"' && content[len(content)-1] == '"' {
-			return content[1 : len(content)-1]
+		if isQuoted(content) {
+			return p.stringContent(content[1:len(content)-1], false)
 		}
 		return content
 
 	case Number:
-		// Optimized number parsing - check for integer vs float efficiently
-		hasDecimal := false
-		hasExp := false
-
-		for i := 0; i < len(content); i++ {
-			c := content[i]
-			if c == '.' {
-				hasDecimal = true
-				break
-			} else if c == 'e' || c == 'E' {
-				hasExp = true
-				break
-			}
-		}
-
-		if !hasDecimal && !hasExp {
-			// Try integer parsing first for performance
-			if val, err := strconv.ParseInt(content, 10, 64); err == nil {
-				return val
-			}
-		}
-
-		// Parse as float
-		if val, err := strconv.ParseFloat(content, 64); err == nil {
-			return val
-		}
-
-		return content // Fallback to string if parsing fails
+		return p.parseNumber(content)
 
 	case Bool:
 		// Optimized boolean check
-		return len(content) == 4 && content[0] == 't' // "true" has length 4 and starts with 't'
+		return len(content) == 4 // "true" and repaired "True" have length 4
 
 	case Null:
 		return nil
@@ -444,106 +1145,209 @@ func (p *StreamJSONParser) parseTokenValue(token Token) interface{} {
 	}
 }
 
-// Get retrieves a value from the AST using a path of keys
+// isQuote checks if character opens a string, including repaired single quotes
+func isQuote(char byte) bool {
+	return char == '"' || char == '\''
+}
+
+// isQuoted checks if content is a complete quoted string
+func isQuoted(content string) bool {
+	return len(content) >= 2 && isQuote(content[0]) && content[len(content)-1] == content[0]
+}
+
+// stringContent returns the value of string content without its quotes,
+// decoding escape sequences unless raw strings were requested. A partial
+// string never ends inside a multi-byte character split across chunks.
+func (p *StreamJSONParser) stringContent(content string, partial bool) string {
+	if partial {
+		content = content[:completeUTF8(content)]
+	}
+	if p.options.rawStrings {
+		return content
+	}
+	return decodeString(content, partial)
+}
+
+// Get retrieves a value from the AST using a path of keys. With no keys it
+// returns the whole root as map[string]interface{} or []interface{}.
 func (p *StreamJSONParser) Get(keys ...string) interface{} {
-	if p.root == nil || len(keys) == 0 {
+	defer p.guardRead("Get")()
+	if p.root == nil {
 		return nil
 	}
 
-	return p.getFromNode(p.root, keys)
+	node := p.findNode(keys)
+	if node == nil {
+		return nil
+	}
+	if node.Type == ValueNode {
+		return node.Value
+	}
+	// Collect subvalues for non-value nodes
+	return p.collectNodeValue(node)
 }
 
-// getFromNode recursively traverses the AST to find the value
-func (p *StreamJSONParser) getFromNode(node *Node, keys []string) interface{} {
-	if node == nil || len(keys) == 0 {
-		if node != nil {
-			if node.Type == ValueNode {
-				return node.Value
-			}
-			// Collect subvalues for non-value nodes
-			return p.collectNodeValue(node)
+// findNode walks the AST along a path of keys and array indices
+func (p *StreamJSONParser) findNode(keys []string) *Node {
+	keys = p.normalizePath(keys)
+	node := p.root
+	for _, key := range keys {
+		if node == nil {
+			return nil
 		}
-		return nil
+		node = p.childNode(node, key)
 	}
+	return node
+}
 
-	key := keys[0]
-	remainingKeys := keys[1:]
-
+// childNode returns the member of an object or element of an array named
+// by a path segment, or nil if there is none
+func (p *StreamJSONParser) childNode(node *Node, key string) *Node {
 	switch node.Type {
 	case ObjectNode:
-		if child, exists := node.Children[key]; exists {
-			if len(remainingKeys) == 0 {
-				if child.Type == ValueNode {
-					return child.Value
-				}
-				// Collect subvalues for non-value nodes
-				return p.collectNodeValue(child)
-			}
-			return p.getFromNode(child, remainingKeys)
-		}
+		return node.Children[key]
 
 	case ArrayNode:
 		// Try to parse key as array index
-		if index, err := strconv.Atoi(key); err == nil {
-			if index >= 0 && index < len(node.Array) {
-				child := node.Array[index]
-				if len(remainingKeys) == 0 {
-					if child.Type == ValueNode {
-						return child.Value
-					}
-					// Collect subvalues for non-value nodes
-					return p.collectNodeValue(child)
-				}
-				return p.getFromNode(child, remainingKeys)
-			}
+		index, err := strconv.Atoi(key)
+		if err == nil && len(p.arrayStreams) > 0 {
+			index -= p.evictedElements(node)
+		}
+		if err != nil || index < 0 || index >= len(node.Array) {
+			return nil
 		}
+		return node.Array[index]
 	}
-
 	return nil
 }
 
+// GetCompleted is like Get but leaves out strings that are still streaming,
+// so every scalar in the result is final. Objects and arrays that are still
+// open are included with the members completed so far.
+func (p *StreamJSONParser) GetCompleted(keys ...string) interface{} {
+	defer p.guardRead("GetCompleted")()
+	if p.root == nil {
+		return nil
+	}
+	node := p.findNode(keys)
+	if node == nil || (node.Type == ValueNode && !node.Completed) {
+		return nil
+	}
+	return collectValue(node, false)
+}
+
 // collectNodeValue collects all values from a node's children
 func (p *StreamJSONParser) collectNodeValue(node *Node) interface{} {
+	return collectValue(node, true)
+}
+
+// collectValue materializes a node, including incomplete values if partial
+// is set. It walks the tree with an explicit stack rather than recursion, so
+// deeply nested documents cannot exhaust the goroutine stack.
+func collectValue(node *Node, partial bool) interface{} {
 	if node == nil {
 		return nil
 	}
+	if node.Type == ValueNode {
+		return node.Value
+	}
 
-	switch node.Type {
-	case ObjectNode:
-		result := make(map[string]interface{})
-		for key, child := range node.Children {
-			if child.Type == ValueNode {
-				result[key] = child.Value
-			} else {
-				result[key] = p.collectNodeValue(child)
+	result, task := newCollectTask(node, partial)
+	stack := []collectTask{task}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		i := 0
+		for key, child := range task.node.Children {
+			if value, next, ok := collectChild(child, partial); ok {
+				task.object[key] = value
+				if next.node != nil {
+					stack = append(stack, next)
+				}
 			}
 		}
-		return result
-
-	case ArrayNode:
-		result := make([]interface{}, len(node.Array))
-		for i, child := range node.Array {
-			if child.Type == ValueNode {
-				result[i] = child.Value
-			} else {
-				result[i] = p.collectNodeValue(child)
+		for _, child := range task.node.Array {
+			if value, next, ok := collectChild(child, partial); ok {
+				task.array[i] = value
+				i++
+				if next.node != nil {
+					stack = append(stack, next)
+				}
 			}
 		}
-		return result
+	}
+	return result
+}
 
-	case ValueNode:
-		return node.Value
+// collectTask is an object or array whose materialized value collectValue
+// has created but not filled in yet
+type collectTask struct {
+	node   *Node
+	object map[string]interface{}
+	array  []interface{}
+}
+
+// collectChild returns the value of a child for collectValue, with the task
+// filling it in if it is an object or array, or false for a value left out
+func collectChild(child *Node, partial bool) (interface{}, collectTask, bool) {
+	if child.Type != ValueNode {
+		value, next := newCollectTask(child, partial)
+		return value, next, true
+	}
+	if !partial && !child.Completed {
+		return nil, collectTask{}, false
 	}
+	return child.Value, collectTask{}, true
+}
 
-	return nil
+// newCollectTask creates the empty value of an object or array node, sized
+// for the members collectValue will add
+func newCollectTask(node *Node, partial bool) (interface{}, collectTask) {
+	if node.Type == ObjectNode {
+		object := make(map[string]interface{}, len(node.Children))
+		return object, collectTask{node: node, object: object}
+	}
+
+	n := 0
+	for _, child := range node.Array {
+		if child.Type != ValueNode || partial || child.Completed {
+			n++
+		}
+	}
+	array := make([]interface{}, n)
+	return array, collectTask{node: node, array: array}
 }
 
 // IsCompleted returns true if the parsing stack is empty (all structures closed)
+// and the root value is complete. Completion also tells a stream cut off by
+// Finish from one that is still arriving.
 func (p *StreamJSONParser) IsCompleted() bool {
-	return len(p.stack) == 0 && p.started
+	defer p.guardRead("IsCompleted")()
+	return len(p.stack) == 0 && p.started && p.root.Completed
+}
+
+// Err returns the error that stopped the parser, if any. It is a
+// *ParseError in strict mode, or an *InvariantError when built with the
+// streamjson_invariants tag.
+func (p *StreamJSONParser) Err() error {
+	return p.err
 }
 
-// GetRoot returns the root node of the AST
+// GetRoot returns the root node of the AST. Nodes belong to the parser:
+// they keep changing as content is appended, are not safe for concurrent
+// use and must not be used after Reset or once evicted by StreamArray.
 func (p *StreamJSONParser) GetRoot() *Node {
+	defer p.guardRead("GetRoot")()
 	return p.root
 }
+
+// GetNode returns the node at the path for inspection, or nil if the path
+// has not been seen. See GetRoot for how long nodes stay valid.
+func (p *StreamJSONParser) GetNode(keys ...string) *Node {
+	defer p.guardRead("GetNode")()
+	if p.root == nil {
+		return nil
+	}
+	return p.findNode(keys)
+}
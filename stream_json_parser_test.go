@@ -15,6 +15,9 @@
 package streamjson
 
 import (
+	"errors"
+	"reflect"
+	"regexp"
 	"testing"
 )
 
@@ -385,9 +388,45 @@ func TestStreamJSONParserGetRootObject(t *testing.T) {
 	}
 
 	// Get root by calling Get with no keys
-	root := parser.Get()
-	if root != nil {
-		t.Errorf("Expected Get() with no keys to return nil, got %v", root)
+	root, ok := parser.Get().(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected Get() with no keys to return the root map, got %T", parser.Get())
+	}
+	if len(root) != 3 || root["key1"] != "value1" || root["key2"] != int64(123) || root["key3"] != true {
+		t.Errorf("Unexpected root %v", root)
+	}
+}
+
+func TestStreamJSONParserGetCompleted(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	if parser.Get() != nil || parser.GetCompleted() != nil {
+		t.Errorf("Expected nil before the root")
+	}
+
+	parser.Append(`{"done":"yes","list":["a","b`)
+
+	// Get includes the strings still streaming
+	root := parser.Get().(map[string]interface{})
+	if list := root["list"].([]interface{}); len(list) != 2 || list[1] != "b" {
+		t.Errorf("Expected partial list [a b], got %v", list)
+	}
+
+	parser.Append(`c"],"partial":"x`)
+
+	// GetCompleted leaves them out
+	completed := parser.GetCompleted().(map[string]interface{})
+	if _, ok := completed["partial"]; ok {
+		t.Errorf("Expected partial string to be left out, got %v", completed)
+	}
+	if completed["done"] != "yes" || len(completed["list"].([]interface{})) != 2 {
+		t.Errorf("Expected completed values, got %v", completed)
+	}
+	if parser.GetCompleted("partial") != nil {
+		t.Errorf("Expected nil for a streaming string")
+	}
+	if parser.GetCompleted("list", "1") != "bc" {
+		t.Errorf("Expected completed list item, got %v", parser.GetCompleted("list", "1"))
 	}
 }
 
@@ -765,3 +804,283 @@ func TestStreamJSONParserIncrementalMessageStatus(t *testing.T) {
 		t.Errorf("Expected parser to be completed")
 	}
 }
+
+func TestStreamJSONParserArrayStringPartialAccess(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	parser.Append(`{"tags":["al`)
+	// An incomplete first element is visible
+	if tag := parser.Get("tags", "0"); tag != "al" {
+		t.Errorf("Expected partial first tag 'al', got %v", tag)
+	}
+
+	parser.Append(`pha","be`)
+	// Elements after a comma are visible too
+	if tag := parser.Get("tags", "0"); tag != "alpha" {
+		t.Errorf("Expected first tag 'alpha', got %v", tag)
+	}
+	if tag := parser.Get("tags", "1"); tag != "be" {
+		t.Errorf("Expected partial second tag 'be', got %v", tag)
+	}
+
+	parser.Append(`ta"],"items":[{"id":1},{"id":2},{"description":"Lo`)
+	if tags := parser.Get("tags").([]interface{}); len(tags) != 2 || tags[1] != "beta" {
+		t.Errorf("Expected tags [alpha beta], got %v", tags)
+	}
+	if desc := parser.Get("items", "2", "description"); desc != "Lo" {
+		t.Errorf("Expected partial description 'Lo', got %v", desc)
+	}
+
+	parser.Append(`ng text"}]}`)
+	if desc := parser.Get("items", "2", "description"); desc != "Long text" {
+		t.Errorf("Expected description 'Long text', got %v", desc)
+	}
+	if !parser.IsCompleted() {
+		t.Errorf("Expected parser to be completed")
+	}
+}
+
+func TestStreamJSONParserRepair(t *testing.T) {
+	input := `{name: 'Alice', "quote": 'say "hi"', active: True, spouse: None, tags: [1, 2,], nested: {x: False,},}`
+
+	for _, bytewise := range []bool{false, true} {
+		parser := NewStreamJSONParser(WithRepair())
+		if bytewise {
+			for i := 0; i < len(input); i++ {
+				parser.Append(input[i : i+1])
+			}
+		} else {
+			parser.Append(input)
+		}
+
+		if !parser.IsCompleted() {
+			t.Errorf("Expected parser to be completed (bytewise=%v)", bytewise)
+		}
+
+		if parser.Get("name") != "Alice" {
+			t.Errorf("Expected name to be 'Alice', got %v", parser.Get("name"))
+		}
+
+		if parser.Get("quote") != `say "hi"` {
+			t.Errorf("Expected quote to be 'say \"hi\"', got %v", parser.Get("quote"))
+		}
+
+		if parser.Get("active") != true {
+			t.Errorf("Expected active to be true, got %v", parser.Get("active"))
+		}
+
+		if value, ok := parser.GetRoot().Children["spouse"]; !ok || value.Value != nil {
+			t.Errorf("Expected spouse to be null, got %v", value)
+		}
+
+		if parser.Get("tags", "1") != int64(2) {
+			t.Errorf("Expected tags[1] to be 2, got %v", parser.Get("tags", "1"))
+		}
+
+		if parser.Get("nested", "x") != false {
+			t.Errorf("Expected nested.x to be false, got %v", parser.Get("nested", "x"))
+		}
+	}
+}
+
+func TestStreamJSONParserRepairPartialSingleQuoted(t *testing.T) {
+	parser := NewStreamJSONParser(WithRepair())
+
+	parser.Append(`{'message': 'Hel`)
+	if parser.Get("message") != "Hel" {
+		t.Errorf("Expected partial message 'Hel', got %v", parser.Get("message"))
+	}
+
+	parser.Append(`lo'}`)
+	if parser.Get("message") != "Hello" {
+		t.Errorf("Expected message 'Hello', got %v", parser.Get("message"))
+	}
+}
+
+func TestStreamJSONParserLenientKeys(t *testing.T) {
+	input := `{name: "Alice", 'role': "admin", content-type: "text", $ref: {'x-id': 1}}`
+
+	for _, bytewise := range []bool{false, true} {
+		parser := NewStreamJSONParser(WithLenientKeys())
+		if bytewise {
+			for i := 0; i < len(input); i++ {
+				parser.Append(input[i : i+1])
+			}
+		} else {
+			parser.Append(input)
+		}
+
+		if !parser.IsCompleted() {
+			t.Errorf("Expected parser to be completed (bytewise=%v)", bytewise)
+		}
+		data, _ := parser.MarshalJSON()
+		if string(data) != `{"$ref":{"x-id":1},"content-type":"text","name":"Alice","role":"admin"}` {
+			t.Errorf("Unexpected document %s (bytewise=%v)", data, bytewise)
+		}
+	}
+}
+
+func TestStreamJSONParserReset(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+
+	calls := 0
+	parser.OnValue("a", func(value interface{}, complete bool) {
+		calls++
+	})
+	events := parser.Events()
+
+	parser.Append(`{"a":"first",`)
+	parser.Reset()
+
+	// The open event channel is closed and callbacks are dropped
+	for range events {
+	}
+	if parser.Get() != nil || parser.IsCompleted() {
+		t.Errorf("Expected an empty parser after Reset")
+	}
+
+	parser.Append(`{"a":"second"}`)
+	if parser.Get("a") != "second" || !parser.IsCompleted() {
+		t.Errorf("Expected second document, got %v", parser.Get())
+	}
+	if calls != 1 {
+		t.Errorf("Expected callback only for the first document, got %d calls", calls)
+	}
+
+	// Errors are cleared, options are kept
+	parser.Reset()
+	parser.Append(`{"a":,}`)
+	if len(parser.Errors()) != 1 {
+		t.Fatalf("Expected strict mode to be kept, got %d errors", len(parser.Errors()))
+	}
+	parser.Reset()
+	if parser.Err() != nil || len(parser.Errors()) != 0 {
+		t.Errorf("Expected errors to be cleared")
+	}
+	parser.Append(`[1]`)
+	if parser.Get("0") != int64(1) {
+		t.Errorf("Expected array after Reset, got %v", parser.Get())
+	}
+}
+
+func TestPartialStringsInArrays(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`["Hi!", "How can`)
+	if got := parser.Get(); !reflect.DeepEqual(got, []interface{}{"Hi!", "How can"}) {
+		t.Errorf("Expected the last root element streaming, got %v", got)
+	}
+	if n, complete := parser.Len(); n != 2 || complete {
+		t.Errorf("Expected 2 elements still streaming, got %d, %v", n, complete)
+	}
+
+	parser.Append(` I help?", "`)
+	if got := parser.Get("1"); got != "How can I help?" {
+		t.Errorf("Expected the element completed, got %v", got)
+	}
+	if got := parser.Get("2"); got != "" {
+		t.Errorf("Expected an empty third element, got %v", got)
+	}
+	if parser.IsComplete("2") {
+		t.Error("Expected the third element to still be streaming")
+	}
+
+	parser.Append(`Bye"]`)
+	if got := parser.Get(); !reflect.DeepEqual(got, []interface{}{"Hi!", "How can I help?", "Bye"}) {
+		t.Errorf("Expected the completed array, got %v", got)
+	}
+
+	// Nested arrays, and an array after an object member
+	parser = NewStreamJSONParser()
+	parser.Append(`{"turns": [["user", "Hello"], ["assistant", "Sure, here`)
+	if got := parser.Get("turns", "1", "1"); got != "Sure, here" {
+		t.Errorf("Expected a nested element streaming, got %v", got)
+	}
+	parser.Append(` it is"]], "done": true}`)
+	want := []interface{}{[]interface{}{"user", "Hello"}, []interface{}{"assistant", "Sure, here it is"}}
+	if got := parser.Get("turns"); !reflect.DeepEqual(got, want) || parser.Get("done") != true {
+		t.Errorf("Expected %v, got %v", want, parser.Get())
+	}
+}
+
+func TestTokenInterceptor(t *testing.T) {
+	secret := regexp.MustCompile(`sk-[A-Za-z0-9]+`)
+	var seen []TokenType
+	parser := NewStreamJSONParser(
+		WithTokenInterceptor(func(token Token) Token {
+			if token.Completed {
+				seen = append(seen, token.TokenType)
+			}
+			return token
+		}),
+		WithTokenInterceptor(func(token Token) Token {
+			if token.TokenType == String {
+				token.Content = secret.ReplaceAllString(token.Content, "[redacted]")
+			}
+			return token
+		}),
+	)
+
+	parser.Append(`{"key": "sk-ab`)
+	if got := parser.Get("key"); got != "[redacted]" {
+		t.Errorf("Expected the streaming string redacted, got %v", got)
+	}
+	parser.Append(`c123", "sk-name": [true]}`)
+
+	want := map[string]interface{}{"key": "[redacted]", "sk-name": []interface{}{true}}
+	if got := parser.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if raw := string(parser.GetRaw("key")); raw != `"sk-abc123"` {
+		t.Errorf("Expected raw bytes to keep the original input, got %s", raw)
+	}
+
+	wantSeen := []TokenType{ObjectStart, ObjectKey, Colon, String, Comma, ObjectKey, Colon, ArrayStart, Bool, ArrayEnd, ObjectEnd}
+	if !reflect.DeepEqual(seen, wantSeen) {
+		t.Errorf("Expected tokens %v, got %v", wantSeen, seen)
+	}
+}
+
+func TestTokenInterceptorChangesType(t *testing.T) {
+	// Turn numbers into strings, keeping their text
+	parser := NewStreamJSONParser(WithTokenInterceptor(func(token Token) Token {
+		if token.TokenType == Number && token.Completed {
+			token.TokenType = String
+			token.Content = `"` + token.Content + `"`
+		}
+		return token
+	}))
+	parser.Append(`{"id": 12345678901234567890, "n": [1.50]}`)
+
+	if parser.Get("id") != "12345678901234567890" || parser.Get("n", "0") != "1.50" {
+		t.Errorf("Expected numbers as strings, got %v", parser.Get())
+	}
+}
+
+func TestTokenInterceptorUnknownType(t *testing.T) {
+	parser := NewStreamJSONParser(WithTokenInterceptor(func(token Token) Token {
+		if token.TokenType == Number {
+			token.TokenType = TokenType(100)
+		}
+		return token
+	}))
+	parser.Append(`{"a": "x", "b": 1, "c": 2}`)
+
+	if err := parser.Err(); !errors.Is(err, ErrTokenType) {
+		t.Fatalf("Err() = %v, want ErrTokenType", err)
+	}
+	if parser.Get("a") != "x" || parser.Get("c") != nil {
+		t.Errorf("document = %v, want parsing stopped at b", parser.Get())
+	}
+}
+
+func BenchmarkParserAppend(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkInput)))
+	parser := NewStreamJSONParser()
+	for i := 0; i < b.N; i++ {
+		parser.Reset()
+		for start := 0; start < len(benchmarkInput); start += 64 {
+			parser.Append(benchmarkInput[start:min(start+64, len(benchmarkInput))])
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+)
+
+// PatchOp is a JSON Patch (RFC 6902) operation. It marshals to the standard
+// {"op": ..., "path": ..., "value": ...} form.
+type PatchOp struct {
+	Op    string      `json:"op"`    // "add" or "replace"
+	Path  string      `json:"path"`  // JSON Pointer (RFC 6901) of the target
+	Value interface{} `json:"value"` // New value
+}
+
+// pointerEscaper escapes reference tokens of a JSON Pointer
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// PatchesSince returns JSON Patch operations that bring a document holding
+// the state at version up to date, requires WithChangeTracking. Applied in
+// order to null for version 0, they rebuild the current, possibly partial,
+// document. New values are added; streaming strings and partial numbers are
+// replaced as they grow.
+func (p *StreamJSONParser) PatchesSince(version int) []PatchOp {
+	defer p.guardRead("PatchesSince")()
+	var ops []PatchOp
+	for _, change := range p.Diff(version) {
+		switch change.Kind {
+		case ChangeAdded:
+			ops = append(ops, PatchOp{Op: "add", Path: jsonPointer(change.Path), Value: change.New})
+		case ChangeExtended, ChangeCompleted:
+			// Closing an object, an array or a string adds no content
+			node := p.findNode(change.Path)
+			if node == nil || node.Type != ValueNode || change.Old == change.New {
+				continue
+			}
+			ops = append(ops, PatchOp{Op: "replace", Path: jsonPointer(change.Path), Value: change.New})
+		}
+	}
+	return ops
+}
+
+// jsonPointer formats a path as a JSON Pointer
+func jsonPointer(path []string) string {
+	var b strings.Builder
+	for _, segment := range path {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(segment))
+	}
+	return b.String()
+}
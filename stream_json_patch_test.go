@@ -0,0 +1,121 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// applyPatch applies add and replace operations to a decoded JSON document
+func applyPatch(t *testing.T, doc interface{}, ops []PatchOp) interface{} {
+	t.Helper()
+	for _, op := range ops {
+		if op.Path == "" {
+			doc = op.Value
+			continue
+		}
+		tokens := strings.Split(op.Path[1:], "/")
+		for i := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+		}
+
+		parent := doc
+		for _, token := range tokens[:len(tokens)-1] {
+			switch v := parent.(type) {
+			case map[string]interface{}:
+				parent = v[token]
+			case []interface{}:
+				index, _ := strconv.Atoi(token)
+				parent = v[index]
+			}
+		}
+
+		last := tokens[len(tokens)-1]
+		switch v := parent.(type) {
+		case map[string]interface{}:
+			v[last] = op.Value
+		case []interface{}:
+			index, err := strconv.Atoi(last)
+			if err != nil || index > len(v) {
+				t.Fatalf("Invalid array index in %+v", op)
+			}
+			if op.Op == "add" {
+				if index != len(v) {
+					t.Fatalf("Expected adds to append, got %+v", op)
+				}
+				v = append(v, op.Value)
+				doc = setAt(doc, tokens[:len(tokens)-1], v)
+			} else {
+				v[index] = op.Value
+			}
+		default:
+			t.Fatalf("Cannot apply %+v", op)
+		}
+	}
+	return doc
+}
+
+// setAt replaces the value at path, used when appending reallocates a slice
+func setAt(doc interface{}, path []string, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		v[path[0]] = setAt(v[path[0]], path[1:], value)
+	case []interface{}:
+		index, _ := strconv.Atoi(path[0])
+		v[index] = setAt(v[index], path[1:], value)
+	}
+	return doc
+}
+
+func TestStreamJSONParserPatchesSince(t *testing.T) {
+	input := `{"title":"Hello, world","items":[1,{"a/b":"x~y","n":[true,null]},"str"],"nested":{"deep":{"v":-12.5}}}`
+
+	for _, size := range []int{1, 3, 7, len(input)} {
+		parser := NewStreamJSONParser(WithChangeTracking(), WithPartialNumbers())
+		var doc interface{}
+		version := 0
+
+		for start := 0; start < len(input); start += size {
+			parser.Append(input[start:min(start+size, len(input))])
+			doc = applyPatch(t, doc, parser.PatchesSince(version))
+			version = parser.Version()
+
+			expected, _ := json.Marshal(parser.Get())
+			got, _ := json.Marshal(doc)
+			if string(got) != string(expected) {
+				t.Fatalf("Chunk size %d after %d bytes: expected %s, got %s", size, start+size, expected, got)
+			}
+		}
+	}
+}
+
+func TestStreamJSONParserPatchOps(t *testing.T) {
+	parser := NewStreamJSONParser(WithChangeTracking())
+	parser.Append(`{"msg":"Hi`)
+	version := parser.Version()
+	parser.Append(` there","list":[]}`)
+
+	data, _ := json.Marshal(parser.PatchesSince(version))
+	expected := `[{"op":"replace","path":"/msg","value":"Hi there"},{"op":"add","path":"/list","value":[]}]`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+)
+
+// pathWildcard matches any single object key or array index in a path pattern
+const pathWildcard = "*"
+
+// pathEach selects every element of an array in GetPath, or counts them as
+// the last segment
+const pathEach = "#"
+
+// splitPath splits a dotted path such as "users.0.name" into its segments.
+// A dot inside a key is escaped as "\.". The empty path refers to the root.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	if !strings.Contains(path, `\.`) {
+		return strings.Split(path, ".")
+	}
+
+	var segments []string
+	var segment strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+			segment.WriteByte('.')
+			i++
+		case path[i] == '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteByte(path[i])
+		}
+	}
+	return append(segments, segment.String())
+}
+
+// pathLink is a key in the paths of a walk over a tree, linked to the path
+// of its parent, so the walk only builds the paths it reports
+type pathLink struct {
+	parent int // Link of the parent's path, 0 for the base path of the walk
+	key    string
+}
+
+// pathLinks holds the links of a walk. Link i is pathLinks[i-1], so link 0
+// stands for the base path.
+type pathLinks []pathLink
+
+// add appends key under the path of parent and returns its link
+func (l *pathLinks) add(parent int, key string) int {
+	*l = append(*l, pathLink{parent: parent, key: key})
+	return len(*l)
+}
+
+// path returns base followed by the keys leading to link
+func (l pathLinks) path(base []string, link int) []string {
+	if link == 0 {
+		return base
+	}
+	n := 0
+	for i := link; i > 0; i = l[i-1].parent {
+		n++
+	}
+	path := make([]string, len(base)+n)
+	copy(path, base)
+	for i := link; i > 0; i = l[i-1].parent {
+		n--
+		path[len(base)+n] = l[i-1].key
+	}
+	return path
+}
+
+// GetPath retrieves a value using a single dotted path in the style of
+// gjson, such as "users.0.name". A "#" segment applies the rest of the path
+// to every element of an array and returns the results that exist as
+// []interface{}, as in "users.#.name"; as the last segment it returns the
+// number of elements as int64. A "*" segment does the same for every member
+// of an object, in sorted key order, or element of an array. A dot inside a
+// key is escaped as "\.". Missing paths return nil.
+func (p *StreamJSONParser) GetPath(path string) interface{} {
+	defer p.guardRead("GetPath")()
+	if p.root == nil {
+		return nil
+	}
+	return p.getPath(p.root, p.normalizePath(splitPath(path)))
+}
+
+// getPath resolves path below node
+func (p *StreamJSONParser) getPath(node *Node, path []string) interface{} {
+	for i, key := range path {
+		rest := path[i+1:]
+		switch {
+		case key == pathEach && node.Type == ArrayNode:
+			if len(rest) == 0 {
+				return int64(len(node.Array))
+			}
+			return p.collectPaths(node.Array, rest)
+
+		case key == pathWildcard && node.Type == ArrayNode:
+			return p.collectPaths(node.Array, rest)
+
+		case key == pathWildcard && node.Type == ObjectNode:
+			keys := node.Keys()
+			children := make([]*Node, len(keys))
+			for j, key := range keys {
+				children[j] = node.Children[key]
+			}
+			return p.collectPaths(children, rest)
+		}
+
+		if node = p.childNode(node, key); node == nil {
+			return nil
+		}
+	}
+
+	if node.Type == ValueNode {
+		return node.Value
+	}
+	return p.collectNodeValue(node)
+}
+
+// collectPaths resolves path below each node, keeping the results that exist
+func (p *StreamJSONParser) collectPaths(nodes []*Node, path []string) []interface{} {
+	results := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		if value := p.getPath(node, path); value != nil {
+			results = append(results, value)
+		}
+	}
+	return results
+}
+
+// matchPath reports whether path matches pattern segment by segment,
+// treating "*" in the pattern as a single-segment wildcard
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, segment := range pattern {
+		if segment != pathWildcard && segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
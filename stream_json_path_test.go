@@ -0,0 +1,98 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	if segments := splitPath(""); segments != nil {
+		t.Errorf("Expected nil for root path, got %v", segments)
+	}
+
+	segments := splitPath("users.0.name")
+	if len(segments) != 3 || segments[0] != "users" || segments[1] != "0" || segments[2] != "name" {
+		t.Errorf("Expected [users 0 name], got %v", segments)
+	}
+
+	segments = splitPath(`headers.content\.type.0`)
+	if !reflect.DeepEqual(segments, []string{"headers", "content.type", "0"}) {
+		t.Errorf("Expected an escaped dot to stay in the key, got %v", segments)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     []string
+		expected bool
+	}{
+		{"", nil, true},
+		{"a", []string{"a"}, true},
+		{"a.b", []string{"a"}, false},
+		{"a.*.c", []string{"a", "3", "c"}, true},
+		{"a.*.c", []string{"a", "3", "d"}, false},
+		{"*", []string{"x"}, true},
+	}
+
+	for _, test := range tests {
+		if got := matchPath(splitPath(test.pattern), test.path); got != test.expected {
+			t.Errorf("matchPath(%q, %v): expected %v, got %v", test.pattern, test.path, test.expected, got)
+		}
+	}
+}
+
+func TestGetPath(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"users": [{"name": "Ann", "tags": ["a"]}, {"age": 3}, {"name": "Bob", "tags": ["b", "c"]}], ` +
+		`"scores": {"x": 1, "y": 2}, "a.b": true}`)
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"users.0.name", "Ann"},
+		{"users.#", int64(3)},
+		{"users.#.name", []interface{}{"Ann", "Bob"}},
+		{"users.#.tags.#", []interface{}{int64(1), int64(2)}},
+		{"users.#.tags.0", []interface{}{"a", "b"}},
+		{"users.*.age", []interface{}{int64(3)}},
+		{"scores.*", []interface{}{int64(1), int64(2)}},
+		{`a\.b`, true},
+		{"users.5.name", nil},
+		{"missing.#", nil},
+		{"scores.#", nil},
+	}
+	for _, test := range tests {
+		if got := parser.GetPath(test.path); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("GetPath(%q) = %v, want %v", test.path, got, test.want)
+		}
+	}
+
+	if !reflect.DeepEqual(parser.GetPath(""), parser.Get()) {
+		t.Errorf("Expected the empty path to return the root")
+	}
+}
+
+func TestGetPathWhileStreaming(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"items": [{"title": "First"}, {"title": "Seco`)
+
+	if got := parser.GetPath("items.#.title"); !reflect.DeepEqual(got, []interface{}{"First", "Seco"}) {
+		t.Errorf("Expected the titles so far, got %v", got)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !tinygo && !streamjson_nopool
+
+package streamjson
+
+import "sync"
+
+// Object pools for memory reuse
+var (
+	nodePool = sync.Pool{
+		New: func() interface{} {
+			return &Node{}
+		},
+	}
+	stackFramePool = sync.Pool{
+		New: func() interface{} {
+			return &StackFrame{}
+		},
+	}
+)
+
+// getNode takes a node from the pool, with stale fields
+func getNode() *Node {
+	return nodePool.Get().(*Node)
+}
+
+// putNode returns a node to the pool
+func putNode(node *Node) {
+	nodePool.Put(node)
+}
+
+// getStackFrame takes a stack frame from the pool, with stale fields
+func getStackFrame() *StackFrame {
+	return stackFramePool.Get().(*StackFrame)
+}
+
+// putStackFrame returns a stack frame to the pool
+func putStackFrame(frame *StackFrame) {
+	stackFramePool.Put(frame)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build tinygo || streamjson_nopool
+
+package streamjson
+
+// Without pooling, nodes and stack frames are plain allocations left to the
+// garbage collector. TinyGo sets the tinygo tag, where sync.Pool keeps
+// nothing and its interface conversions only add cost; other builds opt in
+// with the streamjson_nopool tag.
+
+// getNode allocates a node
+func getNode() *Node {
+	return &Node{}
+}
+
+// putNode drops a node
+func putNode(*Node) {}
+
+// getStackFrame allocates a stack frame
+func getStackFrame() *StackFrame {
+	return &StackFrame{}
+}
+
+// putStackFrame drops a stack frame
+func putStackFrame(*StackFrame) {}
@@ -0,0 +1,149 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ProviderError is an error event sent by a model provider in the middle of
+// a stream, such as an overloaded or rate-limited response
+type ProviderError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("streamjson: provider error %s: %s", e.Type, e.Message)
+}
+
+// openAIChunk is the part of an OpenAI chat.completion.chunk the feeder reads
+type openAIChunk struct {
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *ProviderError `json:"error"`
+}
+
+// anthropicEvent is the part of an Anthropic Messages stream event the
+// feeder reads
+type anthropicEvent struct {
+	Type         string `json:"type"`
+	Index        int    `json:"index"`
+	ContentBlock struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"content_block"`
+	Delta struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+	} `json:"delta"`
+	Error *ProviderError `json:"error"`
+}
+
+// NewOpenAIFeeder creates a feeder for an OpenAI chat completions stream. The
+// content of the first choice is appended to content, and its tool_calls
+// deltas are fed to tools. Either may be nil to ignore that part of the
+// response.
+func NewOpenAIFeeder(r io.Reader, content Appender, tools *ToolCallAccumulator) *SSEFeeder {
+	f := NewSSEFeeder(content, r, "")
+	f.handle = func(data string) error {
+		var chunk openAIChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("streamjson: invalid SSE event data: %w", err)
+		}
+		if chunk.Error != nil {
+			return chunk.Error
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			if content != nil && choice.Delta.Content != "" {
+				content.Append(choice.Delta.Content)
+			}
+			if tools == nil {
+				continue
+			}
+			for _, call := range choice.Delta.ToolCalls {
+				tools.Start(call.Index, call.ID, call.Function.Name)
+				tools.Append(call.Index, call.Function.Arguments)
+			}
+		}
+		return nil
+	}
+	return f
+}
+
+// NewAnthropicFeeder creates a feeder for an Anthropic Messages stream. The
+// text_delta of text blocks is appended to content, and tool_use blocks are
+// fed to tools by content block index, with the partial_json of their
+// input_json_delta events. Either may be nil to ignore that part of the
+// response. The stream ends at message_stop.
+func NewAnthropicFeeder(r io.Reader, content Appender, tools *ToolCallAccumulator) *SSEFeeder {
+	f := NewSSEFeeder(content, r, "")
+	f.handle = func(data string) error {
+		var event anthropicEvent
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return fmt.Errorf("streamjson: invalid SSE event data: %w", err)
+		}
+
+		switch event.Type {
+		case "error":
+			if event.Error != nil {
+				return event.Error
+			}
+			return &ProviderError{Type: "error"}
+
+		case "message_stop":
+			f.done = true
+
+		case "content_block_start":
+			if tools != nil && event.ContentBlock.Type == "tool_use" {
+				tools.Start(event.Index, event.ContentBlock.ID, event.ContentBlock.Name)
+			}
+
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				if content != nil && event.Delta.Text != "" {
+					content.Append(event.Delta.Text)
+				}
+			case "input_json_delta":
+				if tools != nil {
+					tools.Append(event.Index, event.Delta.PartialJSON)
+				}
+			}
+		}
+		return nil
+	}
+	return f
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestOpenAIFeeder(t *testing.T) {
+	fixture, err := os.Open("testdata/openai_chat_stream.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fixture.Close()
+
+	parser := NewStreamJSONParser()
+	tools := NewToolCallAccumulator()
+	feeder := NewOpenAIFeeder(iotest.HalfReader(fixture), parser, tools)
+	if err := feeder.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !feeder.Done() {
+		t.Error("Expected [DONE] to be seen")
+	}
+
+	if parser.Get("summary") != "Sunny in Paris" || parser.Get("temp") != int64(21) || !parser.IsCompleted() {
+		t.Errorf("Unexpected content %v", parser.Get())
+	}
+	calls := tools.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 tool calls, got %d", len(calls))
+	}
+	if calls[0].ID != "call_Q1w" || calls[0].Name != "get_weather" || calls[0].Parser().Get("city") != "Paris" {
+		t.Errorf("Unexpected first call %+v %v", calls[0], calls[0].Arguments())
+	}
+	if calls[1].Name != "get_time" || calls[1].Parser().Get("zone") != "CET" || !calls[1].IsCompleted() {
+		t.Errorf("Unexpected second call %+v %v", calls[1], calls[1].Arguments())
+	}
+}
+
+func TestAnthropicFeeder(t *testing.T) {
+	fixture, err := os.Open("testdata/anthropic_messages_stream.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fixture.Close()
+
+	parser := NewStreamJSONParser()
+	tools := NewToolCallAccumulator()
+	feeder := NewAnthropicFeeder(iotest.OneByteReader(fixture), parser, tools)
+	if err := feeder.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !feeder.Done() {
+		t.Error("Expected message_stop to end the stream")
+	}
+
+	if parser.Get("summary") != "Sunny in Paris" || !parser.IsCompleted() {
+		t.Errorf("Unexpected content %v", parser.Get())
+	}
+	call := tools.Call(1)
+	if call == nil || call.ID != "toolu_01T1x1fJ34qAmk2tNTrN7Up6" || call.Name != "get_weather" {
+		t.Fatalf("Unexpected tool call %+v", call)
+	}
+	if call.Parser().Get("unit") != "celsius" || !call.IsCompleted() {
+		t.Errorf("Unexpected arguments %v", call.Arguments())
+	}
+	if len(tools.Calls()) != 1 {
+		t.Errorf("Expected text blocks not to become calls, got %d calls", len(tools.Calls()))
+	}
+}
+
+func TestProviderFeedersNilTargets(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/anthropic_messages_stream.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools := NewToolCallAccumulator()
+	if err := NewAnthropicFeeder(strings.NewReader(string(fixture)), nil, tools).Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tools.Call(1).Parser().Get("city") != "Paris" {
+		t.Errorf("Expected tool arguments without a content target, got %v", tools.Call(1).Arguments())
+	}
+
+	fixture, err = os.ReadFile("testdata/openai_chat_stream.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := NewStreamJSONParser()
+	if err := NewOpenAIFeeder(strings.NewReader(string(fixture)), parser, nil).Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parser.Get("temp") != int64(21) {
+		t.Errorf("Expected content without a tool target, got %v", parser.Get())
+	}
+}
+
+func TestProviderFeederErrors(t *testing.T) {
+	stream := "event: content_block_delta\n" +
+		"data: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":\"{\\\"a\\\":\"}}\n\n" +
+		"event: error\n" +
+		"data: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n"
+	parser := NewStreamJSONParser()
+	err := NewAnthropicFeeder(strings.NewReader(stream), parser, nil).Run()
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Type != "overloaded_error" || providerErr.Message != "Overloaded" {
+		t.Errorf("Expected overloaded provider error, got %v", err)
+	}
+
+	stream = "data: {\"error\":{\"type\":\"server_error\",\"message\":\"boom\"}}\n\n"
+	err = NewOpenAIFeeder(strings.NewReader(stream), parser, nil).Run()
+	if !errors.As(err, &providerErr) || providerErr.Type != "server_error" {
+		t.Errorf("Expected server provider error, got %v", err)
+	}
+
+	if err := NewOpenAIFeeder(strings.NewReader("data: {oops\n\n"), parser, nil).Run(); err == nil {
+		t.Error("Expected error for invalid event data")
+	}
+}
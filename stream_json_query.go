@@ -0,0 +1,669 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryError reports a JSONPath expression that could not be parsed
+type QueryError struct {
+	Expr    string // The expression passed to Query
+	Offset  int    // Offset of the problem in Expr
+	Message string // Description of the problem
+}
+
+// Error implements the error interface
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("streamjson: invalid query %q at offset %d: %s", e.Expr, e.Offset, e.Message)
+}
+
+// selectorKind identifies what a query step selects
+type selectorKind int
+
+const (
+	selectName     selectorKind = iota // .name or ['name']
+	selectIndex                        // [0] or [-1]
+	selectWildcard                     // .* or [*]
+	selectFilter                       // [?(@.field op literal)]
+	selectSlice                        // [start:end:step]
+)
+
+// queryStep is a single selector, optionally applied to all descendants
+type queryStep struct {
+	recursive bool // Preceded by '..'
+	kind      selectorKind
+	name      string
+	index     int
+	slice     querySlice
+	filter    *queryFilter
+}
+
+// querySlice selects array elements from start up to end by step, with nil
+// for bounds left out
+type querySlice struct {
+	start, end *int
+	step       int
+}
+
+// queryFilter is a test of a field relative to the candidate node, or a
+// boolean combination of other filters
+type queryFilter struct {
+	op       string         // Comparison operator, "" for an existence test, or &&, || or !
+	operands []*queryFilter // Filters combined by &&, || or !
+	path     []string       // Keys below @
+	value    interface{}    // float64, string, bool or nil
+}
+
+// Query evaluates a JSONPath expression against the current, possibly
+// incomplete, AST and returns the matched values in document order, with
+// object members visited in sorted key order.
+//
+// The supported subset is the root $, child access with .name or ['name'],
+// array indexes including negative ones, slices such as [1:3] or [::-1],
+// the * wildcard, recursive descent with .., and filters such as
+// [?(@.field)] or [?(@.field op literal)] where op is one of
+// == != < <= > >= and literal is a number, a quoted string, true, false or
+// null. Filters combine with &&, || and !, and group with parentheses.
+func (p *StreamJSONParser) Query(expr string) ([]interface{}, error) {
+	defer p.guardRead("Query")()
+	steps, err := compileQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	if p.root == nil {
+		return nil, nil
+	}
+	for i := range steps {
+		if steps[i].kind == selectName {
+			steps[i].name = p.normalizeKey(steps[i].name)
+		}
+		if steps[i].filter != nil {
+			steps[i].filter.normalize(p)
+		}
+	}
+
+	nodes := []*Node{p.root}
+	for _, step := range steps {
+		var next []*Node
+		for _, node := range nodes {
+			if step.recursive {
+				for _, descendant := range descendants(node) {
+					next = step.apply(descendant, next)
+				}
+			} else {
+				next = step.apply(node, next)
+			}
+		}
+		nodes = next
+	}
+
+	results := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		results[i] = p.collectNodeValue(node)
+	}
+	return results, nil
+}
+
+// apply appends the children of node selected by the step to matches
+func (s queryStep) apply(node *Node, matches []*Node) []*Node {
+	switch s.kind {
+	case selectName:
+		if node.Type == ObjectNode {
+			if child, ok := node.Children[s.name]; ok {
+				matches = append(matches, child)
+			}
+		}
+
+	case selectIndex:
+		if node.Type == ArrayNode {
+			index := s.index
+			if index < 0 {
+				index += len(node.Array)
+			}
+			if index >= 0 && index < len(node.Array) {
+				matches = append(matches, node.Array[index])
+			}
+		}
+
+	case selectSlice:
+		if node.Type == ArrayNode {
+			for _, index := range s.slice.indices(len(node.Array)) {
+				matches = append(matches, node.Array[index])
+			}
+		}
+
+	case selectWildcard, selectFilter:
+		for _, child := range children(node) {
+			if s.kind == selectWildcard || s.filter.match(child) {
+				matches = append(matches, child)
+			}
+		}
+	}
+	return matches
+}
+
+// indices returns the indexes the slice selects in an array of length n, in
+// the order of step, with negative bounds counting from the end as in
+// RFC 9535
+func (s querySlice) indices(n int) []int {
+	if s.step == 0 {
+		return nil
+	}
+	bound := func(b *int, omitted int) int {
+		switch {
+		case b == nil:
+			return omitted
+		case *b < 0:
+			return n + *b
+		}
+		return *b
+	}
+
+	var indices []int
+	if s.step > 0 {
+		lower := min(max(bound(s.start, 0), 0), n)
+		upper := min(max(bound(s.end, n), 0), n)
+		for i := lower; i < upper; i += s.step {
+			indices = append(indices, i)
+		}
+	} else {
+		upper := min(max(bound(s.start, n-1), -1), n-1)
+		lower := min(max(bound(s.end, -n-1), -1), n-1)
+		for i := upper; i > lower; i += s.step {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// children returns the direct children of node in document order
+func children(node *Node) []*Node {
+	switch node.Type {
+	case ObjectNode:
+		keys := make([]string, 0, len(node.Children))
+		for key := range node.Children {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]*Node, len(keys))
+		for i, key := range keys {
+			result[i] = node.Children[key]
+		}
+		return result
+	case ArrayNode:
+		return node.Array
+	}
+	return nil
+}
+
+// descendants returns node and all nodes below it in pre-order
+func descendants(node *Node) []*Node {
+	var result []*Node
+	stack := []*Node{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		result = append(result, current)
+
+		// Push in reverse so the first child is visited next
+		kids := children(current)
+		for i := len(kids) - 1; i >= 0; i-- {
+			stack = append(stack, kids[i])
+		}
+	}
+	return result
+}
+
+// normalize rewrites the keys of the filter's paths as the parser's keys are
+func (f *queryFilter) normalize(p *StreamJSONParser) {
+	f.path = p.normalizePath(f.path)
+	for _, operand := range f.operands {
+		operand.normalize(p)
+	}
+}
+
+// match reports whether the filter holds for node
+func (f *queryFilter) match(node *Node) bool {
+	switch f.op {
+	case "&&":
+		for _, operand := range f.operands {
+			if !operand.match(node) {
+				return false
+			}
+		}
+		return true
+	case "||":
+		for _, operand := range f.operands {
+			if operand.match(node) {
+				return true
+			}
+		}
+		return false
+	case "!":
+		return !f.operands[0].match(node)
+	}
+
+	for _, key := range f.path {
+		if node.Type != ObjectNode {
+			return false
+		}
+		child, ok := node.Children[key]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+
+	if f.op == "" {
+		return true
+	}
+	if node.Type != ValueNode {
+		return false
+	}
+
+	switch want := f.value.(type) {
+	case float64:
+		have, ok := numberToFloat64(node.Value)
+		if !ok {
+			return false
+		}
+		switch {
+		case have < want:
+			return satisfies(-1, f.op)
+		case have > want:
+			return satisfies(1, f.op)
+		}
+		return satisfies(0, f.op)
+	case string:
+		have, ok := node.Value.(string)
+		return ok && satisfies(strings.Compare(have, want), f.op)
+	default:
+		// Booleans and null only support equality
+		equal := node.Value == f.value
+		switch f.op {
+		case "==":
+			return equal
+		case "!=":
+			return !equal
+		}
+		return false
+	}
+}
+
+// satisfies reports whether a comparison result of -1, 0 or 1 satisfies op
+func satisfies(comparison int, op string) bool {
+	switch op {
+	case "==":
+		return comparison == 0
+	case "!=":
+		return comparison != 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	}
+	return false
+}
+
+// queryParser compiles a JSONPath expression into steps
+type queryParser struct {
+	expr string
+	pos  int
+}
+
+// compileQuery parses expr into a list of steps applied from the root
+func compileQuery(expr string) ([]queryStep, error) {
+	q := &queryParser{expr: expr}
+	if !q.consume("$") {
+		return nil, q.errorf("expected '$'")
+	}
+
+	var steps []queryStep
+	for q.pos < len(q.expr) {
+		step := queryStep{}
+		switch {
+		case q.consume(".."):
+			step.recursive = true
+			if q.peek() != '[' {
+				if err := q.dotSelector(&step); err != nil {
+					return nil, err
+				}
+				break
+			}
+			fallthrough
+		case q.peek() == '[':
+			if err := q.bracketSelector(&step); err != nil {
+				return nil, err
+			}
+		case q.consume("."):
+			if err := q.dotSelector(&step); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, q.errorf("expected '.' or '['")
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// dotSelector parses the name or * after a dot
+func (q *queryParser) dotSelector(step *queryStep) error {
+	if q.consume("*") {
+		step.kind = selectWildcard
+		return nil
+	}
+	name := q.name()
+	if name == "" {
+		return q.errorf("expected a name")
+	}
+	step.kind = selectName
+	step.name = name
+	return nil
+}
+
+// bracketSelector parses a [...] selector
+func (q *queryParser) bracketSelector(step *queryStep) error {
+	q.pos++ // Skip '['
+	q.skipSpaces()
+
+	switch {
+	case q.consume("*"):
+		step.kind = selectWildcard
+
+	case q.consume("?"):
+		filter, err := q.filter()
+		if err != nil {
+			return err
+		}
+		step.kind = selectFilter
+		step.filter = filter
+
+	case q.peek() == '\'' || q.peek() == '"':
+		name, err := q.quoted()
+		if err != nil {
+			return err
+		}
+		step.kind = selectName
+		step.name = name
+
+	default:
+		index, ok := q.integer()
+		q.skipSpaces()
+		if q.consume(":") {
+			step.kind = selectSlice
+			step.slice = q.slice(index, ok)
+			break
+		}
+		if !ok {
+			return q.errorf("expected an index, a slice, '*', a quoted name or a filter")
+		}
+		step.kind = selectIndex
+		step.index = index
+	}
+
+	q.skipSpaces()
+	if !q.consume("]") {
+		return q.errorf("expected ']'")
+	}
+	return nil
+}
+
+// slice parses the rest of a [start:end:step] selector after the first ':'
+func (q *queryParser) slice(start int, hasStart bool) querySlice {
+	s := querySlice{step: 1}
+	if hasStart {
+		s.start = &start
+	}
+	q.skipSpaces()
+	if end, ok := q.integer(); ok {
+		s.end = &end
+	}
+	q.skipSpaces()
+	if q.consume(":") {
+		q.skipSpaces()
+		if step, ok := q.integer(); ok {
+			s.step = step
+		}
+	}
+	return s
+}
+
+// integer parses an optionally negative integer, reporting false if there
+// is none
+func (q *queryParser) integer() (int, bool) {
+	start := q.pos
+	if q.peek() == '-' {
+		q.pos++
+	}
+	for q.pos < len(q.expr) && q.expr[q.pos] >= '0' && q.expr[q.pos] <= '9' {
+		q.pos++
+	}
+	n, err := strconv.Atoi(q.expr[start:q.pos])
+	if err != nil {
+		q.pos = start
+		return 0, false
+	}
+	return n, true
+}
+
+// filter parses the expression after [? up to the ']', filters joined by
+// ||, which binds less tightly than &&
+func (q *queryParser) filter() (*queryFilter, error) {
+	return q.logical("||", q.conjunction)
+}
+
+// conjunction parses filters joined by &&
+func (q *queryParser) conjunction() (*queryFilter, error) {
+	return q.logical("&&", q.unary)
+}
+
+// logical parses one or more operands joined by op
+func (q *queryParser) logical(op string, operand func() (*queryFilter, error)) (*queryFilter, error) {
+	f, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	combined := false
+	for {
+		q.skipSpaces()
+		if !q.consume(op) {
+			return f, nil
+		}
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if !combined {
+			f = &queryFilter{op: op, operands: []*queryFilter{f}}
+			combined = true
+		}
+		f.operands = append(f.operands, next)
+	}
+}
+
+// unary parses a negated or parenthesized filter, or a test
+func (q *queryParser) unary() (*queryFilter, error) {
+	q.skipSpaces()
+	switch {
+	case q.consume("!"):
+		operand, err := q.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &queryFilter{op: "!", operands: []*queryFilter{operand}}, nil
+	case q.consume("("):
+		f, err := q.filter()
+		if err != nil {
+			return nil, err
+		}
+		q.skipSpaces()
+		if !q.consume(")") {
+			return nil, q.errorf("expected ')'")
+		}
+		return f, nil
+	}
+	return q.test()
+}
+
+// test parses @ and a relative path, optionally compared to a literal
+func (q *queryParser) test() (*queryFilter, error) {
+	if !q.consume("@") {
+		return nil, q.errorf("expected '@'")
+	}
+
+	f := &queryFilter{}
+	for {
+		if q.consume(".") {
+			name := q.name()
+			if name == "" {
+				return nil, q.errorf("expected a name")
+			}
+			f.path = append(f.path, name)
+		} else if q.consume("[") {
+			name, err := q.quoted()
+			if err != nil {
+				return nil, err
+			}
+			if !q.consume("]") {
+				return nil, q.errorf("expected ']'")
+			}
+			f.path = append(f.path, name)
+		} else {
+			break
+		}
+	}
+
+	q.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if q.consume(op) {
+			f.op = op
+			break
+		}
+	}
+
+	if f.op != "" {
+		q.skipSpaces()
+		value, err := q.literal()
+		if err != nil {
+			return nil, err
+		}
+		_, isBool := value.(bool)
+		if (isBool || value == nil) && f.op != "==" && f.op != "!=" {
+			return nil, q.errorf("operator %s needs a number or a string", f.op)
+		}
+		f.value = value
+	}
+	return f, nil
+}
+
+// literal parses a number, quoted string, true, false or null
+func (q *queryParser) literal() (interface{}, error) {
+	if q.peek() == '\'' || q.peek() == '"' {
+		return q.quoted()
+	}
+	switch {
+	case q.consume("true"):
+		return true, nil
+	case q.consume("false"):
+		return false, nil
+	case q.consume("null"):
+		return nil, nil
+	}
+
+	start := q.pos
+	for q.pos < len(q.expr) && isNumberChar(q.expr[q.pos]) {
+		q.pos++
+	}
+	value, err := strconv.ParseFloat(q.expr[start:q.pos], 64)
+	if err != nil {
+		q.pos = start
+		return nil, q.errorf("expected a literal")
+	}
+	return value, nil
+}
+
+// quoted parses a single- or double-quoted name. A backslash escapes the
+// next character.
+func (q *queryParser) quoted() (string, error) {
+	quote := q.expr[q.pos]
+	q.pos++
+
+	var b strings.Builder
+	for q.pos < len(q.expr) {
+		c := q.expr[q.pos]
+		q.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && q.pos < len(q.expr):
+			b.WriteByte(q.expr[q.pos])
+			q.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", q.errorf("unterminated string")
+}
+
+// name parses an unquoted member name
+func (q *queryParser) name() string {
+	start := q.pos
+	for q.pos < len(q.expr) {
+		c := q.expr[q.pos]
+		if c == '.' || c == '[' || c == ']' || c == '(' || c == ')' || c == ' ' || c == '=' || c == '!' || c == '<' || c == '>' || c == '&' || c == '|' {
+			break
+		}
+		q.pos++
+	}
+	return q.expr[start:q.pos]
+}
+
+// consume advances past s if the expression continues with it
+func (q *queryParser) consume(s string) bool {
+	if strings.HasPrefix(q.expr[q.pos:], s) {
+		q.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// peek returns the next byte without consuming it, or 0 at the end
+func (q *queryParser) peek() byte {
+	if q.pos < len(q.expr) {
+		return q.expr[q.pos]
+	}
+	return 0
+}
+
+// skipSpaces advances past spaces
+func (q *queryParser) skipSpaces() {
+	for q.pos < len(q.expr) && q.expr[q.pos] == ' ' {
+		q.pos++
+	}
+}
+
+// errorf returns a QueryError at the current position
+func (q *queryParser) errorf(format string, args ...interface{}) error {
+	return &QueryError{Expr: q.expr, Offset: q.pos, Message: fmt.Sprintf(format, args...)}
+}
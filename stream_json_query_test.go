@@ -0,0 +1,107 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"testing"
+)
+
+const queryDocument = `{
+	"users": [
+		{"name": "Alice", "age": 31, "admin": true, "address": {"city": "Paris"}},
+		{"name": "Bob", "age": 25, "admin": false},
+		{"name": "Carol", "age": 42, "admin": true, "address": {"city": "Oslo"}}
+	],
+	"owner": {"name": "Dave"}
+}`
+
+func TestQuery(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(queryDocument)
+
+	tests := []struct {
+		expr     string
+		expected []interface{}
+	}{
+		{"$.owner.name", []interface{}{"Dave"}},
+		{"$['owner']['name']", []interface{}{"Dave"}},
+		{"$.users[*].name", []interface{}{"Alice", "Bob", "Carol"}},
+		{"$.users[1].name", []interface{}{"Bob"}},
+		{"$.users[-1].name", []interface{}{"Carol"}},
+		{"$.users[5].name", nil},
+		{"$..city", []interface{}{"Paris", "Oslo"}},
+		{"$..name", []interface{}{"Dave", "Alice", "Bob", "Carol"}},
+		{"$.users[?(@.age > 30)].name", []interface{}{"Alice", "Carol"}},
+		{"$.users[?(@.age <= 25)].name", []interface{}{"Bob"}},
+		{"$.users[?(@.name == 'Bob')].age", []interface{}{int64(25)}},
+		{"$.users[?(@.admin == true)].name", []interface{}{"Alice", "Carol"}},
+		{"$.users[?(@.address)].name", []interface{}{"Alice", "Carol"}},
+		{"$.users[?(@.address.city != \"Paris\")].name", []interface{}{"Carol"}},
+		{"$..[?(@.name == 'Dave')]", []interface{}{map[string]interface{}{"name": "Dave"}}},
+		{"$.users[0:2].name", []interface{}{"Alice", "Bob"}},
+		{"$.users[1:].name", []interface{}{"Bob", "Carol"}},
+		{"$.users[:-1].name", []interface{}{"Alice", "Bob"}},
+		{"$.users[::2].name", []interface{}{"Alice", "Carol"}},
+		{"$.users[::-1].name", []interface{}{"Carol", "Bob", "Alice"}},
+		{"$.users[-1:0:-1].name", []interface{}{"Carol", "Bob"}},
+		{"$.users[ 5:10 ].name", nil},
+		{"$.users[0:3:0].name", nil},
+		{"$.users[?(@.age > 30 && @.address.city == 'Oslo')].name", []interface{}{"Carol"}},
+		{"$.users[?(@.age < 30 || @.name == 'Carol')].name", []interface{}{"Bob", "Carol"}},
+		{"$.users[?(!@.address)].name", []interface{}{"Bob"}},
+		{"$.users[?(!(@.age > 30))].name", []interface{}{"Bob"}},
+		{"$.users[?(@.admin == true && (@.age < 35 || @.name == 'Bob'))].name", []interface{}{"Alice"}},
+		{"$.users[?@.age >= 31 && @.age <= 42].name", []interface{}{"Alice", "Carol"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			results, err := parser.Query(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(results) != len(tt.expected) || (len(results) > 0 && !reflect.DeepEqual(results, tt.expected)) {
+				t.Errorf("Expected %v, got %v", tt.expected, results)
+			}
+		})
+	}
+}
+
+func TestQueryWhileStreaming(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"users":[{"name":"Alice"},{"name":"Bo`)
+
+	results, err := parser.Query("$.users[*].name")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results, []interface{}{"Alice", "Bo"}) {
+		t.Errorf("Expected partial results, got %v", results)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(queryDocument)
+
+	for _, expr := range []string{"", "users", "$.", "$[", "$[abc]", "$['a", "$[?(@.a >)]", "$[?(@.a > true)]", "$[?(@.a",
+		"$[?(@.a &&)]", "$[?(@.a || !)]", "$[?((@.a)]", "$[1:2:x]", "$[1:2"} {
+		_, err := parser.Query(expr)
+		if _, ok := err.(*QueryError); !ok {
+			t.Errorf("Expected QueryError for %q, got %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// rawSubscription is a callback registered for the raw bytes of a subtree
+type rawSubscription struct {
+	pattern  []string
+	callback func(raw []byte)
+}
+
+// OnRawSubtree registers a callback that receives the exact source bytes of
+// the value at path once it completes, without re-serialization.
+// The path is dotted ("choices.0.message"), "*" matches any single key or
+// index, and "" selects the root. The raw slice aliases the parser's buffer
+// and must be copied if retained after the callback returns.
+func (p *StreamJSONParser) OnRawSubtree(path string, callback func(raw []byte)) {
+	p.rawSubscriptions = append(p.rawSubscriptions, rawSubscription{
+		pattern:  p.normalizePath(splitPath(path)),
+		callback: callback,
+	})
+}
+
+// deliverRawSubtree invokes the raw subscriptions matching a completed node
+func (p *StreamJSONParser) deliverRawSubtree(path []string, node *Node) {
+	if len(p.rawSubscriptions) == 0 {
+		return
+	}
+
+	buffer := p.tokenizer.buffer
+	start, end := node.start-p.tokenizer.base, node.end-p.tokenizer.base
+	if start < 0 || end > len(buffer) || start > end {
+		return
+	}
+
+	for _, sub := range p.rawSubscriptions {
+		if matchPath(sub.pattern, path) {
+			sub.callback(buffer[start:end])
+		}
+	}
+}
+
+// GetRaw returns a copy of the exact source bytes of the completed value at
+// the path, such as a subdocument whose signature must be verified. It
+// returns nil for missing paths, values still streaming and input already
+// released by compaction, and for values that may contain values redacted
+// by WithRedaction. Compaction releases consumed input once a few kilobytes
+// accumulate, so use WithRetainedInput to read any value of larger
+// documents, or OnRawSubtree to capture subtrees of long streams.
+func (p *StreamJSONParser) GetRaw(keys ...string) []byte {
+	defer p.guardRead("GetRaw")()
+	if p.root == nil || len(p.options.redactions) > 0 && p.mayContainRedaction(p.normalizePath(keys)) {
+		return nil
+	}
+	node := p.findNode(keys)
+	if node == nil || !node.Completed {
+		return nil
+	}
+
+	buffer := p.tokenizer.buffer
+	start, end := node.start-p.tokenizer.base, node.end-p.tokenizer.base
+	if start < 0 || end > len(buffer) || start > end {
+		return nil
+	}
+	return append([]byte(nil), buffer[start:end]...)
+}
+
+// rawRetained returns the input offset of the outermost open container whose
+// bytes a raw subscription still needs, or -1 if there is none
+func (p *StreamJSONParser) rawRetained() int {
+	if len(p.rawSubscriptions) == 0 {
+		return -1
+	}
+	for _, frame := range p.stack {
+		for _, sub := range p.rawSubscriptions {
+			if matchPath(sub.pattern, p.framePath(frame)) {
+				return frame.Node.start
+			}
+		}
+	}
+	return -1
+}
+
+// GetRawNumber returns the literal of the completed number at the path as it
+// appeared in the input, such as "1.200" or "1e3", for payloads whose exact
+// formatting must survive re-encoding. The literal is kept with the value,
+// so compaction does not release it, and transformers do not change it. It
+// returns false for other values, values still streaming and values
+// redacted by WithRedaction.
+func (p *StreamJSONParser) GetRawNumber(keys ...string) (string, bool) {
+	defer p.guardRead("GetRawNumber")()
+	if p.root == nil || len(p.options.redactions) > 0 && p.mayContainRedaction(p.normalizePath(keys)) {
+		return "", false
+	}
+	node := p.findNode(keys)
+	if node == nil || !node.Completed || node.literal == "" {
+		return "", false
+	}
+	return node.literal, true
+}
+
+// numberLiteral returns the content of a number token, or "" for other tokens
+func (p *StreamJSONParser) numberLiteral(token Token) string {
+	if token.TokenType != Number || !p.isNumberLiteral(token.Content) {
+		return ""
+	}
+	return token.Content
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOnRawSubtreeObject(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	var raw []string
+	parser.OnRawSubtree("payload", func(b []byte) {
+		raw = append(raw, string(b))
+	})
+
+	parser.Append(`{"id":1,"payload": {"b" : [1, 2.50],`)
+	if len(raw) != 0 {
+		t.Errorf("Expected no delivery before payload completes, got %v", raw)
+	}
+
+	parser.Append(` "c":"x\"y"} ,"tail":true}`)
+	if len(raw) != 1 || raw[0] != `{"b" : [1, 2.50], "c":"x\"y"}` {
+		t.Errorf("Expected verbatim payload bytes, got %v", raw)
+	}
+}
+
+func TestOnRawSubtreeWildcardAndScalars(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	var names []string
+	parser.OnRawSubtree("users.*.name", func(b []byte) {
+		names = append(names, string(b))
+	})
+
+	var root string
+	parser.OnRawSubtree("", func(b []byte) {
+		root = string(b)
+	})
+
+	input := `[ {"users":[{"name":"Alice"},{"name":"Bob"}]} ]`
+	for i := 0; i < len(input); i++ {
+		parser.Append(input[i : i+1])
+	}
+
+	if len(names) != 0 {
+		t.Errorf("Expected no match for non-root users path, got %v", names)
+	}
+
+	if root != input {
+		t.Errorf("Expected root raw %q, got %q", input, root)
+	}
+
+	parser = NewStreamJSONParser()
+	parser.OnRawSubtree("users.*.name", func(b []byte) {
+		names = append(names, string(b))
+	})
+	parser.Append(`{"users":[{"name":"Alice"},{"name":"Bob"}]}`)
+
+	if len(names) != 2 || names[0] != `"Alice"` || names[1] != `"Bob"` {
+		t.Errorf("Expected raw names, got %v", names)
+	}
+}
+
+func TestStreamJSONParserGetRaw(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"payload": {"amount" : 1.50, "to":"A"}, "items":[1, 2e3], "note":"par`)
+
+	if raw := parser.GetRaw("payload"); string(raw) != `{"amount" : 1.50, "to":"A"}` {
+		t.Errorf("Expected verbatim payload, got %s", raw)
+	}
+	if raw := parser.GetRaw("items", "1"); string(raw) != `2e3` {
+		t.Errorf("Expected verbatim number, got %s", raw)
+	}
+	if raw := parser.GetRaw("note"); raw != nil {
+		t.Errorf("Expected nil for a streaming value, got %s", raw)
+	}
+	if raw := parser.GetRaw("missing"); raw != nil {
+		t.Errorf("Expected nil for a missing path, got %s", raw)
+	}
+	if raw := parser.GetRaw(); raw != nil {
+		t.Errorf("Expected nil for an open root, got %s", raw)
+	}
+
+	parser.Append(`t"}`)
+	if raw := parser.GetRaw(); len(raw) == 0 || raw[len(raw)-1] != '}' {
+		t.Errorf("Expected the whole document, got %s", raw)
+	}
+}
+
+func TestStreamJSONParserGetRawNumber(t *testing.T) {
+	parser := NewStreamJSONParser(WithMaxBufferSize(64), WithPartialNumbers())
+	parser.Append(`{"amount": 1.200, "rate": 1e3, "name": "x", "big": 12345678901234567890, `)
+	parser.Append(`"padding": "` + strings.Repeat("p", 100) + `", "count": 4`)
+
+	if parser.GetRaw("amount") != nil {
+		t.Fatal("Expected the input to be released by compaction")
+	}
+	if parser.Get("amount") != 1.2 {
+		t.Errorf("Expected parsed value 1.2, got %v", parser.Get("amount"))
+	}
+	for path, want := range map[string]string{"amount": "1.200", "rate": "1e3", "big": "12345678901234567890"} {
+		if literal, ok := parser.GetRawNumber(path); !ok || literal != want {
+			t.Errorf("Expected %s literal %q after compaction, got %q", path, want, literal)
+		}
+	}
+	if _, ok := parser.GetRawNumber("name"); ok {
+		t.Error("Expected no literal for a string")
+	}
+	if _, ok := parser.GetRawNumber("count"); ok {
+		t.Error("Expected no literal for a streaming number")
+	}
+	parser.Append(`0}`)
+	if literal, ok := parser.GetRawNumber("count"); !ok || literal != "40" {
+		t.Errorf("Expected completed literal 40, got %q", literal)
+	}
+}
+
+func TestStreamJSONParserGetRawNumberScalarAndRedacted(t *testing.T) {
+	scalar := NewStreamJSONParser(WithScalarRoots())
+	scalar.ParseBytes([]byte(`-0.50`))
+	if literal, ok := scalar.GetRawNumber(); !ok || literal != "-0.50" {
+		t.Errorf("Expected root literal -0.50, got %q", literal)
+	}
+
+	redacted := NewStreamJSONParser(WithRedaction("", "card.*"))
+	redacted.Append(`{"card": {"number": 4111111111111111}}`)
+	if _, ok := redacted.GetRawNumber("card", "number"); ok {
+		t.Error("Expected no literal for a redacted number")
+	}
+}
+
+func TestGetRawRetainedInput(t *testing.T) {
+	payload := `{"text":"` + strings.Repeat("x", 5000) + `"}`
+	input := `{"payload":` + payload + `,"tail":[1,2]}`
+
+	parser := NewStreamJSONParser()
+	parser.Append(input)
+	if raw := parser.GetRaw("payload"); raw != nil {
+		t.Errorf("Expected compaction to release the payload by default, got %d bytes", len(raw))
+	}
+
+	for _, chunkSize := range []int{len(input), 100} {
+		parser := NewStreamJSONParser(WithRetainedInput())
+		for i := 0; i < len(input); i += chunkSize {
+			parser.Append(input[i:min(i+chunkSize, len(input))])
+		}
+		if raw := parser.GetRaw("payload"); string(raw) != payload {
+			t.Errorf("Expected the payload with chunks of %d, got %d bytes", chunkSize, len(raw))
+		}
+		if raw := parser.GetRaw(); string(raw) != input {
+			t.Errorf("Expected the whole document with chunks of %d, got %d bytes", chunkSize, len(raw))
+		}
+	}
+}
+
+func TestRetainedInputReleasesEarlierDocuments(t *testing.T) {
+	parser := NewStreamJSONParser(WithRetainedInput(), WithMultipleDocuments())
+	for i := 0; i < 10; i++ {
+		parser.Append(`{"text":"` + strings.Repeat("x", 5000) + `"}`)
+	}
+	parser.Append(`{"last":`)
+
+	if size := len(parser.tokenizer.buffer); size > 2*5000 {
+		t.Errorf("Expected earlier documents to be released, buffer holds %d bytes", size)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+)
+
+// readChunkSize is the size of the chunks read from an io.Reader
+const readChunkSize = 4096
+
+// ParseReader consumes r until io.EOF, appending each chunk as it is read, so
+// values become available through Get while the stream is still arriving.
+// It returns nil at io.EOF and any other read error otherwise.
+func (p *StreamJSONParser) ParseReader(r io.Reader) error {
+	return readChunks(r, p.AppendBytes)
+}
+
+// FeedFrom performs a single read from r and appends whatever was read,
+// letting callers interleave reads with Get. It returns the number of bytes
+// appended and the read error, io.EOF included.
+func (p *StreamJSONParser) FeedFrom(r io.Reader) (int, error) {
+	buf := make([]byte, readChunkSize)
+	n, err := r.Read(buf)
+	if n > 0 {
+		p.AppendBytes(buf[:n])
+	}
+	return n, err
+}
+
+// readChunks reads r until io.EOF, passing each chunk to appendChunk, which
+// must not retain it. It returns nil at io.EOF and any other read error
+// otherwise.
+func readChunks(r io.Reader, appendChunk func(data []byte)) error {
+	buf := make([]byte, readChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			appendChunk(buf[:n])
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestParseReader(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	// OneByteReader forces every token to be split across reads
+	reader := iotest.OneByteReader(strings.NewReader(`{"message":"Hello World","count":3}`))
+	if err := parser.ParseReader(reader); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !parser.IsCompleted() {
+		t.Errorf("Expected parser to be completed")
+	}
+
+	if parser.Get("message") != "Hello World" {
+		t.Errorf("Expected message to be 'Hello World', got %v", parser.Get("message"))
+	}
+
+	if parser.Get("count") != int64(3) {
+		t.Errorf("Expected count to be 3, got %v", parser.Get("count"))
+	}
+}
+
+func TestParseReaderError(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	readErr := errors.New("connection reset")
+	reader := io.MultiReader(strings.NewReader(`{"partial":"te`), iotest.ErrReader(readErr))
+
+	if err := parser.ParseReader(reader); !errors.Is(err, readErr) {
+		t.Errorf("Expected read error, got %v", err)
+	}
+
+	// Content read before the error is still available
+	if parser.Get("partial") != "te" {
+		t.Errorf("Expected partial to be 'te', got %v", parser.Get("partial"))
+	}
+}
+
+func TestFeedFrom(t *testing.T) {
+	parser := NewStreamJSONParser()
+	reader := iotest.OneByteReader(strings.NewReader(`{"a":"xyz"}`))
+
+	n, err := parser.FeedFrom(reader)
+	if err != nil || n == 0 {
+		t.Fatalf("Expected a partial read, got %d %v", n, err)
+	}
+
+	if parser.IsCompleted() {
+		t.Errorf("Expected parser not to be completed after one read")
+	}
+
+	for err == nil {
+		_, err = parser.FeedFrom(reader)
+	}
+
+	if err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	if parser.Get("a") != "xyz" {
+		t.Errorf("Expected a to be 'xyz', got %v", parser.Get("a"))
+	}
+}
+
+func TestStreamJSONParserAppendBytes(t *testing.T) {
+	parser := NewStreamJSONParser()
+	input := []byte(`{"name":"Ada","tags":["x"]}`)
+
+	// The chunk buffer is reused between calls, as a network reader would
+	chunk := make([]byte, 4)
+	for start := 0; start < len(input); start += len(chunk) {
+		n := copy(chunk, input[start:])
+		parser.AppendBytes(chunk[:n])
+		for i := range chunk {
+			chunk[i] = 'X'
+		}
+	}
+
+	if !parser.IsCompleted() || parser.Get("name") != "Ada" || parser.Get("tags", "0") != "x" {
+		t.Errorf("Unexpected result %v", parser.Get())
+	}
+
+	fenced := NewStreamJSONParser(WithCodeFenceExtraction())
+	fenced.AppendBytes([]byte("Sure:\n```json\n{\"a\":1}\n```"))
+	if fenced.Get("a") != int64(1) {
+		t.Errorf("Expected code fences to apply to bytes, got %v", fenced.Get())
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordedChunk is a chunk of input captured by a Recorder
+type RecordedChunk struct {
+	At   time.Duration `json:"at"`   // Time since recording started
+	Data string        `json:"data"` // Content as appended, before code fence extraction
+}
+
+// Recording is the exact sequence of chunks a parser received. It encodes
+// to JSON, so a stream that breaks the parser can be attached to a bug
+// report and replayed with the same chunk boundaries.
+type Recording struct {
+	Chunks   []RecordedChunk `json:"chunks"`
+	Finished bool            `json:"finished"` // Whether Finish was called after the chunks
+}
+
+// Recorder wraps a parser and records every chunk appended through it
+type Recorder struct {
+	parser    *StreamJSONParser
+	started   time.Time
+	recording Recording
+
+	filter    *captureFilter // Rewrites chunks for a CapturePolicy, if any
+	unsampled bool           // Whether the CapturePolicy left this stream out
+}
+
+// NewRecorder starts recording the input of parser. Content must be added
+// through the Recorder to be captured.
+func NewRecorder(parser *StreamJSONParser) *Recorder {
+	return &Recorder{parser: parser, started: time.Now()}
+}
+
+// SetCapturePolicy applies policy to the recorded chunks. Call it before
+// appending. A stream the policy does not sample is not recorded, and
+// Recording returns nil for it. With paths to strip or hash, chunks are
+// tokenized and each value is recorded with the chunk that completes it.
+func (r *Recorder) SetCapturePolicy(policy *CapturePolicy) {
+	r.unsampled = !policy.Sample()
+	r.filter = nil
+	if policy.filtersPaths() {
+		r.filter = newCaptureFilter(policy)
+	}
+}
+
+// Append records content and appends it to the parser
+func (r *Recorder) Append(content string) {
+	r.record(content)
+	r.parser.Append(content)
+}
+
+// record adds a chunk to the recording, as the capture policy allows
+func (r *Recorder) record(content string) {
+	if r.unsampled {
+		return
+	}
+	if r.filter != nil {
+		if content = r.filter.write(content); content == "" {
+			return
+		}
+	}
+	r.recording.Chunks = append(r.recording.Chunks, RecordedChunk{At: time.Since(r.started), Data: content})
+}
+
+// AppendBytes records data and appends it to the parser
+func (r *Recorder) AppendBytes(data []byte) {
+	r.Append(string(data))
+}
+
+// Finish records the end of the input and finishes the parser
+func (r *Recorder) Finish() {
+	if r.filter != nil && !r.unsampled {
+		if rest := r.filter.close(); rest != "" {
+			r.recording.Chunks = append(r.recording.Chunks, RecordedChunk{At: time.Since(r.started), Data: rest})
+		}
+	}
+	r.recording.Finished = true
+	r.parser.Finish()
+}
+
+// Parser returns the recorded parser
+func (r *Recorder) Parser() *StreamJSONParser {
+	return r.parser
+}
+
+// Recording returns a copy of the chunks recorded so far, or nil if the
+// capture policy did not sample the stream
+func (r *Recorder) Recording() *Recording {
+	if r.unsampled {
+		return nil
+	}
+	return &Recording{
+		Chunks:   append([]RecordedChunk(nil), r.recording.Chunks...),
+		Finished: r.recording.Finished,
+	}
+}
+
+// Replay appends the recorded chunks to parser in order, with the same
+// boundaries, and finishes it if the recording was finished. Use a parser
+// with the options of the recorded one.
+func (rec *Recording) Replay(parser *StreamJSONParser) {
+	for _, chunk := range rec.Chunks {
+		parser.Append(chunk.Data)
+	}
+	if rec.Finished {
+		parser.Finish()
+	}
+}
+
+// ReplayTimed is like Replay but waits until each chunk is due, reproducing
+// the pacing of the original stream for timing-dependent code. It stops
+// early and returns the error if ctx is done.
+func (rec *Recording) ReplayTimed(ctx context.Context, parser *StreamJSONParser) error {
+	started := time.Now()
+	for _, chunk := range rec.Chunks {
+		if wait := chunk.At - time.Since(started); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+		parser.Append(chunk.Data)
+	}
+	if rec.Finished {
+		parser.Finish()
+	}
+	return nil
+}
+
+// DumpState returns a readable description of the parser's internal state,
+// for bug reports: the tokenizer position, the pending token, the stack of
+// open containers, the error and the document so far. The format is meant
+// for people and may change.
+func (p *StreamJSONParser) DumpState() string {
+	defer p.guardRead("DumpState")()
+	var b strings.Builder
+	t := p.tokenizer
+
+	fmt.Fprintf(&b, "completion: %s, version %d, finished %v\n", completionNames[p.Completion()], p.version, p.finished)
+	if p.err != nil {
+		fmt.Fprintf(&b, "error: %v\n", p.err)
+	}
+	fmt.Fprintf(&b, "tokenizer: offset %d of %d, %d bytes buffered, line %d\n",
+		t.base+t.position, t.base+len(t.buffer), len(t.buffer), t.lines+1)
+	if t.lastToken != nil {
+		fmt.Fprintf(&b, "pending token: %s %q at %d\n", t.lastToken.TokenType, t.lastToken.Content, t.base+t.lastToken.TokenStart)
+	}
+	if rest := t.buffer[t.position:]; len(rest) > 0 {
+		fmt.Fprintf(&b, "unconsumed: %q\n", rest)
+	}
+
+	fmt.Fprintf(&b, "stack: %d\n", len(p.stack))
+	for i, frame := range p.stack {
+		fmt.Fprintf(&b, "  %d: %s at %q", i, nodeTypeNames[frame.Node.Type], strings.Join(p.stackPath(i), "."))
+		if frame.CurrentKey != "" {
+			fmt.Fprintf(&b, ", key %q", frame.CurrentKey)
+		}
+		fmt.Fprintf(&b, ", expecting key %v, expecting value %v, %s members\n",
+			frame.ExpectingKey, frame.ExpectingValue, strconv.Itoa(frame.Node.Len()))
+	}
+	if p.documentCount > 0 {
+		fmt.Fprintf(&b, "documents: %d\n", p.documentCount)
+	}
+	fmt.Fprintf(&b, "document: %s\n", p.String())
+	return b.String()
+}
+
+// Names used by DumpState
+var (
+	completionNames = map[CompletionState]string{
+		NotStarted: "not started", InProgress: "in progress", Complete: "complete", Truncated: "truncated",
+	}
+	nodeTypeNames = map[NodeType]string{ObjectNode: "object", ArrayNode: "array", ValueNode: "value"}
+)
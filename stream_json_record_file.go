@@ -0,0 +1,236 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRecording is wrapped by the errors returned for input that is
+// not in the recording file format
+var ErrInvalidRecording = errors.New("streamjson: invalid recording")
+
+// errRecordingClosed is returned for chunks written after Close
+var errRecordingClosed = errors.New("streamjson: recording writer closed")
+
+// recordingMagic starts every recording file, ahead of the gzip stream
+const recordingMagic = "SJR1"
+
+// The recording file format is recordingMagic followed by a gzip stream of
+// records. A chunk is the uvarint length of its data plus one, the varint
+// time since the previous chunk in nanoseconds and the data. The last
+// record is a zero followed by a byte that is 1 if the input was finished.
+
+// RecordingWriter writes chunks to a recording file as they arrive, so a
+// long stream can be captured without keeping it in memory
+type RecordingWriter struct {
+	w    io.Writer
+	gz   *gzip.Writer
+	last time.Duration // Time of the previous chunk
+	buf  [2 * binary.MaxVarintLen64]byte
+	err  error
+}
+
+// NewRecordingWriter writes the header of a recording file to w
+func NewRecordingWriter(w io.Writer) (*RecordingWriter, error) {
+	if _, err := io.WriteString(w, recordingMagic); err != nil {
+		return nil, err
+	}
+	return &RecordingWriter{w: w, gz: gzip.NewWriter(w)}, nil
+}
+
+// WriteChunk adds a chunk to the recording. Chunks are compressed in
+// batches; Close flushes them.
+func (rw *RecordingWriter) WriteChunk(chunk RecordedChunk) error {
+	if rw.err != nil {
+		return rw.err
+	}
+	n := binary.PutUvarint(rw.buf[:], uint64(len(chunk.Data))+1)
+	n += binary.PutVarint(rw.buf[n:], int64(chunk.At-rw.last))
+	rw.last = chunk.At
+	if _, rw.err = rw.gz.Write(rw.buf[:n]); rw.err == nil {
+		_, rw.err = io.WriteString(rw.gz, chunk.Data)
+	}
+	return rw.err
+}
+
+// Close ends the recording, noting whether the input was finished, and
+// flushes it. It does not close the underlying writer.
+func (rw *RecordingWriter) Close(finished bool) error {
+	if rw.err != nil {
+		return rw.err
+	}
+	end := []byte{0, 0}
+	if finished {
+		end[1] = 1
+	}
+	if _, err := rw.gz.Write(end); err != nil {
+		rw.err = err
+		return err
+	}
+	if err := rw.gz.Close(); err != nil {
+		rw.err = err
+		return err
+	}
+	rw.err = errRecordingClosed
+	return nil
+}
+
+// WriteTo writes the recording to w in the compressed recording file
+// format, which ReadRecording and NewRecordingReader read back
+func (rec *Recording) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	rw, err := NewRecordingWriter(counter)
+	if err != nil {
+		return counter.n, err
+	}
+	for _, chunk := range rec.Chunks {
+		if err := rw.WriteChunk(chunk); err != nil {
+			return counter.n, err
+		}
+	}
+	err = rw.Close(rec.Finished)
+	return counter.n, err
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// RecordingReader reads the chunks of a recording file one at a time, so a
+// long recording can be replayed without loading it whole
+type RecordingReader struct {
+	r        *bufio.Reader
+	at       time.Duration // Time of the previous chunk
+	finished bool
+	done     bool
+}
+
+// NewRecordingReader checks the header of a recording file and returns a
+// reader for its chunks
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	magic := make([]byte, len(recordingMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != recordingMagic {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidRecording)
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecording, err)
+	}
+	return &RecordingReader{r: bufio.NewReader(gz)}, nil
+}
+
+// Next returns the next chunk, or io.EOF after the last one. A file that
+// ends before its last record returns io.ErrUnexpectedEOF.
+func (rr *RecordingReader) Next() (RecordedChunk, error) {
+	if rr.done {
+		return RecordedChunk{}, io.EOF
+	}
+	length, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return RecordedChunk{}, unexpectedEOF(err)
+	}
+	if length == 0 {
+		flag, err := rr.r.ReadByte()
+		if err != nil {
+			return RecordedChunk{}, unexpectedEOF(err)
+		}
+		rr.done, rr.finished = true, flag == 1
+		return RecordedChunk{}, io.EOF
+	}
+
+	delta, err := binary.ReadVarint(rr.r)
+	if err != nil {
+		return RecordedChunk{}, unexpectedEOF(err)
+	}
+	// Grown as the data arrives, so a corrupt length cannot allocate much
+	var data strings.Builder
+	if _, err := io.CopyN(&data, rr.r, int64(length-1)); err != nil {
+		return RecordedChunk{}, unexpectedEOF(err)
+	}
+	rr.at += time.Duration(delta)
+	return RecordedChunk{At: rr.at, Data: data.String()}, nil
+}
+
+// Finished reports whether the recorded input was finished. It is valid
+// once Next has returned io.EOF.
+func (rr *RecordingReader) Finished() bool {
+	return rr.finished
+}
+
+// Replay appends the remaining chunks to parser like Recording.Replay,
+// reading them as it goes
+func (rr *RecordingReader) Replay(parser *StreamJSONParser) error {
+	for {
+		chunk, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		parser.Append(chunk.Data)
+	}
+	if rr.finished {
+		parser.Finish()
+	}
+	return nil
+}
+
+// ReadRecording reads a whole recording file written by Recording.WriteTo
+// or a RecordingWriter
+func ReadRecording(r io.Reader) (*Recording, error) {
+	rr, err := NewRecordingReader(r)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Recording{}
+	for {
+		chunk, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			rec.Finished = rr.Finished()
+			return rec, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec.Chunks = append(rec.Chunks, chunk)
+	}
+}
+
+// unexpectedEOF turns io.EOF in the middle of a recording into
+// io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
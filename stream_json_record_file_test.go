@@ -0,0 +1,113 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenRecording resembles a recorded LLM response: many small chunks a
+// few milliseconds apart
+func tokenRecording() *Recording {
+	rec := &Recording{Finished: true}
+	rec.Chunks = append(rec.Chunks, RecordedChunk{Data: `{"items":[`})
+	for i := 0; i < 500; i++ {
+		for _, data := range []string{`{"id":`, strconv.Itoa(i), `,"text":"the`, ` quick`, ` brown fox"}`, `,`} {
+			rec.Chunks = append(rec.Chunks, RecordedChunk{At: time.Duration(len(rec.Chunks)) * 3 * time.Millisecond, Data: data})
+		}
+	}
+	rec.Chunks = append(rec.Chunks, RecordedChunk{At: time.Second, Data: `{}]}`})
+	return rec
+}
+
+func TestRecordingFileRoundTrip(t *testing.T) {
+	rec := tokenRecording()
+
+	var file bytes.Buffer
+	n, err := rec.WriteTo(&file)
+	if err != nil || n != int64(file.Len()) {
+		t.Fatalf("WriteTo = %d, %v; wrote %d bytes", n, err, file.Len())
+	}
+
+	data, _ := json.Marshal(rec)
+	if file.Len()*10 > len(data) {
+		t.Errorf("Expected the file to be far smaller than JSON: %d bytes, JSON %d", file.Len(), len(data))
+	}
+
+	decoded, err := ReadRecording(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, rec) {
+		t.Errorf("Expected the recording back, got %d chunks, finished %v", len(decoded.Chunks), decoded.Finished)
+	}
+}
+
+func TestRecordingReaderReplay(t *testing.T) {
+	var file bytes.Buffer
+	writer, err := NewRecordingWriter(&file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record while parsing, without keeping the chunks
+	parser := NewStreamJSONParser()
+	started := time.Now()
+	for _, chunk := range []string{`{"answer":"4`, `2","done":`, `true}`} {
+		if err := writer.WriteChunk(RecordedChunk{At: time.Since(started), Data: chunk}); err != nil {
+			t.Fatal(err)
+		}
+		parser.Append(chunk)
+	}
+	if err := writer.Close(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.WriteChunk(RecordedChunk{Data: "x"}); err == nil {
+		t.Error("Expected an error writing after Close")
+	}
+
+	reader, err := NewRecordingReader(&file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := NewStreamJSONParser()
+	if err := reader.Replay(replayed); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(replayed.Get(), parser.Get()) || reader.Finished() {
+		t.Errorf("Replayed %v, finished %v; want %v, unfinished", replayed.Get(), reader.Finished(), parser.Get())
+	}
+}
+
+func TestRecordingFileErrors(t *testing.T) {
+	if _, err := ReadRecording(strings.NewReader(`{"chunks":[]}`)); !errors.Is(err, ErrInvalidRecording) {
+		t.Errorf("Expected ErrInvalidRecording for JSON, got %v", err)
+	}
+
+	var file bytes.Buffer
+	tokenRecording().WriteTo(&file)
+	truncated := file.Bytes()[:file.Len()/2]
+	if _, err := ReadRecording(bytes.NewReader(truncated)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF for a truncated file, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderReplay(t *testing.T) {
+	chunks := []string{`{"name":"Al`, `ice","tags":["a",`, `"b"],"n":1`, `2}`}
+
+	parser := NewStreamJSONParser()
+	recorder := NewRecorder(parser)
+	for _, chunk := range chunks {
+		recorder.Append(chunk)
+	}
+	recorder.Finish()
+
+	recording := recorder.Recording()
+	if len(recording.Chunks) != len(chunks) || !recording.Finished {
+		t.Fatalf("recording = %+v", recording)
+	}
+	for i, chunk := range recording.Chunks {
+		if chunk.Data != chunks[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunk.Data, chunks[i])
+		}
+		if i > 0 && chunk.At < recording.Chunks[i-1].At {
+			t.Errorf("chunk %d recorded before chunk %d", i, i-1)
+		}
+	}
+
+	// Round trip through JSON, as a bug report would
+	data, err := json.Marshal(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Recording
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, recording) {
+		t.Fatalf("decoded = %+v, want %+v", decoded, recording)
+	}
+
+	replayed := NewStreamJSONParser()
+	decoded.Replay(replayed)
+	if !reflect.DeepEqual(replayed.Get(), parser.Get()) {
+		t.Errorf("replayed %v, want %v", replayed.Get(), parser.Get())
+	}
+	if replayed.Completion() != Complete {
+		t.Errorf("completion = %v, want Complete", replayed.Completion())
+	}
+}
+
+func TestRecorderRecordingIsCopy(t *testing.T) {
+	recorder := NewRecorder(NewStreamJSONParser())
+	recorder.Append(`{"a":`)
+	recording := recorder.Recording()
+	recorder.AppendBytes([]byte(`1}`))
+
+	if len(recording.Chunks) != 1 || recording.Finished {
+		t.Errorf("earlier recording changed: %+v", recording)
+	}
+	if got := recorder.Recording().Chunks[1].Data; got != `1}` {
+		t.Errorf("second chunk = %q", got)
+	}
+	if recorder.Parser().Get("a") != int64(1) {
+		t.Errorf("a = %v", recorder.Parser().Get("a"))
+	}
+}
+
+func TestRecordingReplayTimed(t *testing.T) {
+	recording := &Recording{
+		Chunks: []RecordedChunk{
+			{At: 0, Data: `[1,`},
+			{At: 20 * time.Millisecond, Data: `2]`},
+		},
+		Finished: true,
+	}
+
+	parser := NewStreamJSONParser()
+	started := time.Now()
+	if err := recording.ReplayTimed(context.Background(), parser); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed < 20*time.Millisecond {
+		t.Errorf("replay took %v, want at least 20ms", elapsed)
+	}
+	if !reflect.DeepEqual(parser.Get(), []interface{}{int64(1), int64(2)}) {
+		t.Errorf("got %v", parser.Get())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recording.Chunks[1].At = time.Hour
+	parser = NewStreamJSONParser()
+	if err := recording.ReplayTimed(ctx, parser); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if parser.Completion() == Complete {
+		t.Error("canceled replay finished the parser")
+	}
+}
+
+func TestDumpState(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"user":{"name":"Bo`)
+
+	state := parser.DumpState()
+	for _, want := range []string{
+		"completion: in progress",
+		"stack: 2",
+		`object at "user"`,
+		`key "name"`,
+		"document: ",
+	} {
+		if !strings.Contains(state, want) {
+			t.Errorf("state missing %q:\n%s", want, state)
+		}
+	}
+
+	// Reading the state leaves the lazily built paths alone, so readers
+	// sharing a SafeStreamJSONParser do not write
+	parser.Append(`b","tags":[{"x":[`)
+	state = parser.DumpState()
+	if !strings.Contains(state, `array at "user.tags.0.x"`) {
+		t.Errorf("state missing the innermost path:\n%s", state)
+	}
+	for i, frame := range parser.stack[1:] {
+		if frame.Path != nil {
+			t.Errorf("DumpState stored the path %q of frame %d", frame.Path, i+1)
+		}
+	}
+
+	strict := NewStreamJSONParser(WithStrictMode())
+	strict.Append(`{"a":}`)
+	if state := strict.DumpState(); !strings.Contains(state, "error: ") {
+		t.Errorf("state missing error:\n%s", state)
+	}
+}
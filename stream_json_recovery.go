@@ -0,0 +1,93 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// Recovery describes a resynchronization after invalid input inside an
+// object or array
+type Recovery struct {
+	Offset  int      // Byte offset of the invalid token in the input
+	Line    int      // 1-based line of the invalid token
+	Column  int      // 1-based byte column of the invalid token
+	Content string   // Text of the invalid token
+	Path    []string // Path of the object or array the invalid token appeared in
+	Dropped []string // Path of the key discarded with it, nil if no key was pending
+}
+
+// Recoveries returns the resynchronizations performed so far with
+// WithRecovery, in input order
+func (p *StreamJSONParser) Recoveries() []*Recovery {
+	return p.recoveries
+}
+
+// recover handles an invalid token inside a structure. It discards the
+// member the token belongs to and skips the following tokens up to the next
+// comma or closing bracket of the same container.
+func (p *StreamJSONParser) recover(token Token) {
+	if p.skipping {
+		return // Already resynchronizing
+	}
+
+	frame := p.stack[len(p.stack)-1]
+	recovery := &Recovery{
+		Offset:  token.TokenStart,
+		Line:    token.Line,
+		Column:  token.Column,
+		Content: token.Content,
+		Path:    append([]string(nil), p.framePath(frame)...),
+	}
+	if frame.CurrentKey != "" {
+		recovery.Dropped = p.childPath(frame) // The key whose value is corrupt
+	}
+
+	frame.CurrentKey = ""
+	frame.ExpectingValue = false
+
+	p.recoveries = append(p.recoveries, recovery)
+	p.skipping = true
+	p.skipDepth = 0
+	if p.events != nil {
+		p.emit(Event{Type: Recovered, Path: recovery.Path, Value: recovery})
+	}
+}
+
+// skip consumes a complete token while resynchronizing and reports whether
+// it was skipped. The comma or closing bracket that ends the skipped input,
+// or the key when invalid input came before one, is left for normal
+// processing.
+func (p *StreamJSONParser) skip(token Token) bool {
+	frame := p.stack[len(p.stack)-1]
+	switch token.TokenType {
+	case ObjectStart, ArrayStart:
+		p.skipDepth++
+		return true
+	case ObjectEnd, ArrayEnd:
+		if p.skipDepth > 0 {
+			p.skipDepth--
+			return true
+		}
+	case Comma:
+		if p.skipDepth > 0 {
+			return true
+		}
+	case ObjectKey:
+		if p.skipDepth > 0 || frame.Node.Type != ObjectNode || !frame.ExpectingKey {
+			return true
+		}
+	default:
+		return true
+	}
+	p.skipping = false
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStreamJSONParserRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		dropped  []string
+	}{
+		{"invalid literal", `{"a": tru, "b": 1}`, `{"b":1}`, []string{"a"}},
+		{"garbage before value", `{"a": @@ "junk", "b": 1}`, `{"b":1}`, []string{"a"}},
+		{"skipped containers", `{"a": # {"x": [1, 2]}, "b": 2}`, `{"b":2}`, []string{"a"}},
+		{"garbage before key", `{"a": 1, @ "b": 2}`, `{"a":1,"b":2}`, nil},
+		{"array element", `[1, "x", # "y", 4]`, `[1,"x",4]`, nil},
+		{"nested object", `{"a": {"x": ?? 1}, "b": 2}`, `{"a":{},"b":2}`, []string{"a", "x"}},
+	}
+
+	for _, tt := range tests {
+		// Whole input and one byte at a time
+		for _, chunk := range []int{len(tt.input), 1} {
+			parser := NewStreamJSONParser(WithRecovery())
+			for i := 0; i < len(tt.input); i += chunk {
+				parser.Append(tt.input[i:min(i+chunk, len(tt.input))])
+			}
+
+			if !parser.IsCompleted() {
+				t.Errorf("%s/%d: expected parsing to complete", tt.name, chunk)
+				continue
+			}
+			data, err := parser.MarshalJSON()
+			if err != nil {
+				t.Fatalf("%s/%d: unexpected error: %v", tt.name, chunk, err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("%s/%d: expected %s, got %s", tt.name, chunk, tt.expected, data)
+			}
+
+			recoveries := parser.Recoveries()
+			if len(recoveries) != 1 {
+				t.Errorf("%s/%d: expected 1 recovery, got %d", tt.name, chunk, len(recoveries))
+				continue
+			}
+			if !reflect.DeepEqual(recoveries[0].Dropped, tt.dropped) {
+				t.Errorf("%s/%d: expected dropped %v, got %v", tt.name, chunk, tt.dropped, recoveries[0].Dropped)
+			}
+		}
+	}
+}
+
+func TestStreamJSONParserRecoveryDetails(t *testing.T) {
+	parser := NewStreamJSONParser(WithRecovery())
+	events := parser.Events()
+	parser.Append("{\"user\": {\n  \"name\": nul, \"age\": 3}}")
+
+	recoveries := parser.Recoveries()
+	if len(recoveries) != 1 {
+		t.Fatalf("Expected 1 recovery, got %d", len(recoveries))
+	}
+	r := recoveries[0]
+	if r.Offset != 21 || r.Line != 2 || r.Column != 11 || r.Content != "nul" {
+		t.Errorf("Unexpected recovery position %+v", r)
+	}
+	if !reflect.DeepEqual(r.Path, []string{"user"}) {
+		t.Errorf("Expected container path [user], got %v", r.Path)
+	}
+
+	var recovered *Event
+	for event := range events {
+		if event.Type == Recovered {
+			recovered = &event
+		}
+	}
+	if recovered == nil || recovered.Value != r {
+		t.Errorf("Expected a Recovered event carrying the recovery, got %+v", recovered)
+	}
+
+	var v struct {
+		User map[string]int `json:"user"`
+	}
+	data, _ := parser.MarshalJSON()
+	if err := json.Unmarshal(data, &v); err != nil || v.User["age"] != 3 || len(v.User) != 1 {
+		t.Errorf("Unexpected document %s", data)
+	}
+}
+
+func TestStreamJSONParserWithoutRecovery(t *testing.T) {
+	// Without recovery only the invalid tokens are skipped
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a": @@ "junk", "b": 1}`)
+
+	if parser.Get("a") != "junk" || parser.Get("b") != int64(1) {
+		t.Errorf("Unexpected document %v", parser.Get())
+	}
+	if parser.Recoveries() != nil {
+		t.Errorf("Expected no recoveries, got %v", parser.Recoveries())
+	}
+}
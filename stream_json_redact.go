@@ -0,0 +1,109 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// DefaultRedactionPlaceholder is stored in place of redacted values when
+// WithRedaction is given an empty placeholder
+const DefaultRedactionPlaceholder = "[REDACTED]"
+
+// redaction is a path pattern whose values are replaced by placeholder
+type redaction struct {
+	pattern     []string
+	placeholder string
+}
+
+// WithRedaction stores placeholder instead of the values at the dotted
+// paths, which may contain * wildcards, so snapshots and logs of the
+// document never contain them. Objects and arrays at a path keep their
+// structure with every value inside replaced. Values are replaced while
+// they stream, before callbacks, events, schemas and transformers see them.
+// null stays null. GetRaw returns nil for values that are or may contain
+// redacted values, while OnRawSubtree and WithTokenInterceptor still see
+// the original input.
+func WithRedaction(placeholder string, paths ...string) Option {
+	if placeholder == "" {
+		placeholder = DefaultRedactionPlaceholder
+	}
+	return func(o *parserOptions) {
+		for _, path := range paths {
+			o.redactions = append(o.redactions, redaction{pattern: splitPath(path), placeholder: placeholder})
+		}
+	}
+}
+
+// WithStringTruncation cuts string values longer than n bytes down to n
+// bytes, at a character boundary, and marks them as truncated for
+// IsTruncated. Unlike WithMaxStringLength, parsing continues. Strings are
+// cut while they stream, before callbacks, events, schemas and
+// transformers see them; the input buffer still holds a string until it
+// ends.
+func WithStringTruncation(n int) Option {
+	return func(o *parserOptions) {
+		o.truncateStrings = n
+	}
+}
+
+// hasValuePolicies reports whether values may be redacted or truncated
+func (o *parserOptions) hasValuePolicies() bool {
+	return len(o.redactions) > 0 || o.truncateStrings > 0
+}
+
+// IsTruncated reports whether the string at the path was cut by
+// WithStringTruncation
+func (p *StreamJSONParser) IsTruncated(keys ...string) bool {
+	defer p.guardRead("IsTruncated")()
+	node := p.findValueNode(keys)
+	return node != nil && node.Truncated
+}
+
+// applyValuePolicies redacts or truncates the value of node at path, as
+// WithRedaction and WithStringTruncation request
+func (p *StreamJSONParser) applyValuePolicies(path []string, node *Node) {
+	if node.Value == nil {
+		return
+	}
+	if placeholder, ok := p.redactionAt(path); ok {
+		node.Value = placeholder
+		return
+	}
+	if s, ok := node.Value.(string); ok && p.options.truncateStrings > 0 && len(s) > p.options.truncateStrings {
+		s = s[:p.options.truncateStrings]
+		node.Value = s[:completeUTF8(s)]
+		node.Truncated = true
+	}
+}
+
+// redactionAt returns the placeholder for a value at path, which is
+// redacted if it is at or inside a redacted path
+func (p *StreamJSONParser) redactionAt(path []string) (string, bool) {
+	for _, r := range p.options.redactions {
+		if len(path) >= len(r.pattern) && matchPath(r.pattern, path[:len(r.pattern)]) {
+			return r.placeholder, true
+		}
+	}
+	return "", false
+}
+
+// mayContainRedaction reports whether the value at path is redacted or may
+// contain redacted values
+func (p *StreamJSONParser) mayContainRedaction(path []string) bool {
+	for _, r := range p.options.redactions {
+		n := min(len(path), len(r.pattern))
+		if matchPath(r.pattern[:n], path[:n]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedaction(t *testing.T) {
+	var updates []interface{}
+	parser := NewStreamJSONParser(
+		WithRedaction("", "user.email", "cards.*.number"),
+		WithRedaction("***", "secrets"),
+	)
+	parser.OnValue("user.email", func(value interface{}, complete bool) {
+		updates = append(updates, value)
+	})
+
+	parser.Append(`{"user": {"name": "Ada", "email": "ada@exa`)
+	if got := parser.Get("user", "email"); got != DefaultRedactionPlaceholder {
+		t.Errorf("Expected the streaming value redacted, got %v", got)
+	}
+	parser.Append(`mple.com"}, "cards": [{"number": 4111111111111111, "brand": "visa"}], ` +
+		`"secrets": {"keys": ["k1", {"k": true}], "none": null}}`)
+
+	want := map[string]interface{}{
+		"user":  map[string]interface{}{"name": "Ada", "email": "[REDACTED]"},
+		"cards": []interface{}{map[string]interface{}{"number": "[REDACTED]", "brand": "visa"}},
+		"secrets": map[string]interface{}{
+			"keys": []interface{}{"***", map[string]interface{}{"k": "***"}},
+			"none": nil,
+		},
+	}
+	if got := parser.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if s := parser.String(); strings.Contains(s, "ada@") || strings.Contains(s, "4111") || strings.Contains(s, "k1") {
+		t.Errorf("Expected no redacted value in the document, got %s", s)
+	}
+	for _, value := range updates {
+		if value != DefaultRedactionPlaceholder {
+			t.Errorf("Expected callbacks to only see the placeholder, got %v", value)
+		}
+	}
+
+	if parser.GetRaw("user", "email") != nil || parser.GetRaw("user") != nil || parser.GetRaw() != nil {
+		t.Error("Expected GetRaw to hide redacted values")
+	}
+	if raw := string(parser.GetRaw("cards", "0", "brand")); raw != `"visa"` {
+		t.Errorf("Expected GetRaw for other values, got %q", raw)
+	}
+}
+
+func TestStringTruncation(t *testing.T) {
+	parser := NewStreamJSONParser(WithStringTruncation(5))
+	parser.Append(`{"short": "abc", "long": "héllo wor`)
+	if got := parser.Get("long"); got != "héll" || !parser.IsTruncated("long") {
+		t.Errorf("Expected the streaming string cut at a character boundary, got %q", got)
+	}
+	parser.Append(`ld", "exact": "12345", "n": 123456}`)
+
+	if got := parser.Get("long"); got != "héll" || !parser.IsTruncated("long") {
+		t.Errorf("Expected a truncated string, got %q", got)
+	}
+	if parser.Get("short") != "abc" || parser.IsTruncated("short") {
+		t.Errorf("Expected a short string kept, got %v", parser.Get("short"))
+	}
+	if parser.Get("exact") != "12345" || parser.IsTruncated("exact") {
+		t.Errorf("Expected a string of the limit kept, got %v", parser.Get("exact"))
+	}
+	if parser.Get("n") != int64(123456) || parser.IsTruncated("missing") {
+		t.Errorf("Expected numbers kept, got %v", parser.Get("n"))
+	}
+	if parser.Completion() != Complete {
+		t.Errorf("Expected parsing to continue, got %v", parser.Completion())
+	}
+}
+
+func TestRedactionConfig(t *testing.T) {
+	cfg := Config{StringTruncation: 2, RedactPaths: []string{"pin"}, RedactionPlaceholder: "-"}
+	parser := NewStreamJSONParser(WithConfig(cfg))
+	parser.Append(`{"pin": 1234, "name": "Ada"}`)
+
+	if parser.Get("pin") != "-" || parser.Get("name") != "Ad" {
+		t.Errorf("Expected the settings of the config, got %v", parser.Get())
+	}
+}
@@ -0,0 +1,495 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"sync"
+)
+
+// SafeStreamJSONParser wraps StreamJSONParser for concurrent use, so one
+// goroutine can Append network chunks while others read values.
+// Callbacks registered with OnValue, OnRawSubtree or UnmarshalStream run
+// while the write lock is held and must not call back into the parser.
+type SafeStreamJSONParser struct {
+	mu     sync.RWMutex
+	parser *StreamJSONParser
+}
+
+// NewSafeStreamJSONParser creates a new concurrency-safe streaming JSON parser
+func NewSafeStreamJSONParser(opts ...Option) *SafeStreamJSONParser {
+	return &SafeStreamJSONParser{
+		parser: NewStreamJSONParser(opts...),
+	}
+}
+
+// Append adds more content to the parser and processes tokens
+func (s *SafeStreamJSONParser) Append(content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Append(content)
+}
+
+// AppendBytes adds content from a byte slice, see StreamJSONParser.AppendBytes
+func (s *SafeStreamJSONParser) AppendBytes(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.AppendBytes(data)
+}
+
+// Reset clears the parser for reuse, see StreamJSONParser.Reset
+func (s *SafeStreamJSONParser) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Reset()
+}
+
+// RemoveSpillFiles deletes the temporary files of spilled strings
+func (s *SafeStreamJSONParser) RemoveSpillFiles() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.RemoveSpillFiles()
+}
+
+// Finish signals the end of the input, see StreamJSONParser.Finish
+func (s *SafeStreamJSONParser) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Finish()
+}
+
+// ParseReader consumes r until io.EOF. Reads happen outside the lock, so
+// readers are only blocked while each chunk is being parsed.
+func (s *SafeStreamJSONParser) ParseReader(r io.Reader) error {
+	return readChunks(r, s.AppendBytes)
+}
+
+// ParseBytes parses data as the complete input, see
+// StreamJSONParser.ParseBytes
+func (s *SafeStreamJSONParser) ParseBytes(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.ParseBytes(data)
+}
+
+// ParseFile reads the file at path and parses it as the complete input, see
+// StreamJSONParser.ParseFile. The file is read outside the lock.
+func (s *SafeStreamJSONParser) ParseFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s.ParseBytes(data)
+	return nil
+}
+
+// Get retrieves a value from the AST using a path of keys
+func (s *SafeStreamJSONParser) Get(keys ...string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Get(keys...)
+}
+
+// GetOrdered returns the value at the path with objects in stream order
+func (s *SafeStreamJSONParser) GetOrdered(keys ...string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetOrdered(keys...)
+}
+
+// MatchesFinal compares the document against a reference JSON document
+func (s *SafeStreamJSONParser) MatchesFinal(expected []byte) (bool, []Difference) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.MatchesFinal(expected)
+}
+
+// GetPath retrieves a value using a gjson-style dotted path, see
+// StreamJSONParser.GetPath
+func (s *SafeStreamJSONParser) GetPath(path string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetPath(path)
+}
+
+// GetCompleted retrieves a value without strings that are still streaming
+func (s *SafeStreamJSONParser) GetCompleted(keys ...string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetCompleted(keys...)
+}
+
+// GetString returns the string at the path, see StreamJSONParser.GetString
+func (s *SafeStreamJSONParser) GetString(keys ...string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetString(keys...)
+}
+
+// GetInt returns the number at the path as an int64, see StreamJSONParser.GetInt
+func (s *SafeStreamJSONParser) GetInt(keys ...string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetInt(keys...)
+}
+
+// GetFloat returns the number at the path as a float64, see StreamJSONParser.GetFloat
+func (s *SafeStreamJSONParser) GetFloat(keys ...string) (float64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetFloat(keys...)
+}
+
+// GetOr returns the complete value at the path, or def
+func (s *SafeStreamJSONParser) GetOr(def interface{}, keys ...string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetOr(def, keys...)
+}
+
+// GetStringOr returns the complete string at the path, or def
+func (s *SafeStreamJSONParser) GetStringOr(def string, keys ...string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetStringOr(def, keys...)
+}
+
+// GetReader returns a reader over the string at the path
+func (s *SafeStreamJSONParser) GetReader(keys ...string) (io.Reader, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetReader(keys...)
+}
+
+// SpillErr returns the first error writing a spilled string
+func (s *SafeStreamJSONParser) SpillErr() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.SpillErr()
+}
+
+// GetBool returns the boolean at the path
+func (s *SafeStreamJSONParser) GetBool(keys ...string) (bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetBool(keys...)
+}
+
+// GetIntLenient returns the number at the path as an int64, converting a
+// stringified number, see StreamJSONParser.GetIntLenient
+func (s *SafeStreamJSONParser) GetIntLenient(keys ...string) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetIntLenient(keys...)
+}
+
+// GetBoolLenient returns the boolean at the path, converting "true" and
+// "false" strings
+func (s *SafeStreamJSONParser) GetBoolLenient(keys ...string) (bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetBoolLenient(keys...)
+}
+
+// Exists reports whether the path has been seen, see StreamJSONParser.Exists
+func (s *SafeStreamJSONParser) Exists(keys ...string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Exists(keys...)
+}
+
+// IsComplete reports whether the value at the path has been finalized
+func (s *SafeStreamJSONParser) IsComplete(keys ...string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.IsComplete(keys...)
+}
+
+// Len returns the size of the container at the path and whether it is
+// complete, see StreamJSONParser.Len
+func (s *SafeStreamJSONParser) Len(keys ...string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Len(keys...)
+}
+
+// Keys returns the keys of the object at the path and whether it is
+// complete, see StreamJSONParser.Keys
+func (s *SafeStreamJSONParser) Keys(keys ...string) ([]string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Keys(keys...)
+}
+
+// Unmarshal maps the current AST into v, see StreamJSONParser.Unmarshal
+func (s *SafeStreamJSONParser) Unmarshal(v interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Unmarshal(v)
+}
+
+// Snapshot returns a deep copy of the document, see StreamJSONParser.Snapshot
+func (s *SafeStreamJSONParser) Snapshot() Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Snapshot()
+}
+
+// Query evaluates a JSONPath expression, see StreamJSONParser.Query
+func (s *SafeStreamJSONParser) Query(expr string) ([]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Query(expr)
+}
+
+// MarshalJSON serializes the current AST, see StreamJSONParser.MarshalJSON
+func (s *SafeStreamJSONParser) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.MarshalJSON()
+}
+
+// String returns the current AST as JSON
+func (s *SafeStreamJSONParser) String() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.String()
+}
+
+// DumpState returns a readable description of the parser's internal state
+func (s *SafeStreamJSONParser) DumpState() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.DumpState()
+}
+
+// IsCompleted returns true if all structures of the root have been closed
+func (s *SafeStreamJSONParser) IsCompleted() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.IsCompleted()
+}
+
+// Stats returns counters describing the input and the document so far
+func (s *SafeStreamJSONParser) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Stats()
+}
+
+// Completion reports whether the document is complete, streaming or truncated
+func (s *SafeStreamJSONParser) Completion() CompletionState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Completion()
+}
+
+// Err returns the error that stopped the parser, if any
+func (s *SafeStreamJSONParser) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.Err()
+}
+
+// Events returns the parser's event channel. With WithBlockingEvents,
+// Append blocks while the channel is full, holding the write lock, so the
+// consumer must not call read methods while it is behind on events.
+func (s *SafeStreamJSONParser) Events() <-chan Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.Events()
+}
+
+// DroppedEvents returns the number of events left out of a full channel
+func (s *SafeStreamJSONParser) DroppedEvents() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.DroppedEvents()
+}
+
+// OnValue registers a callback for the value at a dotted path
+func (s *SafeStreamJSONParser) OnValue(path string, callback func(value interface{}, complete bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.OnValue(path, callback)
+}
+
+// Tee streams the string at a dotted path into w, see StreamJSONParser.Tee.
+// w is written while the write lock is held.
+func (s *SafeStreamJSONParser) Tee(path string, w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Tee(path, w)
+}
+
+// UnmarshalStream binds v to the stream, see StreamJSONParser.UnmarshalStream.
+// v is written while the write lock is held.
+func (s *SafeStreamJSONParser) UnmarshalStream(v interface{}, callback func(fieldPath string)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.UnmarshalStream(v, callback)
+}
+
+// Transform registers a transformer for the values at a dotted path, see
+// StreamJSONParser.Transform. It runs while the write lock is held.
+func (s *SafeStreamJSONParser) Transform(path string, transform Transformer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Transform(path, transform)
+}
+
+// TransformErrors returns the values transformers rejected so far
+func (s *SafeStreamJSONParser) TransformErrors() []*TransformError {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.TransformErrors()
+}
+
+// IsTruncated reports whether the string at the path was cut, see
+// StreamJSONParser.IsTruncated
+func (s *SafeStreamJSONParser) IsTruncated(keys ...string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.IsTruncated(keys...)
+}
+
+// ShapeDeviations returns the values deviating from the Shape so far
+func (s *SafeStreamJSONParser) ShapeDeviations() []*ShapeDeviation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.ShapeDeviations()
+}
+
+// Watch returns a channel for the value at the path, see StreamJSONParser.Watch
+func (s *SafeStreamJSONParser) Watch(keys ...string) <-chan interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.Watch(keys...)
+}
+
+// Await blocks until the value at the path completes, see
+// StreamJSONParser.Await. The parser is not locked while waiting.
+func (s *SafeStreamJSONParser) Await(ctx context.Context, keys ...string) (interface{}, error) {
+	s.mu.Lock()
+	result := s.parser.await(keys)
+	s.mu.Unlock()
+	return waitResult(ctx, result)
+}
+
+// Checkpoint saves the state of the parser, see StreamJSONParser.Checkpoint
+func (s *SafeStreamJSONParser) Checkpoint() *ParserCheckpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.Checkpoint()
+}
+
+// Restore returns the parser to a checkpoint, see StreamJSONParser.Restore
+func (s *SafeStreamJSONParser) Restore(cp *ParserCheckpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.parser.Restore(cp)
+}
+
+// Release drops a checkpoint
+func (s *SafeStreamJSONParser) Release(cp *ParserCheckpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.Release(cp)
+}
+
+// NextElements returns the elements completed since cursor, see
+// StreamJSONParser.NextElements
+func (s *SafeStreamJSONParser) NextElements(path string, cursor int) ([]interface{}, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.NextElements(path, cursor)
+}
+
+// MemoryUsage returns the approximate bytes held by the value at the path
+func (s *SafeStreamJSONParser) MemoryUsage(keys ...string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.MemoryUsage(keys...)
+}
+
+// GetRawNumber returns the literal of a completed number
+func (s *SafeStreamJSONParser) GetRawNumber(keys ...string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetRawNumber(keys...)
+}
+
+// GetRaw returns the source bytes of a completed value
+func (s *SafeStreamJSONParser) GetRaw(keys ...string) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parser.GetRaw(keys...)
+}
+
+// StreamArray registers a callback for the elements of an array, see
+// StreamJSONParser.StreamArray
+func (s *SafeStreamJSONParser) StreamArray(path string, callback func(index int, value interface{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.StreamArray(path, callback)
+}
+
+// OnRawSubtree registers a callback for the raw bytes of a completed subtree
+func (s *SafeStreamJSONParser) OnRawSubtree(path string, callback func(raw []byte)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parser.OnRawSubtree(path, callback)
+}
+
+// Entries returns an iterator over the completed members of the object at
+// the path. The members are copied under the lock before iteration starts.
+func (s *SafeStreamJSONParser) Entries(keys ...string) iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		var names []string
+		var values []interface{}
+		s.mu.RLock()
+		for key, value := range s.parser.Entries(keys...) {
+			names = append(names, key)
+			values = append(values, value)
+		}
+		s.mu.RUnlock()
+
+		for i, key := range names {
+			if !yield(key, values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Elements returns an iterator over the completed elements of the array at
+// the path. The elements are copied under the lock before iteration starts.
+func (s *SafeStreamJSONParser) Elements(keys ...string) iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		s.mu.RLock()
+		values := slices.Collect(s.parser.Elements(keys...))
+		s.mu.RUnlock()
+
+		for _, value := range values {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
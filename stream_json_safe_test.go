@@ -0,0 +1,97 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSafeStreamJSONParserConcurrentAccess(t *testing.T) {
+	parser := NewSafeStreamJSONParser()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Readers poll while the writer appends; run with -race to verify
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					parser.Get("items")
+					parser.GetString("message")
+					parser.IsCompleted()
+				}
+			}
+		}()
+	}
+
+	parser.Append(`{"message":"`)
+	for i := 0; i < 200; i++ {
+		parser.Append("x")
+	}
+	parser.Append(`","items":[`)
+	for i := 0; i < 200; i++ {
+		if i > 0 {
+			parser.Append(",")
+		}
+		parser.Append(strconv.Itoa(i))
+	}
+	parser.Append(`]}`)
+
+	close(stop)
+	wg.Wait()
+
+	if !parser.IsCompleted() {
+		t.Errorf("Expected parser to be completed")
+	}
+
+	items, ok := parser.Get("items").([]interface{})
+	if !ok || len(items) != 200 {
+		t.Errorf("Expected 200 items, got %v", len(items))
+	}
+
+	if message, ok := parser.GetString("message"); !ok || len(message) != 200 {
+		t.Errorf("Expected 200 character message, got %d %v", len(message), ok)
+	}
+}
+
+func TestSafeStreamJSONParserEvents(t *testing.T) {
+	parser := NewSafeStreamJSONParser()
+	events := parser.Events()
+
+	done := make(chan int)
+	go func() {
+		count := 0
+		for event := range events {
+			if event.Type == ValueCompleted {
+				count++
+			}
+		}
+		done <- count
+	}()
+
+	parser.Append(`[1,2,3]`)
+
+	if count := <-done; count != 3 {
+		t.Errorf("Expected 3 completed values, got %d", count)
+	}
+}
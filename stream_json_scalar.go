@@ -0,0 +1,71 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// isScalarToken reports whether a token can be a scalar root document
+func isScalarToken(token Token) bool {
+	switch token.TokenType {
+	case String, Number, Bool, Null:
+		return true
+	}
+	return false
+}
+
+// processScalarRoot makes a string, number, bool or null token the root of
+// the document. A string is exposed while it streams, like any other string.
+func (p *StreamJSONParser) processScalarRoot(token Token) {
+	if !token.Completed {
+		value, ok := p.partialValue(token)
+		if !ok {
+			// Literals, and numbers unless partial numbers are enabled, only
+			// count once complete; a number that stopped being valid is dropped
+			p.dropPartial()
+			return
+		}
+
+		var previous interface{}
+		if p.root == nil {
+			p.root = NewNode(ValueNode)
+			p.root.start = token.TokenStart
+			p.started = true
+			p.nodeStarted(nil, p.root)
+		} else {
+			previous = p.root.Value
+		}
+		p.root.Value = value
+		p.root.end = token.TokenEnd
+		p.nodeUpdated(nil, p.root, previous)
+		return
+	}
+
+	isNew := p.root == nil
+	var previous interface{}
+	if isNew {
+		p.root = NewNode(ValueNode)
+		p.root.start = token.TokenStart
+		p.started = true
+	} else {
+		previous = p.root.Value
+	}
+	p.root.Value = p.parseTokenValue(token)
+	p.root.Completed = true
+	p.root.end = token.TokenEnd
+	p.root.literal = p.numberLiteral(token)
+
+	if isNew {
+		p.nodeStarted(nil, p.root)
+	}
+	p.nodeCompleted(nil, p.root, previous)
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestScalarRoots(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"hello"`, "hello"},
+		{`"say \"hi\""`, `say "hi"`},
+		{"42 ", int64(42)},
+		{"-1.5\n", -1.5},
+		{"true", true},
+		{"false", false},
+		{"null", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			parser := NewStreamJSONParser(WithScalarRoots())
+			appendBytewise(parser, tt.input)
+
+			if !parser.IsCompleted() {
+				t.Fatalf("Expected scalar root to complete")
+			}
+			if root := parser.GetRoot(); root.Type != ValueNode || root.Value != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, root.Value)
+			}
+		})
+	}
+}
+
+func TestScalarRootStreamingString(t *testing.T) {
+	parser := NewStreamJSONParser(WithScalarRoots())
+
+	var updates []interface{}
+	parser.OnValue("", func(value interface{}, complete bool) {
+		updates = append(updates, value)
+	})
+
+	parser.Append(`"The answer`)
+	if parser.IsCompleted() {
+		t.Errorf("Expected streaming string not to be completed")
+	}
+	if root := parser.GetRoot(); root == nil || root.Value != "The answer" || root.Completed {
+		t.Errorf("Expected partial root 'The answer', got %v", root)
+	}
+
+	parser.Append(` is 42"`)
+	if !parser.IsCompleted() || parser.GetRoot().Value != "The answer is 42" {
+		t.Errorf("Expected completed root, got %v", parser.GetRoot().Value)
+	}
+	if len(updates) != 2 || updates[1] != "The answer is 42" {
+		t.Errorf("Expected partial and final callbacks, got %v", updates)
+	}
+}
+
+func TestScalarRootUnterminatedNumber(t *testing.T) {
+	parser := NewStreamJSONParser(WithScalarRoots())
+	parser.Append("42")
+
+	// More digits could follow
+	if parser.IsCompleted() || parser.GetRoot() != nil {
+		t.Errorf("Expected number root to wait for a terminator")
+	}
+}
+
+func TestScalarRootsMultipleDocuments(t *testing.T) {
+	parser := NewStreamJSONParser(WithScalarRoots(), WithMultipleDocuments())
+	parser.Append("\"a\"\n1\n{\"b\":true}\nnull\n")
+
+	documents := parser.Documents()
+	if len(documents) != 4 || documents[0] != "a" || documents[1] != int64(1) || documents[3] != nil {
+		t.Errorf("Expected four documents, got %v", documents)
+	}
+}
+
+func TestScalarRootsOptIn(t *testing.T) {
+	// Without the option leading prose and numbers are skipped
+	parser := NewStreamJSONParser()
+	parser.Append(`Here are 2 "items": {"a":1}`)
+
+	if parser.Get("a") != int64(1) {
+		t.Errorf("Expected object root, got %v", parser.GetRoot())
+	}
+}
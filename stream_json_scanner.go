@@ -0,0 +1,89 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNeedMoreInput is returned by Scanner.Next when the buffered input ends
+// before the next token is complete
+var ErrNeedMoreInput = errors.New("streamjson: need more input")
+
+// Scanner adapts the chunk-tolerant tokenizer to a pull-based scanner that
+// only yields complete tokens, so other decoders can use it as a front end
+type Scanner struct {
+	tokenizer *StreamJSONTokenizer
+	closed    bool // Whether the end of input has been signaled
+}
+
+// NewScanner creates a new scanner over an empty input
+func NewScanner() *Scanner {
+	return &Scanner{
+		tokenizer: NewStreamJSONTokenizer(),
+	}
+}
+
+// Append adds more content to the scanner. Input already returned by Next
+// is dropped first, so the buffer holds little more than the pending token.
+func (s *Scanner) Append(content string) {
+	t := s.tokenizer
+	if keep := t.consumed(); keep >= compactMinBytes && keep >= len(t.buffer)-keep {
+		t.compact(keep)
+	}
+	t.Append(content)
+}
+
+// Close signals that no more input will be appended. The tokenizer then
+// treats its buffer as the whole input, so a trailing number is emitted as
+// complete, a token cut off by the end is reported as io.ErrUnexpectedEOF,
+// and Next returns io.EOF at the end.
+func (s *Scanner) Close() {
+	s.closed = true
+	s.tokenizer.final = true // Tokens ending with the input are complete
+}
+
+// Next returns the kind and raw bytes of the next complete token.
+// It returns ErrNeedMoreInput when more input is required before the next
+// token is complete, io.EOF once the scanner is closed and drained, and
+// io.ErrUnexpectedEOF if the input was closed in the middle of a token.
+// The returned bytes alias the scanner's buffer and are only valid until
+// the next call to Append.
+func (s *Scanner) Next() (TokenType, []byte, error) {
+	token := s.tokenizer.NextToken()
+
+	if token.TokenType == EOF {
+		if s.closed {
+			return EOF, nil, io.EOF
+		}
+		return EOF, nil, ErrNeedMoreInput
+	}
+
+	raw := s.tokenizer.buffer[token.TokenStart-s.tokenizer.base : token.TokenEnd-s.tokenizer.base]
+
+	if !token.Completed {
+		if !s.closed {
+			return token.TokenType, nil, ErrNeedMoreInput
+		}
+
+		// Numbers are complete at the end of the final input, so this
+		// token was cut off
+		s.tokenizer.lastToken = nil
+		return Invalid, raw, io.ErrUnexpectedEOF
+	}
+
+	return token.TokenType, raw, nil
+}
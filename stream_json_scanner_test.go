@@ -0,0 +1,153 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestScannerChunkedInput(t *testing.T) {
+	scanner := NewScanner()
+
+	type scanned struct {
+		kind TokenType
+		raw  string
+	}
+	var tokens []scanned
+
+	drain := func() {
+		for {
+			kind, raw, err := scanner.Next()
+			if errors.Is(err, ErrNeedMoreInput) {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tokens = append(tokens, scanned{kind, string(raw)})
+		}
+	}
+
+	// Tokens split across chunks are only returned once complete
+	for _, chunk := range []string{`{"na`, `me":"Al`, `ice","n":1`, `2,"ok":tr`, `ue}`} {
+		scanner.Append(chunk)
+		drain()
+	}
+
+	expected := []scanned{
+		{ObjectStart, "{"}, {ObjectKey, `"name"`}, {Colon, ":"}, {String, `"Alice"`},
+		{Comma, ","}, {ObjectKey, `"n"`}, {Colon, ":"}, {Number, "12"},
+		{Comma, ","}, {ObjectKey, `"ok"`}, {Colon, ":"}, {Bool, "true"}, {ObjectEnd, "}"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %v", len(expected), len(tokens), tokens)
+	}
+	for i := range expected {
+		if tokens[i] != expected[i] {
+			t.Errorf("Token %d: expected %v, got %v", i, expected[i], tokens[i])
+		}
+	}
+
+	scanner.Close()
+	if _, _, err := scanner.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF after close, got %v", err)
+	}
+}
+
+func TestScannerCloseCompletesNumber(t *testing.T) {
+	scanner := NewScanner()
+	scanner.Append(`42`)
+
+	if _, _, err := scanner.Next(); !errors.Is(err, ErrNeedMoreInput) {
+		t.Errorf("Expected ErrNeedMoreInput for unterminated number, got %v", err)
+	}
+
+	scanner.Close()
+	kind, raw, err := scanner.Next()
+	if err != nil || kind != Number || string(raw) != "42" {
+		t.Errorf("Expected Number 42, got %v %q %v", kind, raw, err)
+	}
+
+	if _, _, err := scanner.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestScannerCloseFlushesTrailingNumber(t *testing.T) {
+	for _, input := range []string{`[1,-2.5e3`, `-2.5e3`} {
+		scanner := NewScanner()
+		scanner.Append(input)
+		scanner.Close()
+
+		var last []byte
+		for {
+			kind, raw, err := scanner.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error %v", input, err)
+			}
+			if kind == Number {
+				last = raw
+			}
+		}
+		if string(last) != "-2.5e3" {
+			t.Errorf("%s: trailing number = %q, want -2.5e3", input, last)
+		}
+	}
+}
+
+func TestScannerCloseMidToken(t *testing.T) {
+	for _, input := range []string{`"unterminated`, "\"caf\xc3", `tru`} {
+		scanner := NewScanner()
+		scanner.Append(input)
+		scanner.Close()
+
+		if _, _, err := scanner.Next(); err != io.ErrUnexpectedEOF {
+			t.Errorf("%q: expected io.ErrUnexpectedEOF, got %v", input, err)
+		}
+	}
+}
+
+func TestScannerBufferBounded(t *testing.T) {
+	scanner := NewScanner()
+	scanner.Append("[")
+
+	chunk := `{"id": 12345, "name": "streamjson", "tags": ["a", "b"]}, `
+	tokens := 0
+	for i := 0; i < 10000; i++ {
+		scanner.Append(chunk)
+		for {
+			_, _, err := scanner.Next()
+			if errors.Is(err, ErrNeedMoreInput) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tokens++
+		}
+	}
+
+	if tokens < 10000*17 {
+		t.Fatalf("Scanned %d tokens, want at least %d", tokens, 10000*17)
+	}
+	if size := len(scanner.tokenizer.buffer); size > 2*compactMinBytes+len(chunk) {
+		t.Errorf("Buffer holds %d bytes after %d bytes of input, want it bounded", size, 10000*len(chunk))
+	}
+}
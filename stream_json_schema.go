@@ -0,0 +1,591 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema used to validate documents while they
+// stream. It supports a subset of draft 2020-12: type, enum, const,
+// properties, required, additionalProperties, items, minItems, maxItems,
+// minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum and
+// exclusiveMaximum, plus the OpenAPI discriminator keyword with inline
+// schemas in its mapping. Other keywords are ignored.
+type Schema struct {
+	never bool // The false schema, which nothing satisfies
+
+	types     []string
+	enum      []interface{}
+	constant  interface{}
+	hasConst  bool
+	pattern   *regexp.Regexp
+	required  []string
+	minLength int
+	maxLength int
+	minItems  int
+	maxItems  int
+
+	properties   map[string]*Schema
+	additional   *Schema // nil allows any additional property
+	items        *Schema
+	minimum      *float64
+	maximum      *float64
+	exclusiveMin *float64
+	exclusiveMax *float64
+
+	discriminator string             // Property whose value selects a schema from mapping
+	mapping       map[string]*Schema // Schemas replacing this one, by discriminator value
+}
+
+// SchemaError describes a value that violates the schema
+type SchemaError struct {
+	Path    string // Dotted path of the value, "" for the root
+	Offset  int    // Input offset where the value starts
+	Message string // Description of the violation
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("streamjson: schema violation at %q (offset %d): %s", e.Path, e.Offset, e.Message)
+}
+
+// CompileSchema parses a JSON Schema document
+func CompileSchema(data []byte) (*Schema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	return compileSchema(raw, "")
+}
+
+// compileSchema builds a Schema from a decoded schema value at location
+func compileSchema(raw interface{}, location string) (*Schema, error) {
+	s := &Schema{minLength: -1, maxLength: -1, minItems: -1, maxItems: -1}
+	invalid := func(keyword string) error {
+		return fmt.Errorf("streamjson: invalid schema keyword %q at %q", keyword, location)
+	}
+
+	switch v := raw.(type) {
+	case bool:
+		s.never = !v
+		return s, nil
+	case map[string]interface{}:
+		for keyword, value := range v {
+			var err error
+			switch keyword {
+			case "type":
+				s.types, err = schemaTypes(value)
+			case "enum":
+				values, ok := value.([]interface{})
+				if !ok {
+					err = invalid(keyword)
+				}
+				s.enum = values
+			case "const":
+				s.constant, s.hasConst = value, true
+			case "pattern":
+				pattern, ok := value.(string)
+				if !ok {
+					err = invalid(keyword)
+					break
+				}
+				s.pattern, err = regexp.Compile(pattern)
+			case "required":
+				names, ok := value.([]interface{})
+				for _, name := range names {
+					key, isString := name.(string)
+					ok = ok && isString
+					s.required = append(s.required, key)
+				}
+				if !ok {
+					err = invalid(keyword)
+				}
+			case "minLength", "maxLength", "minItems", "maxItems":
+				n, ok := value.(float64)
+				if !ok || n < 0 || n != math.Trunc(n) {
+					err = invalid(keyword)
+					break
+				}
+				*s.limit(keyword) = int(n)
+			case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+				n, ok := value.(float64)
+				if !ok {
+					err = invalid(keyword)
+					break
+				}
+				*s.bound(keyword) = &n
+			case "properties":
+				properties, ok := value.(map[string]interface{})
+				if !ok {
+					err = invalid(keyword)
+					break
+				}
+				s.properties = make(map[string]*Schema, len(properties))
+				for name, property := range properties {
+					if s.properties[name], err = compileSchema(property, joinLocation(location, name)); err != nil {
+						break
+					}
+				}
+			case "additionalProperties":
+				s.additional, err = compileSchema(value, joinLocation(location, "*"))
+			case "items":
+				s.items, err = compileSchema(value, joinLocation(location, "*"))
+			case "discriminator":
+				err = s.compileDiscriminator(value, location)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("streamjson: schema at %q must be an object or a boolean", location)
+}
+
+// compileDiscriminator decodes the discriminator keyword, an object with
+// the name of the selecting property and a mapping from its values to schemas
+func (s *Schema) compileDiscriminator(value interface{}, location string) error {
+	invalid := fmt.Errorf("streamjson: invalid schema keyword %q at %q", "discriminator", location)
+	discriminator, ok := value.(map[string]interface{})
+	if !ok {
+		return invalid
+	}
+	name, ok := discriminator["propertyName"].(string)
+	mapping, isObject := discriminator["mapping"].(map[string]interface{})
+	if !ok || !isObject {
+		return invalid
+	}
+	s.discriminator = name
+	s.mapping = make(map[string]*Schema, len(mapping))
+	for tag, raw := range mapping {
+		schema, err := compileSchema(raw, joinLocation(location, tag))
+		if err != nil {
+			return err
+		}
+		s.mapping[tag] = schema
+	}
+	return nil
+}
+
+// resolve returns the schema that applies to node: the one mapped from the
+// value of its discriminator property once that value is complete, or s
+func (s *Schema) resolve(node *Node) *Schema {
+	if s == nil || s.mapping == nil || node == nil || node.Type != ObjectNode {
+		return s
+	}
+	tag := node.Children[s.discriminator]
+	if tag == nil || !tag.Completed {
+		return s
+	}
+	name, _ := tag.Value.(string)
+	if mapped, ok := s.mapping[name]; ok {
+		return mapped.resolve(node)
+	}
+	return s
+}
+
+// schemaTypes decodes the type keyword, a name or a list of names
+func schemaTypes(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		types := make([]string, 0, len(v))
+		for _, t := range v {
+			name, ok := t.(string)
+			if !ok {
+				return nil, fmt.Errorf("streamjson: invalid schema type %v", t)
+			}
+			types = append(types, name)
+		}
+		return types, nil
+	}
+	return nil, fmt.Errorf("streamjson: invalid schema type %v", value)
+}
+
+// limit returns the field holding a length or count keyword
+func (s *Schema) limit(keyword string) *int {
+	switch keyword {
+	case "minLength":
+		return &s.minLength
+	case "maxLength":
+		return &s.maxLength
+	case "minItems":
+		return &s.minItems
+	}
+	return &s.maxItems
+}
+
+// bound returns the field holding a numeric bound keyword
+func (s *Schema) bound(keyword string) **float64 {
+	switch keyword {
+	case "minimum":
+		return &s.minimum
+	case "maximum":
+		return &s.maximum
+	case "exclusiveMinimum":
+		return &s.exclusiveMin
+	}
+	return &s.exclusiveMax
+}
+
+// joinLocation appends a segment to a dotted schema location
+func joinLocation(location, segment string) string {
+	if location == "" {
+		return segment
+	}
+	return location + "." + segment
+}
+
+// child returns the schema for a member of a value matching s. A nil schema
+// accepts anything; ok is false if the member is not allowed at all.
+func (s *Schema) child(parent NodeType, key string) (child *Schema, ok bool) {
+	if s == nil {
+		return nil, true
+	}
+	if parent == ArrayNode {
+		return s.items, s.items == nil || !s.items.never
+	}
+	if property, found := s.properties[key]; found {
+		return property, !property.never
+	}
+	return s.additional, s.additional == nil || !s.additional.never
+}
+
+// SchemaErrors returns the schema violations found so far
+func (p *StreamJSONParser) SchemaErrors() []*SchemaError {
+	return p.schemaErrors
+}
+
+// schemaFor returns the schema for node at path and whether the node is
+// allowed by its parent's schema. Discriminators are resolved along the
+// path, but not on node itself.
+func (p *StreamJSONParser) schemaFor(path []string, node *Node) (*Schema, bool) {
+	// Containers along the path decide between properties and items, and
+	// hold the discriminators
+	parents := make([]*Node, len(path))
+	ancestor := node.Parent
+	for i := len(path) - 1; i >= 0 && ancestor != nil; i-- {
+		parents[i] = ancestor
+		ancestor = ancestor.Parent
+	}
+
+	schema := p.options.schema
+	if schema.never {
+		return nil, len(path) > 0
+	}
+	for i, key := range path {
+		var allowed bool
+		var parentType NodeType
+		if parents[i] != nil {
+			parentType = parents[i].Type
+		}
+		schema, allowed = schema.resolve(parents[i]).child(parentType, key)
+		if !allowed {
+			// Only the outermost disallowed node is reported
+			return nil, i < len(path)-1
+		}
+		if schema == nil {
+			return nil, true
+		}
+	}
+	return schema, true
+}
+
+// validateStarted checks a node as soon as it is added: whether its parent
+// allows it and, for objects and arrays, whether the type is allowed
+func (p *StreamJSONParser) validateStarted(path []string, node *Node) {
+	schema, allowed := p.schemaFor(path, node)
+	if !allowed {
+		if len(path) > 0 && node.Parent != nil && node.Parent.Type == ObjectNode {
+			p.schemaViolation(path, node, "property %q is not allowed", path[len(path)-1])
+		} else {
+			p.schemaViolation(path, node, "value is not allowed")
+		}
+		return
+	}
+	if schema == nil || node.Type == ValueNode {
+		return
+	}
+	p.checkType(path, node, schema)
+}
+
+// validateCompleted checks the constraints that need the whole value
+func (p *StreamJSONParser) validateCompleted(path []string, node *Node) {
+	schema, allowed := p.schemaFor(path, node)
+	if schema == nil || !allowed {
+		return
+	}
+	if schema.mapping != nil && node.Type == ObjectNode {
+		if schema = p.checkDiscriminated(path, node, schema); schema == nil {
+			return
+		}
+	}
+
+	switch node.Type {
+	case ObjectNode:
+		var missing []string
+		for _, name := range schema.required {
+			if _, ok := node.Children[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			p.schemaViolation(path, node, "missing required properties %s", strings.Join(missing, ", "))
+		}
+
+	case ArrayNode:
+		if schema.minItems >= 0 && len(node.Array) < schema.minItems {
+			p.schemaViolation(path, node, "expected at least %d items, got %d", schema.minItems, len(node.Array))
+		}
+		if schema.maxItems >= 0 && len(node.Array) > schema.maxItems {
+			p.schemaViolation(path, node, "expected at most %d items, got %d", schema.maxItems, len(node.Array))
+		}
+
+	case ValueNode:
+		if !p.checkType(path, node, schema) {
+			return
+		}
+		p.checkScalar(path, node, schema)
+	}
+
+	if schema.enum != nil || schema.hasConst {
+		value := normalizeSchemaValue(p.collectNodeValue(node))
+		if schema.hasConst && !equalSchemaValues(value, schema.constant) {
+			p.schemaViolation(path, node, "value does not match const")
+		}
+		if schema.enum != nil && !containsSchemaValue(schema.enum, value) {
+			p.schemaViolation(path, node, "value is not one of the enum values")
+		}
+	}
+}
+
+// checkDiscriminated returns the schema mapped from the discriminator of a
+// completed object, or nil after reporting a value with no schema. Members
+// that came before the discriminator were checked against schema, so they
+// are checked again against the mapped one.
+func (p *StreamJSONParser) checkDiscriminated(path []string, node *Node, schema *Schema) *Schema {
+	tag := node.Children[schema.discriminator]
+	if tag == nil {
+		return schema // required reports a missing discriminator
+	}
+	mapped := schema.resolve(node)
+	if mapped == schema {
+		p.schemaViolation(path, node, "no schema for %s %v", schema.discriminator, tag.Value)
+		return nil
+	}
+
+	var earlier []string
+	for key, child := range node.Children {
+		if child.start <= tag.start {
+			earlier = append(earlier, key)
+		}
+	}
+	slices.SortFunc(earlier, func(a, b string) int { return node.Children[a].start - node.Children[b].start })
+	for _, key := range earlier {
+		p.revalidate(append(slices.Clip(path), key), node.Children[key])
+	}
+	return mapped
+}
+
+// revalidate checks a completed node and everything inside it again
+func (p *StreamJSONParser) revalidate(path []string, node *Node) {
+	p.validateStarted(path, node)
+	switch node.Type {
+	case ObjectNode:
+		keys := make([]string, 0, len(node.Children))
+		for key := range node.Children {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, func(a, b string) int { return node.Children[a].start - node.Children[b].start })
+		for _, key := range keys {
+			p.revalidate(append(slices.Clip(path), key), node.Children[key])
+		}
+	case ArrayNode:
+		for i, child := range node.Array {
+			p.revalidate(append(slices.Clip(path), strconv.Itoa(i)), child)
+		}
+	}
+	p.validateCompleted(path, node)
+}
+
+// checkType reports a node whose type is not allowed by schema
+func (p *StreamJSONParser) checkType(path []string, node *Node, schema *Schema) bool {
+	if len(schema.types) == 0 {
+		return true
+	}
+	actual := schemaType(node)
+	for _, t := range schema.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+		if t == "integer" && actual == "number" && isIntegralNumber(node.Value) {
+			return true
+		}
+	}
+	p.schemaViolation(path, node, "expected %s, got %s", strings.Join(schema.types, " or "), actual)
+	return false
+}
+
+// checkScalar applies the string and number keywords to a value node
+func (p *StreamJSONParser) checkScalar(path []string, node *Node, schema *Schema) {
+	switch v := node.Value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if schema.minLength >= 0 && length < schema.minLength {
+			p.schemaViolation(path, node, "expected at least %d characters, got %d", schema.minLength, length)
+		}
+		if schema.maxLength >= 0 && length > schema.maxLength {
+			p.schemaViolation(path, node, "expected at most %d characters, got %d", schema.maxLength, length)
+		}
+		if schema.pattern != nil && !schema.pattern.MatchString(v) {
+			p.schemaViolation(path, node, "does not match pattern %q", schema.pattern.String())
+		}
+
+	default:
+		n, ok := numberToFloat64(v)
+		if !ok {
+			return
+		}
+		if schema.minimum != nil && n < *schema.minimum {
+			p.schemaViolation(path, node, "expected at least %v, got %v", *schema.minimum, v)
+		}
+		if schema.maximum != nil && n > *schema.maximum {
+			p.schemaViolation(path, node, "expected at most %v, got %v", *schema.maximum, v)
+		}
+		if schema.exclusiveMin != nil && n <= *schema.exclusiveMin {
+			p.schemaViolation(path, node, "expected more than %v, got %v", *schema.exclusiveMin, v)
+		}
+		if schema.exclusiveMax != nil && n >= *schema.exclusiveMax {
+			p.schemaViolation(path, node, "expected less than %v, got %v", *schema.exclusiveMax, v)
+		}
+	}
+}
+
+// schemaType returns the JSON Schema type name of a node
+func schemaType(node *Node) string {
+	switch node.Type {
+	case ObjectNode:
+		return "object"
+	case ArrayNode:
+		return "array"
+	}
+	switch node.Value.(type) {
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	if isIntegralNumber(node.Value) {
+		return "integer"
+	}
+	return "number"
+}
+
+// normalizeSchemaValue converts numbers to float64 so parsed values compare
+// equal to values decoded from the schema
+func normalizeSchemaValue(value interface{}) interface{} {
+	if isNumberValue(value) {
+		f, _ := numberToFloat64(value)
+		return f
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			v[key] = normalizeSchemaValue(child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalizeSchemaValue(child)
+		}
+	}
+	return value
+}
+
+// containsSchemaValue reports whether value is one of values
+func containsSchemaValue(values []interface{}, value interface{}) bool {
+	for _, candidate := range values {
+		if equalSchemaValues(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalSchemaValues compares normalized values without reflection, which
+// is left to values a transformer produced
+func equalSchemaValues(a, b interface{}) bool {
+	switch x := a.(type) {
+	case nil:
+		return b == nil
+	case string:
+		y, ok := b.(string)
+		return ok && x == y
+	case float64:
+		y, ok := b.(float64)
+		return ok && x == y
+	case bool:
+		y, ok := b.(bool)
+		return ok && x == y
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, found := y[key]
+			if !found || !equalSchemaValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalSchemaValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// schemaViolation records a SchemaError for node
+func (p *StreamJSONParser) schemaViolation(path []string, node *Node, format string, args ...interface{}) {
+	p.schemaErrors = append(p.schemaErrors, &SchemaError{
+		Path:    strings.Join(path, "."),
+		Offset:  node.start,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"slices"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["name", "tags"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"address": {"type": "object", "properties": {"zip": {"type": "string"}}}
+	}
+}`
+
+func mustCompileSchema(t *testing.T, data string) *Schema {
+	t.Helper()
+	schema, err := CompileSchema([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to compile schema: %v", err)
+	}
+	return schema
+}
+
+func TestSchemaValidDocument(t *testing.T) {
+	parser := NewStreamJSONParser(WithSchema(mustCompileSchema(t, testSchema)))
+	appendBytewise(parser, `{"name":"Alice","age":30.0,"role":"admin","tags":["a","b"],"address":{"zip":"75001"}}`)
+
+	if errs := parser.SchemaErrors(); len(errs) != 0 {
+		t.Errorf("Expected no violations, got %v", errs[0])
+	}
+}
+
+func TestSchemaViolations(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		path    string
+		message string
+	}{
+		{"wrong type", `{"name":42,"tags":[]}`, "name", "expected string, got integer"},
+		{"too short", `{"name":"A","tags":[]}`, "name", "expected at least 2 characters, got 1"},
+		{"pattern", `{"name":"alice","tags":[]}`, "name", `does not match pattern "^[A-Z]"`},
+		{"not integer", `{"name":"Al","age":1.5,"tags":[]}`, "age", "expected integer, got number"},
+		{"minimum", `{"name":"Al","age":-1,"tags":[]}`, "age", "expected at least 0, got -1"},
+		{"exclusive maximum", `{"name":"Al","age":150,"tags":[]}`, "age", "expected less than 150, got 150"},
+		{"enum", `{"name":"Al","role":"root","tags":[]}`, "role", "value is not one of the enum values"},
+		{"max items", `{"name":"Al","tags":["a","b","c"]}`, "tags", "expected at most 2 items, got 3"},
+		{"item type", `{"name":"Al","tags":["a",true]}`, "tags.1", "expected string, got boolean"},
+		{"container type", `{"name":"Al","tags":{}}`, "tags", "expected array, got object"},
+		{"additional property", `{"name":"Al","extra":{"a":1},"tags":[]}`, "extra", `property "extra" is not allowed`},
+		{"required", `{"name":"Al"}`, "", "missing required properties tags"},
+		{"nested", `{"name":"Al","tags":[],"address":{"zip":75001}}`, "address.zip", "expected string, got integer"},
+	}
+
+	schema := mustCompileSchema(t, testSchema)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewStreamJSONParser(WithSchema(schema))
+			parser.Append(tt.input)
+
+			errs := parser.SchemaErrors()
+			if len(errs) != 1 {
+				t.Fatalf("Expected one violation, got %d: %v", len(errs), errs)
+			}
+			if errs[0].Path != tt.path || errs[0].Message != tt.message {
+				t.Errorf("Expected %q at %q, got %q at %q", tt.message, tt.path, errs[0].Message, errs[0].Path)
+			}
+		})
+	}
+}
+
+func TestSchemaReportsEarly(t *testing.T) {
+	parser := NewStreamJSONParser(WithSchema(mustCompileSchema(t, testSchema)))
+
+	// The violation is visible before the document completes
+	parser.Append(`{"name":"Al","extra":{"long":"`)
+
+	errs := parser.SchemaErrors()
+	if len(errs) != 1 || errs[0].Path != "extra" {
+		t.Fatalf("Expected early violation for extra, got %v", errs)
+	}
+	if errs[0].Offset != 21 {
+		t.Errorf("Expected offset 21, got %d", errs[0].Offset)
+	}
+}
+
+func TestCompileSchemaErrors(t *testing.T) {
+	for _, data := range []string{`[`, `42`, `{"type": 1}`, `{"minLength": -1}`, `{"pattern": "("}`, `{"properties": {"a": 1}}`,
+		`{"discriminator": "type"}`, `{"discriminator": {"propertyName": "type"}}`, `{"discriminator": {"propertyName": "t", "mapping": {"a": 1}}}`} {
+		if _, err := CompileSchema([]byte(data)); err == nil {
+			t.Errorf("Expected error for %s", data)
+		}
+	}
+}
+
+const testDiscriminatedSchema = `{
+	"type": "array",
+	"items": {
+		"type": "object",
+		"required": ["type"],
+		"discriminator": {
+			"propertyName": "type",
+			"mapping": {
+				"text": {"required": ["text"], "additionalProperties": false, "properties": {"type": {}, "text": {"type": "string"}}},
+				"image": {"required": ["url"], "properties": {"url": {"type": "string", "pattern": "^https://"}}}
+			}
+		}
+	}
+}`
+
+func TestSchemaDiscriminator(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errors []string // Path and message of each violation
+	}{
+		{"valid", `[{"type":"text","text":"hi"},{"type":"image","url":"https://x"}]`, nil},
+		{"element shape", `[{"type":"text","text":1},{"type":"image","url":"http://x"}]`,
+			[]string{`0.text: expected string, got integer`, `1.url: does not match pattern "^https://"`}},
+		{"disallowed member", `[{"type":"text","text":"a","url":"b"}]`, []string{`0.url: property "url" is not allowed`}},
+		{"missing member", `[{"type":"image"}]`, []string{`0: missing required properties url`}},
+		{"discriminator last", `[{"text":2,"type":"text"}]`, []string{`0.text: expected string, got integer`}},
+		{"unknown value", `[{"type":"video"}]`, []string{`0: no schema for type video`}},
+		{"missing discriminator", `[{"text":"a"}]`, []string{`0: missing required properties type`}},
+	}
+
+	schema := mustCompileSchema(t, testDiscriminatedSchema)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewStreamJSONParser(WithSchema(schema))
+			appendBytewise(parser, tt.input)
+
+			var got []string
+			for _, err := range parser.SchemaErrors() {
+				got = append(got, err.Path+": "+err.Message)
+			}
+			if !slices.Equal(got, tt.errors) {
+				t.Errorf("violations = %q, want %q", got, tt.errors)
+			}
+		})
+	}
+}
+
+func TestSchemaDiscriminatorReportsEarly(t *testing.T) {
+	parser := NewStreamJSONParser(WithSchema(mustCompileSchema(t, testDiscriminatedSchema)))
+	parser.Append(`[{"type":"text","extra":"unfinish`)
+
+	errs := parser.SchemaErrors()
+	if len(errs) != 1 || errs[0].Path != "0.extra" {
+		t.Fatalf("violations = %v, want one at 0.extra before the element completes", errs)
+	}
+}
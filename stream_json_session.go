@@ -0,0 +1,129 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// Session parses a sequence of JSON documents arriving over one connection,
+// such as the structured output of each turn in a multi-turn agent conversation.
+// Completed documents are kept in a bounded history that can be queried.
+type Session struct {
+	current      *StreamJSONParser   // Document currently being parsed
+	history      []*StreamJSONParser // Completed documents, oldest first
+	sizes        []int               // Input bytes of each document in history
+	historyBytes int                 // Raw input bytes retained by history
+	completed    int                 // Total documents completed, including evicted ones
+	maxDocuments int                 // Maximum number of retained documents, 0 for no limit
+	maxBytes     int                 // Maximum raw bytes retained by history, 0 for no limit
+}
+
+// NewSession creates a new session. maxDocuments and maxBytes cap the retained
+// history; zero disables the respective limit. The most recently completed
+// document is always retained.
+func NewSession(maxDocuments, maxBytes int) *Session {
+	return &Session{
+		current:      newSessionParser(),
+		maxDocuments: maxDocuments,
+		maxBytes:     maxBytes,
+	}
+}
+
+// Append adds more content to the session. Every root that completes is moved
+// into the history and parsing continues with the next document.
+func (s *Session) Append(content string) {
+	s.current.Append(content)
+
+	for s.current.IsCompleted() {
+		tokenizer := s.current.tokenizer
+		rest := string(tokenizer.buffer[tokenizer.position:])
+		size := tokenizer.base + tokenizer.position
+
+		// Keep only the document's own bytes in the archived parser
+		tokenizer.buffer = append([]byte(nil), tokenizer.buffer[:tokenizer.position]...)
+		s.archive(s.current, size)
+
+		s.current = newSessionParser()
+		s.current.Append(rest)
+	}
+}
+
+// newSessionParser creates the parser of a session document. It retains its
+// input, so compaction does not release the text archived with it.
+func newSessionParser() *StreamJSONParser {
+	return NewStreamJSONParser(WithRetainedInput())
+}
+
+// archive adds a completed document of size input bytes to the history and
+// enforces the limits
+func (s *Session) archive(parser *StreamJSONParser, size int) {
+	s.history = append(s.history, parser)
+	s.sizes = append(s.sizes, size)
+	s.historyBytes += size
+	s.completed++
+
+	for len(s.history) > 1 &&
+		((s.maxDocuments > 0 && len(s.history) > s.maxDocuments) ||
+			(s.maxBytes > 0 && s.historyBytes > s.maxBytes)) {
+		s.historyBytes -= s.sizes[0]
+		s.history[0] = nil
+		s.history = s.history[1:]
+		s.sizes = s.sizes[1:]
+	}
+}
+
+// Current returns the parser for the document currently being streamed
+func (s *Session) Current() *StreamJSONParser {
+	return s.current
+}
+
+// Len returns the number of completed documents retained in the history
+func (s *Session) Len() int {
+	return len(s.history)
+}
+
+// Completed returns the total number of documents completed in the session,
+// including those evicted from the history
+func (s *Session) Completed() int {
+	return s.completed
+}
+
+// Document returns the i-th retained document, oldest first.
+// Negative indices count back from the most recent document.
+func (s *Session) Document(i int) *StreamJSONParser {
+	if i < 0 {
+		i += len(s.history)
+	}
+	if i < 0 || i >= len(s.history) {
+		return nil
+	}
+	return s.history[i]
+}
+
+// Get retrieves a value from the i-th retained document using a path of keys
+func (s *Session) Get(i int, keys ...string) interface{} {
+	document := s.Document(i)
+	if document == nil {
+		return nil
+	}
+	return document.Get(keys...)
+}
+
+// GetAll retrieves the value at a path from every retained document, oldest first.
+// Documents without the path contribute nil.
+func (s *Session) GetAll(keys ...string) []interface{} {
+	values := make([]interface{}, len(s.history))
+	for i, document := range s.history {
+		values[i] = document.Get(keys...)
+	}
+	return values
+}
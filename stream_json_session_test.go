@@ -0,0 +1,105 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSessionMultipleDocuments(t *testing.T) {
+	session := NewSession(0, 0)
+
+	session.Append(`{"turn":1,"answer":"hi"}`)
+	session.Append("\n")
+	session.Append(`{"turn":2,"ans`)
+
+	if session.Len() != 1 {
+		t.Errorf("Expected 1 completed document, got %d", session.Len())
+	}
+
+	if session.Get(0, "answer") != "hi" {
+		t.Errorf("Expected first answer to be 'hi', got %v", session.Get(0, "answer"))
+	}
+
+	if session.Current().Get("turn") != int64(2) {
+		t.Errorf("Expected current turn to be 2, got %v", session.Current().Get("turn"))
+	}
+
+	// Several documents in a single chunk
+	session.Append(`wer":"there"} {"turn":3}[4]`)
+
+	if session.Len() != 4 {
+		t.Errorf("Expected 4 completed documents, got %d", session.Len())
+	}
+
+	turns := session.GetAll("turn")
+	if len(turns) != 4 || turns[0] != int64(1) || turns[1] != int64(2) || turns[2] != int64(3) || turns[3] != nil {
+		t.Errorf("Expected turns [1 2 3 <nil>], got %v", turns)
+	}
+
+	if session.Get(-1, "0") != int64(4) {
+		t.Errorf("Expected last document to be [4], got %v", session.Get(-1))
+	}
+}
+
+func TestSessionHistoryLimits(t *testing.T) {
+	session := NewSession(2, 0)
+	session.Append(`{"n":1}{"n":2}{"n":3}`)
+
+	if session.Len() != 2 || session.Completed() != 3 {
+		t.Errorf("Expected 2 retained of 3 completed, got %d of %d", session.Len(), session.Completed())
+	}
+
+	if session.Get(0, "n") != int64(2) {
+		t.Errorf("Expected oldest retained n to be 2, got %v", session.Get(0, "n"))
+	}
+
+	// Byte cap keeps only what fits, but always the latest document
+	session = NewSession(0, 16)
+	session.Append(`{"n":1} {"n":2} {"big":"0123456789abcdef"}`)
+
+	if session.Len() != 1 || session.Get(0, "big") != "0123456789abcdef" {
+		t.Errorf("Expected only the latest document to be retained, got %d", session.Len())
+	}
+
+	if session.Document(5) != nil {
+		t.Errorf("Expected nil for out of range document")
+	}
+}
+
+func TestSessionHistoryBytesAfterCompaction(t *testing.T) {
+	session := NewSession(0, 1000)
+	large := `{"text":"` + strings.Repeat("x", 5000) + `"}`
+
+	session.Append(large)
+	session.Append(large)
+	session.Append(`{"small":true}`)
+
+	if session.Len() != 1 {
+		t.Errorf("Expected the large documents to be evicted, got %d documents", session.Len())
+	}
+	if session.Get(-1, "small") != true {
+		t.Errorf("Expected the small document to be retained, got %v", session.Get(-1, "small"))
+	}
+
+	session = NewSession(0, 0)
+	for i := 0; i < len(large); i += 100 {
+		session.Append(large[i:min(i+100, len(large))])
+	}
+	if raw := session.Document(0).GetRaw(); string(raw) != large {
+		t.Errorf("Expected the archived document's text, got %d bytes", len(raw))
+	}
+}
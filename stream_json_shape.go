@@ -0,0 +1,119 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// checkShapeStarted stops parsing if the object or array starting at path
+// cannot be stored into the shape's type at that path
+func (p *StreamJSONParser) checkShapeStarted(path []string, node *Node) bool {
+	t, ok := p.shapeAt(path)
+	if !ok {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 || isUnmarshaler(t) {
+		return true
+	}
+
+	switch {
+	case node.Type == ObjectNode && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map && t.Key().Kind() == reflect.String):
+		return true
+	case node.Type == ArrayNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		return true
+	}
+
+	jsonType := "object"
+	if node.Type == ArrayNode {
+		jsonType = "array"
+	}
+	d := nodeDecoder{parser: p, base: path}
+	d.typeError(jsonType, t)
+	return p.shapeMismatch(d.err, node)
+}
+
+// checkShapeCompleted stops parsing if the value completed at path cannot be
+// stored into the shape's type at that path, decoding it as Unmarshal would
+func (p *StreamJSONParser) checkShapeCompleted(path []string, node *Node) bool {
+	t, ok := p.shapeAt(path)
+	if !ok {
+		return true
+	}
+	d := nodeDecoder{parser: p}
+	d.decode(node, reflect.New(t).Elem(), path)
+	return p.shapeMismatch(d.err, node)
+}
+
+// shapeMismatch records err, if any, as the error that stops parsing, at the
+// offset where node starts
+func (p *StreamJSONParser) shapeMismatch(err error, node *Node) bool {
+	if err == nil {
+		return true
+	}
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+		typeErr.Offset = int64(node.start)
+	}
+	if p.err == nil {
+		p.err = err
+	}
+	return false
+}
+
+// shapeAt returns the type the value at path decodes into. It returns false
+// for paths Unmarshal ignores, such as unknown keys, and for values inside
+// interfaces and types that decode themselves, which accept anything, or
+// inside mismatched containers, which are reported when they start.
+func (p *StreamJSONParser) shapeAt(path []string) (reflect.Type, bool) {
+	t := p.options.shape
+	for _, key := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if isUnmarshaler(t) {
+			return nil, false
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := p.fieldForKey(t, key)
+			if !ok {
+				return nil, false
+			}
+			t = t.FieldByIndex(field.index).Type
+
+		case reflect.Map:
+			t = t.Elem()
+
+		case reflect.Slice:
+			t = t.Elem()
+
+		case reflect.Array:
+			if index, err := strconv.Atoi(key); err != nil || index >= t.Len() {
+				return nil, false
+			}
+			t = t.Elem()
+
+		default:
+			return nil, false
+		}
+	}
+	return t, true
+}
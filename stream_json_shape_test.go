@@ -0,0 +1,129 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type shapeReply struct {
+	Title string            `json:"title"`
+	Count int               `json:"count"`
+	Tags  []string          `json:"tags"`
+	Items []shapeItem       `json:"items"`
+	Meta  map[string]int    `json:"meta"`
+	Any   interface{}       `json:"any"`
+	Next  *shapeReply       `json:"next"`
+	Fixed [1]bool           `json:"fixed"`
+	Names map[string]string `json:"names"`
+}
+
+type shapeItem struct {
+	ID int `json:"id"`
+}
+
+func TestShapeAcceptsMatchingDocument(t *testing.T) {
+	input := `{"title": "ok", "count": 3, "tags": ["a"], "items": [{"id": 1, "extra": "x"}], ` +
+		`"meta": {"a": 1}, "any": {"deep": [true]}, "next": {"count": null}, "fixed": [true, "ignored"], ` +
+		`"unknown": {"anything": 1}}`
+
+	parser := NewStreamJSONParser(WithShape(&shapeReply{}))
+	appendBytewise(parser, input)
+
+	if parser.Err() != nil || !parser.IsCompleted() {
+		t.Errorf("Expected the document to parse, got %v", parser.Err())
+	}
+}
+
+func TestShapeMismatches(t *testing.T) {
+	tests := []struct {
+		input    string
+		field    string
+		jsonType string
+		target   reflect.Type
+		offset   int64
+	}{
+		{`{"count": "three"`, "count", "string", reflect.TypeOf(0), 10},
+		{`{"count": 1.5,`, "count", "number", reflect.TypeOf(0), 10},
+		{`{"tags": {`, "tags", "object", reflect.TypeOf([]string{}), 9},
+		{`{"items": [{"id": 1}, {"id": "2"`, "items.1.id", "string", reflect.TypeOf(0), 29},
+		{`{"meta": {"a": 1, "b": "two"`, "meta.b", "string", reflect.TypeOf(0), 23},
+		{`{"next": {"title": [`, "next.title", "array", reflect.TypeOf(""), 19},
+		{`{"names": {"a": 7,`, "names.a", "number", reflect.TypeOf(""), 16},
+	}
+
+	for _, test := range tests {
+		parser := NewStreamJSONParser(WithShape(shapeReply{}))
+		parser.Append(test.input)
+
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(parser.Err(), &typeErr) {
+			t.Errorf("%s: expected UnmarshalTypeError, got %v", test.input, parser.Err())
+			continue
+		}
+		if typeErr.Field != test.field || typeErr.Value != test.jsonType || typeErr.Type != test.target {
+			t.Errorf("%s: unexpected error %+v", test.input, typeErr)
+		}
+		if typeErr.Offset != test.offset {
+			t.Errorf("%s: expected offset %d, got %d", test.input, test.offset, typeErr.Offset)
+		}
+	}
+}
+
+func TestShapeStopsParsing(t *testing.T) {
+	parser := NewStreamJSONParser(WithShape(shapeReply{}))
+	parser.Append(`{"tags": {"a": 1}, "title": "late"}`)
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(parser.Err(), &typeErr) || typeErr.Offset != 9 {
+		t.Fatalf("Expected a mismatch at offset 9, got %v", parser.Err())
+	}
+	if parser.Get("title") != nil {
+		t.Errorf("Expected parsing to stop at the mismatch, got %v", parser.Get())
+	}
+}
+
+func TestShapeChecksTransformedValues(t *testing.T) {
+	type event struct {
+		Count int `json:"count"`
+	}
+
+	parser := NewStreamJSONParser(WithShape(event{}))
+	parser.Transform("count", func(value interface{}) (interface{}, error) {
+		return int64(len(value.(string))), nil
+	})
+	parser.Append(`{"count": "abc"}`)
+
+	if parser.Err() != nil || parser.Get("count") != int64(3) {
+		t.Errorf("Expected the transformed value to fit, got %v, %v", parser.Get(), parser.Err())
+	}
+}
+
+func TestShapeUnmarshalers(t *testing.T) {
+	parser := NewStreamJSONParser(WithShape(unmarshalInvoice{}))
+	parser.Append(`{"raw": {"x": [1, {"y": 2}]}, "lines": [1.5], "due": "2025-01-31"}`)
+	if err := parser.Err(); err != nil {
+		t.Fatalf("Expected values of unmarshalers to fit, got %v", err)
+	}
+
+	parser = NewStreamJSONParser(WithShape(unmarshalInvoice{}))
+	parser.Append(`{"due": "soon", "total": 1}`)
+	if err := parser.Err(); err == nil || err.Error() != "date: invalid soon" {
+		t.Errorf("Expected the error of UnmarshalText, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+)
+
+// Value is an immutable copy of a document taken by Snapshot. It shares
+// nothing with the parser, so it can be read from any goroutine while
+// content is still being appended, and its accessors return copies so
+// callers cannot change it either.
+type Value struct {
+	value     interface{}
+	normalize func(key string) string // Key normalizer of the parser, for lookups
+}
+
+// Snapshot returns a deep copy of the current document. Unlike Get, whose
+// result shares *big.Float numbers and the values set by transformers with
+// the tree, nothing in it changes as parsing continues. Transformer results
+// other than objects and arrays are assumed to be immutable.
+func (p *StreamJSONParser) Snapshot() Value {
+	defer p.guardRead("Snapshot")()
+	return Value{value: deepCopy(p.Get()), normalize: p.options.keyNormalizer}
+}
+
+// Get returns a copy of the value at a path of keys and array indices, or
+// nil if the path does not exist
+func (v Value) Get(keys ...string) interface{} {
+	value := v.value
+	for _, key := range keys {
+		switch container := value.(type) {
+		case map[string]interface{}:
+			if v.normalize != nil && !isIndex(key) {
+				key = v.normalize(key)
+			}
+			value = container[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil
+			}
+			value = container[index]
+		default:
+			return nil
+		}
+	}
+	return deepCopy(value)
+}
+
+// Interface returns a copy of the whole document
+func (v Value) Interface() interface{} {
+	return deepCopy(v.value)
+}
+
+// MarshalJSON encodes the document, writing *big.Float numbers as numbers
+// like StreamJSONParser.MarshalJSON does
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bigFloatsAsNumbers(v.value))
+}
+
+// deepCopy copies the objects, arrays and *big.Float numbers in a
+// materialized value, sharing the rest
+func deepCopy(value interface{}) interface{} {
+	return copyValue(value, func(leaf interface{}) interface{} {
+		if v, ok := leaf.(*big.Float); ok && v != nil {
+			return new(big.Float).Copy(v)
+		}
+		return leaf
+	})
+}
+
+// bigFloatsAsNumbers returns value with *big.Float numbers replaced by
+// json.Number, which encoding/json writes unquoted
+func bigFloatsAsNumbers(value interface{}) interface{} {
+	return copyValue(value, func(leaf interface{}) interface{} {
+		if v, ok := leaf.(*big.Float); ok && v != nil {
+			return json.Number(v.Text('g', -1))
+		}
+		return leaf
+	})
+}
+
+// copyValue returns a copy of a materialized value with new maps and slices
+// and every other value replaced by leaf. Nested values are copied with an
+// explicit stack, so deep nesting does not grow the goroutine stack.
+func copyValue(value interface{}, leaf func(interface{}) interface{}) interface{} {
+	type copyTask struct{ from, to interface{} }
+
+	shallow := func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			return make(map[string]interface{}, len(v))
+		case []interface{}:
+			return make([]interface{}, len(v))
+		}
+		return leaf(value)
+	}
+
+	result := shallow(value)
+	stack := []copyTask{{value, result}}
+	for len(stack) > 0 {
+		task := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch from := task.from.(type) {
+		case map[string]interface{}:
+			to := task.to.(map[string]interface{})
+			for key, child := range from {
+				to[key] = shallow(child)
+				stack = append(stack, copyTask{child, to[key]})
+			}
+		case []interface{}:
+			to := task.to.([]interface{})
+			for i, child := range from {
+				to[i] = shallow(child)
+				stack = append(stack, copyTask{child, to[i]})
+			}
+		}
+	}
+	return result
+}
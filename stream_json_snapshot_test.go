@@ -0,0 +1,98 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestSnapshotIsIndependent(t *testing.T) {
+	parser := NewStreamJSONParser(WithNumberMode(NumberAsBigFloat))
+	parser.Transform("meta", func(value interface{}) (interface{}, error) {
+		return map[string]interface{}{"wrapped": value}, nil
+	})
+	parser.Append(`{"meta":"a","amount":1.5,"items":[{"name":"Al`)
+
+	snapshot := parser.Snapshot()
+	parser.Append(`ice"},{"name":"Bob"}]}`)
+
+	if snapshot.Get("items", "0", "name") != "Al" {
+		t.Errorf("Expected the snapshot to keep the partial name, got %v", snapshot.Get("items", "0", "name"))
+	}
+	if items := snapshot.Get("items").([]interface{}); len(items) != 1 {
+		t.Errorf("Expected one item in the snapshot, got %v", items)
+	}
+
+	// Changing what the accessors return changes neither the snapshot nor the tree
+	snapshot.Get("meta").(map[string]interface{})["wrapped"] = "changed"
+	snapshot.Get("amount").(*big.Float).SetInt64(7)
+	if snapshot.Get("meta", "wrapped") != "a" {
+		t.Errorf("Expected the snapshot to be immutable, got %v", snapshot.Get("meta", "wrapped"))
+	}
+	if amount := snapshot.Get("amount").(*big.Float).String(); amount != "1.5" {
+		t.Errorf("Expected the snapshot amount to be 1.5, got %v", amount)
+	}
+	if parser.Get("meta").(map[string]interface{})["wrapped"] != "a" {
+		t.Errorf("Expected the tree to be unchanged, got %v", parser.Get("meta"))
+	}
+
+	if data, err := snapshot.MarshalJSON(); err != nil || string(data) != `{"amount":1.5,"items":[{"name":"Al"}],"meta":{"wrapped":"a"}}` {
+		t.Errorf("Unexpected snapshot JSON %s, %v", data, err)
+	}
+	if snapshot.Get("items", "1") != nil || snapshot.Get("missing", "x") != nil {
+		t.Errorf("Expected missing paths to be nil")
+	}
+}
+
+func TestSnapshotNormalizesKeys(t *testing.T) {
+	parser := NewStreamJSONParser(WithKeyNormalizer(SnakeCaseKey))
+	parser.Append(`{"userName":"al","tags":["x"]}`)
+
+	snapshot := parser.Snapshot()
+	if snapshot.Get("userName") != "al" || snapshot.Get("user_name") != "al" || snapshot.Get("tags", "0") != "x" {
+		t.Errorf("Expected normalized lookups, got %v", snapshot.Interface())
+	}
+}
+
+func TestSnapshotConcurrentRead(t *testing.T) {
+	parser := NewSafeStreamJSONParser()
+	parser.Append(`{"log":[`)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			parser.Append(`{"n":1,"tags":["a","b"]},`)
+		}
+		parser.Append(`{}]}`)
+	}()
+
+	for i := 0; i < 50; i++ {
+		snapshot := parser.Snapshot()
+		if log, ok := snapshot.Get("log").([]interface{}); ok {
+			for _, entry := range log {
+				_ = entry.(map[string]interface{})["tags"]
+			}
+		}
+	}
+	wg.Wait()
+
+	if log := parser.Snapshot().Get("log").([]interface{}); len(log) != 201 {
+		t.Errorf("Expected 201 entries, got %d", len(log))
+	}
+}
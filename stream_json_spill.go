@@ -0,0 +1,183 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrSpillNotReadable is returned when reading a spilled string whose
+// target does not implement io.ReaderAt
+var ErrSpillNotReadable = errors.New("streamjson: spill target is not readable")
+
+// SpilledString is the value of a string moved out of memory by
+// WithStringSpill. Get and the other accessors return it in place of the
+// string. Each update of a streaming string yields a new value, so one
+// taken earlier keeps reading the content it had.
+type SpilledString struct {
+	target io.WriterAt
+	size   int
+}
+
+// Len returns the length of the string in bytes
+func (s *SpilledString) Len() int {
+	return s.size
+}
+
+// Reader returns a reader over the content, or ErrSpillNotReadable if the
+// target is write-only
+func (s *SpilledString) Reader() (io.Reader, error) {
+	r, ok := s.target.(io.ReaderAt)
+	if !ok {
+		return nil, ErrSpillNotReadable
+	}
+	return io.NewSectionReader(r, 0, int64(s.size)), nil
+}
+
+// String reads the whole content back into memory, or returns "" if it
+// cannot be read
+func (s *SpilledString) String() string {
+	r, err := s.Reader()
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(s.size)
+	if _, err := io.Copy(&b, r); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes the content as a JSON string
+func (s *SpilledString) MarshalJSON() ([]byte, error) {
+	r, err := s.Reader()
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(content))
+}
+
+// WithStringSpill moves strings longer than threshold bytes out of memory,
+// such as a base64 image a model inlines. Their content is written to the
+// io.WriterAt open returns for the string's path as it streams, or to a
+// temporary file when open is nil, and the value becomes a *SpilledString.
+// Subscribers that receive values still see each string in full; the input
+// buffer holds a string until it ends. Temporary files are deleted by Reset
+// or RemoveSpillFiles.
+func WithStringSpill(threshold int, open func(path []string) (io.WriterAt, error)) Option {
+	return func(o *parserOptions) {
+		o.spillThreshold = threshold
+		o.spillOpen = open
+	}
+}
+
+// GetReader returns a reader over the string at the path, spilled or not.
+// Like GetString, ok is true only for a complete string; a reader for a
+// string still streaming covers the content received so far.
+func (p *StreamJSONParser) GetReader(keys ...string) (io.Reader, bool) {
+	defer p.guardRead("GetReader")()
+	node := p.findValueNode(keys)
+	if node == nil {
+		return nil, false
+	}
+	switch v := node.Value.(type) {
+	case string:
+		return strings.NewReader(v), node.Completed
+	case *SpilledString:
+		r, err := v.Reader()
+		if err != nil {
+			return nil, false
+		}
+		return r, node.Completed
+	}
+	return nil, false
+}
+
+// SpillErr returns the first error opening or writing a spill target.
+// Strings are kept in memory from then on.
+func (p *StreamJSONParser) SpillErr() error {
+	return p.spillErr
+}
+
+// RemoveSpillFiles closes and deletes the temporary files of spilled
+// strings, after which their values can no longer be read. Targets returned
+// by the open function of WithStringSpill are left to the caller.
+func (p *StreamJSONParser) RemoveSpillFiles() error {
+	var errs []error
+	for _, file := range p.spillFiles {
+		errs = append(errs, file.Close(), os.Remove(file.Name()))
+	}
+	p.spillFiles = nil
+	return errors.Join(errs...)
+}
+
+// spillString writes the text the string of node gained since previous to
+// its spill target and replaces the value with a *SpilledString, once the
+// string is over the threshold
+func (p *StreamJSONParser) spillString(path []string, node *Node, previous interface{}) {
+	value, ok := node.Value.(string)
+	if !ok || p.spillErr != nil {
+		return
+	}
+	spilled, _ := previous.(*SpilledString)
+	if spilled == nil && len(value) <= p.options.spillThreshold {
+		return
+	}
+
+	var target io.WriterAt
+	written := 0
+	if spilled != nil {
+		target, written = spilled.target, min(spilled.size, len(value))
+	} else if target, p.spillErr = p.openSpill(path); p.spillErr != nil {
+		return
+	}
+	if _, p.spillErr = target.WriteAt([]byte(value[written:]), int64(written)); p.spillErr != nil {
+		return
+	}
+	node.Value = &SpilledString{target: target, size: len(value)}
+}
+
+// openSpill opens the target of a string to spill
+func (p *StreamJSONParser) openSpill(path []string) (io.WriterAt, error) {
+	if p.options.spillOpen != nil {
+		return p.options.spillOpen(path)
+	}
+	file, err := os.CreateTemp("", "streamjson-spill-*")
+	if err != nil {
+		return nil, err
+	}
+	p.spillFiles = append(p.spillFiles, file)
+	return file, nil
+}
+
+// textLen returns the length of a partial string value, spilled or not
+func textLen(value interface{}) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case *SpilledString:
+		return v.size
+	}
+	return 0
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// memoryTarget is an in-memory io.WriterAt and io.ReaderAt
+type memoryTarget struct {
+	data []byte
+}
+
+func (m *memoryTarget) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(m.data) {
+		m.data = append(m.data, make([]byte, end-len(m.data))...)
+	}
+	return copy(m.data[off:], p), nil
+}
+
+func (m *memoryTarget) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, m.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// writeOnlyTarget discards what is written to it
+type writeOnlyTarget struct{}
+
+func (writeOnlyTarget) WriteAt(p []byte, off int64) (int, error) {
+	return len(p), nil
+}
+
+func readAll(t *testing.T, r io.Reader) string {
+	t.Helper()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStringSpill(t *testing.T) {
+	targets := make(map[string]*memoryTarget)
+	parser := NewStreamJSONParser(WithStringSpill(8, func(path []string) (io.WriterAt, error) {
+		target := &memoryTarget{}
+		targets[strings.Join(path, ".")] = target
+		return target, nil
+	}))
+	var teed strings.Builder
+	parser.Tee("image", &teed)
+
+	parser.Append(`{"name": "short", "image": "aGVsbG8g`)
+	if _, ok := parser.Get("image").(*SpilledString); ok {
+		t.Fatalf("Expected a string under the threshold to stay in memory")
+	}
+	parser.Append(`d29ybGQh`)
+	spilled, ok := parser.Get("image").(*SpilledString)
+	if !ok || spilled.Len() != 16 {
+		t.Fatalf("Expected a spilled string of 16 bytes, got %#v", parser.Get("image"))
+	}
+	if r, ok := parser.GetReader("image"); ok || readAll(t, r) != "aGVsbG8gd29ybGQh" {
+		t.Errorf("Expected a reader over the partial content")
+	}
+
+	parser.Append(`IQ=="}`)
+	if r, ok := parser.GetReader("image"); !ok || readAll(t, r) != "aGVsbG8gd29ybGQhIQ==" {
+		t.Errorf("Expected a reader over the complete content")
+	}
+	if string(targets["image"].data) != "aGVsbG8gd29ybGQhIQ==" {
+		t.Errorf("Expected the target to hold the content, got %q", targets["image"].data)
+	}
+	if parser.Get("name") != "short" {
+		t.Errorf("Expected short strings to stay in memory, got %v", parser.Get("name"))
+	}
+	if teed.String() != "aGVsbG8gd29ybGQhIQ==" {
+		t.Errorf("Expected Tee to see every delta once, got %q", teed.String())
+	}
+
+	// The value taken while streaming keeps its length
+	if spilled.String() != "aGVsbG8gd29ybGQh" {
+		t.Errorf("Expected an earlier value to keep its content, got %q", spilled.String())
+	}
+
+	data, err := json.Marshal(parser.Get())
+	if err != nil || string(data) != `{"image":"aGVsbG8gd29ybGQhIQ==","name":"short"}` {
+		t.Errorf("Expected spilled strings to marshal as strings, got %s %v", data, err)
+	}
+}
+
+func TestStringSpillTempFile(t *testing.T) {
+	parser := NewStreamJSONParser(WithConfig(Config{SpillThreshold: 4}))
+	parser.Append(`["a", "a long string"]`)
+
+	r, ok := parser.GetReader("1")
+	if !ok || readAll(t, r) != "a long string" {
+		t.Fatalf("Expected the spilled string back, got %v", parser.Get("1"))
+	}
+	if r, ok := parser.GetReader("0"); !ok || readAll(t, r) != "a" {
+		t.Errorf("Expected a reader over an in-memory string")
+	}
+	if len(parser.spillFiles) != 1 {
+		t.Fatalf("Expected one temporary file, got %d", len(parser.spillFiles))
+	}
+
+	name := parser.spillFiles[0].Name()
+	parser.Reset()
+	if _, err := os.Stat(name); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected Reset to remove the temporary file, got %v", err)
+	}
+}
+
+func TestStringSpillErrors(t *testing.T) {
+	failure := errors.New("disk full")
+	parser := NewStreamJSONParser(WithStringSpill(2, func([]string) (io.WriterAt, error) {
+		return nil, failure
+	}))
+	parser.Append(`{"a": "kept in memory"}`)
+	if !errors.Is(parser.SpillErr(), failure) || parser.Get("a") != "kept in memory" {
+		t.Errorf("Expected the string to stay in memory after a failure, got %v %v", parser.Get("a"), parser.SpillErr())
+	}
+
+	parser = NewStreamJSONParser(WithStringSpill(2, func([]string) (io.WriterAt, error) {
+		return writeOnlyTarget{}, nil
+	}))
+	parser.Append(`{"a": "write only"}`)
+	spilled := parser.Get("a").(*SpilledString)
+	if _, err := spilled.Reader(); !errors.Is(err, ErrSpillNotReadable) {
+		t.Errorf("Expected ErrSpillNotReadable, got %v", err)
+	}
+	if _, ok := parser.GetReader("a"); ok {
+		t.Errorf("Expected no reader for a write-only target")
+	}
+}
+
+func TestStringSpillMemoryUsage(t *testing.T) {
+	parser := NewStreamJSONParser(WithMemoryBudget(1<<20), WithStringSpill(16, func([]string) (io.WriterAt, error) {
+		return &memoryTarget{}, nil
+	}))
+	parser.Append(`{"a": "` + strings.Repeat("x", 4096) + `", "b": "` + strings.Repeat("y", 100))
+	parser.Append(strings.Repeat("y", 4000) + `"}`)
+
+	if usage := parser.MemoryUsage(); usage > 1024 {
+		t.Errorf("Expected spilled strings not to count toward memory, got %d bytes", usage)
+	}
+	if parser.memory > 1024 {
+		t.Errorf("Expected the running count to drop spilled strings, got %d bytes", parser.memory)
+	}
+}
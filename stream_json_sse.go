@@ -0,0 +1,142 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// sseDone is the data payload that ends an OpenAI-style stream
+const sseDone = "[DONE]"
+
+// Appender receives streamed content. StreamJSONParser, SafeStreamJSONParser,
+// AsyncParser and Session all implement it.
+type Appender interface {
+	Append(content string)
+}
+
+// SSEFeeder reads a Server-Sent Events stream whose events carry JSON
+// payloads, such as an OpenAI-style chat completion stream, and appends the
+// string found at a path in each payload to a parser
+type SSEFeeder struct {
+	target Appender
+	reader *bufio.Reader
+	path   []string
+	handle func(data string) error // Handles the payload of each event
+	done   bool
+}
+
+// NewSSEFeeder creates a feeder that extracts the string at path, for example
+// "choices.0.delta.content", from every event read from r and appends it to
+// target. Events without a string at the path are skipped.
+func NewSSEFeeder(target Appender, r io.Reader, path string) *SSEFeeder {
+	f := &SSEFeeder{
+		target: target,
+		reader: bufio.NewReaderSize(r, readChunkSize),
+		path:   splitPath(path),
+	}
+	f.handle = f.feed
+	return f
+}
+
+// Run consumes the stream until its end, as reported by Done, or io.EOF and
+// returns nil in both cases. It returns read errors, events whose data is not
+// JSON and a *ProviderError for error events.
+func (f *SSEFeeder) Run() error {
+	for !f.done {
+		data, err := f.nextEvent()
+		if strings.TrimSpace(data) == sseDone {
+			f.done = true
+		} else if data != "" {
+			if feedErr := f.handle(data); feedErr != nil {
+				return feedErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Done reports whether the end of the stream has been received: the [DONE]
+// sentinel, or message_stop for an Anthropic stream
+func (f *SSEFeeder) Done() bool {
+	return f.done
+}
+
+// nextEvent reads lines up to the blank line that ends an event and returns
+// the event's data, with multiple data lines joined by newlines
+func (f *SSEFeeder) nextEvent() (string, error) {
+	var data []string
+	for {
+		line, err := f.reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if len(data) > 0 || err != nil {
+				return strings.Join(data, "\n"), err
+			}
+			continue // Blank lines between events
+		}
+
+		// Comments start with a colon; fields other than data are ignored
+		if field, value, found := strings.Cut(line, ":"); found && field == "data" {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+
+		if err != nil {
+			return strings.Join(data, "\n"), err
+		}
+	}
+}
+
+// feed extracts the string at the feeder's path from an event payload and
+// appends it
+func (f *SSEFeeder) feed(data string) error {
+	var payload interface{}
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return fmt.Errorf("streamjson: invalid SSE event data: %w", err)
+	}
+
+	for _, key := range f.path {
+		switch v := payload.(type) {
+		case map[string]interface{}:
+			payload = v[key]
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil
+			}
+			payload = v[index]
+		default:
+			return nil
+		}
+	}
+
+	if content, ok := payload.(string); ok && content != "" {
+		f.target.Append(content)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const sseStream = ": keep-alive\n" +
+	"data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\"{\\\"answer\\\":\"}}]}\r\n\r\n" +
+	"event: message\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\"\\\"4\"}}]}\n\n" +
+	"data: {\"choices\":\n" +
+	"data: [{\"delta\":{\"content\":\"2\\\"}\"}}]}\n\n" +
+	"data: [DONE]\n\n" +
+	"data: {\"choices\":[{\"delta\":{\"content\":\"ignored\"}}]}\n\n"
+
+func TestSSEFeeder(t *testing.T) {
+	parser := NewStreamJSONParser()
+	feeder := NewSSEFeeder(parser, iotest.OneByteReader(strings.NewReader(sseStream)), "choices.0.delta.content")
+
+	if err := feeder.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !feeder.Done() {
+		t.Errorf("Expected [DONE] to be seen")
+	}
+	if parser.Get("answer") != "42" {
+		t.Errorf("Expected answer 42, got %v", parser.Get("answer"))
+	}
+}
+
+func TestSSEFeederEOFWithoutDone(t *testing.T) {
+	parser := NewStreamJSONParser()
+	stream := "data: {\"text\":\"[1,\"}\n\ndata: {\"text\":\"2]\"}"
+	feeder := NewSSEFeeder(parser, strings.NewReader(stream), "text")
+
+	if err := feeder.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if feeder.Done() {
+		t.Errorf("Expected no [DONE]")
+	}
+	if !parser.IsCompleted() || parser.Get("1") != int64(2) {
+		t.Errorf("Expected [1,2], got %v", parser.Get())
+	}
+}
+
+func TestSSEFeederInvalidData(t *testing.T) {
+	feeder := NewSSEFeeder(NewStreamJSONParser(), strings.NewReader("data: {oops\n\n"), "text")
+	if err := feeder.Run(); err == nil {
+		t.Errorf("Expected error for invalid event data")
+	}
+}
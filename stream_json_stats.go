@@ -0,0 +1,77 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+// Stats describes the work a parser has done, for monitoring stream health
+type Stats struct {
+	BytesReceived int               // Bytes appended, after code fence extraction
+	BytesConsumed int               // Bytes tokenized so far
+	Tokens        map[TokenType]int // Completed tokens by type
+	Objects       int               // Object nodes in the current document
+	Arrays        int               // Array nodes in the current document
+	Values        int               // String, number, bool and null nodes in the current document
+	Incomplete    int               // Nodes of the current document not completed yet, open containers included
+	MaxDepth      int               // Deepest nesting of objects and arrays reached
+	Documents     int               // Documents completed in multi-document mode
+}
+
+// Stats returns counters describing the input and the document so far.
+// Node counts walk the current document, so the cost grows with its size.
+func (p *StreamJSONParser) Stats() Stats {
+	defer p.guardRead("Stats")()
+	t := p.tokenizer
+	stats := Stats{
+		BytesReceived: t.base + len(t.buffer),
+		BytesConsumed: t.base + t.position,
+		Tokens:        make(map[TokenType]int),
+		MaxDepth:      p.maxDepthSeen,
+		Documents:     p.documentCount,
+	}
+	for tokenType, count := range p.tokenCounts {
+		if count > 0 {
+			stats.Tokens[TokenType(tokenType)] = count
+		}
+	}
+	stats.countNodes(p.root)
+	return stats
+}
+
+// countNodes adds node and its descendants to the node counts
+func (s *Stats) countNodes(node *Node) {
+	stack := []*Node{node}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == nil {
+			continue
+		}
+		if !node.Completed {
+			s.Incomplete++
+		}
+
+		switch node.Type {
+		case ObjectNode:
+			s.Objects++
+			for _, child := range node.Children {
+				stack = append(stack, child)
+			}
+		case ArrayNode:
+			s.Arrays++
+			stack = append(stack, node.Array...)
+		default:
+			s.Values++
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserStats(t *testing.T) {
+	parser := NewStreamJSONParser()
+	if stats := parser.Stats(); stats.BytesReceived != 0 || len(stats.Tokens) != 0 || stats.Objects != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+
+	input := `{"a": [1, {"b": null}], "c": "stre`
+	parser.Append(input)
+	stats := parser.Stats()
+
+	if stats.BytesReceived != len(input) || stats.BytesConsumed != len(input) {
+		t.Errorf("Expected %d bytes, got %d received and %d consumed", len(input), stats.BytesReceived, stats.BytesConsumed)
+	}
+	if stats.Tokens[ObjectStart] != 2 || stats.Tokens[Number] != 1 || stats.Tokens[Null] != 1 || stats.Tokens[String] != 0 {
+		t.Errorf("Unexpected token counts %v", stats.Tokens)
+	}
+	if stats.Objects != 2 || stats.Arrays != 1 || stats.Values != 3 {
+		t.Errorf("Unexpected node counts %+v", stats)
+	}
+	// The root and the streaming string
+	if stats.Incomplete != 2 {
+		t.Errorf("Expected 2 incomplete nodes, got %d", stats.Incomplete)
+	}
+	if stats.MaxDepth != 3 {
+		t.Errorf("Expected max depth 3, got %d", stats.MaxDepth)
+	}
+
+	parser.Append(`am"}`)
+	stats = parser.Stats()
+	if stats.Incomplete != 0 || stats.Tokens[String] != 1 || stats.MaxDepth != 3 {
+		t.Errorf("Unexpected stats after completion %+v", stats)
+	}
+
+	parser.Reset()
+	if stats := parser.Stats(); stats.MaxDepth != 0 || len(stats.Tokens) != 0 {
+		t.Errorf("Expected Reset to clear stats, got %+v", stats)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrIncompleteStream is returned by ParseStream when the input ends before
+// the document completes
+var ErrIncompleteStream = errors.New("streamjson: stream ended before the document completed")
+
+// Result is the outcome of ParseStream
+type Result struct {
+	Value      interface{}       // The document as returned by Get, including anything salvaged from a cut-off stream
+	Completion CompletionState   // Whether the document completed or was cut off
+	Parser     *StreamJSONParser // The finished parser, for GetRaw, Meta and the other queries
+}
+
+// Unmarshal decodes the document into v, see StreamJSONParser.Unmarshal
+func (r *Result) Unmarshal(v interface{}) error {
+	return r.Parser.Unmarshal(v)
+}
+
+// readResult is a chunk read by ParseStream, or the error that ended reading
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// ParseStream parses r to the end with a new parser configured by opts, in
+// one call. Reading happens on a separate goroutine, so cancelling ctx
+// returns ctx.Err() without waiting for a blocked Read; close r to release
+// it. A read error, a parse error reported by Err, or an input that ends
+// before the document completes, as ErrIncompleteStream, is returned
+// together with a Result holding what was parsed.
+func ParseStream(ctx context.Context, r io.Reader, opts ...Option) (*Result, error) {
+	parser := NewStreamJSONParser(opts...)
+	reads := make(chan readResult)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			buf := make([]byte, readChunkSize)
+			n, err := r.Read(buf)
+			select {
+			case reads <- readResult{data: buf[:n], err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return parseResult(parser), ctx.Err()
+		case read := <-reads:
+			if len(read.data) > 0 {
+				parser.AppendBytes(read.data)
+				if err := parser.Err(); err != nil {
+					return parseResult(parser), err
+				}
+			}
+			if errors.Is(read.err, io.EOF) {
+				result := parseResult(parser)
+				if err := parser.Err(); err != nil {
+					return result, err
+				}
+				if result.Completion != Complete {
+					return result, ErrIncompleteStream
+				}
+				return result, nil
+			}
+			if read.err != nil {
+				return parseResult(parser), read.err
+			}
+		}
+	}
+}
+
+// parseResult finishes the parser and collects its result
+func parseResult(parser *StreamJSONParser) *Result {
+	parser.Finish()
+	return &Result{Value: parser.Get(), Completion: parser.Completion(), Parser: parser}
+}
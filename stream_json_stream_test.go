@@ -0,0 +1,89 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestParseStream(t *testing.T) {
+	input := `{"name": "Ada", "langs": ["go", "rust"]}`
+	result, err := ParseStream(context.Background(), iotest.OneByteReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Completion != Complete || result.Parser.Get("langs", "1") != "rust" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	var target struct {
+		Name  string   `json:"name"`
+		Langs []string `json:"langs"`
+	}
+	if err := result.Unmarshal(&target); err != nil || target.Name != "Ada" || len(target.Langs) != 2 {
+		t.Errorf("Expected typed result, got %+v %v", target, err)
+	}
+}
+
+func TestParseStreamIncomplete(t *testing.T) {
+	result, err := ParseStream(context.Background(), strings.NewReader(`{"items": [1, 2], "note": "cut`))
+	if !errors.Is(err, ErrIncompleteStream) {
+		t.Fatalf("Expected ErrIncompleteStream, got %v", err)
+	}
+	if result.Completion != Truncated || result.Parser.Get("note") != "cut" {
+		t.Errorf("Expected the salvaged document, got %+v", result)
+	}
+
+	if _, err := ParseStream(context.Background(), strings.NewReader("")); !errors.Is(err, ErrIncompleteStream) {
+		t.Errorf("Expected ErrIncompleteStream for empty input, got %v", err)
+	}
+}
+
+func TestParseStreamErrors(t *testing.T) {
+	failure := errors.New("connection reset")
+	reader := io.MultiReader(strings.NewReader(`{"a": 1,`), iotest.ErrReader(failure))
+	result, err := ParseStream(context.Background(), reader)
+	if !errors.Is(err, failure) || result.Parser.Get("a") != int64(1) {
+		t.Errorf("Expected the read error with partial result, got %v %v", err, result.Value)
+	}
+
+	_, err = ParseStream(context.Background(), strings.NewReader(`{"a": tru}`), WithStrictMode())
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("Expected a *ParseError, got %v", err)
+	}
+}
+
+func TestParseStreamCancel(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	go pw.Write([]byte(`{"partial": "val`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err := ParseStream(ctx, pr)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the context error, got %v", err)
+	}
+	if result.Parser.Get("partial") != "val" {
+		t.Errorf("Expected content read before cancellation, got %v", result.Value)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+)
+
+// ParseError reports input that is not valid JSON. It is only recorded in
+// strict mode, where it also stops parsing.
+type ParseError struct {
+	Offset  int    // Byte offset of the offending token in the input
+	Line    int    // 1-based line of the offending token
+	Column  int    // 1-based byte column of the offending token
+	Message string // Description of the problem
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("streamjson: %s at line %d, column %d", e.Message, e.Line, e.Column)
+}
+
+// grammarState is what strict mode expects next inside the innermost container
+type grammarState int
+
+const (
+	expectDocument   grammarState = iota // Before the root
+	expectFirstKey                       // After '{': a key or '}'
+	expectKey                            // After ',' in an object
+	expectColon                          // After a key
+	expectFirstValue                     // After '[': a value or ']'
+	expectValue                          // After ':' or ',' in an array
+	expectSeparator                      // After a value: ',' or the closing bracket
+)
+
+// Errors returns the parse errors recorded in strict mode. A parser without
+// errors that is not completed is still streaming.
+func (p *StreamJSONParser) Errors() []*ParseError {
+	return p.errors
+}
+
+// checkStrict validates a completed token against the JSON grammar and
+// advances the expected state. On a violation it records a ParseError,
+// makes it the sticky error and returns false. A value the token already
+// streamed into is removed, so the tree doesn't depend on where chunks end.
+func (p *StreamJSONParser) checkStrict(token Token) bool {
+	message := p.strictViolation(token)
+	if message == "" {
+		return true
+	}
+	p.dropPartial()
+
+	err := &ParseError{Offset: token.TokenStart, Line: token.Line, Column: token.Column, Message: message}
+	p.errors = append(p.errors, err)
+	p.err = err
+	return false
+}
+
+// strictViolation describes why token is not allowed in the current state,
+// or returns "" and moves to the next state if it is
+func (p *StreamJSONParser) strictViolation(token Token) string {
+	if token.TokenType == Invalid {
+		return fmt.Sprintf("invalid token %q", token.Content)
+	}
+
+	if !p.started {
+		if p.options.scalarRoots && isScalarToken(token) {
+			if token.TokenType == Number && !p.isNumberLiteral(token.Content) {
+				return fmt.Sprintf("invalid number %q", token.Content)
+			}
+			return ""
+		}
+		if token.TokenType != ObjectStart && token.TokenType != ArrayStart {
+			return fmt.Sprintf("unexpected %q before the document", token.Content)
+		}
+		p.expect = p.openedState(token.TokenType)
+		return ""
+	}
+
+	if len(p.stack) == 0 {
+		// A scalar root completing after it streamed
+		if token.TokenType == Number && !p.isNumberLiteral(token.Content) {
+			return fmt.Sprintf("invalid number %q", token.Content)
+		}
+		return ""
+	}
+
+	expectingValue := p.expect == expectFirstValue || p.expect == expectValue
+	unexpected := fmt.Sprintf("unexpected %q", token.Content)
+
+	switch token.TokenType {
+	case ObjectStart, ArrayStart:
+		if !expectingValue {
+			return unexpected
+		}
+		p.expect = p.openedState(token.TokenType)
+
+	case ObjectEnd, ArrayEnd:
+		frame := p.stack[len(p.stack)-1]
+		if (token.TokenType == ObjectEnd) != (frame.Node.Type == ObjectNode) {
+			return fmt.Sprintf("mismatched %q", token.Content)
+		}
+		if p.expect != expectSeparator && p.expect != expectFirstKey && p.expect != expectFirstValue {
+			return unexpected
+		}
+		p.expect = expectSeparator
+
+	case ObjectKey, String:
+		// Keys and values are told apart by position, not by token type
+		if p.expect == expectFirstKey || p.expect == expectKey {
+			p.expect = expectColon
+		} else if expectingValue {
+			p.expect = expectSeparator
+		} else {
+			return unexpected
+		}
+
+	case Number:
+		if !expectingValue {
+			return unexpected
+		}
+		if !p.isNumberLiteral(token.Content) {
+			return fmt.Sprintf("invalid number %q", token.Content)
+		}
+		p.expect = expectSeparator
+
+	case Bool, Null:
+		if !expectingValue {
+			return unexpected
+		}
+		p.expect = expectSeparator
+
+	case Colon:
+		if p.expect != expectColon {
+			return unexpected
+		}
+		p.expect = expectValue
+
+	case Comma:
+		if p.expect != expectSeparator {
+			return unexpected
+		}
+		if p.stack[len(p.stack)-1].Node.Type == ObjectNode {
+			p.expect = expectKey
+		} else {
+			p.expect = expectValue
+		}
+	}
+	return ""
+}
+
+// openedState returns the state right after a container opens
+func (p *StreamJSONParser) openedState(tokenType TokenType) grammarState {
+	if tokenType == ObjectStart {
+		return expectFirstKey
+	}
+	return expectFirstValue
+}
+
+// isValidNumber reports whether s is a number in JSON syntax
+func isValidNumber(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+
+	// Integer part without leading zeros
+	if i < len(s) && s[i] == '0' {
+		i++
+	} else {
+		digits := skipDigits(s, i)
+		if digits == i {
+			return false
+		}
+		i = digits
+	}
+
+	if i < len(s) && s[i] == '.' {
+		digits := skipDigits(s, i+1)
+		if digits == i+1 {
+			return false
+		}
+		i = digits
+	}
+
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		digits := skipDigits(s, i)
+		if digits == i {
+			return false
+		}
+		i = digits
+	}
+
+	return i == len(s)
+}
+
+// skipDigits returns the index of the first non-digit at or after i
+func skipDigits(s string, i int) int {
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
+}
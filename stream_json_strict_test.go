@@ -0,0 +1,116 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStrictModeValidDocument(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+	appendBytewise(parser, `{"a": [1, -2.5e3, "x", {"b": null}, [], {}], "": true, "c": "d"}`)
+
+	if len(parser.Errors()) != 0 {
+		t.Fatalf("Expected no errors, got %v", parser.Errors()[0])
+	}
+	if !parser.IsCompleted() {
+		t.Errorf("Expected parsing to complete")
+	}
+	if parser.Get("c") != "d" {
+		t.Errorf("Expected c to be d, got %v", parser.Get("c"))
+	}
+}
+
+func TestStrictModeStillStreaming(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+	parser.Append(`{"a": [1, 2`)
+
+	if parser.Err() != nil || len(parser.Errors()) != 0 {
+		t.Errorf("Expected no errors while streaming, got %v", parser.Err())
+	}
+	if parser.IsCompleted() {
+		t.Errorf("Expected parsing to be in progress")
+	}
+}
+
+func TestStrictModeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		offset  int
+		message string
+	}{
+		{"prose", `Sure! {"a":1}`, 0, `invalid token "S"`},
+		{"scalar root", `7 {"a":1}`, 0, `unexpected "7" before the document`},
+		{"stray character", `{"a":1 @}`, 7, `invalid token "@"`},
+		{"trailing comma", `{"a":1,}`, 7, `unexpected "}"`},
+		{"missing colon", `{"a" 1}`, 5, `unexpected "1"`},
+		{"missing comma", `[1 2]`, 3, `unexpected "2"`},
+		{"mismatched bracket", `{"a":[1}`, 7, `mismatched "}"`},
+		{"invalid number", `[01]`, 1, `invalid number "01"`},
+		{"empty value", `{"a":,}`, 5, `unexpected ","`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewStreamJSONParser(WithStrictMode())
+			parser.Append(tt.input)
+			parser.Append(" ")
+
+			errs := parser.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("Expected one error, got %d", len(errs))
+			}
+			if errs[0].Offset != tt.offset || errs[0].Message != tt.message {
+				t.Errorf("Expected %q at %d, got %q at %d", tt.message, tt.offset, errs[0].Message, errs[0].Offset)
+			}
+			if parser.Err() != errs[0] {
+				t.Errorf("Expected Err to return the parse error")
+			}
+		})
+	}
+}
+
+func TestStrictModeStopsAtError(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+	parser.Append("{\n  \"a\": 1,\n  \"b\": ?,\n")
+	parser.Append(`  "c": 3}`)
+
+	errs := parser.Errors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %d", len(errs))
+	}
+	if errs[0].Line != 3 || errs[0].Column != 8 {
+		t.Errorf("Expected error at 3:8, got %d:%d", errs[0].Line, errs[0].Column)
+	}
+	if parser.Get("c") != nil {
+		t.Errorf("Expected parsing to stop at the error, got c=%v", parser.Get("c"))
+	}
+	if parser.Get("a") != int64(1) {
+		t.Errorf("Expected values before the error to be kept, got %v", parser.Get("a"))
+	}
+}
+
+func TestTolerantModeRecordsNoErrors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`Sure! {"a":1,}`)
+
+	if len(parser.Errors()) != 0 || parser.Err() != nil {
+		t.Errorf("Expected no errors outside strict mode")
+	}
+	if parser.Get("a") != int64(1) {
+		t.Errorf("Expected a to be 1, got %v", parser.Get("a"))
+	}
+}
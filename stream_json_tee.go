@@ -0,0 +1,65 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"io"
+)
+
+// teeSubscription is a writer registered for the strings at a path
+type teeSubscription struct {
+	pattern []string
+	writer  io.Writer
+	err     error // First write error, after which nothing more is written
+}
+
+// Tee streams the decoded content of the string at a dotted path into w as
+// it arrives, such as a long text field piped straight to an HTTP response.
+// Paths use the syntax of OnValue; strings at several matching paths are
+// written one after the other. Other values are ignored. Once a write
+// fails, nothing more is written to w and TeeErr reports the error.
+func (p *StreamJSONParser) Tee(path string, w io.Writer) {
+	p.tees = append(p.tees, &teeSubscription{
+		pattern: p.normalizePath(splitPath(path)),
+		writer:  w,
+	})
+}
+
+// TeeErr returns the first error returned by a writer registered with Tee
+func (p *StreamJSONParser) TeeErr() error {
+	for _, tee := range p.tees {
+		if tee.err != nil {
+			return tee.err
+		}
+	}
+	return nil
+}
+
+// deliverTee writes the text a string at path gained since previous to the
+// matching writers
+func (p *StreamJSONParser) deliverTee(path []string, node *Node, previous interface{}) {
+	value, ok := node.Value.(string)
+	if !ok || len(value) <= textLen(previous) {
+		return
+	}
+
+	delta := value[textLen(previous):]
+	for _, tee := range p.tees {
+		if tee.err != nil || !matchPath(tee.pattern, path) {
+			continue
+		}
+		_, tee.err = io.WriteString(tee.writer, delta)
+	}
+}
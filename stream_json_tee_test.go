@@ -0,0 +1,81 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTeeStreamsString(t *testing.T) {
+	var out strings.Builder
+	parser := NewStreamJSONParser()
+	parser.Tee("message", &out)
+
+	parser.Append(`{"id": 1, "message": "Hel`)
+	if out.String() != "Hel" {
+		t.Errorf("Expected the partial content to be written, got %q", out.String())
+	}
+	parser.Append(`lo, \"world\"\n`)
+	parser.Append(`", "other": "x"}`)
+
+	if out.String() != "Hello, \"world\"\n" {
+		t.Errorf("Expected the decoded string, got %q", out.String())
+	}
+}
+
+func TestTeeWholeChunkAndWildcard(t *testing.T) {
+	var out strings.Builder
+	parser := NewStreamJSONParser()
+	parser.Tee("parts.*.text", &out)
+	parser.Append(`{"parts": [{"text": "one "}, {"text": 2}, {"text": "two"}]}`)
+
+	if out.String() != "one two" {
+		t.Errorf("Expected the strings at matching paths, got %q", out.String())
+	}
+}
+
+func TestTeeSeesTextBeforeTransforms(t *testing.T) {
+	var out strings.Builder
+	parser := NewStreamJSONParser()
+	parser.Tee("n", &out)
+	parser.Transform("n", func(value interface{}) (interface{}, error) {
+		return len(value.(string)), nil
+	})
+	appendBytewise(parser, `{"n": "abc"}`)
+
+	if out.String() != "abc" {
+		t.Errorf("Expected the text as parsed, got %q", out.String())
+	}
+}
+
+func TestTeeWriteError(t *testing.T) {
+	var out strings.Builder
+	parser := NewStreamJSONParser()
+	parser.Tee("message", failingWriter{})
+	parser.Tee("message", &out)
+	parser.Append(`{"message": "a`)
+	parser.Append(`b"}`)
+
+	if parser.TeeErr() == nil || parser.TeeErr().Error() != "broken pipe" {
+		t.Errorf("Expected the write error, got %v", parser.TeeErr())
+	}
+	if out.String() != "ab" {
+		t.Errorf("Expected other writers to be unaffected, got %q", out.String())
+	}
+	if parser.Err() != nil || parser.Get("message") != "ab" {
+		t.Errorf("Expected parsing to continue, got %v, %v", parser.Get(), parser.Err())
+	}
+}
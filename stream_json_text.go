@@ -0,0 +1,114 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned by Err for text around the root, when requested with
+// WithErrorOnLeadingText and WithErrorOnTrailingText
+var (
+	ErrLeadingText  = errors.New("streamjson: text before the document")
+	ErrTrailingText = errors.New("streamjson: text after the document")
+)
+
+// LeadingText returns the text skipped before the root, such as a model's
+// preamble, without surrounding whitespace. It is only kept with
+// WithCaptureLeadingText, and includes prose dropped by code fence
+// extraction.
+func (p *StreamJSONParser) LeadingText() string {
+	text := string(p.leadingText)
+	if p.fence != nil {
+		text = string(p.fence.before) + text
+	}
+	return strings.TrimSpace(text)
+}
+
+// TrailingText returns the text after the completed root, without
+// surrounding whitespace. It is only kept with WithCaptureTrailingText, and
+// includes prose dropped after the closing code fence.
+func (p *StreamJSONParser) TrailingText() string {
+	if !p.options.captureTrailingText {
+		return ""
+	}
+	text := string(p.afterRoot())
+	if p.fence != nil {
+		text += string(p.fence.after)
+	}
+	return strings.TrimSpace(text)
+}
+
+// beforeRoot reports whether no root has started yet. Text between
+// documents in multi-document mode is not leading text.
+func (p *StreamJSONParser) beforeRoot() bool {
+	return !p.started && p.documentCount == 0
+}
+
+// isRootToken reports whether token can start the root
+func (p *StreamJSONParser) isRootToken(token Token) bool {
+	return token.TokenType == ObjectStart || token.TokenType == ArrayStart ||
+		p.options.scalarRoots && isScalarToken(token)
+}
+
+// checkLeadingText handles a token seen before the root. Text up to the root
+// is captured as requested; a complete token that cannot start the root
+// stops parsing with WithErrorOnLeadingText, and false is returned.
+func (p *StreamJSONParser) checkLeadingText(token Token) bool {
+	if p.isRootToken(token) {
+		p.captureLeadingText(token.TokenStart)
+		return true
+	}
+	if !token.Completed {
+		return true
+	}
+	if p.options.errorOnLeadingText {
+		p.err = ErrLeadingText
+		return false
+	}
+	p.captureLeadingText(token.TokenEnd)
+	return true
+}
+
+// captureLeadingText appends the input up to offset end to the leading text
+func (p *StreamJSONParser) captureLeadingText(end int) {
+	if !p.options.captureLeadingText || end <= p.leadingEnd {
+		return
+	}
+	t := p.tokenizer
+	p.leadingText = append(p.leadingText, t.buffer[p.leadingEnd-t.base:end-t.base]...)
+	p.leadingEnd = end
+}
+
+// afterRoot returns the buffered input after the completed root
+func (p *StreamJSONParser) afterRoot() []byte {
+	if p.options.multipleDocuments || !p.IsCompleted() {
+		return nil
+	}
+	t := p.tokenizer
+	return t.buffer[p.root.end-t.base:]
+}
+
+// checkTrailingText stops parsing with WithErrorOnTrailingText once a token
+// follows the completed root
+func (p *StreamJSONParser) checkTrailingText() {
+	if !p.options.errorOnTrailingText || p.err != nil || p.options.multipleDocuments || !p.IsCompleted() {
+		return
+	}
+	if p.tokenizer.Peek().TokenType != EOF {
+		p.err = ErrTrailingText
+	}
+}
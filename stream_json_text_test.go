@@ -0,0 +1,110 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestCaptureLeadingAndTrailingText(t *testing.T) {
+	input := "Here is the result, as requested:\n\n{\"ok\": true}\n\nLet me know if it helps."
+
+	for _, bytewise := range []bool{false, true} {
+		parser := NewStreamJSONParser(WithCaptureLeadingText(), WithCaptureTrailingText())
+		if bytewise {
+			appendBytewise(parser, input)
+		} else {
+			parser.Append(input)
+		}
+
+		if parser.Get("ok") != true || parser.Err() != nil {
+			t.Errorf("Expected the document to parse (bytewise=%v), got %v, %v", bytewise, parser.Get(), parser.Err())
+		}
+		if text := parser.LeadingText(); text != "Here is the result, as requested:" {
+			t.Errorf("Unexpected leading text (bytewise=%v): %q", bytewise, text)
+		}
+		if text := parser.TrailingText(); text != "Let me know if it helps." {
+			t.Errorf("Unexpected trailing text (bytewise=%v): %q", bytewise, text)
+		}
+	}
+}
+
+func TestTextNotCapturedByDefault(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`Result: {"a": 1} done`)
+
+	if parser.LeadingText() != "" || parser.TrailingText() != "" {
+		t.Errorf("Expected no captured text, got %q and %q", parser.LeadingText(), parser.TrailingText())
+	}
+	if parser.Err() != nil {
+		t.Errorf("Expected text to be skipped silently, got %v", parser.Err())
+	}
+}
+
+func TestErrorOnLeadingText(t *testing.T) {
+	parser := NewStreamJSONParser(WithErrorOnLeadingText())
+	parser.Append(`Sure: {"a": 1}`)
+
+	if parser.Err() != ErrLeadingText {
+		t.Errorf("Expected ErrLeadingText, got %v", parser.Err())
+	}
+	if parser.Get() != nil {
+		t.Errorf("Expected no document, got %v", parser.Get())
+	}
+
+	// Whitespace is not text
+	parser = NewStreamJSONParser(WithErrorOnLeadingText())
+	parser.Append(" \n\t{\"a\": 1}")
+	if parser.Err() != nil || parser.Get("a") != int64(1) {
+		t.Errorf("Expected the document to parse, got %v, %v", parser.Get(), parser.Err())
+	}
+}
+
+func TestErrorOnTrailingText(t *testing.T) {
+	parser := NewStreamJSONParser(WithErrorOnTrailingText())
+	parser.Append(`{"a": 1}`)
+	parser.Append("\n  ")
+	if parser.Err() != nil {
+		t.Fatalf("Expected trailing whitespace to be accepted, got %v", parser.Err())
+	}
+
+	parser.Append("Done.")
+	if parser.Err() != ErrTrailingText {
+		t.Errorf("Expected ErrTrailingText, got %v", parser.Err())
+	}
+	if !parser.IsCompleted() || parser.Get("a") != int64(1) {
+		t.Errorf("Expected the completed document to be kept, got %v", parser.Get())
+	}
+}
+
+func TestTextAroundCodeFence(t *testing.T) {
+	input := "Sure! Here it is:\n```json\n{\"a\": 1}\n```\nAnything else?"
+
+	parser := NewStreamJSONParser(WithCodeFenceExtraction(),
+		WithCaptureLeadingText(), WithCaptureTrailingText(),
+		WithErrorOnLeadingText(), WithErrorOnTrailingText())
+	appendBytewise(parser, input)
+
+	// Prose outside the fence is expected, so it is captured but not an error
+	if parser.Err() != nil || parser.Get("a") != int64(1) {
+		t.Fatalf("Expected the fenced document to parse, got %v, %v", parser.Get(), parser.Err())
+	}
+	if text := parser.LeadingText(); text != "Sure! Here it is:" {
+		t.Errorf("Unexpected leading text: %q", text)
+	}
+	if text := parser.TrailingText(); text != "Anything else?" {
+		t.Errorf("Unexpected trailing text: %q", text)
+	}
+}
@@ -15,7 +15,9 @@
 package streamjson
 
 import (
-	"strings"
+	"bytes"
+	"iter"
+	"strconv"
 )
 
 // TokenType represents the type of JSON token
@@ -35,6 +37,44 @@ const (
 	Null                         // null
 	EOF                          // End of input
 	Invalid                      // Invalid token
+	Whitespace                   // Spaces, tabs and newlines, in passthrough mode
+	RawText                      // Comments and invisible characters, in passthrough mode
+)
+
+// tokenTypeNames holds the names returned by TokenType.String
+var tokenTypeNames = [...]string{
+	ObjectStart: "ObjectStart",
+	ObjectEnd:   "ObjectEnd",
+	ArrayStart:  "ArrayStart",
+	ArrayEnd:    "ArrayEnd",
+	Number:      "Number",
+	Bool:        "Bool",
+	ObjectKey:   "ObjectKey",
+	String:      "String",
+	Colon:       "Colon",
+	Comma:       "Comma",
+	Null:        "Null",
+	EOF:         "EOF",
+	Invalid:     "Invalid",
+	Whitespace:  "Whitespace",
+	RawText:     "RawText",
+}
+
+// String returns the name of the token type
+func (t TokenType) String() string {
+	if t >= 0 && int(t) < len(tokenTypeNames) {
+		return tokenTypeNames[t]
+	}
+	return "TokenType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Container identifies the kind of container a token appears in
+type Container int
+
+const (
+	TopLevel Container = iota // Outside any object or array
+	InObject                  // Directly inside an object
+	InArray                   // Directly inside an array
 )
 
 // Token represents a JSON token
@@ -44,6 +84,10 @@ type Token struct {
 	TokenType  TokenType // Type of the token
 	Content    string    // Content of the token
 	Completed  bool      // Whether the token is complete
+	Container  Container // Innermost container the token appears in
+	Depth      int       // Number of containers the token appears in
+	Line       int       // 1-based line of TokenStart
+	Column     int       // 1-based byte column of TokenStart
 }
 
 // StreamJSONTokenizer implements a streaming JSON tokenizer
@@ -52,18 +96,46 @@ type StreamJSONTokenizer struct {
 	position     int    // Current position in buffer
 	lastToken    *Token // Last incomplete token
 	escapeNext   bool   // Whether next character is escaped
-	expectingKey bool   // Whether we're expecting an object key
+	expectingKey bool   // Whether the next string is an object key
+	containers   []byte // Open containers, '{' or '[', innermost last
+	quote        byte   // Quote character of the current string
+	inWord       bool   // Whether the incomplete token is a bare word (repair mode)
+	repair       bool   // Whether to accept common malformations (quotes, bare words)
+	lenientKeys  bool   // Whether to accept single-quoted and unquoted object keys
+	nonFinite    bool   // Whether to accept NaN, Infinity and -Infinity as numbers
+	comments     bool   // Whether to skip // and /* */ comments
+	smartQuotes  bool   // Whether typographic double quotes delimit strings
+	passthrough  bool   // Whether skipped input is returned as Whitespace and RawText tokens
+	comment      byte   // Kind of the comment being skipped, '/' or '*', or 0
+	final        bool   // Whether the buffer holds the whole input, borrowed by load
+
+	base      int // Input offset of buffer[0], the number of compacted bytes
+	counted   int // Input offset up to which newlines are counted
+	lines     int // Newlines before counted
+	lineStart int // Input offset just past the last newline before counted
 
-	// Pre-allocated string builder for efficient string construction
-	contentBuilder strings.Builder
+	interned map[string]string // Content of short tokens seen before
+	resets   int               // Number of Reset calls, which invalidate checkpoints
 }
 
+// Limits of the table of interned token content
+const (
+	maxInternLength = 32
+	maxInterned     = 1024
+)
+
 // Predefined constants to avoid allocations
 var (
 	singleChars = [256]string{} // Pre-allocated single character strings
 	trueBytes   = []byte("true")
 	falseBytes  = []byte("false")
 	nullBytes   = []byte("null")
+
+	// Bare words accepted as literals in repair mode
+	wordLiterals = map[string]TokenType{
+		"true": Bool, "false": Bool, "True": Bool, "False": Bool,
+		"null": Null, "None": Null,
+	}
 )
 
 // Initialize single character strings once
@@ -80,7 +152,6 @@ func NewStreamJSONTokenizer() *StreamJSONTokenizer {
 		position:     0,
 		expectingKey: false,
 	}
-	tokenizer.contentBuilder.Grow(256) // Pre-allocate builder capacity
 	return tokenizer
 }
 
@@ -90,24 +161,180 @@ func (t *StreamJSONTokenizer) Append(content string) {
 	t.buffer = append(t.buffer, content...)
 }
 
-// NextToken returns the next token from the input
+// AppendBytes adds more content to the tokenizer from a byte slice, which
+// may be reused once it returns
+func (t *StreamJSONTokenizer) AppendBytes(data []byte) {
+	t.buffer = append(t.buffer, data...)
+}
+
+// load sets data as the whole input. The buffer borrows data, capped so
+// that appending never writes to it, and tokens ending with it are complete.
+func (t *StreamJSONTokenizer) load(data []byte) {
+	t.buffer = data[:len(data):len(data)]
+	t.final = true
+}
+
+// Reset clears the tokenizer for new input, keeping the buffer's capacity
+func (t *StreamJSONTokenizer) Reset() {
+	if t.final {
+		t.buffer = make([]byte, 0, 1024) // The loaded input belongs to the caller
+	} else {
+		t.buffer = t.buffer[:0]
+	}
+	t.final = false
+	t.resets++
+	t.position = 0
+	t.lastToken = nil
+	t.escapeNext = false
+	t.expectingKey = false
+	t.containers = t.containers[:0]
+	t.quote = 0
+	t.inWord = false
+	t.comment = 0
+	t.base = 0
+	t.counted = 0
+	t.lines = 0
+	t.lineStart = 0
+}
+
+// NextToken returns the next token from the input. Token offsets count from
+// the start of the input, including bytes already dropped by compaction.
 func (t *StreamJSONTokenizer) NextToken() Token {
+	token := t.nextToken()
+	t.track(&token)
+	token.TokenStart += t.base
+	token.TokenEnd += t.base
+	token.Line, token.Column = t.lineColumn(token.TokenStart)
+	return token
+}
+
+// lineColumn returns the 1-based line and byte column of an input offset at
+// or after the previous one, counting only the newlines in between
+func (t *StreamJSONTokenizer) lineColumn(offset int) (int, int) {
+	if offset > t.counted {
+		skipped := t.buffer[t.counted-t.base : offset-t.base]
+		if newlines := bytes.Count(skipped, []byte{'\n'}); newlines > 0 {
+			t.lines += newlines
+			t.lineStart = t.counted + bytes.LastIndexByte(skipped, '\n') + 1
+		}
+		t.counted = offset
+	}
+	return t.lines + 1, offset - t.lineStart + 1
+}
+
+// Peek returns the token NextToken would return, without consuming it
+func (t *StreamJSONTokenizer) Peek() Token {
+	position, escapeNext, expectingKey, quote, inWord, comment := t.position, t.escapeNext, t.expectingKey, t.quote, t.inWord, t.comment
+	var lastToken *Token
+	if t.lastToken != nil {
+		saved := *t.lastToken
+		lastToken = &saved
+	}
+	// A token pushes or pops at most one container, leaving the saved
+	// elements untouched
+	containers := t.containers
+
+	token := t.NextToken()
+
+	t.position, t.escapeNext, t.expectingKey, t.quote, t.inWord, t.comment = position, escapeNext, expectingKey, quote, inWord, comment
+	t.lastToken = lastToken
+	t.containers = containers
+	return token
+}
+
+// track sets the container context of token and moves the container stack
+// past it. Brackets belong to the container around the one they open or
+// close. Strings are keys right after '{' and after a comma in an object.
+func (t *StreamJSONTokenizer) track(token *Token) {
+	if token.Completed {
+		switch token.TokenType {
+		case ObjectEnd, ArrayEnd:
+			// Closing brackets always close the innermost container, as
+			// the parser's do
+			if len(t.containers) > 0 {
+				t.containers = t.containers[:len(t.containers)-1]
+			}
+			t.expectingKey = false
+		case ObjectKey, Colon:
+			t.expectingKey = false
+		case Comma:
+			t.expectingKey = t.container() == InObject
+		}
+	}
+
+	token.Container = t.container()
+	token.Depth = len(t.containers)
+
+	switch token.TokenType {
+	case ObjectStart:
+		t.containers = append(t.containers, '{')
+		t.expectingKey = true
+	case ArrayStart:
+		t.containers = append(t.containers, '[')
+		t.expectingKey = false
+	}
+}
+
+// container returns the kind of the innermost open container
+func (t *StreamJSONTokenizer) container() Container {
+	if len(t.containers) == 0 {
+		return TopLevel
+	}
+	if t.containers[len(t.containers)-1] == '{' {
+		return InObject
+	}
+	return InArray
+}
+
+// Tokens returns an iterator over the complete tokens available so far.
+// It stops at the end of the buffered input or at a token that needs more
+// input; after the next Append a new iteration picks up where it stopped.
+//
+//	for token := range tokenizer.Tokens() {
+//		fmt.Println(token.TokenType, token.Content)
+//	}
+func (t *StreamJSONTokenizer) Tokens() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			if next := t.Peek(); next.TokenType == EOF || !next.Completed {
+				return
+			}
+			if !yield(t.NextToken()) {
+				return
+			}
+		}
+	}
+}
+
+// nextToken returns the next token with offsets relative to the buffer
+func (t *StreamJSONTokenizer) nextToken() Token {
 	// If we have an incomplete token, try to complete it
 	if t.lastToken != nil && !t.lastToken.Completed {
-		token := t.continueToken()
+		var token Token
+		if t.inWord {
+			token = t.continueWord(*t.lastToken)
+		} else {
+			token = t.continueToken()
+		}
 		if token.Completed {
 			t.lastToken = nil
 		} else {
-			t.lastToken = &token
+			t.lastToken = savedToken(token)
 		}
 		return token
 	}
 
-	// Skip whitespace
-	t.skipWhitespace()
+	// Skip whitespace, or return it in passthrough mode
+	if t.passthrough {
+		if token, ok := t.skippedToken(); ok {
+			return token
+		}
+	} else {
+		t.skipWhitespace()
+	}
 
 	// Check if we've reached the end
-	if t.position >= len(t.buffer) {
+	if t.position >= len(t.buffer) || t.awaitingComment() || t.awaitingSign() || t.awaitingMultibyte() {
 		return Token{
 			TokenStart: t.position,
 			TokenEnd:   t.position,
@@ -120,10 +347,34 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 	startPos := t.position
 	char := t.buffer[t.position]
 
+	if t.nonFinite && isNonFiniteStart(t.buffer[t.position:]) {
+		if char == '-' {
+			t.position++
+		}
+		return t.parseWord(startPos)
+	}
+
+	if t.smartQuotes && char >= 0x80 {
+		if n := matchChar(t.buffer[t.position:], smartQuotes, true); n > 0 {
+			return t.parseSmartString(startPos, n)
+		}
+	}
+
+	// Repair mode accepts single-quoted strings and bare words, lenient
+	// keys only in key position
+	if t.repair || t.lenientKeys && t.expectingKey {
+		if char == '\'' {
+			t.quote = char
+			return t.parseString(startPos)
+		}
+		if isWordStart(char) {
+			return t.parseWord(startPos)
+		}
+	}
+
 	switch char {
 	case '{':
 		t.position++
-		t.expectingKey = true
 		return Token{
 			TokenStart: startPos,
 			TokenEnd:   t.position,
@@ -133,7 +384,6 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 		}
 	case '}':
 		t.position++
-		t.expectingKey = false
 		return Token{
 			TokenStart: startPos,
 			TokenEnd:   t.position,
@@ -143,7 +393,6 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 		}
 	case '[':
 		t.position++
-		t.expectingKey = false
 		return Token{
 			TokenStart: startPos,
 			TokenEnd:   t.position,
@@ -162,7 +411,6 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 		}
 	case ':':
 		t.position++
-		t.expectingKey = false
 		return Token{
 			TokenStart: startPos,
 			TokenEnd:   t.position,
@@ -172,7 +420,6 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 		}
 	case ',':
 		t.position++
-		t.expectingKey = true // After comma in object, expect key
 		return Token{
 			TokenStart: startPos,
 			TokenEnd:   t.position,
@@ -181,6 +428,7 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 			Completed:  true,
 		}
 	case '"':
+		t.quote = '"'
 		return t.parseString(startPos)
 	case 't', 'f':
 		return t.parseBool(startPos)
@@ -202,6 +450,12 @@ func (t *StreamJSONTokenizer) NextToken() Token {
 	}
 }
 
+// savedToken returns a heap copy of an incomplete token. Taking the address
+// of a local only when it is needed keeps complete tokens off the heap.
+func savedToken(token Token) *Token {
+	return &token
+}
+
 // continueToken continues parsing an incomplete token
 func (t *StreamJSONTokenizer) continueToken() Token {
 	if t.lastToken == nil {
@@ -210,6 +464,9 @@ func (t *StreamJSONTokenizer) continueToken() Token {
 
 	switch t.lastToken.TokenType {
 	case String, ObjectKey:
+		if t.quote == smartQuote {
+			return t.continueSmartString(*t.lastToken)
+		}
 		return t.continueString(*t.lastToken)
 	case Number:
 		return t.continueNumber(*t.lastToken)
@@ -222,28 +479,140 @@ func (t *StreamJSONTokenizer) continueToken() Token {
 	}
 }
 
-// skipWhitespace skips whitespace characters using fast byte comparison
+// consumed returns the number of leading buffer bytes no longer needed to
+// produce tokens
+func (t *StreamJSONTokenizer) consumed() int {
+	if t.lastToken != nil {
+		return t.lastToken.TokenStart
+	}
+	return t.position
+}
+
+// compact drops the first n bytes of the buffer, which must not exceed consumed
+func (t *StreamJSONTokenizer) compact(n int) {
+	if n <= 0 {
+		return
+	}
+
+	// Count the newlines in the dropped bytes while they are there
+	t.lineColumn(t.base + n)
+
+	t.buffer = t.buffer[:copy(t.buffer, t.buffer[n:])]
+	t.base += n
+	t.position -= n
+	if t.lastToken != nil {
+		t.lastToken.TokenStart -= n
+		t.lastToken.TokenEnd -= n
+	}
+}
+
+// skipWhitespace skips whitespace characters using fast byte comparison,
+// and comments when they are enabled
 func (t *StreamJSONTokenizer) skipWhitespace() {
 	for t.position < len(t.buffer) {
+		if t.comments && t.skipComment() {
+			continue
+		}
+		if t.comment != 0 {
+			break // The rest of the comment has not arrived yet
+		}
 		char := t.buffer[t.position]
 		// Fast byte-level whitespace check for common cases
 		if char == ' ' || char == '\t' || char == '\n' || char == '\r' {
 			t.position++
+		} else if n := matchChar(t.buffer[t.position:], invisibleChars, true); char >= 0x80 && n > 0 {
+			t.position += n
 		} else {
 			break
 		}
 	}
 }
 
-// buildString efficiently builds a string from buffer slice
+// SetPassthrough makes the tokenizer return the input it otherwise skips,
+// so that the content of the tokens reconstructs the input byte for byte,
+// for formatters and highlighters. Runs of spaces, tabs and newlines become
+// Whitespace tokens, and comments and invisible characters such as a byte
+// order mark become RawText tokens. Input split across Append calls may
+// yield several tokens for one run.
+func (t *StreamJSONTokenizer) SetPassthrough(enabled bool) {
+	t.passthrough = enabled
+}
+
+// skippedToken returns the input skipWhitespace would skip next as a
+// Whitespace or RawText token, or false if there is none
+func (t *StreamJSONTokenizer) skippedToken() (Token, bool) {
+	start := t.position
+	tokenType := Whitespace
+	for t.position < len(t.buffer) && t.comment == 0 {
+		char := t.buffer[t.position]
+		if char != ' ' && char != '\t' && char != '\n' && char != '\r' {
+			break
+		}
+		t.position++
+	}
+
+	if t.position == start && t.position < len(t.buffer) {
+		tokenType = RawText
+		if !(t.comments && t.skipComment()) {
+			if n := matchChar(t.buffer[t.position:], invisibleChars, true); t.buffer[t.position] >= 0x80 && n > 0 {
+				t.position += n
+			}
+		}
+	}
+	if t.position == start {
+		return Token{}, false
+	}
+	return Token{
+		TokenStart: start,
+		TokenEnd:   t.position,
+		TokenType:  tokenType,
+		Content:    t.buildString(start, t.position),
+		Completed:  true,
+	}, true
+}
+
+// buildString returns the content of a buffer slice. Short content, such as
+// keys, literals and small numbers, is interned so repeated tokens do not
+// allocate.
 func (t *StreamJSONTokenizer) buildString(start, end int) string {
-	if start >= end {
+	switch n := end - start; {
+	case n <= 0:
 		return ""
+	case n == 1:
+		return singleChars[t.buffer[start]]
+	case n > maxInternLength:
+		return string(t.buffer[start:end])
 	}
-	// Reset and reuse the builder for efficiency
-	t.contentBuilder.Reset()
-	t.contentBuilder.Write(t.buffer[start:end])
-	return t.contentBuilder.String()
+
+	content := t.buffer[start:end]
+	if s, ok := t.interned[string(content)]; ok {
+		return s
+	}
+	if t.interned == nil || len(t.interned) >= maxInterned {
+		// Start over rather than grow
+		t.interned = make(map[string]string, 64)
+	}
+	s := string(content)
+	t.interned[s] = s
+	return s
+}
+
+// partialString returns the content of an incomplete token. It is not
+// interned, since the prefixes a streaming token goes through are rarely
+// seen again and would only churn the table.
+func (t *StreamJSONTokenizer) partialString(start, end int) string {
+	if end-start == 1 {
+		return singleChars[t.buffer[start]]
+	}
+	return string(t.buffer[start:end])
+}
+
+// tokenString returns the content of a token that may be incomplete
+func (t *StreamJSONTokenizer) tokenString(start, end int, completed bool) string {
+	if completed {
+		return t.buildString(start, end)
+	}
+	return t.partialString(start, end)
 }
 
 // parseString parses a string token
@@ -265,7 +634,7 @@ func (t *StreamJSONTokenizer) parseString(startPos int) Token {
 			continue
 		}
 
-		if char == '"' {
+		if char == t.quote {
 			// String is complete
 			tokenType := String
 			if t.expectingKey {
@@ -290,7 +659,7 @@ func (t *StreamJSONTokenizer) parseString(startPos int) Token {
 		TokenStart: startPos,
 		TokenEnd:   t.position,
 		TokenType:  tokenType,
-		Content:    t.buildString(contentStart, t.position),
+		Content:    t.partialString(contentStart, t.position),
 		Completed:  false,
 	}
 	t.lastToken = &token
@@ -313,7 +682,7 @@ func (t *StreamJSONTokenizer) continueString(token Token) Token {
 			continue
 		}
 
-		if char == '"' {
+		if char == t.quote {
 			// String is now complete
 			return Token{
 				TokenStart: token.TokenStart,
@@ -330,7 +699,7 @@ func (t *StreamJSONTokenizer) continueString(token Token) Token {
 		TokenStart: token.TokenStart,
 		TokenEnd:   t.position,
 		TokenType:  token.TokenType,
-		Content:    t.buildString(token.TokenStart, t.position),
+		Content:    t.partialString(token.TokenStart, t.position),
 		Completed:  false,
 	}
 }
@@ -353,7 +722,7 @@ func (t *StreamJSONTokenizer) parseNumber(startPos int) Token {
 	}
 
 	// Check if number is complete
-	completed := false
+	completed := t.final
 	if t.position < len(t.buffer) {
 		// If there's more content, check if next char would continue the number
 		nextChar := t.buffer[t.position]
@@ -362,18 +731,18 @@ func (t *StreamJSONTokenizer) parseNumber(startPos int) Token {
 			completed = true
 		}
 	}
-	// If we're at the end of content, the number is incomplete until terminated by another token
+	// If we're at the end of content, the number is incomplete until terminated by another token, unless the input is final
 
 	token := Token{
 		TokenStart: startPos,
 		TokenEnd:   t.position,
 		TokenType:  Number,
-		Content:    t.buildString(startPos, t.position),
+		Content:    t.tokenString(startPos, t.position, completed),
 		Completed:  completed,
 	}
 
 	if !completed {
-		t.lastToken = &token
+		t.lastToken = savedToken(token)
 	}
 
 	return token
@@ -391,7 +760,7 @@ func (t *StreamJSONTokenizer) continueNumber(token Token) Token {
 	}
 
 	// Check if number is now complete
-	completed := false
+	completed := t.final
 	if t.position < len(t.buffer) {
 		// If there's more content, check if next char would continue the number
 		nextChar := t.buffer[t.position]
@@ -400,13 +769,13 @@ func (t *StreamJSONTokenizer) continueNumber(token Token) Token {
 			completed = true
 		}
 	}
-	// If we're at the end of content, the number might still be incomplete
+	// If we're at the end of content, the number might still be incomplete, unless the input is final
 
 	return Token{
 		TokenStart: token.TokenStart,
 		TokenEnd:   t.position,
 		TokenType:  Number,
-		Content:    t.buildString(token.TokenStart, t.position),
+		Content:    t.tokenString(token.TokenStart, t.position, completed),
 		Completed:  completed,
 	}
 }
@@ -429,8 +798,10 @@ func (t *StreamJSONTokenizer) parseBool(startPos int) Token {
 	// Check if we can match the full expected word
 	for i := 0; i < len(expected) && t.position < len(t.buffer); i++ {
 		if t.buffer[t.position] != expected[i] {
-			// Invalid boolean
-			t.position++
+			// Invalid boolean, leaving a delimiter for the next token
+			if isLetter(t.buffer[t.position]) {
+				t.position++
+			}
 			return Token{
 				TokenStart: startPos,
 				TokenEnd:   t.position,
@@ -442,26 +813,9 @@ func (t *StreamJSONTokenizer) parseBool(startPos int) Token {
 		t.position++
 	}
 
-	// Check if we've read the complete word
+	// Check if we've read the complete word. Letters after it start the
+	// next token, so the result doesn't depend on where a chunk ends.
 	if t.position-startPos == len(expected) {
-		// Check if next character is a valid terminator
-		if t.position < len(t.buffer) {
-			nextChar := t.buffer[t.position]
-			if isLetter(nextChar) {
-				// Still part of an invalid word
-				for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-					t.position++
-				}
-				return Token{
-					TokenStart: startPos,
-					TokenEnd:   t.position,
-					TokenType:  Invalid,
-					Content:    t.buildString(startPos, t.position),
-					Completed:  true,
-				}
-			}
-		}
-
 		// Complete and valid boolean
 		return Token{
 			TokenStart: startPos,
@@ -477,7 +831,7 @@ func (t *StreamJSONTokenizer) parseBool(startPos int) Token {
 		TokenStart: startPos,
 		TokenEnd:   t.position,
 		TokenType:  Bool,
-		Content:    t.buildString(startPos, t.position),
+		Content:    t.partialString(startPos, t.position),
 		Completed:  false,
 	}
 	t.lastToken = &token
@@ -499,8 +853,8 @@ func (t *StreamJSONTokenizer) continueBool(token Token) Token {
 	// Continue matching from where we left off
 	for i := tokenLen; i < len(expected) && t.position < len(t.buffer); i++ {
 		if t.buffer[t.position] != expected[i] {
-			// Invalid boolean
-			for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
+			// Invalid boolean, ending where parseBool would
+			if isLetter(t.buffer[t.position]) {
 				t.position++
 			}
 			return Token{
@@ -516,21 +870,6 @@ func (t *StreamJSONTokenizer) continueBool(token Token) Token {
 
 	// Check if complete
 	if t.position-token.TokenStart == len(expected) {
-		// Check terminator
-		if t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-			// Invalid - continues with more letters
-			for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-				t.position++
-			}
-			return Token{
-				TokenStart: token.TokenStart,
-				TokenEnd:   t.position,
-				TokenType:  Invalid,
-				Content:    t.buildString(token.TokenStart, t.position),
-				Completed:  true,
-			}
-		}
-
 		return Token{
 			TokenStart: token.TokenStart,
 			TokenEnd:   t.position,
@@ -544,7 +883,7 @@ func (t *StreamJSONTokenizer) continueBool(token Token) Token {
 		TokenStart: token.TokenStart,
 		TokenEnd:   t.position,
 		TokenType:  Bool,
-		Content:    t.buildString(token.TokenStart, t.position),
+		Content:    t.partialString(token.TokenStart, t.position),
 		Completed:  false,
 	}
 }
@@ -554,8 +893,10 @@ func (t *StreamJSONTokenizer) parseNull(startPos int) Token {
 	// Check if we can match "null"
 	for i := 0; i < len(nullBytes) && t.position < len(t.buffer); i++ {
 		if t.buffer[t.position] != nullBytes[i] {
-			// Invalid null
-			t.position++
+			// Invalid null, leaving a delimiter for the next token
+			if isLetter(t.buffer[t.position]) {
+				t.position++
+			}
 			return Token{
 				TokenStart: startPos,
 				TokenEnd:   t.position,
@@ -567,26 +908,9 @@ func (t *StreamJSONTokenizer) parseNull(startPos int) Token {
 		t.position++
 	}
 
-	// Check if we've read the complete word
+	// Check if we've read the complete word. Letters after it start the
+	// next token, so the result doesn't depend on where a chunk ends.
 	if t.position-startPos == len(nullBytes) {
-		// Check if next character is a valid terminator
-		if t.position < len(t.buffer) {
-			nextChar := t.buffer[t.position]
-			if isLetter(nextChar) {
-				// Still part of an invalid word
-				for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-					t.position++
-				}
-				return Token{
-					TokenStart: startPos,
-					TokenEnd:   t.position,
-					TokenType:  Invalid,
-					Content:    t.buildString(startPos, t.position),
-					Completed:  true,
-				}
-			}
-		}
-
 		// Complete and valid null
 		return Token{
 			TokenStart: startPos,
@@ -602,7 +926,7 @@ func (t *StreamJSONTokenizer) parseNull(startPos int) Token {
 		TokenStart: startPos,
 		TokenEnd:   t.position,
 		TokenType:  Null,
-		Content:    t.buildString(startPos, t.position),
+		Content:    t.partialString(startPos, t.position),
 		Completed:  false,
 	}
 	t.lastToken = &token
@@ -616,8 +940,8 @@ func (t *StreamJSONTokenizer) continueNull(token Token) Token {
 	// Continue matching from where we left off
 	for i := tokenLen; i < len(nullBytes) && t.position < len(t.buffer); i++ {
 		if t.buffer[t.position] != nullBytes[i] {
-			// Invalid null
-			for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
+			// Invalid null, ending where parseNull would
+			if isLetter(t.buffer[t.position]) {
 				t.position++
 			}
 			return Token{
@@ -633,21 +957,6 @@ func (t *StreamJSONTokenizer) continueNull(token Token) Token {
 
 	// Check if complete
 	if t.position-token.TokenStart == len(nullBytes) {
-		// Check terminator
-		if t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-			// Invalid - continues with more letters
-			for t.position < len(t.buffer) && isLetter(t.buffer[t.position]) {
-				t.position++
-			}
-			return Token{
-				TokenStart: token.TokenStart,
-				TokenEnd:   t.position,
-				TokenType:  Invalid,
-				Content:    t.buildString(token.TokenStart, t.position),
-				Completed:  true,
-			}
-		}
-
 		return Token{
 			TokenStart: token.TokenStart,
 			TokenEnd:   t.position,
@@ -661,7 +970,7 @@ func (t *StreamJSONTokenizer) continueNull(token Token) Token {
 		TokenStart: token.TokenStart,
 		TokenEnd:   t.position,
 		TokenType:  Null,
-		Content:    t.buildString(token.TokenStart, t.position),
+		Content:    t.partialString(token.TokenStart, t.position),
 		Completed:  false,
 	}
 }
@@ -670,3 +979,97 @@ func (t *StreamJSONTokenizer) continueNull(token Token) Token {
 func isLetter(char byte) bool {
 	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
 }
+
+// isWordStart checks if character can start a bare word in repair mode
+func isWordStart(char byte) bool {
+	return isLetter(char) || char == '_' || char == '$'
+}
+
+// isWordChar checks if character can continue a bare word in repair mode
+func isWordChar(char byte) bool {
+	return isWordStart(char) || (char >= '0' && char <= '9')
+}
+
+// parseWord parses a bare word in repair mode: Python and JSON literals,
+// or an unquoted object key
+func (t *StreamJSONTokenizer) parseWord(startPos int) Token {
+	return t.continueWord(Token{TokenStart: startPos, TokenEnd: startPos})
+}
+
+// continueWord continues parsing a bare word. Keys may also contain
+// hyphens, as in content-type.
+func (t *StreamJSONTokenizer) continueWord(token Token) Token {
+	for t.position < len(t.buffer) && (isWordChar(t.buffer[t.position]) || t.expectingKey && t.buffer[t.position] == '-') {
+		t.position++
+	}
+
+	word := t.buffer[token.TokenStart:t.position]
+	tokenType, completed := t.classifyWord(word, t.position < len(t.buffer) || t.final)
+
+	token = Token{
+		TokenStart: token.TokenStart,
+		TokenEnd:   t.position,
+		TokenType:  tokenType,
+		Content:    t.tokenString(token.TokenStart, t.position, completed),
+		Completed:  completed,
+	}
+
+	t.inWord = !completed
+	if !completed {
+		t.lastToken = savedToken(token)
+	}
+	return token
+}
+
+// literalType returns the token type of a bare word literal
+func (t *StreamJSONTokenizer) literalType(word []byte) (TokenType, bool) {
+	if t.nonFinite {
+		if _, ok := nonFiniteLiterals[string(word)]; ok {
+			return Number, true
+		}
+	}
+	tokenType, ok := wordLiterals[string(word)]
+	return tokenType, ok
+}
+
+// literalPrefix returns the token type of a bare word literal that word is
+// a prefix of
+func (t *StreamJSONTokenizer) literalPrefix(word []byte) (TokenType, bool) {
+	if t.nonFinite {
+		for literal := range nonFiniteLiterals {
+			if len(word) <= len(literal) && literal[:len(word)] == string(word) {
+				return Number, true
+			}
+		}
+	}
+	for literal, tokenType := range wordLiterals {
+		if len(word) <= len(literal) && literal[:len(word)] == string(word) {
+			return tokenType, true
+		}
+	}
+	return 0, false
+}
+
+// classifyWord determines the token type of a bare word. Unterminated words
+// stay incomplete until a delimiter follows them.
+func (t *StreamJSONTokenizer) classifyWord(word []byte, terminated bool) (TokenType, bool) {
+	if terminated {
+		if tokenType, ok := t.literalType(word); ok {
+			return tokenType, true
+		}
+		if t.expectingKey {
+			return ObjectKey, true
+		}
+		return Invalid, true
+	}
+
+	if tokenType, ok := t.literalPrefix(word); ok {
+		return tokenType, false
+	}
+	if t.expectingKey {
+		return ObjectKey, false
+	}
+	// Can never become valid, but its extent must not depend on where the
+	// chunk ends
+	return Invalid, false
+}
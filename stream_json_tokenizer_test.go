@@ -15,6 +15,9 @@
 package streamjson
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -323,6 +326,82 @@ func TestInvalidTokens(t *testing.T) {
 	}
 }
 
+func TestKeyClassificationInContainers(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`[{"a":"b"},"c",["d",{"e":["f","g"],"h":{}}],"i"]`)
+
+	expected := []struct {
+		tokenType TokenType
+		content   string
+		container Container
+		depth     int
+	}{
+		{ArrayStart, "[", TopLevel, 0},
+		{ObjectStart, "{", InArray, 1}, {ObjectKey, `"a"`, InObject, 2}, {Colon, ":", InObject, 2},
+		{String, `"b"`, InObject, 2}, {ObjectEnd, "}", InArray, 1},
+		{Comma, ",", InArray, 1}, {String, `"c"`, InArray, 1}, {Comma, ",", InArray, 1},
+		{ArrayStart, "[", InArray, 1}, {String, `"d"`, InArray, 2}, {Comma, ",", InArray, 2},
+		{ObjectStart, "{", InArray, 2}, {ObjectKey, `"e"`, InObject, 3}, {Colon, ":", InObject, 3},
+		{ArrayStart, "[", InObject, 3}, {String, `"f"`, InArray, 4}, {Comma, ",", InArray, 4},
+		{String, `"g"`, InArray, 4}, {ArrayEnd, "]", InObject, 3}, {Comma, ",", InObject, 3},
+		{ObjectKey, `"h"`, InObject, 3}, {Colon, ":", InObject, 3},
+		{ObjectStart, "{", InObject, 3}, {ObjectEnd, "}", InObject, 3},
+		{ObjectEnd, "}", InArray, 2}, {ArrayEnd, "]", InArray, 1}, {Comma, ",", InArray, 1},
+		{String, `"i"`, InArray, 1}, {ArrayEnd, "]", TopLevel, 0},
+	}
+
+	for i, exp := range expected {
+		token := tokenizer.NextToken()
+		if token.TokenType != exp.tokenType || token.Content != exp.content ||
+			token.Container != exp.container || token.Depth != exp.depth {
+			t.Errorf("Token %d: expected %v %s in %v at depth %d, got %v %s in %v at depth %d",
+				i, exp.tokenType, exp.content, exp.container, exp.depth,
+				token.TokenType, token.Content, token.Container, token.Depth)
+		}
+	}
+}
+
+func TestPeekKeepsContainers(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`{"a":[1],"b":2}`)
+
+	for {
+		peeked := tokenizer.Peek()
+		token := tokenizer.NextToken()
+		if peeked != token {
+			t.Fatalf("Expected Peek to match NextToken, got %v and %v", peeked, token)
+		}
+		if token.TokenType == EOF {
+			break
+		}
+	}
+}
+
+func TestTokenLineColumn(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append("{\n  \"name\": \"Al")
+
+	var positions [][2]int
+	for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		positions = append(positions, [2]int{token.Line, token.Column})
+		if !token.Completed {
+			break
+		}
+	}
+
+	// The rest arrives in a later chunk, after the consumed bytes are dropped
+	tokenizer.compact(tokenizer.consumed())
+	tokenizer.Append("ice\",\n\n  \"n\": 1\n}")
+	for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		positions = append(positions, [2]int{token.Line, token.Column})
+	}
+
+	want := [][2]int{{1, 1}, {2, 3}, {2, 9}, {2, 11}, {2, 11}, {2, 18}, {4, 3}, {4, 6}, {4, 8}, {5, 1}}
+	if !reflect.DeepEqual(positions, want) {
+		t.Errorf("Unexpected positions:\n got %v\nwant %v", positions, want)
+	}
+}
+
 func TestComplexPartialJSON(t *testing.T) {
 	tokenizer := NewStreamJSONTokenizer()
 
@@ -401,3 +480,315 @@ func TestPositionTracking(t *testing.T) {
 		t.Errorf("ObjectEnd position: expected 10-11, got %d-%d", token.TokenStart, token.TokenEnd)
 	}
 }
+
+func TestRepairModeTokens(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.repair = true
+	tokenizer.Append(`{name: 'it\'s', ok: True, none: None, off: False} `)
+
+	expected := []struct {
+		tokenType TokenType
+		content   string
+	}{
+		{ObjectStart, "{"}, {ObjectKey, "name"}, {Colon, ":"}, {String, `'it\'s'`},
+		{Comma, ","}, {ObjectKey, "ok"}, {Colon, ":"}, {Bool, "True"},
+		{Comma, ","}, {ObjectKey, "none"}, {Colon, ":"}, {Null, "None"},
+		{Comma, ","}, {ObjectKey, "off"}, {Colon, ":"}, {Bool, "False"},
+		{ObjectEnd, "}"}, {EOF, ""},
+	}
+
+	for i, exp := range expected {
+		token := tokenizer.NextToken()
+		if token.TokenType != exp.tokenType || token.Content != exp.content || !token.Completed {
+			t.Errorf("Token %d: expected %v %q, got %v", i, exp.tokenType, exp.content, token)
+		}
+	}
+}
+
+func TestRepairModePartialWords(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.repair = true
+
+	tokenizer.Append(`{Tr`)
+	tokenizer.NextToken() // {
+	token := tokenizer.NextToken()
+	if token.Completed || token.TokenType != Bool {
+		t.Errorf("Expected incomplete literal prefix, got %v", token)
+	}
+
+	// The word turns out to be a key
+	tokenizer.Append(`ee:`)
+	token = tokenizer.NextToken()
+	if token.TokenType != ObjectKey || token.Content != "Tree" || !token.Completed {
+		t.Errorf("Expected ObjectKey 'Tree', got %v", token)
+	}
+
+	// Unknown words in value position are rejected once they end, so the
+	// token doesn't depend on where the chunk ends
+	tokenizer.Append(`foo`)
+	tokenizer.NextToken() // :
+	token = tokenizer.NextToken()
+	if token.TokenType != Invalid || token.Completed {
+		t.Errorf("Expected incomplete Invalid token, got %v", token)
+	}
+
+	tokenizer.Append(`bar,`)
+	token = tokenizer.NextToken()
+	if token.TokenType != Invalid || token.Content != "foobar" || !token.Completed {
+		t.Errorf("Expected completed Invalid token 'foobar', got %v", token)
+	}
+}
+
+func TestSingleQuotesRequireRepairMode(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`'a'`)
+
+	token := tokenizer.NextToken()
+	if token.TokenType != Invalid || token.Content != "'" {
+		t.Errorf("Expected Invalid quote without repair mode, got %v", token)
+	}
+}
+
+func TestLenientKeyTokens(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.lenientKeys = true
+	tokenizer.Append(`{key: "v", 'quoted': 1, content-type: None}`)
+
+	expected := []struct {
+		tokenType TokenType
+		content   string
+	}{
+		{ObjectStart, "{"}, {ObjectKey, "key"}, {Colon, ":"}, {String, `"v"`},
+		{Comma, ","}, {ObjectKey, "'quoted'"}, {Colon, ":"}, {Number, "1"},
+		{Comma, ","}, {ObjectKey, "content-type"}, {Colon, ":"}, {Invalid, "N"},
+	}
+
+	// Values keep standard JSON rules
+	for i, exp := range expected {
+		token := tokenizer.NextToken()
+		if token.TokenType != exp.tokenType || token.Content != exp.content || !token.Completed {
+			t.Errorf("Token %d: expected %v %q, got %v", i, exp.tokenType, exp.content, token)
+		}
+	}
+}
+
+func TestPeek(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`{"key":"val`)
+
+	// Peeking does not consume
+	if token := tokenizer.Peek(); token.TokenType != ObjectStart {
+		t.Errorf("Expected ObjectStart, got %v", token.TokenType)
+	}
+	if token := tokenizer.NextToken(); token.TokenType != ObjectStart {
+		t.Errorf("Expected ObjectStart after peek, got %v", token.TokenType)
+	}
+
+	tokenizer.NextToken() // key
+	tokenizer.NextToken() // colon
+
+	// Peeking an incomplete string leaves it pending
+	if token := tokenizer.Peek(); token.TokenType != String || token.Completed {
+		t.Errorf("Expected incomplete String, got %v", token)
+	}
+	tokenizer.Append(`ue"}`)
+	if token := tokenizer.Peek(); token.Content != `"value"` || !token.Completed {
+		t.Errorf("Expected completed string after append, got %v", token)
+	}
+	if token := tokenizer.NextToken(); token.Content != `"value"` {
+		t.Errorf("Expected completed string, got %v", token)
+	}
+}
+
+func TestTokensIterator(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`[1, 2`)
+
+	var types []string
+	for token := range tokenizer.Tokens() {
+		types = append(types, token.TokenType.String())
+	}
+	// The first number is terminated by the comma, the second is pending
+	if strings.Join(types, " ") != "ArrayStart Number Comma" {
+		t.Errorf("Unexpected tokens %v", types)
+	}
+
+	tokenizer.Append(`3, true]`)
+	types = types[:0]
+	for token := range tokenizer.Tokens() {
+		types = append(types, token.TokenType.String())
+		if token.TokenType == Bool {
+			break
+		}
+	}
+	if strings.Join(types, " ") != "Number Comma Bool" {
+		t.Errorf("Unexpected tokens after append %v", types)
+	}
+
+	// Breaking out does not lose the following token
+	if token := tokenizer.NextToken(); token.TokenType != ArrayEnd {
+		t.Errorf("Expected ArrayEnd, got %v", token.TokenType)
+	}
+}
+
+func TestTokenizerReset(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(`{"key":"unfinished`)
+	for token := tokenizer.NextToken(); token.Completed && token.TokenType != EOF; token = tokenizer.NextToken() {
+	}
+
+	tokenizer.Reset()
+	tokenizer.Append(`[1]`)
+
+	if token := tokenizer.NextToken(); token.TokenType != ArrayStart || token.TokenStart != 0 {
+		t.Errorf("Expected ArrayStart at 0 after Reset, got %v", token)
+	}
+}
+
+func TestTokenizerZeroAllocations(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	input := `{"id":7,"ok":true,"tags":["a",null],"score":0.5}`
+
+	// The first pass interns the short token content
+	tokenizer.Append(input)
+	for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		tokenizer.Reset()
+		tokenizer.Append(input)
+		for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for repeated tokens, got %v", allocs)
+	}
+}
+
+// benchmarkInput is a tool-call style document with repeated keys and short values
+var benchmarkInput = func() string {
+	var b strings.Builder
+	b.WriteString(`{"results":[`)
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"id":7,"type":"item","active":true,"score":0.5,"parent":null,"tags":["a","b"]}`)
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}()
+
+func BenchmarkTokenizer(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkInput)))
+	tokenizer := NewStreamJSONTokenizer()
+	for i := 0; i < b.N; i++ {
+		tokenizer.Reset()
+		tokenizer.Append(benchmarkInput)
+		for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		}
+	}
+}
+
+// benchmarkDistinctInput has a distinct short value in every object, the
+// worst case for the intern table
+var benchmarkDistinctInput = func() string {
+	var b strings.Builder
+	b.WriteString(`{"results":[`)
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"id":%d,"name":"item-%d","score":%d.25}`, i*7919, i, i)
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}()
+
+func BenchmarkTokenizerDistinct(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkDistinctInput)))
+	tokenizer := NewStreamJSONTokenizer()
+	for i := 0; i < b.N; i++ {
+		tokenizer.Reset()
+		tokenizer.Append(benchmarkDistinctInput)
+		for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		}
+	}
+}
+
+func TestPartialTokensNotInterned(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	for _, chunk := range []string{`["abc`, `def","12`} {
+		tokenizer.Append(chunk)
+		for range tokenizer.Tokens() {
+		}
+	}
+
+	if _, ok := tokenizer.interned["abc"]; ok {
+		t.Errorf("Expected the prefix of a streaming string not to be interned")
+	}
+	if _, ok := tokenizer.interned["12"]; ok {
+		t.Errorf("Expected the prefix of a streaming number not to be interned")
+	}
+	if _, ok := tokenizer.interned[`"abcdef"`]; !ok {
+		t.Errorf("Expected the completed string to be interned, got %v", tokenizer.interned)
+	}
+}
+
+func TestPassthroughTokens(t *testing.T) {
+	input := "\uFEFF{\n  \"a\": [1,\t2],\r\n  \"b\" : null\n}\n"
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.SetPassthrough(true)
+
+	var rebuilt strings.Builder
+	var types []TokenType
+	for i := 0; i < len(input); i += 3 {
+		tokenizer.Append(input[i:min(i+3, len(input))])
+		for token := range tokenizer.Tokens() {
+			rebuilt.WriteString(token.Content)
+			types = append(types, token.TokenType)
+		}
+	}
+	tokenizer.Append(" ")
+	for token := range tokenizer.Tokens() {
+		rebuilt.WriteString(token.Content)
+	}
+
+	if rebuilt.String() != input+" " {
+		t.Errorf("Expected the input byte for byte, got %q", rebuilt.String())
+	}
+	if types[0] != RawText || types[1] != ObjectStart || types[2] != Whitespace {
+		t.Errorf("Expected a BOM, brace and whitespace first, got %v", types[:3])
+	}
+}
+
+func TestPassthroughComments(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.SetPassthrough(true)
+	tokenizer.comments = true
+	tokenizer.Append("[1, /* two */ 2 // end\n]")
+
+	var got []string
+	for token := range tokenizer.Tokens() {
+		got = append(got, token.TokenType.String()+":"+token.Content)
+	}
+	want := []string{
+		"ArrayStart:[", "Number:1", "Comma:,", "Whitespace: ", "RawText:/* two */", "Whitespace: ",
+		"Number:2", "Whitespace: ", "RawText:// end\n", "ArrayEnd:]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestPassthroughOff(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.Append(" [ 1 ] ")
+	for token := range tokenizer.Tokens() {
+		if token.TokenType == Whitespace || token.TokenType == RawText {
+			t.Errorf("Expected whitespace to be skipped by default, got %v", token)
+		}
+	}
+}
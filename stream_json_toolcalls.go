@@ -0,0 +1,125 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"sort"
+)
+
+// ToolCall is one function call being streamed by a model. Its arguments
+// are parsed incrementally as fragments arrive.
+type ToolCall struct {
+	Index  int    // Position of the call in the response
+	ID     string // Provider-assigned call ID, once known
+	Name   string // Function name, once known
+	parser *StreamJSONParser
+}
+
+// Parser returns the parser holding the call's arguments
+func (c *ToolCall) Parser() *StreamJSONParser {
+	return c.parser
+}
+
+// Arguments returns the arguments parsed so far, including strings that are
+// still streaming, or nil before any argument has arrived
+func (c *ToolCall) Arguments() interface{} {
+	return c.parser.Get()
+}
+
+// IsCompleted reports whether the argument object has been closed
+func (c *ToolCall) IsCompleted() bool {
+	return c.parser.IsCompleted()
+}
+
+// ToolCallAccumulator collects streamed tool call argument fragments, running
+// one parser per call. Calls are identified by their index in the response,
+// as in OpenAI's tool_calls deltas and Anthropic's content block index:
+//
+//   - OpenAI: call Start(index, id, name) with the fields of each delta
+//     (empty fields are ignored) and Append(index, function.arguments)
+//   - Anthropic: call Start on content_block_start and Append with the
+//     partial_json of each input_json_delta
+type ToolCallAccumulator struct {
+	calls   map[int]*ToolCall
+	options []Option
+}
+
+// NewToolCallAccumulator creates an accumulator whose parsers use opts
+func NewToolCallAccumulator(opts ...Option) *ToolCallAccumulator {
+	return &ToolCallAccumulator{
+		calls:   make(map[int]*ToolCall),
+		options: opts,
+	}
+}
+
+// Start records the ID and name of the call at index, creating the call if
+// it is new. Empty values leave known fields unchanged, so it can be called
+// with every delta.
+func (a *ToolCallAccumulator) Start(index int, id, name string) *ToolCall {
+	call := a.call(index)
+	if id != "" {
+		call.ID = id
+	}
+	if name != "" {
+		call.Name = name
+	}
+	return call
+}
+
+// Append feeds an argument fragment to the call at index, creating the call
+// if it is new
+func (a *ToolCallAccumulator) Append(index int, fragment string) {
+	if fragment == "" {
+		return
+	}
+	a.call(index).parser.Append(fragment)
+}
+
+// Call returns the call at index, or nil if it has not been seen
+func (a *ToolCallAccumulator) Call(index int) *ToolCall {
+	return a.calls[index]
+}
+
+// CallByID returns the call with the given ID, or nil if there is none
+func (a *ToolCallAccumulator) CallByID(id string) *ToolCall {
+	for _, call := range a.calls {
+		if call.ID == id {
+			return call
+		}
+	}
+	return nil
+}
+
+// Calls returns all calls seen so far, ordered by index
+func (a *ToolCallAccumulator) Calls() []*ToolCall {
+	calls := make([]*ToolCall, 0, len(a.calls))
+	for _, call := range a.calls {
+		calls = append(calls, call)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Index < calls[j].Index
+	})
+	return calls
+}
+
+// call returns the call at index, creating it if needed
+func (a *ToolCallAccumulator) call(index int) *ToolCall {
+	call, ok := a.calls[index]
+	if !ok {
+		call = &ToolCall{Index: index, parser: NewStreamJSONParser(a.options...)}
+		a.calls[index] = call
+	}
+	return call
+}
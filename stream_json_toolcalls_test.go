@@ -0,0 +1,72 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestToolCallAccumulatorOpenAI(t *testing.T) {
+	acc := NewToolCallAccumulator()
+
+	// tool_calls deltas as sent by OpenAI, two calls interleaved
+	acc.Start(0, "call_a", "get_weather")
+	acc.Append(0, `{"city":"Par`)
+	acc.Start(1, "call_b", "get_time")
+	acc.Append(1, `{"zone":`)
+
+	first := acc.Call(0)
+	if first.Name != "get_weather" || first.ID != "call_a" {
+		t.Errorf("Unexpected call %+v", first)
+	}
+	if first.Parser().Get("city") != "Par" || first.IsCompleted() {
+		t.Errorf("Expected partial arguments, got %v", first.Arguments())
+	}
+
+	acc.Start(0, "", "")
+	acc.Append(0, `is"}`)
+	acc.Append(1, `"UTC"}`)
+
+	if !first.IsCompleted() || first.Parser().Get("city") != "Paris" || first.Name != "get_weather" {
+		t.Errorf("Expected completed arguments, got %v", first.Arguments())
+	}
+	if acc.CallByID("call_b").Parser().Get("zone") != "UTC" {
+		t.Errorf("Expected second call by ID, got %v", acc.CallByID("call_b").Arguments())
+	}
+	if acc.CallByID("missing") != nil || acc.Call(5) != nil {
+		t.Error("Expected nil for unknown calls")
+	}
+}
+
+func TestToolCallAccumulatorOrder(t *testing.T) {
+	acc := NewToolCallAccumulator(WithRepair())
+
+	// Anthropic content blocks may start at any index
+	acc.Start(3, "toolu_2", "search")
+	acc.Start(1, "toolu_1", "lookup")
+	acc.Append(1, `{query: 'x'}`)
+	acc.Append(3, "")
+
+	calls := acc.Calls()
+	if len(calls) != 2 || calls[0].Index != 1 || calls[1].Index != 3 {
+		t.Fatalf("Expected calls ordered by index, got %v", calls)
+	}
+	if calls[0].Parser().Get("query") != "x" {
+		t.Errorf("Expected options to apply to each parser, got %v", calls[0].Arguments())
+	}
+	if calls[1].Arguments() != nil {
+		t.Errorf("Expected nil arguments before any fragment, got %v", calls[1].Arguments())
+	}
+}
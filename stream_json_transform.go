@@ -0,0 +1,113 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transformer converts a completed value, such as a timestamp string into a
+// time.Time
+type Transformer func(value interface{}) (interface{}, error)
+
+// transformSubscription is a transformer registered for a path pattern
+type transformSubscription struct {
+	pattern   []string
+	transform func(path []string, value interface{}) (interface{}, error)
+}
+
+// TransformError reports a value a transformer rejected. The value is kept
+// as parsed.
+type TransformError struct {
+	Path  string      // Dotted path of the value, "" for the root
+	Value interface{} // Value passed to the transformer
+	Err   error       // Error returned by the transformer
+}
+
+// Error implements the error interface
+func (e *TransformError) Error() string {
+	return fmt.Sprintf("streamjson: transform of %q failed: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the transformer's error
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// Transform registers a transformer for the string, number, bool or null
+// values at a dotted path, where "*" matches any single key or index. It
+// runs when the value completes, and every accessor, callback and event
+// sees its result from then on; partial values of a streaming string are
+// not transformed. Transformers for the same value run in registration
+// order, each receiving the previous result. Register transformers before
+// appending content.
+func (p *StreamJSONParser) Transform(path string, transform Transformer) {
+	p.transforms = append(p.transforms, transformSubscription{
+		pattern: p.normalizePath(splitPath(path)),
+		transform: func(_ []string, value interface{}) (interface{}, error) {
+			return transform(value)
+		},
+	})
+}
+
+// TransformAll registers a transformer for every completed string, number,
+// bool or null value, receiving its path. It runs after the transformers
+// registered with Transform for the same value.
+func (p *StreamJSONParser) TransformAll(transform func(path []string, value interface{}) (interface{}, error)) {
+	p.globalTransforms = append(p.globalTransforms, transform)
+}
+
+// TransformErrors returns the values transformers rejected so far
+func (p *StreamJSONParser) TransformErrors() []*TransformError {
+	return p.transformErrors
+}
+
+// applyTransforms replaces the value of a completed node with the result of
+// the transformers matching its path
+func (p *StreamJSONParser) applyTransforms(path []string, node *Node) {
+	apply := func(transform func(path []string, value interface{}) (interface{}, error)) {
+		value, err := transform(path, node.Value)
+		if err != nil {
+			p.transformErrors = append(p.transformErrors, &TransformError{
+				Path:  strings.Join(path, "."),
+				Value: node.Value,
+				Err:   err,
+			})
+			return
+		}
+		node.Value = value
+	}
+
+	for _, sub := range p.transforms {
+		if matchPath(sub.pattern, path) {
+			apply(sub.transform)
+		}
+	}
+	for _, transform := range p.globalTransforms {
+		apply(transform)
+	}
+}
+
+// ParseRFC3339 is a Transformer that parses an RFC 3339 timestamp string,
+// as produced by time.Time's JSON encoding, into a time.Time
+func ParseRFC3339(value interface{}) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string, got %T", value)
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamJSONParserTransform(t *testing.T) {
+	input := `{"created_at": "2024-05-01T12:30:00Z", "events": [{"at": "2024-05-02T08:00:00Z"}], "name": "job"}`
+
+	// Whole input and one byte at a time
+	for _, chunk := range []int{len(input), 1} {
+		parser := NewStreamJSONParser()
+		parser.Transform("created_at", ParseRFC3339)
+		parser.Transform("events.*.at", ParseRFC3339)
+
+		var delivered interface{}
+		parser.OnValue("created_at", func(value interface{}, complete bool) {
+			if complete {
+				delivered = value
+			}
+		})
+		for i := 0; i < len(input); i += chunk {
+			parser.Append(input[i:min(i+chunk, len(input))])
+		}
+
+		expected := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+		if created, ok := parser.Get("created_at").(time.Time); !ok || !created.Equal(expected) {
+			t.Errorf("%d: expected time %v, got %v", chunk, expected, parser.Get("created_at"))
+		}
+		if delivered != parser.Get("created_at") {
+			t.Errorf("%d: expected OnValue to receive the transformed value, got %v", chunk, delivered)
+		}
+		if _, ok := parser.Get("events", "0", "at").(time.Time); !ok {
+			t.Errorf("%d: expected wildcard transform, got %T", chunk, parser.Get("events", "0", "at"))
+		}
+		if parser.Get("name") != "job" {
+			t.Errorf("%d: expected other values untouched, got %v", chunk, parser.Get("name"))
+		}
+	}
+}
+
+func TestStreamJSONParserTransformTyped(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Transform("at", ParseRFC3339)
+	parser.Append(`{"at": "2024-05-01T12:30:00Z"}`)
+
+	var target struct {
+		At time.Time `json:"at"`
+	}
+	if err := parser.Unmarshal(&target); err != nil || target.At.Year() != 2024 {
+		t.Errorf("Expected Unmarshal to store the time, got %v %v", target.At, err)
+	}
+
+	data, err := parser.MarshalJSON()
+	if err != nil || string(data) != `{"at":"2024-05-01T12:30:00Z"}` {
+		t.Errorf("Expected the time in JSON form, got %s %v", data, err)
+	}
+}
+
+func TestStreamJSONParserTransformAll(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Transform("code", func(value interface{}) (interface{}, error) {
+		return strings.ToLower(value.(string)), nil
+	})
+	var paths []string
+	parser.TransformAll(func(path []string, value interface{}) (interface{}, error) {
+		paths = append(paths, strings.Join(path, "."))
+		if s, ok := value.(string); ok {
+			return strings.TrimSpace(s), nil
+		}
+		return value, nil
+	})
+	parser.Append(`{"code": " ABC ", "n": 1}`)
+
+	if parser.Get("code") != "abc" {
+		t.Errorf("Expected transformers to chain, got %q", parser.Get("code"))
+	}
+	if strings.Join(paths, ",") != "code,n" {
+		t.Errorf("Expected the global hook for every value, got %v", paths)
+	}
+}
+
+func TestStreamJSONParserTransformErrors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Transform("at", ParseRFC3339)
+	events := parser.Events()
+	parser.Append(`{"at": "yesterday"}`)
+
+	if parser.Get("at") != "yesterday" {
+		t.Errorf("Expected the value to be kept, got %v", parser.Get("at"))
+	}
+	errs := parser.TransformErrors()
+	var parseErr *time.ParseError
+	if len(errs) != 1 || errs[0].Path != "at" || !errors.As(errs[0], &parseErr) {
+		t.Errorf("Expected a transform error for at, got %v", errs)
+	}
+
+	// The text still arrives in full through the event stream
+	var text string
+	for event := range events {
+		if event.Type == StringDelta {
+			text += event.Delta
+		}
+	}
+	if text != "yesterday" {
+		t.Errorf("Expected the full text in deltas, got %q", text)
+	}
+}
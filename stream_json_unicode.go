@@ -0,0 +1,134 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Characters some model frontends inject between tokens: the byte order
+// mark, zero-width spaces and joiners, and the word joiner. They are
+// skipped like whitespace.
+var invisibleChars = [][]byte{
+	[]byte("\uFEFF"), []byte("\u200B"), []byte("\u200C"), []byte("\u200D"), []byte("\u2060"),
+}
+
+// Typographic double quotes accepted as string delimiters with smart quotes
+var smartQuotes = [][]byte{[]byte("\u201C"), []byte("\u201D")}
+
+// smartQuote is the quote of a string delimited by typographic quotes. Its
+// delimiters are multi-byte, so it never matches a byte of the content.
+const smartQuote = 0xFF
+
+// matchChar returns the length of the character of chars that b starts with,
+// or -1 if b ends inside one of them before more input arrives, or 0
+func matchChar(b []byte, chars [][]byte, final bool) int {
+	for _, c := range chars {
+		if bytes.HasPrefix(b, c) {
+			return len(c)
+		}
+		if !final && len(b) < len(c) && bytes.HasPrefix(c, b) {
+			return -1
+		}
+	}
+	return 0
+}
+
+// awaitingMultibyte reports whether the input ends inside a character that
+// may be skipped or start a string, so no token can be produced yet
+func (t *StreamJSONTokenizer) awaitingMultibyte() bool {
+	rest := t.buffer[t.position:]
+	if len(rest) == 0 || rest[0] < 0x80 {
+		return false
+	}
+	return matchChar(rest, invisibleChars, t.final) < 0 || t.smartQuotes && matchChar(rest, smartQuotes, t.final) < 0
+}
+
+// parseSmartString parses a string opened by a typographic quote
+func (t *StreamJSONTokenizer) parseSmartString(startPos, open int) Token {
+	t.quote = smartQuote
+	t.position += open
+
+	tokenType := String
+	if t.expectingKey {
+		tokenType = ObjectKey
+	}
+	token := t.continueSmartString(Token{TokenStart: startPos, TokenType: tokenType})
+	if !token.Completed {
+		t.lastToken = savedToken(token)
+	}
+	return token
+}
+
+// continueSmartString continues parsing a string opened by a typographic
+// quote, which either typographic quote closes. Its content is normalized
+// to an ASCII-quoted string, escaping the ASCII quotes inside.
+func (t *StreamJSONTokenizer) continueSmartString(token Token) Token {
+	contentStart := token.TokenStart + matchChar(t.buffer[token.TokenStart:], smartQuotes, true)
+	for t.position < len(t.buffer) {
+		char := t.buffer[t.position]
+		if t.escapeNext {
+			t.escapeNext = false
+			t.position++
+			continue
+		}
+		if char == '\\' {
+			t.escapeNext = true
+			t.position++
+			continue
+		}
+		if char >= 0x80 {
+			n := matchChar(t.buffer[t.position:], smartQuotes, t.final)
+			if n < 0 {
+				break // Wait for the rest of what may be the closing quote
+			}
+			if n > 0 {
+				content := smartContent(t.buffer[contentStart:t.position], true)
+				t.position += n
+				token.TokenEnd = t.position
+				token.Content = content
+				token.Completed = true
+				return token
+			}
+		}
+		t.position++
+	}
+
+	token.TokenEnd = t.position
+	token.Content = smartContent(t.buffer[contentStart:t.position], false)
+	token.Completed = false
+	return token
+}
+
+// smartContent returns the content of a typographically quoted string as an
+// ASCII-quoted one, closed if the string is complete
+func smartContent(content []byte, closed bool) string {
+	var b strings.Builder
+	b.Grow(len(content) + 2)
+	b.WriteByte('"')
+	escaped := false
+	for _, c := range content {
+		if c == '"' && !escaped {
+			b.WriteByte('\\')
+		}
+		escaped = c == '\\' && !escaped
+		b.WriteByte(c)
+	}
+	if closed {
+		b.WriteByte('"')
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserInvisibleCharacters(t *testing.T) {
+	input := "\uFEFF{\u200B\"a\": 1,\u200D \"b\"\u2060: \"x\u200By\"}"
+
+	// Every split point, including inside the multi-byte characters
+	for i := 1; i < len(input); i++ {
+		parser := NewStreamJSONParser(WithStrictMode())
+		parser.Append(input[:i])
+		parser.Append(input[i:])
+
+		if parser.Err() != nil || !parser.IsCompleted() {
+			t.Fatalf("Split at %d: err %v, completed %v", i, parser.Err(), parser.IsCompleted())
+		}
+		if parser.Get("a") != int64(1) || parser.Get("b") != "x\u200By" {
+			t.Errorf("Split at %d: unexpected values %v", i, parser.Get())
+		}
+	}
+}
+
+func TestStreamJSONParserSmartQuotes(t *testing.T) {
+	input := "{“name”: “say \"hi\" \\u201d”, “tags”: [“a”, \"b\", “c“]}"
+
+	for i := 1; i < len(input); i++ {
+		parser := NewStreamJSONParser(WithSmartQuotes(), WithStrictMode())
+		parser.Append(input[:i])
+		parser.Append(input[i:])
+
+		if parser.Err() != nil || !parser.IsCompleted() {
+			t.Fatalf("Split at %d: err %v, completed %v", i, parser.Err(), parser.IsCompleted())
+		}
+		if parser.Get("name") != "say \"hi\" ”" {
+			t.Errorf("Split at %d: unexpected name %q", i, parser.Get("name"))
+		}
+		if parser.Get("tags", "0") != "a" || parser.Get("tags", "1") != "b" || parser.Get("tags", "2") != "c" {
+			t.Errorf("Split at %d: unexpected tags %v", i, parser.Get("tags"))
+		}
+	}
+}
+
+func TestStreamJSONParserSmartQuotesPartial(t *testing.T) {
+	parser := NewStreamJSONParser(WithSmartQuotes())
+	parser.Append("{“msg”: “hello wor")
+	if parser.Get("msg") != "hello wor" {
+		t.Errorf("Expected partial string, got %q", parser.Get("msg"))
+	}
+
+	// The first byte of the closing quote is held back until it is known
+	parser.Append("ld\xe2")
+	if parser.Get("msg") != "hello world" {
+		t.Errorf("Expected partial string without the split quote, got %q", parser.Get("msg"))
+	}
+	parser.Append("\x80\x9d}")
+	if !parser.IsCompleted() || parser.Get("msg") != "hello world" {
+		t.Errorf("Expected completed string, got %q", parser.Get("msg"))
+	}
+}
+
+func TestStreamJSONParserSmartQuotesInsideStrings(t *testing.T) {
+	parser := NewStreamJSONParser(WithSmartQuotes(), WithStrictMode())
+	parser.Append(`{"quote": "she said “yes”"}`)
+
+	if parser.Err() != nil || parser.Get("quote") != "she said “yes”" {
+		t.Errorf("Expected smart quotes in ASCII strings to be kept, got %q (err %v)", parser.Get("quote"), parser.Err())
+	}
+}
+
+func TestStreamJSONParserSmartQuotesDisabled(t *testing.T) {
+	parser := NewStreamJSONParser(WithStrictMode())
+	parser.Append("{“a”: 1}")
+
+	if parser.Err() == nil {
+		t.Error("Expected smart quotes to be rejected without WithSmartQuotes")
+	}
+
+	repaired := NewStreamJSONParser(WithRepair())
+	repaired.Append("{“a”: 1}")
+	if repaired.Get("a") != int64(1) {
+		t.Errorf("Expected WithRepair to accept smart quotes, got %v", repaired.Get())
+	}
+}
+
+func TestTokenizerSmartQuoteOffsets(t *testing.T) {
+	tokenizer := NewStreamJSONTokenizer()
+	tokenizer.smartQuotes = true
+	tokenizer.Append("[“ab”]")
+
+	var tokens []Token
+	for token := tokenizer.NextToken(); token.TokenType != EOF; token = tokenizer.NextToken() {
+		tokens = append(tokens, token)
+	}
+	if len(tokens) < 2 || tokens[1].Content != `"ab"` {
+		t.Fatalf("Expected normalized string token, got %+v", tokens)
+	}
+	if tokens[1].TokenStart != 1 || tokens[1].TokenEnd != 9 {
+		t.Errorf("Expected offsets into the input, got %d-%d", tokens[1].TokenStart, tokens[1].TokenEnd)
+	}
+}
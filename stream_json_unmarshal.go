@@ -0,0 +1,634 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fieldInfo describes a struct field reachable from a JSON object key
+type fieldInfo struct {
+	name   string // JSON key from the tag or the field name
+	index  []int  // Index path for reflect.Value.FieldByIndex, through embedded structs
+	quoted bool   // Whether the tag has the ,string option, for a scalar field
+}
+
+// Types with dedicated handling for exact numbers
+var (
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+	bigFloatType   = reflect.TypeOf(big.Float{})
+)
+
+// Interfaces of types that decode themselves
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structFieldCache caches the decoded field list per struct type
+var structFieldCache sync.Map // map[reflect.Type][]fieldInfo
+
+// Unmarshal maps the current, possibly incomplete, AST into the value pointed
+// to by v using the same rules as encoding/json: `json` struct tags including
+// the ,string option, embedded struct promotion, exact and then
+// case-insensitive key matching, and int64/float64 conversion to the
+// target numeric type. Types implementing json.Unmarshaler, or
+// encoding.TextUnmarshaler for strings, decode themselves once their value
+// is complete, so custom types such as decimals and dates work; until then
+// they are left unchanged. Only the values present so far are filled in, including
+// the partial content of strings that are still streaming, so it can be called
+// repeatedly as more input arrives. Type mismatches are reported as
+// *json.UnmarshalTypeError after the remaining fields have been decoded.
+func (p *StreamJSONParser) Unmarshal(v interface{}) error {
+	defer p.guardRead("Unmarshal")()
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+
+	if p.root == nil {
+		return nil
+	}
+
+	d := nodeDecoder{parser: p}
+	d.decode(p.root, rv.Elem(), nil)
+	return d.err
+}
+
+// nodeDecoder decodes AST nodes into Go values, recording the first error
+type nodeDecoder struct {
+	parser *StreamJSONParser
+	err    error
+
+	base       []string     // Path of the node decode was called with
+	links      pathLinks    // Paths of the nodes below it
+	link       int          // Link of the node being decoded
+	structType reflect.Type // Innermost struct around the node being decoded
+	stack      []decodeTask // Nodes left to decode
+}
+
+// decodeTask is a node to decode into target, or with mapOf set, a decoded
+// map element to store under mapKey once the tasks above it are done
+type decodeTask struct {
+	node       *Node
+	target     reflect.Value
+	link       int
+	structType reflect.Type
+	quoted     bool
+	mapOf      reflect.Value
+	mapKey     reflect.Value
+}
+
+// typeError records a type mismatch at the current path if no error was
+// recorded before
+func (d *nodeDecoder) typeError(jsonType string, target reflect.Type) {
+	if d.err != nil {
+		return
+	}
+	err := &json.UnmarshalTypeError{
+		Value: jsonType,
+		Type:  target,
+		Field: strings.Join(d.links.path(d.base, d.link), "."),
+	}
+	if d.structType != nil {
+		err.Struct = d.structType.Name()
+	}
+	d.err = err
+}
+
+// decode stores node, found at path, into target. Nested values are decoded
+// with an explicit stack, so deep nesting does not grow the goroutine stack.
+func (d *nodeDecoder) decode(node *Node, target reflect.Value, path []string) {
+	d.base, d.links = path, d.links[:0]
+	d.stack = append(d.stack[:0], decodeTask{node: node, target: target})
+	for len(d.stack) > 0 {
+		task := d.stack[len(d.stack)-1]
+		d.stack = d.stack[:len(d.stack)-1]
+		d.link, d.structType = task.link, task.structType
+		switch {
+		case task.mapOf.IsValid():
+			task.mapOf.SetMapIndex(task.mapKey, task.target)
+		case task.quoted:
+			d.decodeQuoted(task.node, task.target)
+		default:
+			d.decodeNode(task.node, task.target)
+		}
+	}
+}
+
+// push schedules child, under key of the node being decoded, for decoding
+// into target
+func (d *nodeDecoder) push(child *Node, target reflect.Value, key string) {
+	d.stack = append(d.stack, decodeTask{node: child, target: target, link: d.links.add(d.link, key), structType: d.structType})
+}
+
+// pushField schedules child for decoding into field of the struct target
+func (d *nodeDecoder) pushField(child *Node, target reflect.Value, field fieldInfo, key string) {
+	fieldValue, ok := fieldByIndexAlloc(target, field.index)
+	if !ok {
+		return
+	}
+	d.stack = append(d.stack, decodeTask{
+		node:       child,
+		target:     fieldValue,
+		link:       d.links.add(d.link, key),
+		structType: target.Type(),
+		quoted:     field.quoted,
+	})
+}
+
+// decodeQuoted stores the value of a field with the ,string option, a JSON
+// string holding a scalar literal, into target. Strings still streaming are
+// skipped, and null is decoded as usual.
+func (d *nodeDecoder) decodeQuoted(node *Node, target reflect.Value) {
+	if node.Type == ValueNode && node.Value == nil {
+		d.decodeNode(node, target)
+		return
+	}
+	s, ok := node.Value.(string)
+	if node.Type != ValueNode || !ok {
+		d.fail(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal unquoted value into %v", target.Type()))
+		return
+	}
+	if !node.Completed || !target.CanAddr() {
+		return
+	}
+	if d.parser.options.rawStrings {
+		s = decodeString(s, false)
+	}
+	if err := json.Unmarshal([]byte(s), target.Addr().Interface()); err != nil {
+		d.fail(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", s, target.Type()))
+	}
+}
+
+// fail records err if no error was recorded before
+func (d *nodeDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// decodeNode stores node into target, allocating pointers as needed, and
+// pushes its children
+func (d *nodeDecoder) decodeNode(node *Node, target reflect.Value) {
+	if node == nil {
+		return
+	}
+
+	// JSON null resets pointers, maps, slices and interfaces
+	if node.Type == ValueNode && node.Value == nil {
+		switch target.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			target.Set(reflect.Zero(target.Type()))
+		}
+		return
+	}
+
+	for {
+		if target.Kind() == reflect.Pointer && target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		if d.decodeUnmarshaler(node, target) {
+			return
+		}
+		if target.Kind() != reflect.Pointer {
+			break
+		}
+		target = target.Elem()
+	}
+
+	if target.Kind() == reflect.Interface && target.NumMethod() == 0 {
+		target.Set(reflect.ValueOf(d.parser.collectNodeValue(node)))
+		return
+	}
+
+	switch node.Type {
+	case ObjectNode:
+		d.decodeObject(node, target)
+	case ArrayNode:
+		d.decodeArray(node, target)
+	case ValueNode:
+		d.decodeValue(node.Value, target)
+	}
+}
+
+// decodeUnmarshaler lets target decode node itself if it implements
+// json.Unmarshaler or encoding.TextUnmarshaler, and reports whether it does.
+// Values set by transformers are assigned as they are when they fit, and
+// values still streaming are skipped.
+func (d *nodeDecoder) decodeUnmarshaler(node *Node, target reflect.Value) bool {
+	if !isUnmarshaler(target.Type()) {
+		return false
+	}
+	if target.Kind() != reflect.Pointer {
+		if !target.CanAddr() {
+			return false
+		}
+		target = target.Addr()
+	}
+	if !node.Completed {
+		return true
+	}
+
+	if node.Type == ValueNode && node.Value != nil {
+		if value := reflect.ValueOf(node.Value); value.Type().AssignableTo(target.Type().Elem()) {
+			target.Elem().Set(value)
+			return true
+		}
+	}
+
+	var err error
+	switch u := target.Interface().(type) {
+	case json.Unmarshaler:
+		var buf bytes.Buffer
+		d.parser.writeNode(&buf, node)
+		err = u.UnmarshalJSON(buf.Bytes())
+
+	case encoding.TextUnmarshaler:
+		s, ok := node.Value.(string)
+		if node.Type != ValueNode || !ok {
+			d.typeError(jsonTypeName(node), target.Type().Elem())
+			return true
+		}
+		if d.parser.options.rawStrings {
+			s = decodeString(s, false)
+		}
+		err = u.UnmarshalText([]byte(s))
+	}
+	if err != nil {
+		d.fail(err)
+	}
+	return true
+}
+
+// isUnmarshaler reports whether t or a pointer to it implements
+// json.Unmarshaler or encoding.TextUnmarshaler. big.Float, which decodes
+// from numbers, is handled by decodeValue instead.
+func isUnmarshaler(t reflect.Type) bool {
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	if t.Elem() == bigFloatType {
+		return false
+	}
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType)
+}
+
+// jsonTypeName names the JSON type of node for type errors
+func jsonTypeName(node *Node) string {
+	switch node.Type {
+	case ObjectNode:
+		return "object"
+	case ArrayNode:
+		return "array"
+	}
+	switch node.Value.(type) {
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case nil:
+		return "null"
+	}
+	return "number"
+}
+
+// decodeObject decodes an object node into a struct or a string-keyed map
+func (d *nodeDecoder) decodeObject(node *Node, target reflect.Value) {
+	switch target.Kind() {
+	case reflect.Struct:
+		// Pushed in reverse, so fields are decoded in order
+		fields := cachedFields(target.Type())
+		for i := len(fields) - 1; i >= 0; i-- {
+			child, key := lookupChild(node, d.parser.normalizeKey(fields[i].name))
+			if child != nil {
+				d.pushField(child, target, fields[i], key)
+			}
+		}
+
+	case reflect.Map:
+		if target.Type().Key().Kind() != reflect.String {
+			d.typeError("object", target.Type())
+			return
+		}
+		if target.IsNil() {
+			target.Set(reflect.MakeMapWithSize(target.Type(), len(node.Children)))
+		}
+		elemType := target.Type().Elem()
+		for key, child := range node.Children {
+			elem := reflect.New(elemType).Elem()
+			mapKey := reflect.ValueOf(key).Convert(target.Type().Key())
+			d.stack = append(d.stack, decodeTask{target: elem, mapOf: target, mapKey: mapKey})
+			d.push(child, elem, key)
+		}
+
+	default:
+		d.typeError("object", target.Type())
+	}
+}
+
+// decodeArray decodes an array node into a slice or a fixed-size array
+func (d *nodeDecoder) decodeArray(node *Node, target reflect.Value) {
+	switch target.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(target.Type(), len(node.Array), len(node.Array))
+		for i := len(node.Array) - 1; i >= 0; i-- {
+			d.push(node.Array[i], slice.Index(i), strconv.Itoa(i))
+		}
+		target.Set(slice)
+
+	case reflect.Array:
+		for i := target.Len() - 1; i >= 0; i-- {
+			if i < len(node.Array) {
+				d.push(node.Array[i], target.Index(i), strconv.Itoa(i))
+			} else {
+				target.Index(i).Set(reflect.Zero(target.Type().Elem()))
+			}
+		}
+
+	default:
+		d.typeError("array", target.Type())
+	}
+}
+
+// decodeValue decodes a primitive value into a scalar target
+func (d *nodeDecoder) decodeValue(value interface{}, target reflect.Value) {
+	switch v := value.(type) {
+	case string:
+		if target.Kind() != reflect.String {
+			d.typeError("string", target.Type())
+			return
+		}
+		target.SetString(v)
+
+	case bool:
+		if target.Kind() != reflect.Bool {
+			d.typeError("bool", target.Type())
+			return
+		}
+		target.SetBool(v)
+
+	case int64:
+		d.decodeNumber(float64(v), v, true, target)
+
+	case float64:
+		d.decodeNumber(v, 0, false, target)
+
+	case json.Number:
+		if target.Type() == jsonNumberType {
+			target.SetString(string(v))
+			return
+		}
+		d.decodeExactNumber(v, target)
+
+	case *big.Float:
+		if target.Type() == bigFloatType {
+			target.Set(reflect.ValueOf(new(big.Float).Set(v)).Elem())
+			return
+		}
+		d.decodeExactNumber(v, target)
+
+	default:
+		// Values produced by transformers, such as time.Time
+		if v != nil && reflect.TypeOf(v).AssignableTo(target.Type()) {
+			target.Set(reflect.ValueOf(v))
+			return
+		}
+		d.typeError("value", target.Type())
+	}
+}
+
+// decodeExactNumber stores a json.Number or *big.Float into a numeric target
+func (d *nodeDecoder) decodeExactNumber(value interface{}, target reflect.Value) {
+	i, isInt := numberToInt64(value)
+	f, ok := numberToFloat64(value)
+	if !ok && !isInt {
+		d.typeError("number", target.Type())
+		return
+	}
+	d.decodeNumber(f, i, isInt, target)
+}
+
+// decodeNumber stores a number into a numeric target, rejecting lossy conversions
+func (d *nodeDecoder) decodeNumber(f float64, i int64, isInt bool, target reflect.Value) {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !isInt {
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				d.typeError("number", target.Type())
+				return
+			}
+			i = int64(f)
+		}
+		if target.OverflowInt(i) {
+			d.typeError("number", target.Type())
+			return
+		}
+		target.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !isInt {
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				d.typeError("number", target.Type())
+				return
+			}
+			i = int64(f)
+		}
+		if i < 0 || target.OverflowUint(uint64(i)) {
+			d.typeError("number", target.Type())
+			return
+		}
+		target.SetUint(uint64(i))
+
+	case reflect.Float32, reflect.Float64:
+		if target.OverflowFloat(f) {
+			d.typeError("number", target.Type())
+			return
+		}
+		target.SetFloat(f)
+
+	default:
+		d.typeError("number", target.Type())
+	}
+}
+
+// lookupChild finds the child for a field name, falling back to a
+// case-insensitive match like encoding/json. It returns the matched key.
+func lookupChild(node *Node, name string) (*Node, string) {
+	if child, exists := node.Children[name]; exists {
+		return child, name
+	}
+	for key, child := range node.Children {
+		if strings.EqualFold(key, name) {
+			return child, key
+		}
+	}
+	return nil, ""
+}
+
+// fieldByIndexAlloc walks an index path, allocating nil embedded pointers.
+// It returns false if an unexported embedded pointer blocks the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// cachedFields returns the JSON-visible fields of a struct type
+func cachedFields(t reflect.Type) []fieldInfo {
+	if fields, ok := structFieldCache.Load(t); ok {
+		return fields.([]fieldInfo)
+	}
+	fields := collectFields(t)
+	structFieldCache.Store(t, fields)
+	return fields
+}
+
+// collectFields lists the fields of t the way encoding/json does: exported
+// fields honoring `json` tags, and the fields of untagged embedded structs,
+// walked breadth first so each embedded type is expanded once. Of the
+// fields sharing a name, the shallowest wins, then the only tagged one at
+// that depth; names that are still ambiguous are left out.
+func collectFields(t reflect.Type) []fieldInfo {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	type candidate struct {
+		fieldInfo
+		tagged bool
+	}
+
+	var candidates []candidate
+	next := []embedded{{typ: t}}
+	nextCount := map[reflect.Type]int{}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, count := next, nextCount
+		next, nextCount = nil, map[reflect.Type]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(slices.Clip(e.index), i)
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, embedded{typ: ft, index: index})
+					}
+					continue
+				}
+
+				field := candidate{fieldInfo: fieldInfo{name: name, index: index}, tagged: name != ""}
+				if name == "" {
+					field.name = sf.Name
+				}
+				if slices.Contains(strings.Split(opts, ","), "string") {
+					switch ft.Kind() {
+					case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+						reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+						reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+						field.quoted = true
+					}
+				}
+				candidates = append(candidates, field)
+				if count[e.typ] > 1 {
+					// The type is embedded twice at this depth, so its fields
+					// conflict with each other
+					candidates = append(candidates, field)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	var fields []fieldInfo
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].name == candidates[i].name {
+			j++
+		}
+		dominant := candidates[i]
+		if j-i == 1 || len(candidates[i+1].index) > len(dominant.index) || candidates[i+1].tagged != dominant.tagged {
+			fields = append(fields, dominant.fieldInfo)
+		}
+		i = j
+	}
+
+	// Fields are decoded in declaration order
+	slices.SortFunc(fields, func(a, b fieldInfo) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return fields
+}
@@ -0,0 +1,389 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type unmarshalAddress struct {
+	City string `json:"city"`
+}
+
+type unmarshalBase struct {
+	ID int `json:"id"`
+}
+
+type unmarshalUser struct {
+	unmarshalBase
+	Name    string            `json:"name"`
+	Age     uint8             `json:"age"`
+	Score   float32           `json:"score"`
+	Active  bool              `json:"active"`
+	Tags    []string          `json:"tags"`
+	Address *unmarshalAddress `json:"address"`
+	Extra   map[string]int    `json:"extra"`
+	Any     interface{}       `json:"any"`
+	Pair    [2]int            `json:"pair"`
+	Ignored string            `json:"-"`
+	Title   string
+}
+
+func TestUnmarshalComplete(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"id":7,"name":"Alice","age":30,"score":1.5,"active":true,"tags":["a"],` +
+		`"address":{"city":"Paris"},"extra":{"x":1},"any":[1,2.5],"pair":[3,4],"Ignored":"no","title":"Dr"}`)
+
+	var user unmarshalUser
+	if err := parser.Unmarshal(&user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if user.ID != 7 || user.Name != "Alice" || user.Age != 30 || user.Score != 1.5 || !user.Active {
+		t.Errorf("Unexpected scalar fields: %+v", user)
+	}
+
+	if len(user.Tags) != 1 || user.Tags[0] != "a" {
+		t.Errorf("Expected tags [a], got %v", user.Tags)
+	}
+
+	if user.Address == nil || user.Address.City != "Paris" {
+		t.Errorf("Expected address city Paris, got %+v", user.Address)
+	}
+
+	if user.Extra["x"] != 1 {
+		t.Errorf("Expected extra x to be 1, got %v", user.Extra)
+	}
+
+	anySlice, ok := user.Any.([]interface{})
+	if !ok || len(anySlice) != 2 || anySlice[1] != 2.5 {
+		t.Errorf("Expected any to be [1 2.5], got %v", user.Any)
+	}
+
+	if user.Pair != [2]int{3, 4} {
+		t.Errorf("Expected pair [3 4], got %v", user.Pair)
+	}
+
+	if user.Ignored != "" {
+		t.Errorf("Expected ignored field to stay empty, got %q", user.Ignored)
+	}
+
+	// Untagged fields match case-insensitively
+	if user.Title != "Dr" {
+		t.Errorf("Expected title Dr, got %q", user.Title)
+	}
+}
+
+func TestUnmarshalPartial(t *testing.T) {
+	parser := NewStreamJSONParser()
+
+	var user unmarshalUser
+	parser.Append(`{"name":"Al`)
+	if err := parser.Unmarshal(&user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if user.Name != "Al" {
+		t.Errorf("Expected partial name 'Al', got %q", user.Name)
+	}
+
+	parser.Append(`ice","age":3`)
+	if err := parser.Unmarshal(&user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The number is not terminated yet and is left untouched
+	if user.Name != "Alice" || user.Age != 0 {
+		t.Errorf("Expected name Alice and no age yet, got %+v", user)
+	}
+
+	parser.Append(`1,"tags":["x"`)
+	if err := parser.Unmarshal(&user); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if user.Age != 31 || len(user.Tags) != 1 || user.Tags[0] != "x" {
+		t.Errorf("Expected age 31 and tags [x], got %+v", user)
+	}
+}
+
+func TestUnmarshalTypeMismatch(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"age":"old","name":"Bob","score":300,"id":1.5}`)
+
+	var user unmarshalUser
+	err := parser.Unmarshal(&user)
+
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Fatalf("Expected UnmarshalTypeError, got %v", err)
+	}
+
+	// Remaining fields are still decoded
+	if user.Name != "Bob" || user.Score != 300 {
+		t.Errorf("Expected other fields to be decoded, got %+v", user)
+	}
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"a":1}`)
+
+	var user unmarshalUser
+	var invalidErr *json.InvalidUnmarshalError
+	if err := parser.Unmarshal(user); !errors.As(err, &invalidErr) {
+		t.Errorf("Expected InvalidUnmarshalError for non-pointer, got %v", err)
+	}
+
+	// A generic target receives the materialized root
+	var generic interface{}
+	if err := parser.Unmarshal(&generic); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m, ok := generic.(map[string]interface{}); !ok || m["a"] != int64(1) {
+		t.Errorf("Expected map with a=1, got %v", generic)
+	}
+}
+
+// unmarshalDecimal keeps the exact text of a number, like decimal types do
+type unmarshalDecimal struct {
+	text string
+}
+
+func (d *unmarshalDecimal) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] == '"' || data[0] == '{' || data[0] == '[' {
+		return errors.New("decimal: not a number")
+	}
+	d.text = string(data)
+	return nil
+}
+
+// unmarshalDate decodes a "YYYY-MM-DD" string, like civil.Date
+type unmarshalDate struct {
+	Year, Month, Day int
+}
+
+func (d *unmarshalDate) UnmarshalText(text []byte) error {
+	parts := strings.Split(string(text), "-")
+	if len(parts) != 3 {
+		return errors.New("date: invalid " + string(text))
+	}
+	d.Year, d.Month, d.Day = len(parts[0]), len(parts[1]), len(parts[2])
+	return nil
+}
+
+type unmarshalInvoice struct {
+	Total unmarshalDecimal            `json:"total"`
+	Tax   *unmarshalDecimal           `json:"tax"`
+	Due   unmarshalDate               `json:"due"`
+	Dates map[string]unmarshalDate    `json:"dates"`
+	Lines []unmarshalDecimal          `json:"lines"`
+	Raw   map[string]*json.RawMessage `json:"raw"`
+}
+
+func TestUnmarshalUnmarshalers(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"total":12.50,"tax":0.25,"due":"2025-01-31",` +
+		`"dates":{"paid":"2025-02-01"},"lines":[1.10,2],"raw":{"x":{"b":[true],"a":null}}}`)
+
+	var invoice unmarshalInvoice
+	if err := parser.Unmarshal(&invoice); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if invoice.Total.text != "12.5" {
+		t.Errorf("Expected total 12.5, got %q", invoice.Total.text)
+	}
+	if invoice.Tax == nil || invoice.Tax.text != "0.25" {
+		t.Errorf("Expected tax decoded through a pointer, got %+v", invoice.Tax)
+	}
+	if invoice.Due != (unmarshalDate{4, 2, 2}) {
+		t.Errorf("Expected due date decoded from text, got %+v", invoice.Due)
+	}
+	if invoice.Dates["paid"] != (unmarshalDate{4, 2, 2}) {
+		t.Errorf("Expected map value decoded from text, got %+v", invoice.Dates)
+	}
+	if len(invoice.Lines) != 2 || invoice.Lines[0].text != "1.1" || invoice.Lines[1].text != "2" {
+		t.Errorf("Expected slice elements decoded, got %+v", invoice.Lines)
+	}
+	if raw := invoice.Raw["x"]; raw == nil || string(*raw) != `{"a":null,"b":[true]}` {
+		t.Errorf("Expected raw object, got %v", invoice.Raw)
+	}
+}
+
+func TestUnmarshalUnmarshalersWaitForCompleteValues(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"due":"2025-01`)
+
+	var invoice unmarshalInvoice
+	if err := parser.Unmarshal(&invoice); err != nil {
+		t.Fatalf("Unexpected error for a partial value: %v", err)
+	}
+	if invoice.Due != (unmarshalDate{}) {
+		t.Errorf("Expected partial date left unchanged, got %+v", invoice.Due)
+	}
+
+	parser.Append(`-31"}`)
+	if err := parser.Unmarshal(&invoice); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if invoice.Due != (unmarshalDate{4, 2, 2}) {
+		t.Errorf("Expected date once complete, got %+v", invoice.Due)
+	}
+}
+
+func TestUnmarshalUnmarshalerErrors(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"total":"12.50","due":20250131}`)
+
+	var invoice unmarshalInvoice
+	err := parser.Unmarshal(&invoice)
+	if err == nil || err.Error() != "decimal: not a number" {
+		t.Errorf("Expected the error of UnmarshalJSON, got %v", err)
+	}
+
+	parser = NewStreamJSONParser()
+	parser.Append(`{"due":20250131}`)
+	var typeErr *json.UnmarshalTypeError
+	if err := parser.Unmarshal(&invoice); !errors.As(err, &typeErr) || typeErr.Value != "number" {
+		t.Errorf("Expected UnmarshalTypeError for a number into a TextUnmarshaler, got %v", err)
+	}
+}
+
+// unmarshalSelf embeds itself, which must not recurse forever
+type unmarshalSelf struct {
+	*unmarshalSelf
+	X int `json:"x"`
+}
+
+type unmarshalLeft struct {
+	Name string
+	Left string `json:"left"`
+}
+
+type unmarshalRight struct {
+	Name  string
+	Right string `json:"right"`
+}
+
+type unmarshalTagged struct {
+	Name string `json:"Name"`
+}
+
+type unmarshalUntagged struct {
+	Name string
+}
+
+// unmarshalConflicts has fields whose names conflict at different depths
+type unmarshalConflicts struct {
+	unmarshalLeft
+	unmarshalRight
+	Inner struct {
+		unmarshalTagged
+		unmarshalUntagged
+	} `json:"inner"`
+}
+
+func TestUnmarshalEmbeddingRules(t *testing.T) {
+	input := `{"x":1,"Name":"n","left":"l","right":"r","inner":{"Name":"tagged"}}`
+	parser := NewStreamJSONParser()
+	parser.Append(input)
+
+	var self, wantSelf unmarshalSelf
+	if err := parser.Unmarshal(&self); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(input), &wantSelf); err != nil {
+		t.Fatal(err)
+	}
+	if self.X != 1 || self.unmarshalSelf != nil || self.X != wantSelf.X {
+		t.Errorf("Expected x 1 like encoding/json, got %+v", self)
+	}
+
+	// Ambiguous names at the same depth are dropped, and a tagged field
+	// wins over an untagged one
+	var got, want unmarshalConflicts
+	if err := parser.Unmarshal(&got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(input), &want); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Expected %+v like encoding/json, got %+v", want, got)
+	}
+}
+
+type unmarshalQuoted struct {
+	ID     int64    `json:"id,string"`
+	Price  *float64 `json:"price,string"`
+	Active bool     `json:"active,string"`
+	Label  string   `json:"label,string"`
+	Tags   []string `json:"tags,string"`
+}
+
+func TestUnmarshalStringOption(t *testing.T) {
+	input := `{"id":"42","price":"9.5","active":"true","label":"\"x\"","tags":["a"]}`
+	parser := NewStreamJSONParser()
+
+	// A quoted number still streaming is left alone
+	parser.Append(input[:8])
+	var got unmarshalQuoted
+	if err := parser.Unmarshal(&got); err != nil || got.ID != 0 {
+		t.Fatalf("Expected no id while it streams, got %d, %v", got.ID, err)
+	}
+
+	parser.Append(input[8:])
+	if err := parser.Unmarshal(&got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var want unmarshalQuoted
+	if err := json.Unmarshal([]byte(input), &want); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != want.ID || got.Price == nil || *got.Price != *want.Price || got.Active != want.Active ||
+		got.Label != want.Label || len(got.Tags) != 1 {
+		t.Errorf("Expected %+v like encoding/json, got %+v", want, got)
+	}
+
+	for _, input := range []string{`{"id":42}`, `{"id":"4x"}`} {
+		parser := NewStreamJSONParser()
+		parser.Append(input)
+		var got unmarshalQuoted
+		err := parser.Unmarshal(&got)
+		if err == nil || !strings.Contains(err.Error(), "invalid use of ,string struct tag") {
+			t.Errorf("%s: expected an invalid use of ,string error, got %v", input, err)
+		}
+	}
+}
+
+func TestUnmarshalTypeErrorStruct(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"address":{"city":1}}`)
+
+	var user unmarshalUser
+	err := parser.Unmarshal(&user)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Struct != "unmarshalAddress" || typeErr.Field != "address.city" {
+		t.Fatalf("Expected a type error in unmarshalAddress.address.city, got %#v", err)
+	}
+	want := "json: cannot unmarshal number into Go struct field unmarshalAddress.address.city of type string"
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a 128-bit universally unique identifier, as returned by GetUUID
+type UUID [16]byte
+
+// uuidDashes are the offsets of the dashes in the canonical form
+var uuidDashes = [...]int{8, 13, 18, 23}
+
+// ParseUUID parses a UUID in the canonical form
+// xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, in either case
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 {
+		return u, fmt.Errorf("streamjson: invalid UUID %q", s)
+	}
+	for _, i := range uuidDashes {
+		if s[i] != '-' {
+			return u, fmt.Errorf("streamjson: invalid UUID %q", s)
+		}
+	}
+
+	digits := s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36]
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return UUID{}, fmt.Errorf("streamjson: invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// String returns the UUID in the canonical lowercase form
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	hex.Encode(buf[9:13], u[4:6])
+	hex.Encode(buf[14:18], u[6:8])
+	hex.Encode(buf[19:23], u[8:10])
+	hex.Encode(buf[24:36], u[10:16])
+	for _, i := range uuidDashes {
+		buf[i] = '-'
+	}
+	return string(buf[:])
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"slices"
+)
+
+// watchSubscription is a channel registered by Watch
+type watchSubscription struct {
+	path   []string
+	ch     chan interface{}
+	result chan awaitResult // Set instead of ch for Await
+}
+
+// Watch returns a channel that receives the value at the path each time it
+// is updated and is closed once the value completes. Streaming strings are
+// sent with their partial content; objects and arrays are sent once, when
+// they close. A value already present is sent right away.
+//
+// Sending never blocks Append: the channel holds the latest value only, so a
+// slow consumer may skip intermediate partial values but always receives the
+// final one before the channel is closed. Reset closes pending channels.
+func (p *StreamJSONParser) Watch(keys ...string) <-chan interface{} {
+	sub := watchSubscription{path: slices.Clone(p.normalizePath(keys)), ch: make(chan interface{}, 1)}
+
+	if p.root != nil {
+		if node := p.findNode(keys); node != nil {
+			sendLatest(sub.ch, p.collectNodeValue(node))
+			if node.Completed {
+				close(sub.ch)
+				return sub.ch
+			}
+		}
+	}
+	if p.finished {
+		close(sub.ch) // Nothing more will arrive
+		return sub.ch
+	}
+
+	p.watches = append(p.watches, sub)
+	return sub.ch
+}
+
+// deliverWatch sends the value of the node at path to matching watches and
+// closes them once it is complete
+func (p *StreamJSONParser) deliverWatch(path []string, node *Node) {
+	if len(p.watches) == 0 {
+		return
+	}
+	if len(path) == 0 && node.Completed && !p.options.multipleDocuments {
+		defer p.resolveAwaits() // Paths not in the document never complete
+	}
+
+	var value interface{}
+	materialized := false
+	kept := p.watches[:0]
+	for _, sub := range p.watches {
+		if !slices.Equal(sub.path, path) || sub.result != nil && !node.Completed {
+			kept = append(kept, sub)
+			continue
+		}
+		if !materialized {
+			value = p.collectNodeValue(node)
+			materialized = true
+		}
+		if sub.result != nil {
+			sub.result <- awaitResult{value: value}
+			continue
+		}
+		sendLatest(sub.ch, value)
+		if node.Completed {
+			close(sub.ch)
+		} else {
+			kept = append(kept, sub)
+		}
+	}
+	clear(p.watches[len(kept):])
+	p.watches = kept
+}
+
+// closeWatches closes the channels of all pending watches and fails
+// pending awaits
+func (p *StreamJSONParser) closeWatches() {
+	for _, sub := range p.watches {
+		if sub.result != nil {
+			sub.result <- awaitResult{err: ErrValueNotCompleted}
+		} else {
+			close(sub.ch)
+		}
+	}
+	p.watches = nil
+}
+
+// sendLatest sends value on a channel with a buffer of one, replacing a
+// value the consumer has not received yet
+func sendLatest(ch chan interface{}, value interface{}) {
+	for {
+		select {
+		case ch <- value:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"testing"
+)
+
+func TestStreamJSONParserWatch(t *testing.T) {
+	parser := NewStreamJSONParser()
+	ch := parser.Watch("choices", "0", "message", "content")
+
+	var received []interface{}
+	chunks := []string{`{"choices":[{"message":{"content":"Hel`, `lo`, `, world"}}]}`}
+	for _, chunk := range chunks {
+		parser.Append(chunk)
+		for len(ch) > 0 {
+			received = append(received, <-ch)
+		}
+	}
+
+	if _, open := <-ch; open {
+		t.Fatal("Expected channel to be closed after completion")
+	}
+	expected := []interface{}{"Hel", "Hello", "Hello, world"}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Value %d: expected %v, got %v", i, expected[i], received[i])
+		}
+	}
+}
+
+func TestStreamJSONParserWatchSlowConsumer(t *testing.T) {
+	parser := NewStreamJSONParser()
+	ch := parser.Watch("text")
+
+	// Nobody reads while appending, so only the final value is kept
+	parser.Append(`{"text":"a`)
+	parser.Append(`b`)
+	parser.Append(`c"}`)
+
+	if value := <-ch; value != "abc" {
+		t.Errorf("Expected final value, got %v", value)
+	}
+	if _, open := <-ch; open {
+		t.Error("Expected channel to be closed")
+	}
+}
+
+func TestStreamJSONParserWatchExisting(t *testing.T) {
+	parser := NewStreamJSONParser()
+	parser.Append(`{"done":[1,2],"open":"par`)
+
+	done := parser.Watch("done")
+	value, ok := (<-done).([]interface{})
+	if !ok || len(value) != 2 {
+		t.Errorf("Expected completed array, got %v", value)
+	}
+	if _, open := <-done; open {
+		t.Error("Expected channel for a completed value to be closed")
+	}
+
+	pending := parser.Watch("open")
+	if value := <-pending; value != "par" {
+		t.Errorf("Expected current partial value, got %v", value)
+	}
+
+	missing := parser.Watch("missing")
+	parser.Reset()
+	if _, open := <-missing; open {
+		t.Error("Expected Reset to close pending watches")
+	}
+	if _, open := <-pending; open {
+		t.Error("Expected Reset to close pending watches")
+	}
+}
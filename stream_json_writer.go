@@ -0,0 +1,297 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrWriterState is wrapped by the errors returned for calls made out of
+// order, such as a value in an object without a key
+var ErrWriterState = errors.New("streamjson: invalid writer state")
+
+// writerFrame tracks an open container of a StreamJSONWriter
+type writerFrame struct {
+	array  bool
+	count  int  // Values written so far
+	hasKey bool // An object key was written and its value is expected
+}
+
+// StreamJSONWriter incrementally writes valid JSON to an io.Writer, so
+// output can be forwarded to a client token by token. Every call writes
+// through immediately. Once the underlying writer fails, every later call
+// returns the same error.
+type StreamJSONWriter struct {
+	out      io.Writer
+	buf      bytes.Buffer
+	stack    []writerFrame
+	roots    int
+	inString bool   // A string value is open
+	pending  string // Trailing bytes of an incomplete UTF-8 sequence
+	err      error
+}
+
+// NewStreamJSONWriter creates a writer that writes JSON to out. Several root
+// values may be written in turn; they are separated by newlines.
+func NewStreamJSONWriter(out io.Writer) *StreamJSONWriter {
+	return &StreamJSONWriter{out: out}
+}
+
+// BeginObject opens an object
+func (w *StreamJSONWriter) BeginObject() error {
+	return w.begin(false)
+}
+
+// EndObject closes the innermost object, ending an open string first
+func (w *StreamJSONWriter) EndObject() error {
+	return w.end(false)
+}
+
+// BeginArray opens an array
+func (w *StreamJSONWriter) BeginArray() error {
+	return w.begin(true)
+}
+
+// EndArray closes the innermost array, ending an open string first
+func (w *StreamJSONWriter) EndArray() error {
+	return w.end(true)
+}
+
+// WriteKey writes an object key, ending an open string value first
+func (w *StreamJSONWriter) WriteKey(key string) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+	w.endString()
+
+	if len(w.stack) == 0 || w.stack[len(w.stack)-1].array {
+		return w.misuse("key outside of an object")
+	}
+	frame := &w.stack[len(w.stack)-1]
+	if frame.hasKey {
+		return w.misuse("key without a value")
+	}
+
+	if frame.count > 0 {
+		w.buf.WriteByte(',')
+	}
+	w.buf.WriteByte('"')
+	writeEscaped(&w.buf, key)
+	w.buf.WriteString(`":`)
+	frame.hasKey = true
+	return w.flush()
+}
+
+// WriteStringChunk appends chunk to the current string value, opening a new
+// string if none is open. The string stays open until EndString or the next
+// key, value or closing call. A multi-byte character split across chunks is
+// held back until it is complete.
+func (w *StreamJSONWriter) WriteStringChunk(chunk string) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+
+	if !w.inString {
+		if err := w.beginValue(); err != nil {
+			return err
+		}
+		w.buf.WriteByte('"')
+		w.inString = true
+	}
+
+	data := w.pending + chunk
+	n := completeUTF8(data)
+	writeEscaped(&w.buf, data[:n])
+	w.pending = data[n:]
+	return w.flush()
+}
+
+// EndString closes the open string value
+func (w *StreamJSONWriter) EndString() error {
+	if w.err != nil {
+		return w.err
+	}
+	if !w.inString {
+		return w.misuse("no open string")
+	}
+	w.buf.Reset()
+	w.endString()
+	return w.flush()
+}
+
+// WriteString writes a complete string value
+func (w *StreamJSONWriter) WriteString(value string) error {
+	if err := w.WriteStringChunk(value); err != nil {
+		return err
+	}
+	return w.EndString()
+}
+
+// WriteValue writes any value using encoding/json, without HTML escaping
+func (w *StreamJSONWriter) WriteValue(value interface{}) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	w.buf.Reset()
+	if err := w.beginValue(); err != nil {
+		return err
+	}
+	w.buf.Write(bytes.TrimSuffix(data.Bytes(), []byte("\n")))
+	return w.flush()
+}
+
+// Close ends an open string and closes every open container, writing null
+// for a key that is still waiting for its value, so a stream cut short still
+// ends as valid JSON. It does not close the underlying writer.
+func (w *StreamJSONWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+	w.endString()
+
+	for len(w.stack) > 0 {
+		frame := w.stack[len(w.stack)-1]
+		w.stack = w.stack[:len(w.stack)-1]
+		if frame.array {
+			w.buf.WriteByte(']')
+			continue
+		}
+		if frame.hasKey {
+			w.buf.WriteString("null")
+		}
+		w.buf.WriteByte('}')
+	}
+	return w.flush()
+}
+
+// begin opens a container
+func (w *StreamJSONWriter) begin(array bool) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+	if err := w.beginValue(); err != nil {
+		return err
+	}
+
+	if array {
+		w.buf.WriteByte('[')
+	} else {
+		w.buf.WriteByte('{')
+	}
+	w.stack = append(w.stack, writerFrame{array: array})
+	return w.flush()
+}
+
+// end closes the innermost container, which must match array
+func (w *StreamJSONWriter) end(array bool) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+	w.endString()
+
+	if len(w.stack) == 0 || w.stack[len(w.stack)-1].array != array {
+		if array {
+			return w.misuse("no open array")
+		}
+		return w.misuse("no open object")
+	}
+	if w.stack[len(w.stack)-1].hasKey {
+		return w.misuse("key without a value")
+	}
+
+	w.stack = w.stack[:len(w.stack)-1]
+	if array {
+		w.buf.WriteByte(']')
+	} else {
+		w.buf.WriteByte('}')
+	}
+	return w.flush()
+}
+
+// beginValue ends an open string and writes the separator that precedes a
+// new value, checking that a value is allowed here
+func (w *StreamJSONWriter) beginValue() error {
+	w.endString()
+
+	if len(w.stack) == 0 {
+		if w.roots > 0 {
+			w.buf.WriteByte('\n')
+		}
+		w.roots++
+		return nil
+	}
+
+	frame := &w.stack[len(w.stack)-1]
+	if frame.array {
+		if frame.count > 0 {
+			w.buf.WriteByte(',')
+		}
+	} else if !frame.hasKey {
+		return w.misuse("value in an object without a key")
+	}
+	frame.count++
+	frame.hasKey = false
+	return nil
+}
+
+// endString closes an open string into buf. Held back bytes that never
+// formed a character are written as �.
+func (w *StreamJSONWriter) endString() {
+	if !w.inString {
+		return
+	}
+	writeEscaped(&w.buf, w.pending)
+	w.buf.WriteByte('"')
+	w.pending = ""
+	w.inString = false
+}
+
+// misuse writes what has been buffered, such as a string closed on the way,
+// and returns an ErrWriterState error
+func (w *StreamJSONWriter) misuse(message string) error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrWriterState, message)
+}
+
+// flush writes the buffer to the underlying writer
+func (w *StreamJSONWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	if _, err := w.out.Write(w.buf.Bytes()); err != nil {
+		w.err = err
+	}
+	w.buf.Reset()
+	return w.err
+}
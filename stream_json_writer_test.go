@@ -0,0 +1,155 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStreamJSONWriterChunks(t *testing.T) {
+	var out strings.Builder
+	w := NewStreamJSONWriter(&out)
+
+	w.BeginObject()
+	w.WriteKey("content")
+	w.WriteStringChunk("Hello, ")
+	w.WriteStringChunk("\"world\"\n")
+	w.WriteKey("tags")
+	w.BeginArray()
+	w.WriteString("a")
+	w.WriteValue(1.5)
+	w.WriteValue(map[string]interface{}{"b": "<b>"})
+	w.EndArray()
+	if err := w.EndObject(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"content":"Hello, \"world\"\n","tags":["a",1.5,{"b":"<b>"}]}`
+	if out.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, out.String())
+	}
+}
+
+func TestStreamJSONWriterSplitRune(t *testing.T) {
+	var out strings.Builder
+	w := NewStreamJSONWriter(&out)
+
+	euro := "€"
+	w.WriteStringChunk("price " + euro[:1])
+	if out.String() != `"price ` {
+		t.Errorf("Expected incomplete character to be held back, got %s", out.String())
+	}
+	w.WriteStringChunk(euro[1:])
+	w.WriteStringChunk("\xe2")
+	w.EndString()
+
+	if out.String() != "\"price €\\ufffd\"" {
+		t.Errorf("Unexpected output %s", out.String())
+	}
+}
+
+func TestStreamJSONWriterRoundTrip(t *testing.T) {
+	var out strings.Builder
+	w := NewStreamJSONWriter(&out)
+	parser := NewStreamJSONParser()
+
+	w.BeginObject()
+	w.WriteKey("a\tb")
+	w.WriteStringChunk("x\u0001y")
+	w.WriteKey("n")
+	w.WriteValue(nil)
+	w.EndObject()
+	parser.Append(out.String())
+
+	if !parser.IsCompleted() || parser.Get("a\tb") != "x\u0001y" {
+		t.Errorf("Expected output to parse back, got %s", out.String())
+	}
+}
+
+func TestStreamJSONWriterClose(t *testing.T) {
+	var out strings.Builder
+	w := NewStreamJSONWriter(&out)
+
+	w.BeginObject()
+	w.WriteKey("items")
+	w.BeginArray()
+	w.BeginObject()
+	w.WriteKey("text")
+	w.WriteStringChunk("cut")
+	w.Close()
+
+	expected := `{"items":[{"text":"cut"}]}`
+	if out.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, out.String())
+	}
+
+	out.Reset()
+	w.BeginObject()
+	w.WriteKey("pending")
+	w.Close()
+	if out.String() != "\n"+`{"pending":null}` {
+		t.Errorf("Expected second root with null value, got %q", out.String())
+	}
+}
+
+func TestStreamJSONWriterMisuse(t *testing.T) {
+	var out strings.Builder
+	w := NewStreamJSONWriter(&out)
+
+	if err := w.WriteKey("a"); !errors.Is(err, ErrWriterState) {
+		t.Errorf("Expected ErrWriterState for key at the root, got %v", err)
+	}
+
+	w.BeginObject()
+	if err := w.WriteString("a"); !errors.Is(err, ErrWriterState) {
+		t.Errorf("Expected ErrWriterState for value without key, got %v", err)
+	}
+	if err := w.EndArray(); !errors.Is(err, ErrWriterState) {
+		t.Errorf("Expected ErrWriterState for mismatched close, got %v", err)
+	}
+	w.WriteKey("a")
+	if err := w.EndObject(); !errors.Is(err, ErrWriterState) {
+		t.Errorf("Expected ErrWriterState for key without value, got %v", err)
+	}
+	if err := w.EndString(); !errors.Is(err, ErrWriterState) {
+		t.Errorf("Expected ErrWriterState without open string, got %v", err)
+	}
+
+	w.WriteValue(true)
+	w.EndObject()
+	if out.String() != `{"a":true}` {
+		t.Errorf("Expected misuse to write nothing, got %s", out.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestStreamJSONWriterStickyError(t *testing.T) {
+	w := NewStreamJSONWriter(failingWriter{})
+
+	err := w.BeginObject()
+	if err == nil {
+		t.Fatal("Expected write error")
+	}
+	if w.WriteKey("a") != err || w.Close() != err {
+		t.Errorf("Expected later calls to return the first error")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package streamjsontest checks that code feeding a streaming parser gives
+// the same result however the input is split into chunks. It splits a
+// complete JSON document every way at two points, byte by byte and at
+// random, and compares each result with what encoding/json decodes.
+//
+//	func TestFeeding(t *testing.T) {
+//		streamjsontest.CheckSplits(t, `{"a": [1, "two"]}`, func(chunks []string) (interface{}, error) {
+//			return myFeeder(chunks) // Code under test
+//		})
+//	}
+package streamjsontest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/easyagent-dev/streamjson"
+)
+
+// ErrIncomplete is returned by a Feed from Parser when the document did not
+// complete
+var ErrIncomplete = errors.New("streamjsontest: document incomplete")
+
+// Feed parses a document delivered in chunks, in order, and returns the
+// parsed value
+type Feed func(chunks []string) (interface{}, error)
+
+// Parser returns a Feed that appends the chunks to a new StreamJSONParser
+// created with opts and finishes it
+func Parser(opts ...streamjson.Option) Feed {
+	return func(chunks []string) (interface{}, error) {
+		parser := streamjson.NewStreamJSONParser(opts...)
+		for _, chunk := range chunks {
+			parser.Append(chunk)
+		}
+		parser.Finish()
+		if err := parser.Err(); err != nil {
+			return nil, err
+		}
+		if !parser.IsCompleted() {
+			return nil, ErrIncomplete
+		}
+		return parser.Get(), nil
+	}
+}
+
+// MismatchError reports a chunking for which a Feed failed or returned a
+// value other than encoding/json's
+type MismatchError struct {
+	Chunks []string    // The chunking that was fed
+	Got    interface{} // The value the Feed returned, normalized
+	Want   interface{} // The value encoding/json decoded
+	Err    error       // The error the Feed returned, if any
+}
+
+func (e *MismatchError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("streamjsontest: chunks %q: %v", e.Chunks, e.Err)
+	}
+	return fmt.Sprintf("streamjsontest: chunks %q: got %#v, want %#v", e.Chunks, e.Got, e.Want)
+}
+
+func (e *MismatchError) Unwrap() error {
+	return e.Err
+}
+
+// Splits returns every split of document into two non-empty chunks, then
+// the document one byte per chunk
+func Splits(document string) [][]string {
+	var chunkings [][]string
+	for i := 1; i < len(document); i++ {
+		chunkings = append(chunkings, []string{document[:i], document[i:]})
+	}
+	if len(document) > 2 {
+		bytes := make([]string, len(document))
+		for i := range len(document) {
+			bytes[i] = document[i : i+1]
+		}
+		chunkings = append(chunkings, bytes)
+	}
+	return chunkings
+}
+
+// RandomSplits returns n splits of document into chunks of 1 to maxChunk
+// bytes, the same for the same seed
+func RandomSplits(document string, n, maxChunk int, seed uint64) [][]string {
+	rng := rand.New(rand.NewPCG(seed, seed>>32))
+	chunkings := make([][]string, n)
+	for i := range chunkings {
+		rest := document
+		for len(rest) > 0 {
+			size := min(1+rng.IntN(max(maxChunk, 1)), len(rest))
+			chunkings[i] = append(chunkings[i], rest[:size])
+			rest = rest[size:]
+		}
+	}
+	return chunkings
+}
+
+// Verify feeds each chunking to feed and returns a *MismatchError for the
+// first one whose value differs from what encoding/json decodes from
+// document. Numbers are compared as float64, as encoding/json decodes them.
+func Verify(document string, feed Feed, chunkings [][]string) error {
+	var want interface{}
+	if err := json.Unmarshal([]byte(document), &want); err != nil {
+		return fmt.Errorf("streamjsontest: invalid document: %w", err)
+	}
+	for _, chunks := range chunkings {
+		got, err := feed(chunks)
+		if err == nil {
+			got, err = normalize(got)
+		}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			return &MismatchError{Chunks: chunks, Got: got, Want: want, Err: err}
+		}
+	}
+	return nil
+}
+
+// CheckSplits verifies feed against every chunking from Splits and reports
+// the first mismatch on t
+func CheckSplits(t testing.TB, document string, feed Feed) {
+	t.Helper()
+	if err := Verify(document, feed, Splits(document)); err != nil {
+		t.Error(err)
+	}
+}
+
+// CheckRandomSplits verifies feed against n chunkings from RandomSplits,
+// with chunks of up to 16 bytes, and reports the first mismatch on t
+func CheckRandomSplits(t testing.TB, document string, feed Feed, n int) {
+	t.Helper()
+	if err := Verify(document, feed, RandomSplits(document, n, 16, uint64(len(document)))); err != nil {
+		t.Error(err)
+	}
+}
+
+// normalize converts a parsed value to the types encoding/json decodes into
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 easyagent
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streamjsontest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/easyagent-dev/streamjson"
+)
+
+const document = `{"name": "café ☕", "n": [1, -2.5e3, 0], "ok": true, "none": null, "nested": {"a": [{}, []]}}`
+
+func TestCheckSplits(t *testing.T) {
+	CheckSplits(t, document, Parser())
+	CheckRandomSplits(t, document, Parser(), 50)
+	CheckSplits(t, `"scalar"`, Parser(streamjson.WithScalarRoots()))
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	// A feeder that drops the last chunk
+	broken := func(chunks []string) (interface{}, error) {
+		return Parser()(chunks[:len(chunks)-1])
+	}
+	err := Verify(document, broken, Splits(document))
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a MismatchError, got %v", err)
+	}
+	if !errors.Is(err, ErrIncomplete) || len(mismatch.Chunks) != 2 {
+		t.Errorf("Expected the first split to fail as incomplete, got %v", err)
+	}
+
+	// A feeder that changes a value
+	wrong := func(chunks []string) (interface{}, error) {
+		return Parser()([]string{strings.Replace(strings.Join(chunks, ""), "true", "false", 1)})
+	}
+	if err := Verify(document, wrong, Splits(document)); !errors.As(err, &mismatch) || mismatch.Err != nil {
+		t.Errorf("Expected a value mismatch, got %v", err)
+	}
+}
+
+func TestVerifyInvalidDocument(t *testing.T) {
+	if err := Verify(`{"a": `, Parser(), nil); err == nil {
+		t.Error("Expected an error for an invalid document")
+	}
+}
+
+func TestRandomSplits(t *testing.T) {
+	chunkings := RandomSplits(document, 10, 4, 7)
+	if len(chunkings) != 10 {
+		t.Fatalf("Expected 10 chunkings, got %d", len(chunkings))
+	}
+	for _, chunks := range chunkings {
+		if strings.Join(chunks, "") != document {
+			t.Fatalf("Chunks %q do not reassemble the document", chunks)
+		}
+		for _, chunk := range chunks {
+			if len(chunk) == 0 || len(chunk) > 4 {
+				t.Fatalf("Chunk %q outside 1 to 4 bytes", chunk)
+			}
+		}
+	}
+	if !equalChunkings(chunkings, RandomSplits(document, 10, 4, 7)) {
+		t.Error("Expected the same chunkings for the same seed")
+	}
+}
+
+func equalChunkings(a, b [][]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.Join(a[i], "|") != strings.Join(b[i], "|") {
+			return false
+		}
+	}
+	return true
+}